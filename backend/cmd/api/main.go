@@ -7,9 +7,12 @@ import (
 	"backend/internal/repository"
 	"backend/internal/service"
 	"backend/internal/storage"
+	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -82,15 +85,16 @@ func main() {
 	superAdminBootstrapHandler := handler.NewSuperAdminBootstrapHandler(superAdminBootstrapService)
 
 	classRepo := repository.NewClassRepository(db)
+	chatRepo := repository.NewChatRepository(db)
 	classService := service.NewClassService(classRepo, schoolService)
 	classHandler := handler.NewClassHandler(classService, schoolService)
 
 	subjectClassRepo := repository.NewSubjectClassRepository(db)
-	subjectClassService := service.NewSubjectClassService(subjectClassRepo)
+	subjectClassService := service.NewSubjectClassService(subjectClassRepo, chatRepo)
 	subjectClassHandler := handler.NewSubjectClassHandler(subjectClassService, classService)
 
 	enrollmentRepo := repository.NewEnrollmentRepository(db)
-	enrollmentService := service.NewEnrollmentService(enrollmentRepo, classRepo, schoolUserRepo)
+	enrollmentService := service.NewEnrollmentService(enrollmentRepo, classRepo, schoolUserRepo, chatRepo)
 	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentService, classService)
 
 	mediaRepo := repository.NewMediaRepository(db)
@@ -104,8 +108,12 @@ func main() {
 	attachmentRepo := repository.NewAttachmentRepository(db)
 	attachmentService := service.NewAttachmentService(attachmentRepo)
 
+	realtimeHub := realtime.NewHub()
+	go realtimeHub.Run()
+	realtimePublisher := realtime.NewPublisher(realtimeHub)
+
 	notificationRepo := repository.NewNotificationRepository(db)
-	notificationService := service.NewNotificationService(notificationRepo)
+	notificationService := service.NewNotificationService(notificationRepo, realtimePublisher)
 	notificationHandler := handler.NewNotificationHandler(notificationService)
 
 	materialRepo := repository.NewMaterialRepository(db)
@@ -118,22 +126,41 @@ func main() {
 	studentNoteHandler := handler.NewStudentNoteHandler(studentNoteService)
 
 	feedRepo := repository.NewFeedRepository(db)
-	feedService := service.NewFeedService(feedRepo, attachmentService, notificationService, enrollmentRepo, classRepo, subjectClassRepo)
+	feedService := service.NewFeedService(feedRepo, attachmentService, notificationService, enrollmentRepo, classRepo, subjectClassRepo, realtimePublisher)
 	commentRepo := repository.NewCommentRepository(db)
 	contentOwnerRepo := repository.NewContentOwnerRepository(db)
 	commentService := service.NewCommentService(commentRepo, contentOwnerRepo, notificationService, feedRepo, materialRepo, assignmentRepo, enrollmentRepo, subjectClassRepo)
 	feedHandler := handler.NewFeedHandler(feedService, commentService, classService, notificationService)
 	commentHandler := handler.NewCommentHandler(commentService)
 
-	chatRepo := repository.NewChatRepository(db)
-	chatService := service.NewChatService(chatRepo, mediaRepo)
-	chatHub := realtime.NewHub()
-	go chatHub.Run()
-	chatHandler := handler.NewChatHandler(chatService, chatHub)
-	chatWebSocketHandler := realtime.NewWebSocketHandler(chatHub, chatService)
-
-	assignmentService := service.NewAssignmentService(assignmentRepo, attachmentService, mediaRepo, notificationService, enrollmentRepo)
+	chatModerationRepo := repository.NewChatModerationRepository(db)
+	logRepo := repository.NewLogRepository(db)
+	chatService := service.NewChatService(chatRepo, mediaRepo, chatModerationRepo, logRepo, notificationService, chatMessageEditWindow())
+	chatHandler := handler.NewChatHandler(chatService, realtimeHub)
+	chatWebSocketHandler := realtime.NewWebSocketHandler(realtimeHub, chatService)
+	go syncAllClassRooms(chatService)
+	chatRetentionRepo := repository.NewChatRetentionRepository(db)
+	chatRetentionService := service.NewChatRetentionService(chatRetentionRepo, chatRepo, mediaService, logRepo)
+	chatRetentionHandler := handler.NewChatRetentionHandler(chatRetentionService)
+	go runEvery(envInterval("CHAT_RETENTION_INTERVAL_MINUTES", 60*time.Minute), func() { purgeExpiredChatMessages(chatRetentionService) })
+	chatScheduleRepo := repository.NewChatScheduleRepository(db)
+	chatScheduleService := service.NewChatScheduleService(chatScheduleRepo, chatService, chatHandler, notificationService)
+	chatScheduleHandler := handler.NewChatScheduleHandler(chatScheduleService)
+	go runEvery(envInterval("CHAT_SCHEDULE_INTERVAL_SECONDS", 30*time.Second), func() { dispatchScheduledChatMessages(chatScheduleService) })
+
+	rubricRepo := repository.NewRubricRepository(db)
+	rubricService := service.NewRubricService(rubricRepo)
+	rubricHandler := handler.NewRubricHandler(rubricService)
+	questionRepo := repository.NewQuestionRepository(db)
+	questionService := service.NewQuestionService(questionRepo, subjectRepo)
+	questionHandler := handler.NewQuestionHandler(questionService)
+	studentGroupRepo := repository.NewStudentGroupRepository(db)
+	studentGroupService := service.NewStudentGroupService(studentGroupRepo, subjectClassService, enrollmentRepo)
+	studentGroupHandler := handler.NewStudentGroupHandler(studentGroupService)
+	assignmentService := service.NewAssignmentService(assignmentRepo, attachmentService, mediaRepo, notificationService, enrollmentRepo, rubricRepo, questionRepo, studentGroupRepo, realtimePublisher)
 	assignmentHandler := handler.NewAssignmentHandler(assignmentService, schoolService, subjectClassService)
+	go runEvery(envInterval("PEER_REVIEW_ALLOCATION_INTERVAL_SECONDS", 60*time.Second), func() { allocateDuePeerReviews(assignmentService) })
+	go runEvery(envInterval("PUBLISH_SCHEDULE_INTERVAL_SECONDS", 60*time.Second), func() { publishDueContent(assignmentService, materialService) })
 
 	gradeHandler := handler.NewGradeHandler(service.NewGradeService(
 		repository.NewAssessmentWeightRepository(db),
@@ -143,7 +170,6 @@ func main() {
 		userRepo,
 	))
 
-	logRepo := repository.NewLogRepository(db)
 	logService := service.NewLogService(logRepo)
 	logHandler := handler.NewLogHandler(logService)
 
@@ -177,10 +203,13 @@ func main() {
 		api.GET("/invitations/:token", invitationHandler.GetMetadata)
 		api.POST("/invitations/:token/accept", invitationHandler.Accept)
 		api.GET("/ws/chat", chatWebSocketHandler.Chat)
+		api.GET("/sse/chat", chatWebSocketHandler.Stream)
 
 		//protected routes
 		api.Use(middleware.AuthRequired())
 
+		api.GET("/realtime/stats", middleware.RequireSystemSuperAdmin(schoolService), chatWebSocketHandler.Stats)
+
 		schoolAPI := api.Group("/schools")
 		{
 			schoolAPI.POST("", middleware.RequireRole(schoolService, "super_admin"), schoolHandler.CreateSchool)
@@ -389,8 +418,35 @@ func main() {
 			chatAPI.POST("/groups/:roomId/members", middleware.RequireSchoolMember(schoolService), chatHandler.AddGroupMembers)
 			chatAPI.DELETE("/groups/:roomId/members/:userId", middleware.RequireSchoolMember(schoolService), chatHandler.RemoveGroupMember)
 			chatAPI.GET("/rooms/:roomId/read-summary", middleware.RequireSchoolMember(schoolService), chatHandler.GetReadSummary)
+			chatAPI.GET("/rooms/:roomId/settings", middleware.RequireSchoolMember(schoolService), chatHandler.GetRoomSettings)
+			chatAPI.PUT("/rooms/:roomId/settings", middleware.RequireSchoolMember(schoolService), chatHandler.UpdateRoomSettings)
+			chatAPI.GET("/rooms/:roomId/online", middleware.RequireSchoolMember(schoolService), chatHandler.ListOnlineMembers)
+			chatAPI.GET("/rooms/:roomId/pins", middleware.RequireSchoolMember(schoolService), chatHandler.ListPinnedMessages)
+			chatAPI.PATCH("/rooms/:roomId/posting-mode", middleware.RequireSchoolMember(schoolService), chatHandler.UpdatePostingMode)
+			chatAPI.GET("/messages/search", middleware.RequireSchoolMember(schoolService), chatHandler.SearchMessages)
+			chatAPI.GET("/scheduled-messages", middleware.RequireSchoolMember(schoolService), chatScheduleHandler.List)
+			chatAPI.PATCH("/scheduled-messages/:scheduledId", middleware.RequireSchoolMember(schoolService), chatScheduleHandler.Update)
+			chatAPI.DELETE("/scheduled-messages/:scheduledId", middleware.RequireSchoolMember(schoolService), chatScheduleHandler.Cancel)
+			chatAPI.GET("/moderation/retention", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "admin"), chatRetentionHandler.GetPolicy)
+			chatAPI.PUT("/moderation/retention", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "admin"), chatRetentionHandler.UpdatePolicy)
+			chatAPI.GET("/moderation/rooms/:roomId/transcript", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "admin"), chatRetentionHandler.ExportTranscript)
+			chatAPI.GET("/moderation/reports", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "admin"), chatHandler.ListReports)
+			chatAPI.PATCH("/moderation/reports/:reportId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "admin"), chatHandler.ResolveReport)
+			chatAPI.GET("/moderation/banned-words", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "admin"), chatHandler.GetBannedWords)
+			chatAPI.PUT("/moderation/banned-words", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "admin"), chatHandler.UpdateBannedWords)
+			chatAPI.POST("/rooms/:roomId/mutes", middleware.RequireSchoolMember(schoolService), chatHandler.MuteMember)
+			chatAPI.DELETE("/rooms/:roomId/mutes/:userId", middleware.RequireSchoolMember(schoolService), chatHandler.UnmuteMember)
 			chatAPI.GET("/rooms/:roomId/messages", middleware.RequireSchoolMember(schoolService), chatHandler.ListMessages)
 			chatAPI.POST("/rooms/:roomId/messages", middleware.RequireSchoolMember(schoolService), chatHandler.CreateMessage)
+			chatAPI.POST("/rooms/:roomId/scheduled-messages", middleware.RequireSchoolMember(schoolService), chatScheduleHandler.Schedule)
+			chatAPI.PATCH("/rooms/:roomId/messages/:messageId", middleware.RequireSchoolMember(schoolService), chatHandler.EditMessage)
+			chatAPI.DELETE("/rooms/:roomId/messages/:messageId", middleware.RequireSchoolMember(schoolService), chatHandler.DeleteMessage)
+			chatAPI.GET("/rooms/:roomId/messages/:messageId/thread", middleware.RequireSchoolMember(schoolService), chatHandler.GetThread)
+			chatAPI.POST("/rooms/:roomId/messages/:messageId/reactions", middleware.RequireSchoolMember(schoolService), chatHandler.AddReaction)
+			chatAPI.POST("/rooms/:roomId/messages/:messageId/reports", middleware.RequireSchoolMember(schoolService), chatHandler.ReportMessage)
+			chatAPI.POST("/rooms/:roomId/messages/:messageId/pin", middleware.RequireSchoolMember(schoolService), chatHandler.PinMessage)
+			chatAPI.DELETE("/rooms/:roomId/messages/:messageId/pin", middleware.RequireSchoolMember(schoolService), chatHandler.UnpinMessage)
+			chatAPI.DELETE("/rooms/:roomId/messages/:messageId/reactions/:emoji", middleware.RequireSchoolMember(schoolService), chatHandler.RemoveReaction)
 			chatAPI.PATCH("/rooms/:roomId/read", middleware.RequireSchoolMember(schoolService), chatHandler.MarkRead)
 		}
 
@@ -419,11 +475,86 @@ func main() {
 			assignmentAPI.GET("/submit/:submissionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.GetSubmissionByID)
 			assignmentAPI.PATCH("/submit/:submissionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.UpdateSubmission)
 			assignmentAPI.DELETE("/submit/:submissionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.DeleteSubmission)
+			assignmentAPI.GET("/submit/versions/:submissionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.GetSubmissionVersions)
+			assignmentAPI.PATCH("/submit/late-penalty/:submissionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.WaiveLatePenalty)
+			assignmentAPI.GET("/late-policy/categories/:categoryId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.GetCategoryLatePolicy)
+			assignmentAPI.PUT("/late-policy/categories/:categoryId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "admin"), assignmentHandler.SaveCategoryLatePolicy)
+			assignmentAPI.DELETE("/late-policy/categories/:categoryId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "admin"), assignmentHandler.DeleteCategoryLatePolicy)
+			assignmentAPI.GET("/late-policy/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.GetLatePolicy)
+			assignmentAPI.PUT("/late-policy/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.SaveLatePolicy)
+			assignmentAPI.DELETE("/late-policy/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.DeleteLatePolicy)
+
+			// Extensions
+			assignmentAPI.POST("/extensions/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.RequestExtension)
+			assignmentAPI.GET("/extensions/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.GetExtensionsByAssignment)
+			assignmentAPI.GET("/my-extension/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.GetMyExtension)
+			assignmentAPI.PATCH("/extensions/review/:extensionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.ReviewExtension)
+
+			// Quizzes
+			assignmentAPI.GET("/quiz/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.GetQuiz)
+			assignmentAPI.PUT("/quiz/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.ReplaceQuiz)
+			assignmentAPI.GET("/quiz/attempts/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.GetQuizAttempts)
+			assignmentAPI.GET("/quiz/attempts/detail/:attemptId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.GetQuizAttemptDetail)
+			assignmentAPI.PATCH("/quiz/attempts/grade/:attemptId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.GradeQuizAttempt)
+			assignmentAPI.POST("/quiz/start/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.StartQuiz)
+			assignmentAPI.GET("/quiz/my-attempt/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.GetMyQuizAttempt)
+			assignmentAPI.PUT("/quiz/answers/:attemptId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.SaveQuizAnswers)
+			assignmentAPI.POST("/quiz/submit/:attemptId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.SubmitQuizAttempt)
+
+			// Peer reviews
+			assignmentAPI.GET("/peer-review/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.GetPeerReview)
+			assignmentAPI.PUT("/peer-review/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.SavePeerReview)
+			assignmentAPI.DELETE("/peer-review/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.DeletePeerReview)
+			assignmentAPI.POST("/peer-review/allocate/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.AllocatePeerReviews)
+			assignmentAPI.GET("/peer-review/reviews/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.GetPeerReviews)
+			assignmentAPI.GET("/peer-review/my-reviews/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.GetMyPeerReviews)
+			assignmentAPI.GET("/peer-review/received/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.GetReceivedPeerReviews)
+			assignmentAPI.GET("/peer-review/review/:reviewId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.GetPeerReviewDetail)
+			assignmentAPI.PUT("/peer-review/review/:reviewId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "student"), assignmentHandler.SubmitPeerReview)
+
+			// Anonymous grading
+			assignmentAPI.POST("/anonymous-grading/release/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"), assignmentHandler.ReleaseIdentities)
 
 			// Assessments
 			assignmentAPI.POST("/assess/:submissionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.Assess)
 			assignmentAPI.PATCH("/assess/:submissionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.UpdateAssessment)
 			assignmentAPI.DELETE("/assess/:submissionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.DeleteAssessment)
+			assignmentAPI.PUT("/assess/adjustments/:submissionId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.UpdateGroupScoreAdjustments)
+			assignmentAPI.GET("/assess/export/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.ExportGrades)
+			assignmentAPI.POST("/assess/import/preview/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.PreviewGradeImport)
+			assignmentAPI.POST("/assess/import/:assignmentId", middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher"), assignmentHandler.CommitGradeImport)
+		}
+
+		studentGroupAPI := api.Group("/student-groups")
+		studentGroupAPI.Use(middleware.RequireSchoolMember(schoolService))
+		{
+			studentGroupAPI.POST("", middleware.RequireRole(schoolService, "teacher", "admin"), studentGroupHandler.Create)
+			studentGroupAPI.GET("/subject-class/:subjectClassId", middleware.RequireRole(schoolService, "admin", "teacher", "student"), studentGroupHandler.ListBySubjectClass)
+			studentGroupAPI.PATCH("/:id", middleware.RequireRole(schoolService, "teacher", "admin"), studentGroupHandler.Update)
+			studentGroupAPI.DELETE("/:id", middleware.RequireRole(schoolService, "teacher", "admin"), studentGroupHandler.Delete)
+			studentGroupAPI.PUT("/members/:id", middleware.RequireRole(schoolService, "teacher", "admin"), studentGroupHandler.SetMembers)
+			studentGroupAPI.POST("/join/:id", middleware.RequireRole(schoolService, "student"), studentGroupHandler.Join)
+			studentGroupAPI.POST("/leave/:id", middleware.RequireRole(schoolService, "student"), studentGroupHandler.Leave)
+		}
+
+		rubricAPI := api.Group("/rubrics")
+		rubricAPI.Use(middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"))
+		{
+			rubricAPI.POST("", rubricHandler.Create)
+			rubricAPI.GET("", rubricHandler.List)
+			rubricAPI.GET("/:id", rubricHandler.Get)
+			rubricAPI.PUT("/:id", rubricHandler.Replace)
+			rubricAPI.DELETE("/:id", rubricHandler.Delete)
+		}
+
+		questionAPI := api.Group("/questions")
+		questionAPI.Use(middleware.RequireSchoolMember(schoolService), middleware.RequireRole(schoolService, "teacher", "admin"))
+		{
+			questionAPI.POST("", questionHandler.Create)
+			questionAPI.GET("", questionHandler.List)
+			questionAPI.GET("/:id", questionHandler.Get)
+			questionAPI.PUT("/:id", questionHandler.Replace)
+			questionAPI.DELETE("/:id", questionHandler.Delete)
 		}
 
 		gradeAPI := api.Group("/grades")
@@ -544,6 +675,109 @@ func serverPort() string {
 	return port
 }
 
+// chatMessageEditWindow reads CHAT_MESSAGE_EDIT_WINDOW_MINUTES. Unset or invalid
+// values fall back to 15 minutes; 0 removes the limit.
+func chatMessageEditWindow() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("CHAT_MESSAGE_EDIT_WINDOW_MINUTES"))
+	minutes, err := strconv.Atoi(raw)
+	if raw == "" || err != nil || minutes < 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// envInterval reads a job interval from the environment variable name, in
+// minutes when name ends in _MINUTES and in seconds otherwise. Unset or
+// invalid values fall back to fallback; 0 disables the job.
+func envInterval(name string, fallback time.Duration) time.Duration {
+	unit := time.Second
+	if strings.HasSuffix(name, "_MINUTES") {
+		unit = time.Minute
+	}
+	raw := strings.TrimSpace(os.Getenv(name))
+	value, err := strconv.Atoi(raw)
+	if raw == "" || err != nil || value < 0 {
+		return fallback
+	}
+	return time.Duration(value) * unit
+}
+
+// runEvery calls fn once at startup and then on every interval for the
+// lifetime of the process. A zero interval disables the job.
+func runEvery(interval time.Duration, fn func()) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fn()
+		<-ticker.C
+	}
+}
+
+// syncAllClassRooms backfills class and subject class chat rooms once at
+// startup.
+func syncAllClassRooms(chatService service.ChatService) {
+	synced, err := chatService.SyncAllClassRooms()
+	if err != nil {
+		fmt.Printf("[Chat Rooms] class room sync failed (%d classes synced): %s\n", synced, err.Error())
+		return
+	}
+	fmt.Printf("[Chat Rooms] synced rooms for %d classes\n", synced)
+}
+
+// purgeExpiredChatMessages deletes chat messages past their room's retention.
+func purgeExpiredChatMessages(retentionService service.ChatRetentionService) {
+	purged, err := retentionService.PurgeExpired(context.Background(), time.Now())
+	if err != nil {
+		fmt.Printf("[Chat Retention] purge failed: %s\n", err.Error())
+	}
+	if purged > 0 {
+		fmt.Printf("[Chat Retention] purged %d expired messages\n", purged)
+	}
+}
+
+// dispatchScheduledChatMessages sends due scheduled chat messages.
+func dispatchScheduledChatMessages(scheduleService service.ChatScheduleService) {
+	sent, err := scheduleService.DispatchDue(time.Now())
+	if err != nil {
+		fmt.Printf("[Chat Schedule] dispatch failed: %s\n", err.Error())
+	}
+	if sent > 0 {
+		fmt.Printf("[Chat Schedule] sent %d scheduled messages\n", sent)
+	}
+}
+
+// allocateDuePeerReviews assigns peer reviewers to assignments past their
+// deadline.
+func allocateDuePeerReviews(assignmentService service.AssignmentService) {
+	allocated, err := assignmentService.AllocateDuePeerReviews(time.Now())
+	if err != nil {
+		fmt.Printf("[Peer Review] allocation failed: %s\n", err.Error())
+	}
+	if allocated > 0 {
+		fmt.Printf("[Peer Review] allocated reviewers for %d assignments\n", allocated)
+	}
+}
+
+// publishDueContent publishes scheduled assignments and materials whose
+// publish time has passed.
+func publishDueContent(assignmentService service.AssignmentService, materialService service.MaterialService) {
+	now := time.Now()
+	assignments, err := assignmentService.PublishDueAssignments(now)
+	if err != nil {
+		fmt.Printf("[Publish] assignment publishing failed: %s\n", err.Error())
+	}
+	materials, err := materialService.PublishDue(now)
+	if err != nil {
+		fmt.Printf("[Publish] material publishing failed: %s\n", err.Error())
+	}
+	if assignments > 0 || materials > 0 {
+		fmt.Printf("[Publish] published %d assignments and %d materials\n", assignments, materials)
+	}
+}
+
 func buildStorageProvider() (storage.Provider, error) {
 	provider := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_PROVIDER")))
 	if provider == "" || provider == "disabled" {
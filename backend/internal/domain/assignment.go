@@ -29,6 +29,20 @@ type Assignment struct {
 	Description         string             `gorm:"column:asg_desc" json:"assignmentDescription"`
 	Deadline            *time.Time         `gorm:"column:asg_deadline" json:"deadline"`
 	AllowLateSubmission bool               `gorm:"column:asg_allowed_late;default:true" json:"allowLateSubmission"`
+	RubricID            *string            `gorm:"column:asg_rbr_id;type:uuid" json:"rubricId,omitempty"`
+	Type                string             `gorm:"column:asg_type;default:file" json:"type"`
+	TimeLimitMinutes    *int               `gorm:"column:asg_time_limit_minutes" json:"timeLimitMinutes,omitempty"`
+	ShuffleQuestions    bool               `gorm:"column:asg_shuffle_questions" json:"shuffleQuestions"`
+	ShuffleOptions      bool               `gorm:"column:asg_shuffle_options" json:"shuffleOptions"`
+	MaxAttempts         *int               `gorm:"column:asg_max_attempts" json:"maxAttempts,omitempty"`
+	GradingPolicy       string             `gorm:"column:asg_grading_policy;default:latest" json:"gradingPolicy"`
+	GroupMode           bool               `gorm:"column:asg_group_mode" json:"groupMode"`
+	PublishStatus       string             `gorm:"column:asg_publish_status;default:published" json:"publishStatus"`
+	PublishAt           *time.Time         `gorm:"column:asg_publish_at" json:"publishAt,omitempty"`
+	PublishedAt         *time.Time         `gorm:"column:asg_published_at" json:"publishedAt,omitempty"`
+	AnonymousGrading    bool               `gorm:"column:asg_anonymous_grading" json:"anonymousGrading"`
+	AnonymousKey        string             `gorm:"column:asg_anonymous_key" json:"-"`
+	IdentityReleasedAt  *time.Time         `gorm:"column:asg_identities_released_at" json:"identitiesReleasedAt,omitempty"`
 	CreatedBy           string             `gorm:"column:created_by;type:uuid" json:"createdBy"`
 	Creator             User               `gorm:"foreignKey:CreatedBy;references:ID" json:"creator,omitempty"`
 	CreatedAt           time.Time          `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
@@ -43,17 +57,24 @@ func (Assignment) TableName() string {
 }
 
 type Submission struct {
-	ID           string         `gorm:"primaryKey;column:sbm_id;default:gen_random_uuid()" json:"submissionId"`
-	SchoolID     string         `gorm:"column:sbm_sch_id;type:uuid" json:"schoolId"`
-	AssignmentID string         `gorm:"column:sbm_asg_id;type:uuid" json:"assignmentId"`
-	Assignment   Assignment     `gorm:"foreignKey:AssignmentID;references:ID" json:"assignment,omitempty"`
-	UserID       string         `gorm:"column:sbm_usr_id;type:uuid" json:"userId"`
-	User         User           `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
-	SubmittedAt  time.Time      `gorm:"column:submitted_at;autoCreateTime" json:"submittedAt"`
-	IsLate       bool           `gorm:"-" json:"isLate"`
-	DeletedAt    gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"`
-	Attachments  []Attachment   `gorm:"-" json:"attachments,omitempty"`
-	Assessment   *Assessment    `gorm:"foreignKey:SubmissionID" json:"assessment,omitempty"`
+	ID              string                  `gorm:"primaryKey;column:sbm_id;default:gen_random_uuid()" json:"submissionId"`
+	SchoolID        string                  `gorm:"column:sbm_sch_id;type:uuid" json:"schoolId"`
+	AssignmentID    string                  `gorm:"column:sbm_asg_id;type:uuid" json:"assignmentId"`
+	Assignment      Assignment              `gorm:"foreignKey:AssignmentID;references:ID" json:"assignment,omitempty"`
+	UserID          string                  `gorm:"column:sbm_usr_id;type:uuid" json:"userId"`
+	User            User                    `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	GroupID         *string                 `gorm:"column:sbm_grp_id;type:uuid" json:"groupId,omitempty"`
+	Group           *StudentGroup           `gorm:"foreignKey:GroupID;references:ID" json:"group,omitempty"`
+	SubmittedAt     time.Time               `gorm:"column:submitted_at;autoCreateTime" json:"submittedAt"`
+	IsLate          bool                    `gorm:"-" json:"isLate"`
+	AttemptCount    int                     `gorm:"-" json:"attemptCount"`
+	PenaltyWaived   bool                    `gorm:"column:sbm_penalty_waived" json:"penaltyWaived"`
+	PenaltyWaivedBy *string                 `gorm:"column:sbm_penalty_waived_by;type:uuid" json:"penaltyWaivedBy,omitempty"`
+	PenaltyWaivedAt *time.Time              `gorm:"column:sbm_penalty_waived_at" json:"penaltyWaivedAt,omitempty"`
+	DeletedAt       gorm.DeletedAt          `gorm:"column:deleted_at;index" json:"-"`
+	Attachments     []Attachment            `gorm:"-" json:"attachments,omitempty"`
+	Assessment      *Assessment             `gorm:"foreignKey:SubmissionID" json:"assessment,omitempty"`
+	Members         []GroupSubmissionMember `gorm:"foreignKey:SubmissionID" json:"members,omitempty"`
 }
 
 func (Submission) TableName() string {
@@ -61,14 +82,16 @@ func (Submission) TableName() string {
 }
 
 type Assessment struct {
-	ID           string     `gorm:"primaryKey;column:asm_id;default:gen_random_uuid()" json:"assessmentId"`
-	SubmissionID string     `gorm:"column:asm_sbm_id;type:uuid" json:"submissionId"`
-	Submission   Submission `gorm:"foreignKey:SubmissionID;references:ID" json:"submission,omitempty"` // TAMBAH INI
-	Score        float64    `gorm:"column:asm_score" json:"score"`
-	Feedback     string     `gorm:"column:asm_feedback" json:"feedback"`
-	AssessedBy   string     `gorm:"column:assessed_by;type:uuid" json:"assessedBy"`
-	Assessor     User       `gorm:"foreignKey:AssessedBy;references:ID" json:"assessor,omitempty"`
-	AssessedAt   time.Time  `gorm:"column:assessed_at;autoCreateTime" json:"assessedAt"`
+	ID           string                  `gorm:"primaryKey;column:asm_id;default:gen_random_uuid()" json:"assessmentId"`
+	SubmissionID string                  `gorm:"column:asm_sbm_id;type:uuid" json:"submissionId"`
+	Submission   Submission              `gorm:"foreignKey:SubmissionID;references:ID" json:"submission,omitempty"` // TAMBAH INI
+	Score        float64                 `gorm:"column:asm_score" json:"score"`
+	Feedback     string                  `gorm:"column:asm_feedback" json:"feedback"`
+	AssessedBy   string                  `gorm:"column:assessed_by;type:uuid" json:"assessedBy"`
+	Assessor     User                    `gorm:"foreignKey:AssessedBy;references:ID" json:"assessor,omitempty"`
+	AssessedAt   time.Time               `gorm:"column:assessed_at;autoCreateTime" json:"assessedAt"`
+	VersionID    *string                 `gorm:"column:asm_sbv_id;type:uuid" json:"versionId,omitempty"`
+	RubricScores []AssessmentRubricScore `gorm:"foreignKey:AssessmentID" json:"rubricScores,omitempty"`
 }
 
 func (Assessment) TableName() string {
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+const (
+	AssignmentExtensionPending  = "pending"
+	AssignmentExtensionApproved = "approved"
+	AssignmentExtensionRejected = "rejected"
+)
+
+// AssignmentExtension is a student's request for more time on an assignment.
+// ExtendedDeadline is set by the reviewing teacher when the request is
+// approved and replaces the assignment deadline for that student only.
+type AssignmentExtension struct {
+	ID               string     `gorm:"primaryKey;column:asx_id;default:gen_random_uuid()" json:"extensionId"`
+	SchoolID         string     `gorm:"column:asx_sch_id;type:uuid" json:"schoolId"`
+	AssignmentID     string     `gorm:"column:asx_asg_id;type:uuid" json:"assignmentId"`
+	UserID           string     `gorm:"column:asx_usr_id;type:uuid" json:"userId"`
+	User             User       `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	Reason           string     `gorm:"column:asx_reason" json:"reason"`
+	Status           string     `gorm:"column:asx_status;default:pending" json:"status"`
+	ExtendedDeadline *time.Time `gorm:"column:asx_deadline" json:"extendedDeadline,omitempty"`
+	ReviewNote       *string    `gorm:"column:asx_review_note" json:"reviewNote,omitempty"`
+	ReviewedBy       *string    `gorm:"column:asx_reviewed_by;type:uuid" json:"reviewedBy,omitempty"`
+	Reviewer         *User      `gorm:"foreignKey:ReviewedBy;references:ID" json:"reviewer,omitempty"`
+	ReviewedAt       *time.Time `gorm:"column:asx_reviewed_at" json:"reviewedAt,omitempty"`
+	CreatedAt        time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (AssignmentExtension) TableName() string {
+	return "edv.assignment_extensions"
+}
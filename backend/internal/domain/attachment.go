@@ -12,6 +12,8 @@ const (
 	SourceFeed       SourceType = "feed"
 	SourceSubmission SourceType = "submission"
 	SourceComment    SourceType = "comment"
+	// SourceSubmissionVersion keeps the attachment set of one turn-in.
+	SourceSubmissionVersion SourceType = "submission_version"
 )
 
 type Attachment struct {
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// ChatBannedWord is a word or phrase a school blocks in chat messages.
+type ChatBannedWord struct {
+	ID        string    `gorm:"primaryKey;column:cbw_id;default:gen_random_uuid()" json:"bannedWordId"`
+	SchoolID  string    `gorm:"column:cbw_sch_id;type:uuid" json:"schoolId"`
+	Word      string    `gorm:"column:cbw_word" json:"word"`
+	CreatedBy string    `gorm:"column:created_by;type:uuid" json:"createdBy"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (ChatBannedWord) TableName() string {
+	return "edv.chat_banned_words"
+}
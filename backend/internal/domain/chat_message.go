@@ -17,7 +17,9 @@ type ChatMessage struct {
 	RefType   *string        `gorm:"column:msg_ref_type" json:"refType,omitempty"`
 	RefID     *string        `gorm:"column:msg_ref_id;type:uuid" json:"refId,omitempty"`
 	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	EditedAt  *time.Time     `gorm:"column:edited_at" json:"editedAt,omitempty"`
 	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"`
+	DeletedBy *string        `gorm:"column:deleted_by;type:uuid" json:"-"`
 }
 
 func (ChatMessage) TableName() string {
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// ChatMessageEdit keeps the content a message had before each edit so
+// moderators can review what was originally sent.
+type ChatMessageEdit struct {
+	ID              string    `gorm:"primaryKey;column:cme_id;default:gen_random_uuid()" json:"editId"`
+	MessageID       string    `gorm:"column:cme_msg_id;type:uuid" json:"messageId"`
+	EditedBy        string    `gorm:"column:cme_edited_by;type:uuid" json:"editedBy"`
+	PreviousContent string    `gorm:"column:cme_previous_content" json:"previousContent"`
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (ChatMessageEdit) TableName() string {
+	return "edv.chat_message_edits"
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+type ChatMessageMention struct {
+	ID        string    `gorm:"primaryKey;column:cmn_id;default:gen_random_uuid()" json:"mentionId"`
+	MessageID string    `gorm:"column:cmn_msg_id;type:uuid" json:"messageId"`
+	UserID    string    `gorm:"column:cmn_usr_id;type:uuid" json:"userId"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (ChatMessageMention) TableName() string {
+	return "edv.chat_message_mentions"
+}
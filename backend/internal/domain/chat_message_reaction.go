@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+type ChatMessageReaction struct {
+	ID        string    `gorm:"primaryKey;column:cmr_id;default:gen_random_uuid()" json:"reactionId"`
+	MessageID string    `gorm:"column:cmr_msg_id;type:uuid" json:"messageId"`
+	UserID    string    `gorm:"column:cmr_usr_id;type:uuid" json:"userId"`
+	Emoji     string    `gorm:"column:cmr_emoji" json:"emoji"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (ChatMessageReaction) TableName() string {
+	return "edv.chat_message_reactions"
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// ChatMessageReport is a member's report of a chat message, reviewed by school
+// admins in the moderation queue.
+type ChatMessageReport struct {
+	ID             string     `gorm:"primaryKey;column:crp_id;default:gen_random_uuid()" json:"reportId"`
+	SchoolID       string     `gorm:"column:crp_sch_id;type:uuid" json:"schoolId"`
+	RoomID         string     `gorm:"column:crp_room_id;type:uuid" json:"roomId"`
+	MessageID      string     `gorm:"column:crp_msg_id;type:uuid" json:"messageId"`
+	ReporterID     string     `gorm:"column:crp_reporter_id;type:uuid" json:"reporterId"`
+	Reason         string     `gorm:"column:crp_reason" json:"reason"`
+	Status         string     `gorm:"column:crp_status" json:"status"`
+	ResolvedBy     *string    `gorm:"column:crp_resolved_by;type:uuid" json:"resolvedBy,omitempty"`
+	ResolvedAt     *time.Time `gorm:"column:crp_resolved_at" json:"resolvedAt,omitempty"`
+	ResolutionNote *string    `gorm:"column:crp_resolution_note" json:"resolutionNote,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (ChatMessageReport) TableName() string {
+	return "edv.chat_message_reports"
+}
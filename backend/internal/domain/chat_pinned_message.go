@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+type ChatPinnedMessage struct {
+	ID        string    `gorm:"primaryKey;column:cpm_id;default:gen_random_uuid()" json:"pinId"`
+	RoomID    string    `gorm:"column:cpm_room_id;type:uuid" json:"roomId"`
+	MessageID string    `gorm:"column:cpm_msg_id;type:uuid" json:"messageId"`
+	PinnedBy  string    `gorm:"column:cpm_pinned_by;type:uuid" json:"pinnedBy"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"pinnedAt"`
+}
+
+func (ChatPinnedMessage) TableName() string {
+	return "edv.chat_pinned_messages"
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// ChatRetentionPolicy controls how long a school keeps chat messages. Messages
+// older than RetentionDays are purged by the retention job; schools without a
+// policy keep chat history indefinitely.
+type ChatRetentionPolicy struct {
+	ID                 string     `gorm:"primaryKey;column:crr_id;default:gen_random_uuid()" json:"policyId"`
+	SchoolID           string     `gorm:"column:crr_sch_id;type:uuid" json:"schoolId"`
+	RetentionDays      *int       `gorm:"column:crr_retention_days" json:"retentionDays"`
+	UpdatedBy          string     `gorm:"column:crr_updated_by;type:uuid" json:"updatedBy"`
+	LastPurgedAt       *time.Time `gorm:"column:crr_last_purged_at" json:"lastPurgedAt,omitempty"`
+	LastPurgedMessages int64      `gorm:"column:crr_last_purged_messages" json:"lastPurgedMessages"`
+	UpdatedAt          time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (ChatRetentionPolicy) TableName() string {
+	return "edv.chat_retention_policies"
+}
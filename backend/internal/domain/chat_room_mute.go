@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ChatRoomMute stops a user from sending messages in a room until MutedUntil.
+type ChatRoomMute struct {
+	ID         string    `gorm:"primaryKey;column:cmu_id;default:gen_random_uuid()" json:"muteId"`
+	RoomID     string    `gorm:"column:cmu_room_id;type:uuid" json:"roomId"`
+	UserID     string    `gorm:"column:cmu_usr_id;type:uuid" json:"userId"`
+	MutedBy    string    `gorm:"column:cmu_muted_by;type:uuid" json:"mutedBy"`
+	Reason     *string   `gorm:"column:cmu_reason" json:"reason,omitempty"`
+	MutedUntil time.Time `gorm:"column:cmu_muted_until" json:"mutedUntil"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (ChatRoomMute) TableName() string {
+	return "edv.chat_room_mutes"
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// ChatRoomSetting holds a member's own notification preferences for a room.
+// Rooms without a row use the default level for their room type.
+type ChatRoomSetting struct {
+	ID          string     `gorm:"primaryKey;column:crs_id;default:gen_random_uuid()" json:"settingId"`
+	RoomID      string     `gorm:"column:crs_room_id;type:uuid" json:"roomId"`
+	UserID      string     `gorm:"column:crs_usr_id;type:uuid" json:"userId"`
+	NotifyLevel string     `gorm:"column:crs_notify_level" json:"notifyLevel"`
+	MutedUntil  *time.Time `gorm:"column:crs_muted_until" json:"mutedUntil,omitempty"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (ChatRoomSetting) TableName() string {
+	return "edv.chat_room_settings"
+}
+
+const (
+	ChatNotifyAll      = "all"
+	ChatNotifyMentions = "mentions"
+	ChatNotifyNone     = "none"
+)
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+const (
+	ChatScheduledPending   = "pending"
+	ChatScheduledSending   = "sending"
+	ChatScheduledSent      = "sent"
+	ChatScheduledCancelled = "cancelled"
+	ChatScheduledFailed    = "failed"
+)
+
+// ChatScheduledMessage is a message queued by a user to be posted to a room at
+// SendAt. MediaIDs is a JSON array of upload-first media IDs. MessageID is the
+// posted message once the dispatcher has sent it.
+type ChatScheduledMessage struct {
+	ID        string     `gorm:"primaryKey;column:csm_id;default:gen_random_uuid()" json:"scheduledId"`
+	RoomID    string     `gorm:"column:csm_room_id;type:uuid" json:"roomId"`
+	SchoolID  string     `gorm:"column:csm_sch_id;type:uuid" json:"schoolId"`
+	UserID    string     `gorm:"column:csm_usr_id;type:uuid" json:"userId"`
+	Content   string     `gorm:"column:csm_content" json:"content"`
+	MediaIDs  string     `gorm:"column:csm_media_ids;type:jsonb" json:"mediaIds"`
+	ReplyTo   *string    `gorm:"column:csm_reply_to;type:uuid" json:"replyTo,omitempty"`
+	RefType   *string    `gorm:"column:csm_ref_type" json:"refType,omitempty"`
+	RefID     *string    `gorm:"column:csm_ref_id;type:uuid" json:"refId,omitempty"`
+	SendAt    time.Time  `gorm:"column:csm_send_at" json:"sendAt"`
+	Status    string     `gorm:"column:csm_status;default:pending" json:"status"`
+	MessageID *string    `gorm:"column:csm_msg_id;type:uuid" json:"messageId,omitempty"`
+	Error     *string    `gorm:"column:csm_error" json:"error,omitempty"`
+	SentAt    *time.Time `gorm:"column:csm_sent_at" json:"sentAt,omitempty"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (ChatScheduledMessage) TableName() string {
+	return "edv.chat_scheduled_messages"
+}
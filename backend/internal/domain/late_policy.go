@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// LatePolicy deducts a percentage of the score of late work for every
+// started day past the student's deadline once the grace period is over.
+// A policy belongs to either an assignment or an assignment category; the
+// assignment policy wins over the policy of its category.
+type LatePolicy struct {
+	ID            string    `gorm:"primaryKey;column:lpl_id;default:gen_random_uuid()" json:"policyId"`
+	SchoolID      string    `gorm:"column:lpl_sch_id;type:uuid" json:"schoolId"`
+	AssignmentID  *string   `gorm:"column:lpl_asg_id;type:uuid" json:"assignmentId,omitempty"`
+	CategoryID    *string   `gorm:"column:lpl_asc_id;type:uuid" json:"categoryId,omitempty"`
+	PercentPerDay float64   `gorm:"column:lpl_percent_per_day" json:"percentPerDay"`
+	MaxPercent    *float64  `gorm:"column:lpl_max_percent" json:"maxPercent,omitempty"`
+	GraceMinutes  int       `gorm:"column:lpl_grace_minutes" json:"graceMinutes"`
+	ZeroAfterDays *int      `gorm:"column:lpl_zero_after_days" json:"zeroAfterDays,omitempty"`
+	UpdatedBy     string    `gorm:"column:lpl_updated_by;type:uuid" json:"updatedBy"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (LatePolicy) TableName() string {
+	return "edv.late_policies"
+}
@@ -24,6 +24,9 @@ type Material struct {
 	Title          string         `gorm:"column:mat_title" json:"materialTitle"`
 	Description    string         `gorm:"column:mat_desc" json:"materialDescription"`
 	Type           MaterialType   `gorm:"column:mat_types;type:material_type" json:"materialType"`
+	PublishStatus  string         `gorm:"column:mat_publish_status;default:published" json:"publishStatus"`
+	PublishAt      *time.Time     `gorm:"column:mat_publish_at" json:"publishAt,omitempty"`
+	PublishedAt    *time.Time     `gorm:"column:mat_published_at" json:"publishedAt,omitempty"`
 	CreatedBy      string         `gorm:"column:created_by;type:uuid" json:"createdBy"`
 	Creator        User           `gorm:"foreignKey:CreatedBy;references:ID" json:"creator,omitempty"`
 	CreatedAt      time.Time      `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
@@ -23,9 +23,14 @@ func (Notification) TableName() string {
 }
 
 const (
-	NotifAssignmentCreated = "assignment_created"
-	NotifAssignmentGraded  = "assignment_graded"
-	NotifCommentAdded      = "comment_added"
-	NotifMaterialAdded     = "material_added"
-	NotifFeedPosted        = "feed_posted"
+	NotifAssignmentCreated  = "assignment_created"
+	NotifAssignmentGraded   = "assignment_graded"
+	NotifExtensionRequested = "assignment_extension_requested"
+	NotifExtensionReviewed  = "assignment_extension_reviewed"
+	NotifCommentAdded       = "comment_added"
+	NotifMaterialAdded      = "material_added"
+	NotifFeedPosted         = "feed_posted"
+	NotifChatMention        = "chat_mention"
+	NotifChatScheduleFailed = "chat_schedule_failed"
+	NotifPeerReviewAssigned = "peer_review_assigned"
 )
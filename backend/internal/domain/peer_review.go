@@ -0,0 +1,84 @@
+package domain
+
+import "time"
+
+const (
+	PeerReviewQuestionRating = "rating"
+	PeerReviewQuestionText   = "text"
+)
+
+const (
+	PeerReviewAssigned  = "assigned"
+	PeerReviewSubmitted = "submitted"
+)
+
+// PeerReviewSetting turns on the peer-review phase of an assignment. After
+// the deadline every submission gets ReviewersPerSubmission anonymous
+// reviewers among the other students who submitted. CompletionWeight is the
+// percentage of the assignment grade earned by completing the assigned
+// reviews.
+type PeerReviewSetting struct {
+	ID                     string               `gorm:"primaryKey;column:prs_id;default:gen_random_uuid()" json:"settingId"`
+	SchoolID               string               `gorm:"column:prs_sch_id;type:uuid" json:"schoolId"`
+	AssignmentID           string               `gorm:"column:prs_asg_id;type:uuid" json:"assignmentId"`
+	ReviewersPerSubmission int                  `gorm:"column:prs_reviewers_per_submission" json:"reviewersPerSubmission"`
+	ReviewDeadline         time.Time            `gorm:"column:prs_review_deadline" json:"reviewDeadline"`
+	CompletionWeight       float64              `gorm:"column:prs_completion_weight" json:"completionWeight"`
+	AllocatedAt            *time.Time           `gorm:"column:prs_allocated_at" json:"allocatedAt,omitempty"`
+	UpdatedBy              string               `gorm:"column:prs_updated_by;type:uuid" json:"updatedBy"`
+	CreatedAt              time.Time            `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt              time.Time            `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	Questions              []PeerReviewQuestion `gorm:"foreignKey:SettingID" json:"questions,omitempty"`
+}
+
+func (PeerReviewSetting) TableName() string {
+	return "edv.peer_review_settings"
+}
+
+// PeerReviewQuestion is one item of the review form: a rating from 1 to
+// MaxRating or a written comment.
+type PeerReviewQuestion struct {
+	ID        string `gorm:"primaryKey;column:prq_id;default:gen_random_uuid()" json:"questionId"`
+	SettingID string `gorm:"column:prq_prs_id;type:uuid" json:"settingId"`
+	Position  int    `gorm:"column:prq_position" json:"position"`
+	Prompt    string `gorm:"column:prq_prompt" json:"prompt"`
+	Type      string `gorm:"column:prq_type" json:"type"`
+	MaxRating *int   `gorm:"column:prq_max_rating" json:"maxRating,omitempty"`
+	Required  bool   `gorm:"column:prq_required" json:"required"`
+}
+
+func (PeerReviewQuestion) TableName() string {
+	return "edv.peer_review_questions"
+}
+
+// PeerReview assigns a reviewer to a submission of another student.
+type PeerReview struct {
+	ID           string             `gorm:"primaryKey;column:prv_id;default:gen_random_uuid()" json:"reviewId"`
+	SchoolID     string             `gorm:"column:prv_sch_id;type:uuid" json:"schoolId"`
+	AssignmentID string             `gorm:"column:prv_asg_id;type:uuid" json:"assignmentId"`
+	SubmissionID string             `gorm:"column:prv_sbm_id;type:uuid" json:"submissionId"`
+	Submission   Submission         `gorm:"foreignKey:SubmissionID;references:ID" json:"submission,omitempty"`
+	ReviewerID   string             `gorm:"column:prv_reviewer_id;type:uuid" json:"reviewerId"`
+	Reviewer     User               `gorm:"foreignKey:ReviewerID;references:ID" json:"reviewer,omitempty"`
+	Status       string             `gorm:"column:prv_status;default:assigned" json:"status"`
+	SubmittedAt  *time.Time         `gorm:"column:prv_submitted_at" json:"submittedAt,omitempty"`
+	CreatedAt    time.Time          `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time          `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	Answers      []PeerReviewAnswer `gorm:"foreignKey:ReviewID" json:"answers,omitempty"`
+}
+
+func (PeerReview) TableName() string {
+	return "edv.peer_reviews"
+}
+
+type PeerReviewAnswer struct {
+	ID         string  `gorm:"primaryKey;column:pra_id;default:gen_random_uuid()" json:"answerId"`
+	ReviewID   string  `gorm:"column:pra_prv_id;type:uuid" json:"reviewId"`
+	QuestionID string  `gorm:"column:pra_prq_id;type:uuid" json:"questionId"`
+	Rating     *int    `gorm:"column:pra_rating" json:"rating,omitempty"`
+	Text       *string `gorm:"column:pra_text" json:"text,omitempty"`
+}
+
+func (PeerReviewAnswer) TableName() string {
+	return "edv.peer_review_answers"
+}
@@ -0,0 +1,10 @@
+package domain
+
+// Publish states of assignments and materials. Students only see published
+// items; scheduled items are published by the scheduler at their publish
+// time.
+const (
+	PublishStatusDraft     = "draft"
+	PublishStatusScheduled = "scheduled"
+	PublishStatusPublished = "published"
+)
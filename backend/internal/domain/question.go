@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionMultiSelect    = "multi_select"
+	QuestionTrueFalse      = "true_false"
+	QuestionShortAnswer    = "short_answer"
+	QuestionNumeric        = "numeric"
+	QuestionEssay          = "essay"
+)
+
+// Question is an item of a subject's question bank. Only the fields of its
+// type are set: Options for multiple choice and multi-select, BooleanAnswer
+// for true/false, AcceptedAnswers (a JSON array) for short answer and
+// NumericAnswer with NumericTolerance for numeric. Essays are graded by hand.
+type Question struct {
+	ID               string           `gorm:"primaryKey;column:qst_id;default:gen_random_uuid()" json:"questionId"`
+	SchoolID         string           `gorm:"column:qst_sch_id;type:uuid" json:"schoolId"`
+	SubjectID        string           `gorm:"column:qst_sub_id;type:uuid" json:"subjectId"`
+	Subject          Subject          `gorm:"foreignKey:SubjectID;references:ID" json:"subject,omitempty"`
+	Type             string           `gorm:"column:qst_type" json:"type"`
+	Prompt           string           `gorm:"column:qst_prompt" json:"prompt"`
+	Points           float64          `gorm:"column:qst_points" json:"points"`
+	BooleanAnswer    *bool            `gorm:"column:qst_boolean_answer" json:"booleanAnswer,omitempty"`
+	AcceptedAnswers  string           `gorm:"column:qst_accepted_answers" json:"acceptedAnswers,omitempty"`
+	CaseSensitive    bool             `gorm:"column:qst_case_sensitive" json:"caseSensitive"`
+	NumericAnswer    *float64         `gorm:"column:qst_numeric_answer" json:"numericAnswer,omitempty"`
+	NumericTolerance float64          `gorm:"column:qst_numeric_tolerance" json:"numericTolerance"`
+	CreatedBy        string           `gorm:"column:created_by;type:uuid" json:"createdBy"`
+	Creator          User             `gorm:"foreignKey:CreatedBy;references:ID" json:"creator,omitempty"`
+	CreatedAt        time.Time        `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time        `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	DeletedAt        gorm.DeletedAt   `gorm:"column:deleted_at;index" json:"-"`
+	Options          []QuestionOption `gorm:"foreignKey:QuestionID" json:"options,omitempty"`
+}
+
+func (Question) TableName() string {
+	return "edv.questions"
+}
+
+type QuestionOption struct {
+	ID         string `gorm:"primaryKey;column:qop_id;default:gen_random_uuid()" json:"optionId"`
+	QuestionID string `gorm:"column:qop_qst_id;type:uuid" json:"questionId"`
+	Text       string `gorm:"column:qop_text" json:"text"`
+	IsCorrect  bool   `gorm:"column:qop_is_correct" json:"isCorrect"`
+	Position   int    `gorm:"column:qop_position" json:"position"`
+}
+
+func (QuestionOption) TableName() string {
+	return "edv.question_options"
+}
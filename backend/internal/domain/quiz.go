@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+const (
+	AssignmentTypeFile = "file"
+	AssignmentTypeQuiz = "quiz"
+)
+
+const (
+	QuizAttemptInProgress   = "in_progress"
+	QuizAttemptNeedsGrading = "needs_grading"
+	QuizAttemptGraded       = "graded"
+)
+
+// QuizQuestion places a bank question in a quiz assignment.
+type QuizQuestion struct {
+	ID           string   `gorm:"primaryKey;column:qzq_id;default:gen_random_uuid()" json:"quizQuestionId"`
+	AssignmentID string   `gorm:"column:qzq_asg_id;type:uuid" json:"assignmentId"`
+	QuestionID   string   `gorm:"column:qzq_qst_id;type:uuid" json:"questionId"`
+	Question     Question `gorm:"foreignKey:QuestionID;references:ID" json:"question,omitempty"`
+	Position     int      `gorm:"column:qzq_position" json:"position"`
+}
+
+func (QuizQuestion) TableName() string {
+	return "edv.quiz_questions"
+}
+
+// QuizAttempt is one student's sitting of a quiz. QuestionOrder (a JSON array
+// of question IDs) and OptionOrder (a JSON object of option IDs per question)
+// hold the order drawn for the student when the attempt started.
+type QuizAttempt struct {
+	ID            string       `gorm:"primaryKey;column:qza_id;default:gen_random_uuid()" json:"attemptId"`
+	SchoolID      string       `gorm:"column:qza_sch_id;type:uuid" json:"schoolId"`
+	AssignmentID  string       `gorm:"column:qza_asg_id;type:uuid" json:"assignmentId"`
+	UserID        string       `gorm:"column:qza_usr_id;type:uuid" json:"userId"`
+	User          User         `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	SubmissionID  *string      `gorm:"column:qza_sbm_id;type:uuid" json:"submissionId,omitempty"`
+	Status        string       `gorm:"column:qza_status;default:in_progress" json:"status"`
+	QuestionOrder string       `gorm:"column:qza_question_order" json:"-"`
+	OptionOrder   string       `gorm:"column:qza_option_order" json:"-"`
+	StartedAt     time.Time    `gorm:"column:qza_started_at" json:"startedAt"`
+	ExpiresAt     *time.Time   `gorm:"column:qza_expires_at" json:"expiresAt,omitempty"`
+	SubmittedAt   *time.Time   `gorm:"column:qza_submitted_at" json:"submittedAt,omitempty"`
+	Answers       []QuizAnswer `gorm:"foreignKey:AttemptID" json:"answers,omitempty"`
+}
+
+func (QuizAttempt) TableName() string {
+	return "edv.quiz_attempts"
+}
+
+// QuizAnswer is a student's answer to one quiz question. Points stays nil
+// until the answer is graded, automatically or by the teacher.
+type QuizAnswer struct {
+	ID            string     `gorm:"primaryKey;column:qzn_id;default:gen_random_uuid()" json:"answerId"`
+	AttemptID     string     `gorm:"column:qzn_qza_id;type:uuid" json:"attemptId"`
+	QuestionID    string     `gorm:"column:qzn_qst_id;type:uuid" json:"questionId"`
+	OptionIDs     string     `gorm:"column:qzn_option_ids" json:"-"`
+	Text          *string    `gorm:"column:qzn_text" json:"text,omitempty"`
+	Number        *float64   `gorm:"column:qzn_number" json:"number,omitempty"`
+	BooleanAnswer *bool      `gorm:"column:qzn_boolean" json:"boolean,omitempty"`
+	Points        *float64   `gorm:"column:qzn_points" json:"points,omitempty"`
+	IsCorrect     *bool      `gorm:"column:qzn_is_correct" json:"isCorrect,omitempty"`
+	Feedback      *string    `gorm:"column:qzn_feedback" json:"feedback,omitempty"`
+	GradedBy      *string    `gorm:"column:qzn_graded_by;type:uuid" json:"gradedBy,omitempty"`
+	GradedAt      *time.Time `gorm:"column:qzn_graded_at" json:"gradedAt,omitempty"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (QuizAnswer) TableName() string {
+	return "edv.quiz_answers"
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Rubric is a reusable grading guide. A rubric without an owner is shared with
+// the whole school; otherwise only its owner can attach it to assignments.
+type Rubric struct {
+	ID          string            `gorm:"primaryKey;column:rbr_id;default:gen_random_uuid()" json:"rubricId"`
+	SchoolID    string            `gorm:"column:rbr_sch_id;type:uuid" json:"schoolId"`
+	OwnerID     *string           `gorm:"column:rbr_owner_usr_id;type:uuid" json:"ownerId,omitempty"`
+	Title       string            `gorm:"column:rbr_title" json:"title"`
+	Description string            `gorm:"column:rbr_desc" json:"description"`
+	CreatedBy   string            `gorm:"column:created_by;type:uuid" json:"createdBy"`
+	Creator     User              `gorm:"foreignKey:CreatedBy;references:ID" json:"creator,omitempty"`
+	CreatedAt   time.Time         `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time         `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt    `gorm:"column:deleted_at;index" json:"-"`
+	Criteria    []RubricCriterion `gorm:"foreignKey:RubricID" json:"criteria,omitempty"`
+}
+
+func (Rubric) TableName() string {
+	return "edv.rubrics"
+}
+
+type RubricCriterion struct {
+	ID          string        `gorm:"primaryKey;column:rbc_id;default:gen_random_uuid()" json:"criterionId"`
+	RubricID    string        `gorm:"column:rbc_rbr_id;type:uuid" json:"rubricId"`
+	Title       string        `gorm:"column:rbc_title" json:"title"`
+	Description string        `gorm:"column:rbc_desc" json:"description"`
+	Position    int           `gorm:"column:rbc_position" json:"position"`
+	Levels      []RubricLevel `gorm:"foreignKey:CriterionID" json:"levels,omitempty"`
+}
+
+func (RubricCriterion) TableName() string {
+	return "edv.rubric_criteria"
+}
+
+type RubricLevel struct {
+	ID          string  `gorm:"primaryKey;column:rbl_id;default:gen_random_uuid()" json:"levelId"`
+	CriterionID string  `gorm:"column:rbl_rbc_id;type:uuid" json:"criterionId"`
+	Title       string  `gorm:"column:rbl_title" json:"title"`
+	Description string  `gorm:"column:rbl_desc" json:"description"`
+	Points      float64 `gorm:"column:rbl_points" json:"points"`
+	Position    int     `gorm:"column:rbl_position" json:"position"`
+}
+
+func (RubricLevel) TableName() string {
+	return "edv.rubric_levels"
+}
+
+// AssessmentRubricScore is the level a teacher picked for one criterion when
+// grading a submission. Points is copied from the level at grading time.
+type AssessmentRubricScore struct {
+	ID           string  `gorm:"primaryKey;column:ars_id;default:gen_random_uuid()" json:"rubricScoreId"`
+	AssessmentID string  `gorm:"column:ars_asm_id;type:uuid" json:"assessmentId"`
+	CriterionID  string  `gorm:"column:ars_rbc_id;type:uuid" json:"criterionId"`
+	LevelID      string  `gorm:"column:ars_rbl_id;type:uuid" json:"levelId"`
+	Points       float64 `gorm:"column:ars_points" json:"points"`
+	Comment      string  `gorm:"column:ars_comment" json:"comment"`
+}
+
+func (AssessmentRubricScore) TableName() string {
+	return "edv.assessment_rubric_scores"
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// StudentGroup is a team of students within a subject class. Teachers set
+// the members, or let students join on their own when SelfJoin is set. A
+// student belongs to at most one group per subject class.
+type StudentGroup struct {
+	ID             string               `gorm:"primaryKey;column:grp_id;default:gen_random_uuid()" json:"groupId"`
+	SchoolID       string               `gorm:"column:grp_sch_id;type:uuid" json:"schoolId"`
+	SubjectClassID string               `gorm:"column:grp_scl_id;type:uuid" json:"subjectClassId"`
+	Name           string               `gorm:"column:grp_name" json:"name"`
+	MaxSize        *int                 `gorm:"column:grp_max_size" json:"maxSize,omitempty"`
+	SelfJoin       bool                 `gorm:"column:grp_self_join" json:"selfJoin"`
+	CreatedBy      string               `gorm:"column:created_by;type:uuid" json:"createdBy"`
+	CreatedAt      time.Time            `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time            `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	Members        []StudentGroupMember `gorm:"foreignKey:GroupID" json:"members,omitempty"`
+}
+
+func (StudentGroup) TableName() string {
+	return "edv.student_groups"
+}
+
+type StudentGroupMember struct {
+	ID             string    `gorm:"primaryKey;column:gpm_id;default:gen_random_uuid()" json:"memberId"`
+	GroupID        string    `gorm:"column:gpm_grp_id;type:uuid" json:"groupId"`
+	SubjectClassID string    `gorm:"column:gpm_scl_id;type:uuid" json:"subjectClassId"`
+	UserID         string    `gorm:"column:gpm_usr_id;type:uuid" json:"userId"`
+	User           User      `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"joinedAt"`
+}
+
+func (StudentGroupMember) TableName() string {
+	return "edv.student_group_members"
+}
+
+// GroupSubmissionMember is a student a group submission counts for, taken
+// from the group when the work was turned in. ScoreAdjustment is added to
+// the group's score for this student only; Score is the result once the
+// submission is graded.
+type GroupSubmissionMember struct {
+	ID              string    `gorm:"primaryKey;column:gsm_id;default:gen_random_uuid()" json:"id"`
+	SubmissionID    string    `gorm:"column:gsm_sbm_id;type:uuid" json:"submissionId"`
+	UserID          string    `gorm:"column:gsm_usr_id;type:uuid" json:"userId"`
+	User            User      `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	ScoreAdjustment float64   `gorm:"column:gsm_score_adjustment" json:"scoreAdjustment"`
+	AdjustmentNote  string    `gorm:"column:gsm_adjustment_note" json:"adjustmentNote"`
+	Score           *float64  `gorm:"-" json:"score,omitempty"`
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (GroupSubmissionMember) TableName() string {
+	return "edv.group_submission_members"
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Grading policies pick the submission version whose grade counts.
+const (
+	GradingPolicyLatest  = "latest"
+	GradingPolicyHighest = "highest"
+)
+
+// SubmissionVersion is an immutable snapshot of one turn-in of a submission:
+// when it was turned in and the attachments it carried. Each version can be
+// graded on its own; the assignment's grading policy decides which grade is
+// written as the submission's assessment.
+type SubmissionVersion struct {
+	ID           string       `gorm:"primaryKey;column:sbv_id;default:gen_random_uuid()" json:"versionId"`
+	SubmissionID string       `gorm:"column:sbv_sbm_id;type:uuid" json:"submissionId"`
+	Number       int          `gorm:"column:sbv_number" json:"number"`
+	SubmittedAt  time.Time    `gorm:"column:sbv_submitted_at" json:"submittedAt"`
+	Score        *float64     `gorm:"column:sbv_score" json:"score,omitempty"`
+	Feedback     string       `gorm:"column:sbv_feedback" json:"feedback"`
+	GradedBy     *string      `gorm:"column:sbv_graded_by;type:uuid" json:"gradedBy,omitempty"`
+	Grader       *User        `gorm:"foreignKey:GradedBy;references:ID" json:"grader,omitempty"`
+	GradedAt     *time.Time   `gorm:"column:sbv_graded_at" json:"gradedAt,omitempty"`
+	IsLate       bool         `gorm:"-" json:"isLate"`
+	Attachments  []Attachment `gorm:"-" json:"attachments,omitempty"`
+}
+
+func (SubmissionVersion) TableName() string {
+	return "edv.submission_versions"
+}
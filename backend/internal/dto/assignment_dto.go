@@ -31,16 +31,41 @@ type CreateAssignmentDTO struct {
 	Description         string     `json:"assignmentDescription"`
 	Deadline            *time.Time `json:"deadline"`
 	AllowLateSubmission bool       `json:"allowLateSubmission"`
+	RubricID            *string    `json:"rubricId" binding:"omitempty,uuid"`
 	MediaIDs            []string   `json:"mediaIds"`
-}
-
+	// Type "quiz" needs Quiz; file assignments leave it empty.
+	Type string           `json:"type" binding:"omitempty,oneof=file quiz"`
+	Quiz *QuizSettingsDTO `json:"quiz"`
+	// MaxAttempts limits turn-ins per student; nil allows any number.
+	MaxAttempts   *int   `json:"maxAttempts" binding:"omitempty,min=1,max=100"`
+	GradingPolicy string `json:"gradingPolicy" binding:"omitempty,oneof=latest highest"`
+	// GroupMode takes one submission per student group of the subject class.
+	GroupMode bool `json:"groupMode"`
+	// PublishStatus defaults to published, or scheduled when PublishAt lies
+	// in the future.
+	PublishStatus string     `json:"publishStatus" binding:"omitempty,oneof=draft scheduled published"`
+	PublishAt     *time.Time `json:"publishAt"`
+	// AnonymousGrading shows graders pseudonyms until identities are
+	// released.
+	AnonymousGrading bool `json:"anonymousGrading"`
+}
+
+// UpdateAssignmentDTO detaches the rubric when rubricId is an empty string
+// and lifts the attempt limit when maxAttempts is 0.
 type UpdateAssignmentDTO struct {
 	CategoryID          *string    `json:"categoryId" binding:"omitempty,uuid"`
 	Title               *string    `json:"assignmentTitle"`
 	Description         *string    `json:"assignmentDescription"`
 	Deadline            *time.Time `json:"deadline"`
 	AllowLateSubmission *bool      `json:"allowLateSubmission"`
+	RubricID            *string    `json:"rubricId" binding:"omitempty,uuid"`
 	MediaIDs            []string   `json:"mediaIds"`
+	MaxAttempts         *int       `json:"maxAttempts" binding:"omitempty,min=0,max=100"`
+	GradingPolicy       *string    `json:"gradingPolicy" binding:"omitempty,oneof=latest highest"`
+	GroupMode           *bool      `json:"groupMode"`
+	PublishStatus       *string    `json:"publishStatus" binding:"omitempty,oneof=draft scheduled published"`
+	PublishAt           *time.Time `json:"publishAt"`
+	AnonymousGrading    *bool      `json:"anonymousGrading"`
 }
 
 type AssignmentResponseDTO struct {
@@ -50,6 +75,17 @@ type AssignmentResponseDTO struct {
 	CategoryName        string             `json:"categoryName"`
 	Deadline            *time.Time         `json:"deadline,omitempty"`
 	AllowLateSubmission bool               `json:"allowLateSubmission"`
+	RubricID            *string            `json:"rubricId"`
+	Type                string             `json:"type"`
+	TimeLimitMinutes    *int               `json:"timeLimitMinutes,omitempty"`
+	MaxAttempts         *int               `json:"maxAttempts"`
+	GradingPolicy       string             `json:"gradingPolicy"`
+	GroupMode           bool               `json:"groupMode"`
+	PublishStatus       string             `json:"publishStatus"`
+	PublishAt           *time.Time         `json:"publishAt,omitempty"`
+	PublishedAt         *time.Time         `json:"publishedAt,omitempty"`
+	AnonymousGrading    bool               `json:"anonymousGrading"`
+	IdentityReleasedAt  *time.Time         `json:"identitiesReleasedAt,omitempty"`
 	CreatedAt           string             `json:"createdAt"`
 	Attachments         []MediaResponseDTO `json:"attachments,omitempty"`
 }
@@ -64,10 +100,17 @@ type StudentAssignmentDetailDTO struct {
 	Description         string             `json:"assignmentDescription"`
 	CategoryName        string             `json:"categoryName"`
 	Deadline            *time.Time         `json:"deadline,omitempty"`
+	ExtendedDeadline    *time.Time         `json:"extendedDeadline,omitempty"`
 	AllowLateSubmission bool               `json:"allowLateSubmission"`
+	Type                string             `json:"type"`
+	TimeLimitMinutes    *int               `json:"timeLimitMinutes,omitempty"`
+	MaxAttempts         *int               `json:"maxAttempts"`
+	GradingPolicy       string             `json:"gradingPolicy"`
+	GroupMode           bool               `json:"groupMode"`
 	CreatedAt           string             `json:"createdAt"`
 	UpdatedAt           string             `json:"updatedAt"`
 	Attachments         []MediaResponseDTO `json:"attachments,omitempty"`
+	Rubric              *RubricResponseDTO `json:"rubric,omitempty"`
 }
 
 type AssignmentPerSubjectClassResponseDTO struct {
@@ -112,6 +155,7 @@ type SubjectClassSubmissionsResponseDTO struct {
 
 type TeacherSubmissionInboxSummaryDTO struct {
 	TotalSubmissions int `json:"totalSubmissions"`
+	StudentCount     int `json:"studentCount"`
 	PendingCount     int `json:"pendingCount"`
 	GradedCount      int `json:"gradedCount"`
 	LateCount        int `json:"lateCount"`
@@ -127,10 +171,19 @@ type TeacherSubmissionInboxItemDTO struct {
 	ClassName       string     `json:"className" gorm:"column:class_name"`
 	ClassCode       string     `json:"classCode" gorm:"column:class_code"`
 	Deadline        *time.Time `json:"deadline" gorm:"column:deadline"`
+	IsGroup         bool       `json:"isGroupAssignment" gorm:"column:is_group_assignment"`
+	GroupCount      int        `json:"groupCount" gorm:"column:group_count"`
 	SubmissionCount int        `json:"submissionCount" gorm:"column:submission_count"`
+	StudentCount    int        `json:"studentCount" gorm:"column:student_count"`
 	PendingCount    int        `json:"pendingCount" gorm:"column:pending_count"`
 	GradedCount     int        `json:"gradedCount" gorm:"column:graded_count"`
 	LateCount       int        `json:"lateCount" gorm:"column:late_count"`
+	IdentityHidden  bool       `json:"identitiesHidden" gorm:"column:identities_hidden"`
+}
+
+type IdentityReleaseResponseDTO struct {
+	AssignmentID       string    `json:"assignmentId"`
+	IdentityReleasedAt time.Time `json:"identitiesReleasedAt"`
 }
 
 type TeacherSubmissionInboxResponseDTO struct {
@@ -157,6 +210,8 @@ type TeacherAssignmentInboxItemDTO struct {
 	ClassCode       string     `json:"classCode" gorm:"column:class_code"`
 	CategoryName    string     `json:"categoryName" gorm:"column:category_name"`
 	Deadline        *time.Time `json:"deadline" gorm:"column:deadline"`
+	PublishStatus   string     `json:"publishStatus" gorm:"column:publish_status"`
+	PublishAt       *time.Time `json:"publishAt,omitempty" gorm:"column:publish_at"`
 	SubmissionCount int        `json:"submissionCount" gorm:"column:submission_count"`
 	PendingCount    int        `json:"pendingCount" gorm:"column:pending_count"`
 	GradedCount     int        `json:"gradedCount" gorm:"column:graded_count"`
@@ -177,23 +232,25 @@ type StudentAssignmentInboxSummaryDTO struct {
 }
 
 type StudentAssignmentInboxItemDTO struct {
-	AssignmentID    string     `json:"assignmentId" gorm:"column:assignment_id"`
-	SubjectClassID  string     `json:"subjectClassId" gorm:"column:subject_class_id"`
-	AssignmentTitle string     `json:"assignmentTitle" gorm:"column:assignment_title"`
-	SubjectName     string     `json:"subjectName" gorm:"column:subject_name"`
-	SubjectCode     string     `json:"subjectCode" gorm:"column:subject_code"`
-	SubjectColor    string     `json:"subjectColor,omitempty" gorm:"column:subject_color"`
-	ClassName       string     `json:"className" gorm:"column:class_name"`
-	ClassCode       string     `json:"classCode" gorm:"column:class_code"`
-	CategoryName    string     `json:"categoryName" gorm:"column:category_name"`
-	Deadline        *time.Time `json:"deadline" gorm:"column:deadline"`
-	SubmissionID    *string    `json:"submissionId" gorm:"column:submission_id"`
-	SubmittedAt     *time.Time `json:"submittedAt" gorm:"column:submitted_at"`
-	Score           *float64   `json:"score" gorm:"column:score"`
-	IsSubmitted     bool       `json:"isSubmitted" gorm:"column:is_submitted"`
-	IsGraded        bool       `json:"isGraded" gorm:"column:is_graded"`
-	IsOverdue       bool       `json:"isOverdue" gorm:"column:is_overdue"`
-	IsSubmittedLate bool       `json:"isSubmittedLate" gorm:"column:is_submitted_late"`
+	AssignmentID     string     `json:"assignmentId" gorm:"column:assignment_id"`
+	SubjectClassID   string     `json:"subjectClassId" gorm:"column:subject_class_id"`
+	AssignmentTitle  string     `json:"assignmentTitle" gorm:"column:assignment_title"`
+	AssignmentType   string     `json:"assignmentType" gorm:"column:assignment_type"`
+	SubjectName      string     `json:"subjectName" gorm:"column:subject_name"`
+	SubjectCode      string     `json:"subjectCode" gorm:"column:subject_code"`
+	SubjectColor     string     `json:"subjectColor,omitempty" gorm:"column:subject_color"`
+	ClassName        string     `json:"className" gorm:"column:class_name"`
+	ClassCode        string     `json:"classCode" gorm:"column:class_code"`
+	CategoryName     string     `json:"categoryName" gorm:"column:category_name"`
+	Deadline         *time.Time `json:"deadline" gorm:"column:deadline"`
+	ExtendedDeadline *time.Time `json:"extendedDeadline" gorm:"column:extended_deadline"`
+	SubmissionID     *string    `json:"submissionId" gorm:"column:submission_id"`
+	SubmittedAt      *time.Time `json:"submittedAt" gorm:"column:submitted_at"`
+	Score            *float64   `json:"score" gorm:"column:score"`
+	IsSubmitted      bool       `json:"isSubmitted" gorm:"column:is_submitted"`
+	IsGraded         bool       `json:"isGraded" gorm:"column:is_graded"`
+	IsOverdue        bool       `json:"isOverdue" gorm:"column:is_overdue"`
+	IsSubmittedLate  bool       `json:"isSubmittedLate" gorm:"column:is_submitted_late"`
 }
 
 type StudentAssignmentInboxResponseDTO struct {
@@ -208,44 +265,110 @@ type CreateSubmissionDTO struct {
 }
 
 type SubmissionResponseDTO struct {
-	ID          string                 `json:"submissionId"`
-	UserName    string                 `json:"studentName"`
-	SubmittedAt string                 `json:"submittedAt"`
-	IsLate      bool                   `json:"isLate"`
-	Attachments []MediaResponseDTO     `json:"attachments,omitempty"`
-	Assessment  *AssessmentResponseDTO `json:"assessment,omitempty"`
+	ID           string                 `json:"submissionId"`
+	UserName     string                 `json:"studentName"`
+	SubmittedAt  string                 `json:"submittedAt"`
+	IsLate       bool                   `json:"isLate"`
+	AttemptCount int                    `json:"attemptCount,omitempty"`
+	Group        *SubmissionGroupDTO    `json:"group,omitempty"`
+	Attachments  []MediaResponseDTO     `json:"attachments,omitempty"`
+	Assessment   *AssessmentResponseDTO `json:"assessment,omitempty"`
+	LatePenalty  *LatePenaltyDTO        `json:"latePenalty,omitempty"`
+}
+
+// SubmissionVersionResponseDTO is one turn-in of a submission. Counted marks
+// the version whose grade is the submission's assessment.
+type SubmissionVersionResponseDTO struct {
+	VersionID   string             `json:"versionId"`
+	Number      int                `json:"number"`
+	SubmittedAt string             `json:"submittedAt"`
+	IsLate      bool               `json:"isLate"`
+	Attachments []MediaResponseDTO `json:"attachments"`
+	Score       *float64           `json:"score"`
+	Feedback    string             `json:"feedback"`
+	GraderName  *string            `json:"graderName"`
+	GradedAt    *string            `json:"gradedAt"`
+	Counted     bool               `json:"counted"`
+}
+
+type SubmissionVersionHistoryDTO struct {
+	SubmissionID  string                         `json:"submissionId"`
+	StudentName   string                         `json:"studentName"`
+	MaxAttempts   *int                           `json:"maxAttempts"`
+	GradingPolicy string                         `json:"gradingPolicy"`
+	Versions      []SubmissionVersionResponseDTO `json:"versions"`
 }
 
 // Assessment
+// CreateAssessmentDTO takes a score, or criteria when the assignment has a
+// rubric; the score is then computed from the rubric. VersionID grades an
+// earlier turn-in instead of the latest one.
 type CreateAssessmentDTO struct {
-	Score    float64 `json:"score" binding:"required"`
-	Feedback string  `json:"feedback"`
+	Score     *float64                      `json:"score"`
+	Feedback  string                        `json:"feedback"`
+	Criteria  []AssessmentCriterionInputDTO `json:"criteria" binding:"omitempty,max=20,dive"`
+	VersionID string                        `json:"versionId" binding:"omitempty,uuid"`
 }
 
 type UpdateAssessmentDTO struct {
-	Score    *float64 `json:"score"`
-	Feedback *string  `json:"feedback"`
+	Score    *float64                      `json:"score"`
+	Feedback *string                       `json:"feedback"`
+	Criteria []AssessmentCriterionInputDTO `json:"criteria" binding:"omitempty,max=20,dive"`
 }
 
 type AssessmentResponseDTO struct {
-	Score      float64 `json:"score"`
-	Feedback   string  `json:"feedback"`
-	Assessor   string  `json:"assessorName"`
-	AssessedAt string  `json:"assessedAt"`
+	Score      float64          `json:"score"`
+	Feedback   string           `json:"feedback"`
+	Assessor   string           `json:"assessorName"`
+	AssessedAt string           `json:"assessedAt"`
+	VersionID  *string          `json:"versionId,omitempty"`
+	Rubric     *FilledRubricDTO `json:"rubric,omitempty"`
+}
+
+// Extension
+type CreateAssignmentExtensionDTO struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+type ReviewAssignmentExtensionDTO struct {
+	Status           string     `json:"status" binding:"required,oneof=approved rejected"`
+	ExtendedDeadline *time.Time `json:"extendedDeadline"`
+	ReviewNote       *string    `json:"reviewNote" binding:"omitempty,max=1000"`
+}
+
+type AssignmentExtensionResponseDTO struct {
+	ID               string     `json:"extensionId"`
+	AssignmentID     string     `json:"assignmentId"`
+	StudentID        string     `json:"studentId"`
+	StudentName      string     `json:"studentName"`
+	Reason           string     `json:"reason"`
+	Status           string     `json:"status"`
+	ExtendedDeadline *time.Time `json:"extendedDeadline"`
+	ReviewNote       *string    `json:"reviewNote"`
+	ReviewerName     *string    `json:"reviewerName"`
+	ReviewedAt       *string    `json:"reviewedAt"`
+	CreatedAt        string     `json:"createdAt"`
+}
+
+type MyAssignmentExtensionResponseDTO struct {
+	Extension *AssignmentExtensionResponseDTO `json:"extension"`
 }
 
 type MySubmissionAssessmentDTO struct {
-	ID           string  `json:"assessmentId"`
-	Score        float64 `json:"score"`
-	Feedback     string  `json:"feedback"`
-	AssessedAt   string  `json:"assessedAt"`
-	AssessorName string  `json:"assessorName"`
+	ID           string           `json:"assessmentId"`
+	Score        float64          `json:"score"`
+	Feedback     string           `json:"feedback"`
+	AssessedAt   string           `json:"assessedAt"`
+	AssessorName string           `json:"assessorName"`
+	Rubric       *FilledRubricDTO `json:"rubric,omitempty"`
 }
 
 type MySubmissionDTO struct {
 	ID           string                     `json:"submissionId"`
 	AssignmentID string                     `json:"assignmentId"`
 	SubmittedAt  string                     `json:"submittedAt"`
+	AttemptCount int                        `json:"attemptCount"`
+	Group        *SubmissionGroupDTO        `json:"group,omitempty"`
 	Attachments  []MediaResponseDTO         `json:"attachments,omitempty"`
 	Assessment   *MySubmissionAssessmentDTO `json:"assessment"`
 }
@@ -346,16 +469,19 @@ type MyGradebookSubjectDTO struct {
 }
 
 type MyGradebookAssignmentDTO struct {
-	AssignmentID    string     `json:"assignmentId"`
-	AssignmentTitle string     `json:"assignmentTitle"`
-	CategoryName    string     `json:"categoryName"`
-	Deadline        *time.Time `json:"deadline,omitempty"`
-	Status          string     `json:"status"`
-	SubmittedAt     *string    `json:"submittedAt"`
-	Score           *float64   `json:"score"`
-	Feedback        *string    `json:"feedback"`
-	AssessedAt      *string    `json:"assessedAt"`
-	AssessorName    *string    `json:"assessorName"`
+	AssignmentID    string                   `json:"assignmentId"`
+	AssignmentTitle string                   `json:"assignmentTitle"`
+	CategoryName    string                   `json:"categoryName"`
+	Deadline        *time.Time               `json:"deadline,omitempty"`
+	Status          string                   `json:"status"`
+	SubmittedAt     *string                  `json:"submittedAt"`
+	Score           *float64                 `json:"score"`
+	FinalScore      *float64                 `json:"finalScore"`
+	LatePenalty     *LatePenaltyDTO          `json:"latePenalty,omitempty"`
+	PeerReview      *PeerReviewCompletionDTO `json:"peerReview,omitempty"`
+	Feedback        *string                  `json:"feedback"`
+	AssessedAt      *string                  `json:"assessedAt"`
+	AssessorName    *string                  `json:"assessorName"`
 }
 
 type MyGradebookSummaryDTO struct {
@@ -389,6 +515,21 @@ type StudentGradebookRow struct {
 	AssessorName    *string    `gorm:"column:assessor_name"`
 }
 
+// LatePenaltyInputRow is what the late penalty of one submission depends
+// on. The policy fields are nil when no late policy applies.
+type LatePenaltyInputRow struct {
+	SubmissionID     string     `gorm:"column:submission_id"`
+	SubmittedAt      time.Time  `gorm:"column:submitted_at"`
+	Deadline         *time.Time `gorm:"column:deadline"`
+	ExtendedDeadline *time.Time `gorm:"column:extended_deadline"`
+	Waived           bool       `gorm:"column:waived"`
+	PolicyID         *string    `gorm:"column:policy_id"`
+	PercentPerDay    *float64   `gorm:"column:percent_per_day"`
+	MaxPercent       *float64   `gorm:"column:max_percent"`
+	GraceMinutes     *int       `gorm:"column:grace_minutes"`
+	ZeroAfterDays    *int       `gorm:"column:zero_after_days"`
+}
+
 type SubjectHeaderDTO struct {
 	SubjectID    string `json:"subjectId"`
 	SubjectName  string `json:"subjectName"`
@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// GradingRosterRow is one student of an assignment's subject class with the
+// submission that counts for them and its current grade, if any.
+type GradingRosterRow struct {
+	UserID       string
+	FullName     string
+	Email        string
+	GroupName    *string
+	SubmissionID *string
+	SubmittedAt  *time.Time
+	Score        *float64
+	Feedback     *string
+}
+
+// GradeImportRowDTO is one row of a grade CSV checked against the roster.
+// Action is create, update, unchanged or skip; a blank score skips the row.
+type GradeImportRowDTO struct {
+	RowNumber       int      `json:"rowNumber"`
+	Email           string   `json:"email"`
+	FullName        string   `json:"fullName"`
+	SubmissionID    string   `json:"submissionId,omitempty"`
+	CurrentScore    *float64 `json:"currentScore"`
+	CurrentFeedback string   `json:"currentFeedback"`
+	Score           *float64 `json:"score"`
+	Feedback        string   `json:"feedback"`
+	Action          string   `json:"action"`
+	Status          string   `json:"status"`
+	Errors          []string `json:"errors"`
+}
+
+type GradeImportPreviewResponseDTO struct {
+	Rows         []GradeImportRowDTO `json:"rows"`
+	ValidCount   int                 `json:"validCount"`
+	InvalidCount int                 `json:"invalidCount"`
+	CreateCount  int                 `json:"createCount"`
+	UpdateCount  int                 `json:"updateCount"`
+}
+
+type GradeImportCommitRequestDTO struct {
+	Rows []GradeImportRowDTO `json:"rows" binding:"required"`
+}
+
+// GradeImportCommitResponseDTO counts the grades written by an import. When
+// rows are still invalid nothing is written and Rows lists them with their
+// errors.
+type GradeImportCommitResponseDTO struct {
+	CreatedCount   int                 `json:"createdCount"`
+	UpdatedCount   int                 `json:"updatedCount"`
+	UnchangedCount int                 `json:"unchangedCount"`
+	SkippedCount   int                 `json:"skippedCount"`
+	InvalidCount   int                 `json:"invalidCount"`
+	Rows           []GradeImportRowDTO `json:"rows,omitempty"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type ChatLastMessageDTO struct {
 	MessageID          string `json:"messageId"`
 	SenderID           string `json:"senderId"`
@@ -9,24 +11,30 @@ type ChatLastMessageDTO struct {
 	AttachmentCount    int    `json:"attachmentCount"`
 	AttachmentMimeType string `json:"attachmentMimeType,omitempty"`
 	AttachmentFileName string `json:"attachmentFileName,omitempty"`
+	RefType            string `json:"refType,omitempty"`
 	CreatedAt          string `json:"createdAt"`
 }
 
 type ChatRoomDTO struct {
-	RoomID         string              `json:"roomId"`
-	RoomName       string              `json:"roomName"`
-	RoomType       string              `json:"roomType"`
-	RoomRefType    *string             `json:"roomRefType"`
-	RoomRefID      *string             `json:"roomRefId"`
-	SchoolID       string              `json:"schoolId"`
-	SchoolName     string              `json:"schoolName"`
-	DMTargetUserID *string             `json:"dmTargetUserId,omitempty"`
-	DMTargetName   *string             `json:"dmTargetName,omitempty"`
-	DMTargetEmail  *string             `json:"dmTargetEmail,omitempty"`
-	LastMessage    *ChatLastMessageDTO `json:"lastMessage"`
-	LastMessageAt  *string             `json:"lastMessageAt"`
-	UnreadCount    int64               `json:"unreadCount"`
-	CanSend        bool                `json:"canSend"`
+	RoomID                  string              `json:"roomId"`
+	RoomName                string              `json:"roomName"`
+	RoomType                string              `json:"roomType"`
+	RoomRefType             *string             `json:"roomRefType"`
+	RoomRefID               *string             `json:"roomRefId"`
+	SchoolID                string              `json:"schoolId"`
+	SchoolName              string              `json:"schoolName"`
+	DMTargetUserID          *string             `json:"dmTargetUserId,omitempty"`
+	DMTargetName            *string             `json:"dmTargetName,omitempty"`
+	DMTargetEmail           *string             `json:"dmTargetEmail,omitempty"`
+	LastMessage             *ChatLastMessageDTO `json:"lastMessage"`
+	LastMessageAt           *string             `json:"lastMessageAt"`
+	UnreadCount             int64               `json:"unreadCount"`
+	MentionCount            int64               `json:"mentionCount"`
+	NotifyLevel             string              `json:"notifyLevel"`
+	NotificationsMutedUntil *string             `json:"notificationsMutedUntil,omitempty"`
+	CanSend                 bool                `json:"canSend"`
+	PostingMode             string              `json:"postingMode"`
+	MutedUntil              *string             `json:"mutedUntil,omitempty"`
 }
 
 type ChatMessageDTO struct {
@@ -38,10 +46,107 @@ type ChatMessageDTO struct {
 	Content     string              `json:"content"`
 	MessageType string              `json:"messageType"`
 	Attachments []ChatAttachmentDTO `json:"attachments"`
+	ContentCard *ChatContentCardDTO `json:"contentCard"`
 	CreatedAt   string              `json:"createdAt"`
+	EditedAt    *string             `json:"editedAt"`
+	ReplyTo     *string             `json:"replyTo"`
+	ReplyCount  int                 `json:"replyCount"`
+	Reactions   []ChatReactionDTO   `json:"reactions"`
+	IsPinned    bool                `json:"isPinned"`
 	IsMine      bool                `json:"isMine"`
 }
 
+// ChatContentCardDTO previews a material, assignment or feed post shared in a
+// message. When the viewer cannot open the content, or it was deleted, only
+// refType, refId and available are set.
+type ChatContentCardDTO struct {
+	RefType        string  `json:"refType"`
+	RefID          string  `json:"refId"`
+	Available      bool    `json:"available"`
+	Title          *string `json:"title"`
+	ClassID        *string `json:"classId"`
+	ClassName      *string `json:"className"`
+	SubjectClassID *string `json:"subjectClassId"`
+	SubjectName    *string `json:"subjectName"`
+	Deadline       *string `json:"deadline"`
+	Link           *string `json:"link"`
+}
+
+type ChatReactionDTO struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reactedByMe"`
+}
+
+type ChatReactionUpdatedDTO struct {
+	MessageID string `json:"messageId"`
+	RoomID    string `json:"roomId"`
+	UserID    string `json:"userId"`
+	Emoji     string `json:"emoji"`
+	Reacted   bool   `json:"reacted"`
+	Count     int    `json:"count"`
+}
+
+type ChatThreadResponseDTO struct {
+	Parent     ChatMessageDTO   `json:"parent"`
+	Replies    []ChatMessageDTO `json:"replies"`
+	ReplyCount int              `json:"replyCount"`
+	NextBefore *string          `json:"nextBefore"`
+	HasMore    bool             `json:"hasMore"`
+}
+
+type ChatThreadSummaryDTO struct {
+	RoomID          string `json:"roomId"`
+	ParentMessageID string `json:"parentMessageId"`
+	ReplyCount      int    `json:"replyCount"`
+}
+
+// ChatMessageSearchQueryDTO holds the parsed search query parameters. Nil
+// filters are not applied.
+type ChatMessageSearchQueryDTO struct {
+	Query         string
+	RoomID        *string
+	SenderID      *string
+	From          *time.Time
+	To            *time.Time
+	HasAttachment *bool
+	Page          int
+	Limit         int
+}
+
+type ChatMessageSearchResultDTO struct {
+	MessageID       string  `json:"messageId"`
+	RoomID          string  `json:"roomId"`
+	RoomName        string  `json:"roomName"`
+	RoomType        string  `json:"roomType"`
+	SenderID        string  `json:"senderId"`
+	SenderName      string  `json:"senderName"`
+	MessageType     string  `json:"messageType"`
+	Snippet         string  `json:"snippet"`
+	AttachmentCount int     `json:"attachmentCount"`
+	ReplyTo         *string `json:"replyTo,omitempty"`
+	CreatedAt       string  `json:"createdAt"`
+	// JumpBefore is the ListMessages `before` cursor whose page ends with the
+	// message.
+	JumpBefore string `json:"jumpBefore"`
+}
+
+type ChatMessageSearchResponseDTO struct {
+	Data       []ChatMessageSearchResultDTO `json:"data"`
+	TotalItems int64                        `json:"totalItems"`
+	Page       int                          `json:"page"`
+	Limit      int                          `json:"limit"`
+	TotalPages int                          `json:"totalPages"`
+}
+
+type ChatMessageDeletedDTO struct {
+	MessageID string  `json:"messageId"`
+	RoomID    string  `json:"roomId"`
+	ReplyTo   *string `json:"replyTo,omitempty"`
+	DeletedBy string  `json:"deletedBy"`
+	DeletedAt string  `json:"deletedAt"`
+}
+
 type ChatAttachmentDTO struct {
 	AttachmentID string `json:"attachmentId"`
 	MediaID      string `json:"mediaId"`
@@ -86,6 +191,7 @@ type ChatGroupInfoDTO struct {
 	Members     []ChatGroupMemberDTO `json:"members"`
 	CreatedAt   string               `json:"createdAt"`
 	MemberCount int                  `json:"memberCount"`
+	PostingMode string               `json:"postingMode"`
 }
 
 type ChatMembersResponseDTO struct {
@@ -126,6 +232,11 @@ type MarkChatRoomReadResponseDTO struct {
 	LastReadAt        string  `json:"lastReadAt"`
 }
 
+type ChatOnlineMembersResponseDTO struct {
+	RoomID        string   `json:"roomId"`
+	OnlineUserIDs []string `json:"onlineUserIds"`
+}
+
 type ChatMessagesResponseDTO struct {
 	Messages   []ChatMessageDTO `json:"messages"`
 	NextBefore *string          `json:"nextBefore"`
@@ -135,6 +246,17 @@ type ChatMessagesResponseDTO struct {
 type CreateChatMessageDTO struct {
 	Content  string   `json:"content"`
 	MediaIDs []string `json:"mediaIds" binding:"omitempty,dive,uuid"`
+	ReplyTo  *string  `json:"replyTo" binding:"omitempty,uuid"`
+	RefType  *string  `json:"refType" binding:"omitempty,oneof=material assignment feed"`
+	RefID    *string  `json:"refId" binding:"omitempty,uuid"`
+}
+
+type UpdateChatMessageDTO struct {
+	Content string `json:"content"`
+}
+
+type AddChatReactionDTO struct {
+	Emoji string `json:"emoji" binding:"required"`
 }
 
 type CreateChatGroupDTO struct {
@@ -157,3 +279,181 @@ type AddChatGroupMembersDTO struct {
 type MarkChatRoomReadDTO struct {
 	LastReadMessageID *string `json:"lastReadMessageId,omitempty" binding:"omitempty,uuid"`
 }
+
+type ReportChatMessageDTO struct {
+	Reason string `json:"reason"`
+}
+
+type ChatReportDTO struct {
+	ReportID       string  `json:"reportId"`
+	RoomID         string  `json:"roomId"`
+	RoomName       string  `json:"roomName"`
+	RoomType       string  `json:"roomType"`
+	MessageID      string  `json:"messageId"`
+	MessageContent string  `json:"messageContent"`
+	MessageDeleted bool    `json:"messageDeleted"`
+	SenderID       string  `json:"senderId"`
+	SenderName     string  `json:"senderName"`
+	ReporterID     string  `json:"reporterId"`
+	ReporterName   string  `json:"reporterName"`
+	Reason         string  `json:"reason"`
+	Status         string  `json:"status"`
+	ReportCount    int     `json:"reportCount"`
+	ResolvedBy     *string `json:"resolvedBy"`
+	ResolvedAt     *string `json:"resolvedAt"`
+	ResolutionNote *string `json:"resolutionNote"`
+	CreatedAt      string  `json:"createdAt"`
+}
+
+type ChatReportListDTO struct {
+	Data       []ChatReportDTO `json:"data"`
+	TotalItems int64           `json:"totalItems"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	TotalPages int             `json:"totalPages"`
+}
+
+type ResolveChatReportDTO struct {
+	Status        string `json:"status" binding:"required,oneof=dismissed actioned"`
+	Note          string `json:"note"`
+	RemoveMessage bool   `json:"removeMessage"`
+}
+
+type MuteChatMemberDTO struct {
+	UserID          string `json:"userId" binding:"required,uuid"`
+	DurationMinutes int    `json:"durationMinutes" binding:"required"`
+	Reason          string `json:"reason"`
+}
+
+type ChatMuteDTO struct {
+	RoomID     string  `json:"roomId"`
+	UserID     string  `json:"userId"`
+	MutedBy    string  `json:"mutedBy"`
+	Reason     *string `json:"reason"`
+	MutedUntil *string `json:"mutedUntil"`
+	IsMuted    bool    `json:"isMuted"`
+}
+
+type UpdateChatBannedWordsDTO struct {
+	Words []string `json:"words" binding:"required"`
+}
+
+type ChatBannedWordsDTO struct {
+	Words []string `json:"words"`
+}
+
+type UpdateChatRoomSettingsDTO struct {
+	NotifyLevel string     `json:"notifyLevel" binding:"required,oneof=all mentions none"`
+	MutedUntil  *time.Time `json:"mutedUntil"`
+}
+
+type ChatRoomSettingsDTO struct {
+	RoomID      string  `json:"roomId"`
+	NotifyLevel string  `json:"notifyLevel"`
+	MutedUntil  *string `json:"mutedUntil"`
+	IsDefault   bool    `json:"isDefault"`
+}
+
+type UpdateChatRetentionPolicyDTO struct {
+	RetentionDays *int `json:"retentionDays"`
+}
+
+type ChatRetentionPolicyDTO struct {
+	SchoolID           string  `json:"schoolId"`
+	RetentionDays      *int    `json:"retentionDays"`
+	UpdatedBy          *string `json:"updatedBy"`
+	UpdatedAt          *string `json:"updatedAt"`
+	LastPurgedAt       *string `json:"lastPurgedAt"`
+	LastPurgedMessages int64   `json:"lastPurgedMessages"`
+}
+
+type ChatTranscriptMessageDTO struct {
+	MessageID   string              `json:"messageId"`
+	SenderID    string              `json:"senderId"`
+	SenderName  string              `json:"senderName"`
+	SenderEmail string              `json:"senderEmail"`
+	Content     string              `json:"content"`
+	MessageType string              `json:"messageType"`
+	ReplyTo     *string             `json:"replyTo"`
+	CreatedAt   string              `json:"createdAt"`
+	EditedAt    *string             `json:"editedAt"`
+	DeletedAt   *string             `json:"deletedAt"`
+	DeletedBy   *string             `json:"deletedBy"`
+	Attachments []ChatAttachmentDTO `json:"attachments"`
+}
+
+type ChatTranscriptDTO struct {
+	RoomID       string                     `json:"roomId"`
+	RoomName     string                     `json:"roomName"`
+	RoomType     string                     `json:"roomType"`
+	RoomRefType  *string                    `json:"roomRefType"`
+	SchoolID     string                     `json:"schoolId"`
+	SchoolName   string                     `json:"schoolName"`
+	From         *string                    `json:"from"`
+	To           *string                    `json:"to"`
+	ExportedBy   string                     `json:"exportedBy"`
+	ExportedAt   string                     `json:"exportedAt"`
+	MessageCount int                        `json:"messageCount"`
+	Truncated    bool                       `json:"truncated"`
+	Messages     []ChatTranscriptMessageDTO `json:"messages"`
+}
+
+type UpdateChatPostingModeDTO struct {
+	PostingMode string `json:"postingMode" binding:"required,oneof=everyone moderators"`
+}
+
+type ChatPostingModeDTO struct {
+	RoomID      string `json:"roomId"`
+	PostingMode string `json:"postingMode"`
+}
+
+type ChatPinnedMessageDTO struct {
+	Message      ChatMessageDTO `json:"message"`
+	PinnedBy     string         `json:"pinnedBy"`
+	PinnedByName string         `json:"pinnedByName"`
+	PinnedAt     string         `json:"pinnedAt"`
+}
+
+type ChatPinnedMessagesDTO struct {
+	RoomID string                 `json:"roomId"`
+	Pins   []ChatPinnedMessageDTO `json:"pins"`
+}
+
+type ChatPinUpdatedDTO struct {
+	RoomID    string  `json:"roomId"`
+	MessageID string  `json:"messageId"`
+	IsPinned  bool    `json:"isPinned"`
+	PinnedBy  *string `json:"pinnedBy"`
+	PinnedAt  *string `json:"pinnedAt"`
+}
+
+type CreateChatScheduledMessageDTO struct {
+	CreateChatMessageDTO
+	SendAt time.Time `json:"sendAt" binding:"required"`
+}
+
+type UpdateChatScheduledMessageDTO struct {
+	Content *string    `json:"content"`
+	SendAt  *time.Time `json:"sendAt"`
+}
+
+type ChatScheduledMessageDTO struct {
+	ScheduledID string   `json:"scheduledId"`
+	RoomID      string   `json:"roomId"`
+	Content     string   `json:"content"`
+	MediaIDs    []string `json:"mediaIds"`
+	ReplyTo     *string  `json:"replyTo"`
+	RefType     *string  `json:"refType"`
+	RefID       *string  `json:"refId"`
+	SendAt      string   `json:"sendAt"`
+	Status      string   `json:"status"`
+	MessageID   *string  `json:"messageId"`
+	Error       *string  `json:"error"`
+	SentAt      *string  `json:"sentAt"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+}
+
+type ChatScheduledMessagesDTO struct {
+	ScheduledMessages []ChatScheduledMessageDTO `json:"scheduledMessages"`
+}
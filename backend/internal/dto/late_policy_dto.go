@@ -0,0 +1,49 @@
+package dto
+
+// LatePolicyDTO configures the late penalty of an assignment or category.
+// PercentPerDay is deducted from the score for every started day late after
+// the grace period, up to MaxPercent. Work more than ZeroAfterDays days late
+// scores zero.
+type LatePolicyDTO struct {
+	PercentPerDay *float64 `json:"percentPerDay" binding:"required,min=0,max=100"`
+	MaxPercent    *float64 `json:"maxPercent" binding:"omitempty,min=0,max=100"`
+	GraceMinutes  int      `json:"graceMinutes" binding:"min=0,max=10080"`
+	ZeroAfterDays *int     `json:"zeroAfterDays" binding:"omitempty,min=0,max=365"`
+}
+
+// LatePolicyResponseDTO carries Source "assignment" or "category".
+type LatePolicyResponseDTO struct {
+	PolicyID      string   `json:"policyId"`
+	Source        string   `json:"source"`
+	AssignmentID  *string  `json:"assignmentId,omitempty"`
+	CategoryID    *string  `json:"categoryId,omitempty"`
+	PercentPerDay float64  `json:"percentPerDay"`
+	MaxPercent    *float64 `json:"maxPercent"`
+	GraceMinutes  int      `json:"graceMinutes"`
+	ZeroAfterDays *int     `json:"zeroAfterDays"`
+	UpdatedAt     string   `json:"updatedAt"`
+}
+
+type LatePolicyEnvelopeDTO struct {
+	Policy *LatePolicyResponseDTO `json:"policy"`
+}
+
+// LatePenaltyDTO is the penalty of one late submission. Percent is reported
+// even when the penalty is waived; FinalScore is the score that counts.
+type LatePenaltyDTO struct {
+	DaysLate   int      `json:"daysLate"`
+	Percent    float64  `json:"percent"`
+	Waived     bool     `json:"waived"`
+	RawScore   *float64 `json:"rawScore,omitempty"`
+	FinalScore *float64 `json:"finalScore,omitempty"`
+}
+
+type WaiveLatePenaltyDTO struct {
+	Waived *bool `json:"waived" binding:"required"`
+}
+
+type LatePenaltyWaiverResponseDTO struct {
+	SubmissionID  string          `json:"submissionId"`
+	PenaltyWaived bool            `json:"penaltyWaived"`
+	LatePenalty   *LatePenaltyDTO `json:"latePenalty"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type CreateMaterialDTO struct {
 	SchoolID       string              `json:"schoolId" binding:"required,uuid"`
 	SubjectClassID string              `json:"subjectClassId" binding:"required,uuid"`
@@ -8,6 +10,10 @@ type CreateMaterialDTO struct {
 	Type           string              `json:"materialType" binding:"required,oneof=video pdf ppt other"`
 	MediaIDs       []string            `json:"mediaIds"` // Existing media IDs
 	Medias         []CreateMediaInline `json:"medias"`   // New media to create
+	// PublishStatus defaults to published, or scheduled when PublishAt lies
+	// in the future.
+	PublishStatus string     `json:"publishStatus" binding:"omitempty,oneof=draft scheduled published"`
+	PublishAt     *time.Time `json:"publishAt"`
 }
 
 type CreateMediaInline struct {
@@ -19,10 +25,12 @@ type CreateMediaInline struct {
 }
 
 type UpdateMaterialDTO struct {
-	Title       *string  `json:"materialTitle"`
-	Description *string  `json:"materialDesc"`
-	Type        *string  `json:"materialType" binding:"omitempty,oneof=video pdf ppt other"`
-	MediaIDs    []string `json:"mediaIds"`
+	Title         *string    `json:"materialTitle"`
+	Description   *string    `json:"materialDesc"`
+	Type          *string    `json:"materialType" binding:"omitempty,oneof=video pdf ppt other"`
+	MediaIDs      []string   `json:"mediaIds"`
+	PublishStatus *string    `json:"publishStatus" binding:"omitempty,oneof=draft scheduled published"`
+	PublishAt     *time.Time `json:"publishAt"`
 }
 
 type MaterialResponseDTO struct {
@@ -34,6 +42,9 @@ type MaterialResponseDTO struct {
 	Description    string             `json:"materialDesc"`
 	Type           string             `json:"materialType"`
 	CreatorName    string             `json:"creatorName,omitempty"`
+	PublishStatus  string             `json:"publishStatus"`
+	PublishAt      *time.Time         `json:"publishAt,omitempty"`
+	PublishedAt    *time.Time         `json:"publishedAt,omitempty"`
 	CreatedAt      string             `json:"createdAt"`
 	Attachments    []MediaResponseDTO `json:"attachments,omitempty"`
 }
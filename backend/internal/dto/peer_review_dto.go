@@ -0,0 +1,113 @@
+package dto
+
+import "time"
+
+// PeerReviewSettingsDTO turns on the peer review of an assignment. Once the
+// reviews are allocated the form and the number of reviewers are locked;
+// the review deadline and the completion weight stay editable.
+type PeerReviewSettingsDTO struct {
+	ReviewersPerSubmission int                          `json:"reviewersPerSubmission" binding:"required,min=1,max=10"`
+	ReviewDeadline         *time.Time                   `json:"reviewDeadline" binding:"required"`
+	CompletionWeight       *float64                     `json:"completionWeight" binding:"omitempty,min=0,max=100"`
+	Questions              []PeerReviewQuestionInputDTO `json:"questions" binding:"required,min=1,max=30,dive"`
+}
+
+// PeerReviewQuestionInputDTO is a form item. Rating questions are scored
+// from 1 to MaxRating, which defaults to 5.
+type PeerReviewQuestionInputDTO struct {
+	Prompt    string `json:"prompt" binding:"required,max=1000"`
+	Type      string `json:"type" binding:"required,oneof=rating text"`
+	MaxRating *int   `json:"maxRating" binding:"omitempty,min=2,max=10"`
+	Required  bool   `json:"required"`
+}
+
+type PeerReviewQuestionResponseDTO struct {
+	QuestionID string `json:"questionId"`
+	Position   int    `json:"position"`
+	Prompt     string `json:"prompt"`
+	Type       string `json:"type"`
+	MaxRating  *int   `json:"maxRating,omitempty"`
+	Required   bool   `json:"required"`
+}
+
+type PeerReviewSettingsResponseDTO struct {
+	AssignmentID           string                          `json:"assignmentId"`
+	ReviewersPerSubmission int                             `json:"reviewersPerSubmission"`
+	ReviewDeadline         string                          `json:"reviewDeadline"`
+	CompletionWeight       float64                         `json:"completionWeight"`
+	AllocatedAt            *string                         `json:"allocatedAt"`
+	Questions              []PeerReviewQuestionResponseDTO `json:"questions"`
+	UpdatedAt              string                          `json:"updatedAt"`
+}
+
+type PeerReviewSettingsEnvelopeDTO struct {
+	PeerReview *PeerReviewSettingsResponseDTO `json:"peerReview"`
+}
+
+type PeerReviewAllocationResponseDTO struct {
+	AssignmentID string `json:"assignmentId"`
+	AllocatedAt  string `json:"allocatedAt"`
+	ReviewCount  int    `json:"reviewCount"`
+}
+
+type PeerReviewAnswerDTO struct {
+	QuestionID string  `json:"questionId" binding:"required,uuid"`
+	Rating     *int    `json:"rating,omitempty"`
+	Text       *string `json:"text,omitempty" binding:"omitempty,max=5000"`
+}
+
+type SubmitPeerReviewDTO struct {
+	Answers []PeerReviewAnswerDTO `json:"answers" binding:"required,dive"`
+}
+
+type PeerReviewPersonDTO struct {
+	UserID   string `json:"userId"`
+	FullName string `json:"fullName"`
+}
+
+// PeerReviewSubmissionDTO is the reviewed work as shown to its reviewer,
+// without anything that identifies the author.
+type PeerReviewSubmissionDTO struct {
+	Attachments []MediaResponseDTO `json:"attachments"`
+}
+
+// PeerReviewResponseDTO is one review. Students get it without SubmissionID,
+// Author and Reviewer, so reviews stay anonymous both ways.
+type PeerReviewResponseDTO struct {
+	ReviewID     string                   `json:"reviewId"`
+	Status       string                   `json:"status"`
+	SubmittedAt  *string                  `json:"submittedAt"`
+	CanEdit      bool                     `json:"canEdit"`
+	SubmissionID string                   `json:"submissionId,omitempty"`
+	Author       *PeerReviewPersonDTO     `json:"author,omitempty"`
+	Reviewer     *PeerReviewPersonDTO     `json:"reviewer,omitempty"`
+	Submission   *PeerReviewSubmissionDTO `json:"submission,omitempty"`
+	Answers      []PeerReviewAnswerDTO    `json:"answers"`
+}
+
+type PeerReviewListResponseDTO struct {
+	ReviewDeadline string                          `json:"reviewDeadline"`
+	Questions      []PeerReviewQuestionResponseDTO `json:"questions"`
+	Reviews        []PeerReviewResponseDTO         `json:"reviews"`
+}
+
+type PeerReviewDetailResponseDTO struct {
+	ReviewDeadline string                          `json:"reviewDeadline"`
+	Questions      []PeerReviewQuestionResponseDTO `json:"questions"`
+	Review         PeerReviewResponseDTO           `json:"review"`
+}
+
+// PeerReviewCompletionDTO is how many of a student's assigned reviews were
+// submitted. Weight percent of the assignment score comes from this rate.
+type PeerReviewCompletionDTO struct {
+	Assigned  int     `json:"assigned"`
+	Completed int     `json:"completed"`
+	Weight    float64 `json:"weight"`
+}
+
+type PeerReviewCompletionRow struct {
+	AssignmentID string  `gorm:"column:assignment_id"`
+	Weight       float64 `gorm:"column:weight"`
+	Assigned     int     `gorm:"column:assigned"`
+	Completed    int     `gorm:"column:completed"`
+}
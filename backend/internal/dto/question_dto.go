@@ -0,0 +1,44 @@
+package dto
+
+type QuestionOptionInputDTO struct {
+	Text      string `json:"text" binding:"required,max=500"`
+	IsCorrect bool   `json:"isCorrect"`
+}
+
+// CreateQuestionDTO is also used to replace a question. Only the answer
+// fields of the question type are read.
+type CreateQuestionDTO struct {
+	SubjectID        string                   `json:"subjectId" binding:"required,uuid"`
+	Type             string                   `json:"type" binding:"required,oneof=multiple_choice multi_select true_false short_answer numeric essay"`
+	Prompt           string                   `json:"prompt" binding:"required,max=5000"`
+	Points           float64                  `json:"points" binding:"required,gt=0,max=1000"`
+	Options          []QuestionOptionInputDTO `json:"options" binding:"omitempty,max=10,dive"`
+	BooleanAnswer    *bool                    `json:"booleanAnswer"`
+	AcceptedAnswers  []string                 `json:"acceptedAnswers" binding:"omitempty,max=20,dive,max=500"`
+	CaseSensitive    bool                     `json:"caseSensitive"`
+	NumericAnswer    *float64                 `json:"numericAnswer"`
+	NumericTolerance float64                  `json:"numericTolerance" binding:"min=0"`
+}
+
+type QuestionOptionDTO struct {
+	OptionID  string `json:"optionId"`
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"isCorrect"`
+}
+
+type QuestionResponseDTO struct {
+	QuestionID       string              `json:"questionId"`
+	SubjectID        string              `json:"subjectId"`
+	Type             string              `json:"type"`
+	Prompt           string              `json:"prompt"`
+	Points           float64             `json:"points"`
+	Options          []QuestionOptionDTO `json:"options"`
+	BooleanAnswer    *bool               `json:"booleanAnswer,omitempty"`
+	AcceptedAnswers  []string            `json:"acceptedAnswers,omitempty"`
+	CaseSensitive    bool                `json:"caseSensitive"`
+	NumericAnswer    *float64            `json:"numericAnswer,omitempty"`
+	NumericTolerance float64             `json:"numericTolerance"`
+	CreatedBy        string              `json:"createdBy"`
+	CreatedAt        string              `json:"createdAt"`
+	UpdatedAt        string              `json:"updatedAt"`
+}
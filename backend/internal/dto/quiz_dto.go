@@ -0,0 +1,97 @@
+package dto
+
+// QuizSettingsDTO sets up a quiz assignment. The questions come from the
+// question bank of the assignment's subject and are asked in this order
+// unless shuffled.
+type QuizSettingsDTO struct {
+	TimeLimitMinutes *int     `json:"timeLimitMinutes" binding:"omitempty,min=1,max=600"`
+	ShuffleQuestions bool     `json:"shuffleQuestions"`
+	ShuffleOptions   bool     `json:"shuffleOptions"`
+	QuestionIDs      []string `json:"questionIds" binding:"required,min=1,max=100,dive,uuid"`
+}
+
+type QuizResponseDTO struct {
+	AssignmentID     string                `json:"assignmentId"`
+	TimeLimitMinutes *int                  `json:"timeLimitMinutes"`
+	ShuffleQuestions bool                  `json:"shuffleQuestions"`
+	ShuffleOptions   bool                  `json:"shuffleOptions"`
+	TotalPoints      float64               `json:"totalPoints"`
+	AttemptCount     int64                 `json:"attemptCount"`
+	Questions        []QuestionResponseDTO `json:"questions"`
+}
+
+type QuizAnswerInputDTO struct {
+	QuestionID string   `json:"questionId" binding:"required,uuid"`
+	OptionIDs  []string `json:"optionIds" binding:"omitempty,max=10,dive,uuid"`
+	Text       *string  `json:"text" binding:"omitempty,max=10000"`
+	Number     *float64 `json:"number"`
+	Boolean    *bool    `json:"boolean"`
+}
+
+type SaveQuizAnswersDTO struct {
+	Answers []QuizAnswerInputDTO `json:"answers" binding:"required,min=1,max=100,dive"`
+}
+
+type QuizAnswerGradeInputDTO struct {
+	QuestionID string   `json:"questionId" binding:"required,uuid"`
+	Points     *float64 `json:"points" binding:"required,min=0"`
+	Feedback   string   `json:"feedback" binding:"max=2000"`
+}
+
+type GradeQuizAttemptDTO struct {
+	Answers  []QuizAnswerGradeInputDTO `json:"answers" binding:"required,min=1,max=100,dive"`
+	Feedback *string                   `json:"feedback" binding:"omitempty,max=5000"`
+}
+
+// QuizAttemptOptionDTO carries isCorrect only in the teacher view.
+type QuizAttemptOptionDTO struct {
+	OptionID  string `json:"optionId"`
+	Text      string `json:"text"`
+	IsCorrect *bool  `json:"isCorrect,omitempty"`
+}
+
+type QuizAnswerDTO struct {
+	OptionIDs []string `json:"optionIds"`
+	Text      *string  `json:"text"`
+	Number    *float64 `json:"number"`
+	Boolean   *bool    `json:"boolean"`
+	Points    *float64 `json:"points"`
+	IsCorrect *bool    `json:"isCorrect"`
+	Feedback  *string  `json:"feedback"`
+}
+
+// QuizAttemptQuestionDTO is a question as asked in one attempt. The answer
+// key fields are only filled in the teacher view.
+type QuizAttemptQuestionDTO struct {
+	QuestionID       string                 `json:"questionId"`
+	Type             string                 `json:"type"`
+	Prompt           string                 `json:"prompt"`
+	Points           float64                `json:"points"`
+	Options          []QuizAttemptOptionDTO `json:"options"`
+	Answer           *QuizAnswerDTO         `json:"answer"`
+	BooleanAnswer    *bool                  `json:"booleanAnswer,omitempty"`
+	AcceptedAnswers  []string               `json:"acceptedAnswers,omitempty"`
+	NumericAnswer    *float64               `json:"numericAnswer,omitempty"`
+	NumericTolerance *float64               `json:"numericTolerance,omitempty"`
+}
+
+type QuizAttemptResponseDTO struct {
+	AttemptID    string                   `json:"attemptId"`
+	AssignmentID string                   `json:"assignmentId"`
+	StudentID    string                   `json:"studentId"`
+	StudentName  string                   `json:"studentName"`
+	SubmissionID *string                  `json:"submissionId"`
+	Status       string                   `json:"status"`
+	StartedAt    string                   `json:"startedAt"`
+	ExpiresAt    *string                  `json:"expiresAt"`
+	SubmittedAt  *string                  `json:"submittedAt"`
+	EarnedPoints float64                  `json:"earnedPoints"`
+	MaxPoints    float64                  `json:"maxPoints"`
+	PendingCount int                      `json:"pendingCount"`
+	Score        *float64                 `json:"score"`
+	Questions    []QuizAttemptQuestionDTO `json:"questions,omitempty"`
+}
+
+type MyQuizAttemptResponseDTO struct {
+	Attempt *QuizAttemptResponseDTO `json:"attempt"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type FeedPostedEventDTO struct {
+	FeedID    string `json:"feedId"`
+	ClassID   string `json:"classId"`
+	ClassName string `json:"className"`
+	Content   string `json:"content"`
+	CreatedBy string `json:"createdBy"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type SubmissionGradedEventDTO struct {
+	SubmissionID string  `json:"submissionId"`
+	AssignmentID string  `json:"assignmentId"`
+	Score        float64 `json:"score"`
+	Feedback     string  `json:"feedback"`
+	AssessedAt   string  `json:"assessedAt"`
+}
@@ -0,0 +1,75 @@
+package dto
+
+type RubricLevelInputDTO struct {
+	Title       string  `json:"title" binding:"required,max=100"`
+	Description string  `json:"description" binding:"max=1000"`
+	Points      float64 `json:"points" binding:"min=0"`
+}
+
+type RubricCriterionInputDTO struct {
+	Title       string                `json:"title" binding:"required,max=200"`
+	Description string                `json:"description" binding:"max=1000"`
+	Levels      []RubricLevelInputDTO `json:"levels" binding:"required,min=1,max=10,dive"`
+}
+
+// CreateRubricDTO is also used to replace a rubric. Scope "school" shares the
+// rubric with every teacher of the school and needs the admin role.
+type CreateRubricDTO struct {
+	Title       string                    `json:"title" binding:"required,max=200"`
+	Description string                    `json:"description" binding:"max=2000"`
+	Scope       string                    `json:"scope" binding:"omitempty,oneof=personal school"`
+	Criteria    []RubricCriterionInputDTO `json:"criteria" binding:"required,min=1,max=20,dive"`
+}
+
+type RubricLevelDTO struct {
+	LevelID     string  `json:"levelId"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points"`
+}
+
+type RubricCriterionDTO struct {
+	CriterionID string           `json:"criterionId"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	MaxPoints   float64          `json:"maxPoints"`
+	Levels      []RubricLevelDTO `json:"levels"`
+}
+
+type RubricResponseDTO struct {
+	RubricID    string               `json:"rubricId"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Scope       string               `json:"scope"`
+	OwnerID     *string              `json:"ownerId"`
+	MaxPoints   float64              `json:"maxPoints"`
+	Criteria    []RubricCriterionDTO `json:"criteria"`
+	CreatedAt   string               `json:"createdAt"`
+	UpdatedAt   string               `json:"updatedAt"`
+}
+
+type AssessmentCriterionInputDTO struct {
+	CriterionID string `json:"criterionId" binding:"required,uuid"`
+	LevelID     string `json:"levelId" binding:"required,uuid"`
+	Comment     string `json:"comment" binding:"max=1000"`
+}
+
+type FilledRubricCriterionDTO struct {
+	CriterionID     string           `json:"criterionId"`
+	Title           string           `json:"title"`
+	Description     string           `json:"description"`
+	MaxPoints       float64          `json:"maxPoints"`
+	SelectedLevelID *string          `json:"selectedLevelId"`
+	Points          *float64         `json:"points"`
+	Comment         *string          `json:"comment"`
+	Levels          []RubricLevelDTO `json:"levels"`
+}
+
+// FilledRubricDTO is a rubric with the levels picked for one assessment.
+type FilledRubricDTO struct {
+	RubricID     string                     `json:"rubricId"`
+	Title        string                     `json:"title"`
+	EarnedPoints float64                    `json:"earnedPoints"`
+	MaxPoints    float64                    `json:"maxPoints"`
+	Criteria     []FilledRubricCriterionDTO `json:"criteria"`
+}
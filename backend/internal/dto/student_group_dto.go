@@ -0,0 +1,72 @@
+package dto
+
+// CreateStudentGroupDTO creates a group in a subject class. SelfJoin lets
+// students join and leave the group themselves, up to MaxSize members.
+type CreateStudentGroupDTO struct {
+	SubjectClassID string   `json:"subjectClassId" binding:"required,uuid"`
+	Name           string   `json:"name" binding:"required,max=100"`
+	MaxSize        *int     `json:"maxSize" binding:"omitempty,min=1,max=100"`
+	SelfJoin       bool     `json:"selfJoin"`
+	MemberIDs      []string `json:"memberIds" binding:"omitempty,max=100,dive,uuid"`
+}
+
+// UpdateStudentGroupDTO lifts the size limit when maxSize is 0.
+type UpdateStudentGroupDTO struct {
+	Name     *string `json:"name" binding:"omitempty,max=100"`
+	MaxSize  *int    `json:"maxSize" binding:"omitempty,min=0,max=100"`
+	SelfJoin *bool   `json:"selfJoin"`
+}
+
+type SetStudentGroupMembersDTO struct {
+	MemberIDs []string `json:"memberIds" binding:"max=100,dive,uuid"`
+}
+
+type StudentGroupMemberResponseDTO struct {
+	UserID      string `json:"userId"`
+	StudentName string `json:"studentName"`
+	JoinedAt    string `json:"joinedAt"`
+}
+
+type StudentGroupResponseDTO struct {
+	ID             string                          `json:"groupId"`
+	SubjectClassID string                          `json:"subjectClassId"`
+	Name           string                          `json:"name"`
+	MaxSize        *int                            `json:"maxSize"`
+	SelfJoin       bool                            `json:"selfJoin"`
+	MemberCount    int                             `json:"memberCount"`
+	Members        []StudentGroupMemberResponseDTO `json:"members"`
+	CreatedAt      string                          `json:"createdAt"`
+	UpdatedAt      string                          `json:"updatedAt"`
+}
+
+type SubjectClassStudentGroupsDTO struct {
+	SubjectClassID string                    `json:"subjectClassId"`
+	MyGroupID      *string                   `json:"myGroupId,omitempty"`
+	Groups         []StudentGroupResponseDTO `json:"groups"`
+}
+
+// SubmissionGroupMemberDTO is a student a group submission counts for. Score
+// is the group's score with the member's adjustment applied.
+type SubmissionGroupMemberDTO struct {
+	UserID          string   `json:"userId"`
+	StudentName     string   `json:"studentName"`
+	ScoreAdjustment float64  `json:"scoreAdjustment"`
+	AdjustmentNote  string   `json:"adjustmentNote"`
+	Score           *float64 `json:"score"`
+}
+
+type SubmissionGroupDTO struct {
+	GroupID   string                     `json:"groupId"`
+	GroupName string                     `json:"groupName"`
+	Members   []SubmissionGroupMemberDTO `json:"members,omitempty"`
+}
+
+type GroupScoreAdjustmentDTO struct {
+	UserID     string   `json:"userId" binding:"required,uuid"`
+	Adjustment *float64 `json:"adjustment" binding:"required,min=-100,max=100"`
+	Note       string   `json:"note" binding:"max=500"`
+}
+
+type UpdateGroupScoreAdjustmentsDTO struct {
+	Members []GroupScoreAdjustmentDTO `json:"members" binding:"required,min=1,max=100,dive"`
+}
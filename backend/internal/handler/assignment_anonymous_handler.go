@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"backend/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *AssignmentHandler) ReleaseIdentities(c *gin.Context) {
+	assignment, err := h.service.GetAssignmentByID(c.Param("assignmentId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if !h.authorizeAssignmentMutation(c, assignment) {
+		return
+	}
+
+	if err := h.service.ReleaseIdentities(assignment); err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.IdentityReleaseResponseDTO{
+		AssignmentID:       assignment.ID,
+		IdentityReleasedAt: *assignment.IdentityReleasedAt,
+	})
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// anonymousAssignmentService serves one anonymously graded group submission
+// and its peer reviews; anonymization runs through the real service.
+type anonymousAssignmentService struct {
+	service.AssignmentService
+	assignment *domain.Assignment
+}
+
+func (s *anonymousAssignmentService) GetAssignmentByID(id string) (*domain.Assignment, error) {
+	asg := *s.assignment
+	return &asg, nil
+}
+
+func (s *anonymousAssignmentService) GetSubmissionByID(id string) (*domain.Submission, error) {
+	groupID := "group-1"
+	return &domain.Submission{
+		ID:           "submission-1",
+		SchoolID:     "school-1",
+		AssignmentID: s.assignment.ID,
+		UserID:       "student-1",
+		User:         domain.User{FullName: "Siswa Pertama"},
+		GroupID:      &groupID,
+		Group:        &domain.StudentGroup{Name: "Kelompok Merah"},
+		Members: []domain.GroupSubmissionMember{
+			{UserID: "student-1", User: domain.User{FullName: "Siswa Pertama"}},
+			{UserID: "student-2", User: domain.User{FullName: "Siswa Kedua"}},
+		},
+	}, nil
+}
+
+func (s *anonymousAssignmentService) ListSubmissionVersions(sbm *domain.Submission, assignment *domain.Assignment) ([]*domain.SubmissionVersion, error) {
+	return []*domain.SubmissionVersion{}, nil
+}
+
+func (s *anonymousAssignmentService) UpdateGroupScoreAdjustments(sbm *domain.Submission, input []dto.GroupScoreAdjustmentDTO) error {
+	return nil
+}
+
+func (s *anonymousAssignmentService) GetPeerReview(assignment *domain.Assignment) (*domain.PeerReviewSetting, error) {
+	return &domain.PeerReviewSetting{AssignmentID: assignment.ID, ReviewersPerSubmission: 1, ReviewDeadline: time.Now()}, nil
+}
+
+func (s *anonymousAssignmentService) ListPeerReviews(assignment *domain.Assignment) ([]*domain.PeerReview, error) {
+	return []*domain.PeerReview{{
+		ID:           "review-1",
+		AssignmentID: assignment.ID,
+		SubmissionID: "submission-1",
+		Submission:   domain.Submission{UserID: "student-1", User: domain.User{FullName: "Siswa Pertama"}},
+		ReviewerID:   "student-2",
+		Reviewer:     domain.User{FullName: "Siswa Kedua"},
+		Status:       "submitted",
+	}}, nil
+}
+
+type teacherSubjectClassService struct {
+	service.SubjectClassService
+}
+
+func (teacherSubjectClassService) TeacherOwnsSubjectClass(userID string, schoolID string, subjectClassID string) (bool, error) {
+	return true, nil
+}
+
+func TestAnonymousGradingHidesStudentNames(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	assignments := &anonymousAssignmentService{
+		AssignmentService: service.NewAssignmentService(nil, nil, nil, nil, nil, nil, nil, nil, nil),
+		assignment: &domain.Assignment{
+			ID:               "assignment-1",
+			SchoolID:         "school-1",
+			SubjectClassID:   "class-1",
+			AnonymousGrading: true,
+			AnonymousKey:     "key",
+		},
+	}
+	h := NewAssignmentHandler(assignments, nil, teacherSubjectClassService{})
+
+	requests := []struct {
+		name    string
+		method  string
+		body    string
+		params  gin.Params
+		handler gin.HandlerFunc
+	}{
+		{"versions", http.MethodGet, "", gin.Params{{Key: "submissionId", Value: "submission-1"}}, h.GetSubmissionVersions},
+		{"peer reviews", http.MethodGet, "", gin.Params{{Key: "assignmentId", Value: "assignment-1"}}, h.GetPeerReviews},
+		{"score adjustments", http.MethodPut, `{"members":[{"userId":"7b6f2f52-8d5e-4c4c-9f37-2d1a4b0c9e11","adjustment":5}]}`, gin.Params{{Key: "submissionId", Value: "submission-1"}}, h.UpdateGroupScoreAdjustments},
+	}
+	for _, tc := range requests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(tc.method, "/", strings.NewReader(tc.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Request.Header.Set("SchoolId", "school-1")
+			c.Params = tc.params
+			c.Set("user", jwt.MapClaims{"user_id": "teacher-1"})
+			c.Set("user_roles", []string{"teacher"})
+
+			tc.handler(c)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			body := w.Body.String()
+			for _, leak := range []string{"Siswa Pertama", "Siswa Kedua", "Kelompok Merah", "student-1", "student-2"} {
+				if strings.Contains(body, leak) {
+					t.Fatalf("expected %q to be hidden, got %s", leak, body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *AssignmentHandler) ExportGrades(c *gin.Context) {
+	assignment, ok := h.getBulkGradeAssignment(c)
+	if !ok {
+		return
+	}
+
+	content, err := h.service.ExportGradesCSV(assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"grades-%s.csv\"", assignment.ID))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+}
+
+func (h *AssignmentHandler) PreviewGradeImport(c *gin.Context) {
+	assignment, ok := h.getBulkGradeAssignment(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Grade file is required"})
+		return
+	}
+	openedFile, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Grade file could not be opened"})
+		return
+	}
+	defer openedFile.Close()
+
+	response, err := h.service.PreviewGradeImport(assignment, openedFile)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AssignmentHandler) CommitGradeImport(c *gin.Context) {
+	var input dto.GradeImportCommitRequestDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	assignment, ok := h.getBulkGradeAssignment(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.CommitGradeImport(assignment, input.Rows, middleware.GetUserID(c))
+	if err != nil {
+		if response != nil {
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AssignmentHandler) getBulkGradeAssignment(c *gin.Context) (*domain.Assignment, bool) {
+	assignment, err := h.service.GetAssignmentByID(c.Param("assignmentId"))
+	if err != nil {
+		HandleError(c, err)
+		return nil, false
+	}
+	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
+		return nil, false
+	}
+	return assignment, true
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func (h *AssignmentHandler) RequestExtension(c *gin.Context) {
+	var input dto.CreateAssignmentExtensionDTO
+	assignmentID := c.Param("assignmentId")
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	assignment, ok := h.getStudentAssignment(c, assignmentID)
+	if !ok {
+		return
+	}
+
+	ext, err := h.service.RequestExtension(assignment, userID, input.Reason)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapAssignmentExtension(ext))
+}
+
+func (h *AssignmentHandler) GetMyExtension(c *gin.Context) {
+	assignmentID := c.Param("assignmentId")
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if _, ok := h.getStudentAssignment(c, assignmentID); !ok {
+		return
+	}
+
+	ext, err := h.service.GetMyExtension(assignmentID, userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	response := dto.MyAssignmentExtensionResponseDTO{}
+	if ext != nil {
+		mapped := mapAssignmentExtension(ext)
+		response.Extension = &mapped
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AssignmentHandler) GetExtensionsByAssignment(c *gin.Context) {
+	assignmentID := c.Param("assignmentId")
+	assignment, err := h.service.GetAssignmentByID(assignmentID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
+		return
+	}
+
+	extensions, err := h.service.ListExtensions(assignmentID, c.Query("status"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	response := make([]dto.AssignmentExtensionResponseDTO, 0, len(extensions))
+	for _, ext := range extensions {
+		response = append(response, mapAssignmentExtension(ext))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AssignmentHandler) ReviewExtension(c *gin.Context) {
+	var input dto.ReviewAssignmentExtensionDTO
+	extensionID := c.Param("extensionId")
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ext, err := h.service.GetExtensionByID(extensionID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	assignment, err := h.service.GetAssignmentByID(ext.AssignmentID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
+		return
+	}
+
+	reviewed, err := h.service.ReviewExtension(ext, assignment, userID, input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapAssignmentExtension(reviewed))
+}
+
+// getStudentAssignment loads an assignment of the active school that the
+// current student is enrolled in.
+func (h *AssignmentHandler) getStudentAssignment(c *gin.Context, assignmentID string) (*domain.Assignment, bool) {
+	schoolID := h.getSchoolContext(c)
+	if schoolID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required (SchoolId header)"})
+		return nil, false
+	}
+
+	assignment, err := h.service.GetAssignmentByID(assignmentID)
+	if err != nil {
+		HandleError(c, err)
+		return nil, false
+	}
+	if assignment.SchoolID != schoolID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: assignment does not belong to active school"})
+		return nil, false
+	}
+	if !h.authorizeStudentForSubjectClass(c, assignment.SubjectClassID) {
+		return nil, false
+	}
+	if assignment.PublishStatus != domain.PublishStatusPublished {
+		HandleError(c, gorm.ErrRecordNotFound)
+		return nil, false
+	}
+	return assignment, true
+}
+
+func mapAssignmentExtension(ext *domain.AssignmentExtension) dto.AssignmentExtensionResponseDTO {
+	response := dto.AssignmentExtensionResponseDTO{
+		ID:               ext.ID,
+		AssignmentID:     ext.AssignmentID,
+		StudentID:        ext.UserID,
+		StudentName:      ext.User.FullName,
+		Reason:           ext.Reason,
+		Status:           ext.Status,
+		ExtendedDeadline: ext.ExtendedDeadline,
+		ReviewNote:       ext.ReviewNote,
+		CreatedAt:        formatAPITime(ext.CreatedAt),
+	}
+	if ext.Reviewer != nil {
+		reviewerName := ext.Reviewer.FullName
+		response.ReviewerName = &reviewerName
+	}
+	if ext.ReviewedAt != nil {
+		reviewedAt := formatAPITime(*ext.ReviewedAt)
+		response.ReviewedAt = &reviewedAt
+	}
+	return response
+}
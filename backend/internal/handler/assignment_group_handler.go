@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *AssignmentHandler) UpdateGroupScoreAdjustments(c *gin.Context) {
+	var input dto.UpdateGroupScoreAdjustmentsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	submission, err := h.service.GetSubmissionByID(c.Param("submissionId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	assignment, err := h.service.GetAssignmentByID(submission.AssignmentID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
+		return
+	}
+
+	if err := h.service.UpdateGroupScoreAdjustments(submission, input.Members); err != nil {
+		HandleError(c, err)
+		return
+	}
+	submission, err = h.service.GetSubmissionByID(submission.ID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.service.AnonymizeSubmission(assignment, submission)
+	c.JSON(http.StatusOK, mapSubmissionGroup(submission, ""))
+}
+
+// mapSubmissionGroup describes the group of a group submission. Members are
+// listed when they are loaded; with viewerID set, only the viewer's own
+// adjustment and score are shown.
+func mapSubmissionGroup(s *domain.Submission, viewerID string) *dto.SubmissionGroupDTO {
+	if s.GroupID == nil {
+		return nil
+	}
+	group := &dto.SubmissionGroupDTO{GroupID: *s.GroupID}
+	if s.Group != nil {
+		group.GroupName = s.Group.Name
+	}
+	for _, member := range s.Members {
+		item := dto.SubmissionGroupMemberDTO{
+			UserID:      member.UserID,
+			StudentName: member.User.FullName,
+		}
+		if viewerID == "" || viewerID == member.UserID {
+			item.ScoreAdjustment = member.ScoreAdjustment
+			item.AdjustmentNote = member.AdjustmentNote
+			item.Score = member.Score
+		}
+		group.Members = append(group.Members, item)
+	}
+	return group
+}
@@ -7,8 +7,10 @@ import (
 	"backend/internal/service"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type AssignmentHandler struct {
@@ -113,10 +115,18 @@ func (h *AssignmentHandler) CreateAssignment(c *gin.Context) {
 		Description:         input.Description,
 		Deadline:            input.Deadline,
 		AllowLateSubmission: input.AllowLateSubmission,
+		RubricID:            input.RubricID,
+		Type:                input.Type,
+		MaxAttempts:         input.MaxAttempts,
+		GradingPolicy:       input.GradingPolicy,
+		GroupMode:           input.GroupMode,
+		PublishStatus:       input.PublishStatus,
+		PublishAt:           input.PublishAt,
+		AnonymousGrading:    input.AnonymousGrading,
 		CreatedBy:           userID,
 	}
 
-	if err := h.service.CreateAssignment(&asg, input.MediaIDs, userID, h.hasActiveRole(c, "admin")); err != nil {
+	if err := h.service.CreateAssignment(&asg, input.MediaIDs, input.Quiz, userID, h.hasActiveRole(c, "admin")); err != nil {
 		HandleError(c, err)
 		return
 	}
@@ -158,6 +168,34 @@ func (h *AssignmentHandler) UpdateAssignment(c *gin.Context) {
 	if input.AllowLateSubmission != nil {
 		existing.AllowLateSubmission = *input.AllowLateSubmission
 	}
+	if input.RubricID != nil {
+		existing.RubricID = input.RubricID
+		if *input.RubricID == "" {
+			existing.RubricID = nil
+		}
+	}
+	if input.MaxAttempts != nil {
+		existing.MaxAttempts = input.MaxAttempts
+		if *input.MaxAttempts == 0 {
+			existing.MaxAttempts = nil
+		}
+	}
+	if input.GradingPolicy != nil {
+		existing.GradingPolicy = *input.GradingPolicy
+	}
+	if input.GroupMode != nil {
+		existing.GroupMode = *input.GroupMode
+	}
+	if input.AnonymousGrading != nil {
+		existing.AnonymousGrading = *input.AnonymousGrading
+	}
+	if input.PublishStatus != nil || input.PublishAt != nil {
+		existing.PublishStatus = ""
+		if input.PublishStatus != nil {
+			existing.PublishStatus = *input.PublishStatus
+		}
+		existing.PublishAt = input.PublishAt
+	}
 
 	if err := h.service.UpdateAssignment(id, existing, input.MediaIDs, middleware.GetUserID(c), h.hasActiveRole(c, "admin"), input.CategoryID != nil); err != nil {
 		HandleError(c, err)
@@ -202,8 +240,13 @@ func (h *AssignmentHandler) GetBySubjectClass(c *gin.Context) {
 		return
 	}
 
-	// 2. Get Assignments
-	results, total, err := h.service.GetAssignmentsBySubjectClass(subjectClassID, search, page, limit)
+	// 2. Get Assignments; students only see published ones
+	includeUnpublished, err := h.canSeeUnpublished(c, subjectClassID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	results, total, err := h.service.GetAssignmentsBySubjectClass(subjectClassID, search, !includeUnpublished, page, limit)
 	if err != nil {
 		HandleError(c, err)
 		return
@@ -257,6 +300,29 @@ func (h *AssignmentHandler) GetStudentAssignmentDetail(c *gin.Context) {
 	if !h.authorizeStudentForSubjectClass(c, assignment.SubjectClassID) {
 		return
 	}
+	if assignment.PublishStatus != domain.PublishStatusPublished {
+		HandleError(c, gorm.ErrRecordNotFound)
+		return
+	}
+	deadline, err := h.service.GetEffectiveDeadline(assignment, middleware.GetUserID(c))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	var extendedDeadline *time.Time
+	if deadline != nil && assignment.Deadline != nil && deadline.After(*assignment.Deadline) {
+		extendedDeadline = deadline
+	}
+	rubric, err := h.service.GetAssignmentRubric(assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	var rubricResponse *dto.RubricResponseDTO
+	if rubric != nil {
+		mapped := mapRubricResponse(rubric)
+		rubricResponse = &mapped
+	}
 
 	attachments := make([]dto.MediaResponseDTO, 0, len(assignment.Attachments))
 	for _, attachment := range assignment.Attachments {
@@ -274,11 +340,18 @@ func (h *AssignmentHandler) GetStudentAssignmentDetail(c *gin.Context) {
 		Title:               assignment.Title,
 		Description:         assignment.Description,
 		CategoryName:        assignment.Category.Name,
+		Type:                assignment.Type,
+		TimeLimitMinutes:    assignment.TimeLimitMinutes,
+		MaxAttempts:         assignment.MaxAttempts,
+		GradingPolicy:       assignment.GradingPolicy,
+		GroupMode:           assignment.GroupMode,
 		Deadline:            assignment.Deadline,
+		ExtendedDeadline:    extendedDeadline,
 		AllowLateSubmission: assignment.AllowLateSubmission,
 		CreatedAt:           formatAPITime(assignment.CreatedAt),
 		UpdatedAt:           formatAPITime(assignment.UpdatedAt),
 		Attachments:         attachments,
+		Rubric:              rubricResponse,
 	})
 }
 
@@ -353,6 +426,7 @@ func (h *AssignmentHandler) GetSubjectClassSubmissions(c *gin.Context) {
 		response.Summary.AssignmentCount++
 
 		for _, submission := range asg.Submissions {
+			h.service.AnonymizeSubmission(asg, &submission)
 			var assessmentDTO *dto.AssessmentResponseDTO
 			if submission.Assessment != nil {
 				assessmentDTO = &dto.AssessmentResponseDTO{
@@ -368,7 +442,7 @@ func (h *AssignmentHandler) GetSubjectClassSubmissions(c *gin.Context) {
 				response.Summary.PendingCount++
 			}
 
-			isLate := asg.Deadline != nil && submission.SubmittedAt.After(*asg.Deadline)
+			isLate := submission.IsLate
 			if isLate {
 				response.Summary.LateCount++
 			}
@@ -385,6 +459,7 @@ func (h *AssignmentHandler) GetSubjectClassSubmissions(c *gin.Context) {
 				UserName:    submission.User.FullName,
 				SubmittedAt: formatAPITime(submission.SubmittedAt),
 				IsLate:      isLate,
+				Group:       mapSubmissionGroup(&submission, ""),
 				Attachments: atts,
 				Assessment:  assessmentDTO,
 			})
@@ -477,6 +552,7 @@ func (h *AssignmentHandler) GetSubmissionsByAssignment(c *gin.Context) {
 
 	var submissionsDTO []dto.SubmissionResponseDTO
 	for _, s := range asg.Submissions {
+		h.service.AnonymizeSubmission(asg, &s)
 		var assessmentDTO *dto.AssessmentResponseDTO
 		if s.Assessment != nil {
 			assessmentDTO = &dto.AssessmentResponseDTO{
@@ -498,7 +574,8 @@ func (h *AssignmentHandler) GetSubmissionsByAssignment(c *gin.Context) {
 			ID:          s.ID,
 			UserName:    s.User.FullName,
 			SubmittedAt: formatAPITime(s.SubmittedAt),
-			IsLate:      asg.Deadline != nil && s.SubmittedAt.After(*asg.Deadline),
+			IsLate:      s.IsLate,
+			Group:       mapSubmissionGroup(&s, ""),
 			Attachments: atts,
 			Assessment:  assessmentDTO,
 		})
@@ -564,6 +641,10 @@ func (h *AssignmentHandler) GetMySubmissionByAssignment(c *gin.Context) {
 	if !h.authorizeStudentForSubjectClass(c, assignment.SubjectClassID) {
 		return
 	}
+	if assignment.PublishStatus != domain.PublishStatusPublished {
+		HandleError(c, gorm.ErrRecordNotFound)
+		return
+	}
 
 	submission, err := h.service.GetMySubmissionByAssignment(assignmentID, userID, schoolID)
 	if err != nil {
@@ -583,10 +664,15 @@ func (h *AssignmentHandler) GetMySubmissionByAssignment(c *gin.Context) {
 	if submission.Assessment != nil {
 		status = "graded"
 	}
+	rubric, err := h.getAssessmentRubric(assignment, submission)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
 
 	c.JSON(http.StatusOK, dto.MySubmissionResponseDTO{
 		Status:     status,
-		Submission: h.mapMySubmissionToResponse(submission),
+		Submission: h.mapMySubmissionToResponse(submission, rubric, userID),
 	})
 }
 
@@ -702,6 +788,22 @@ func (h *AssignmentHandler) GetSubmissionByID(c *gin.Context) {
 	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
 		return
 	}
+	deadline, err := h.service.GetEffectiveDeadline(assignment, submission.UserID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	rubric, err := h.getAssessmentRubric(assignment, submission)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	penalty, err := h.service.GetLatePenalty(submission)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.service.AnonymizeSubmission(assignment, submission)
 
 	var assessmentDTO *dto.AssessmentResponseDTO
 	if submission.Assessment != nil {
@@ -710,6 +812,8 @@ func (h *AssignmentHandler) GetSubmissionByID(c *gin.Context) {
 			Feedback:   submission.Assessment.Feedback,
 			Assessor:   submission.Assessment.Assessor.FullName,
 			AssessedAt: formatAPITime(submission.Assessment.AssessedAt),
+			VersionID:  submission.Assessment.VersionID,
+			Rubric:     mapFilledRubric(rubric, submission.Assessment.RubricScores),
 		}
 	}
 
@@ -721,12 +825,15 @@ func (h *AssignmentHandler) GetSubmissionByID(c *gin.Context) {
 	}
 
 	response := dto.SubmissionResponseDTO{
-		ID:          submission.ID,
-		UserName:    submission.User.FullName,
-		SubmittedAt: formatAPITime(submission.SubmittedAt),
-		IsLate:      assignment.Deadline != nil && submission.SubmittedAt.After(*assignment.Deadline),
-		Attachments: atts,
-		Assessment:  assessmentDTO,
+		ID:           submission.ID,
+		UserName:     submission.User.FullName,
+		SubmittedAt:  formatAPITime(submission.SubmittedAt),
+		IsLate:       deadline != nil && submission.SubmittedAt.After(*deadline),
+		AttemptCount: submission.AttemptCount,
+		Group:        mapSubmissionGroup(submission, ""),
+		Attachments:  atts,
+		Assessment:   assessmentDTO,
+		LatePenalty:  penalty,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -745,18 +852,24 @@ func (h *AssignmentHandler) Assess(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
+	if input.Score == nil && len(input.Criteria) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Score or rubric criteria is required"})
+		return
+	}
 	if !h.authorizeTeacherForSubmission(c, submissionId) {
 		return
 	}
 
 	asm := domain.Assessment{
 		SubmissionID: submissionId,
-		Score:        input.Score,
 		Feedback:     input.Feedback,
 		AssessedBy:   userID,
 	}
+	if input.Score != nil {
+		asm.Score = *input.Score
+	}
 
-	if err := h.service.Assess(&asm); err != nil {
+	if err := h.service.Assess(&asm, input.Criteria, input.VersionID); err != nil {
 		HandleError(c, err)
 		return
 	}
@@ -786,7 +899,7 @@ func (h *AssignmentHandler) UpdateAssessment(c *gin.Context) {
 		asm.Feedback = *input.Feedback
 	}
 
-	if err := h.service.UpdateAssessment(submissionId, asm); err != nil {
+	if err := h.service.UpdateAssessment(submissionId, asm, input.Criteria); err != nil {
 		HandleError(c, err)
 		return
 	}
@@ -821,13 +934,33 @@ func (h *AssignmentHandler) mapAsgToResponse(a *domain.Assignment) dto.Assignmen
 		Title:               a.Title,
 		Description:         a.Description,
 		CategoryName:        a.Category.Name,
+		Type:                a.Type,
+		TimeLimitMinutes:    a.TimeLimitMinutes,
+		MaxAttempts:         a.MaxAttempts,
+		GradingPolicy:       a.GradingPolicy,
+		GroupMode:           a.GroupMode,
+		PublishStatus:       a.PublishStatus,
+		PublishAt:           a.PublishAt,
+		PublishedAt:         a.PublishedAt,
+		AnonymousGrading:    a.AnonymousGrading,
+		IdentityReleasedAt:  a.IdentityReleasedAt,
 		Deadline:            a.Deadline,
 		AllowLateSubmission: a.AllowLateSubmission,
+		RubricID:            a.RubricID,
 		CreatedAt:           formatAPITime(a.CreatedAt),
 		Attachments:         atts,
 	}
 }
 
+// getAssessmentRubric loads the assignment rubric only when the submission was
+// graded with it.
+func (h *AssignmentHandler) getAssessmentRubric(assignment *domain.Assignment, submission *domain.Submission) (*domain.Rubric, error) {
+	if submission.Assessment == nil || len(submission.Assessment.RubricScores) == 0 {
+		return nil, nil
+	}
+	return h.service.GetAssignmentRubric(assignment)
+}
+
 func (h *AssignmentHandler) getSchoolContext(c *gin.Context) string {
 	if sid, exists := c.Get("school_id"); exists {
 		if value, ok := sid.(string); ok {
@@ -941,6 +1074,18 @@ func (h *AssignmentHandler) authorizeAssignmentMutation(c *gin.Context, assignme
 	return false
 }
 
+// canSeeUnpublished reports whether the user may see draft and scheduled
+// items of the subject class: admins and the teacher of the class.
+func (h *AssignmentHandler) canSeeUnpublished(c *gin.Context, subjectClassID string) (bool, error) {
+	if h.hasActiveRole(c, "admin") {
+		return true, nil
+	}
+	if !h.hasActiveRole(c, "teacher") {
+		return false, nil
+	}
+	return h.subjectClassService.TeacherOwnsSubjectClass(middleware.GetUserID(c), h.getSchoolContext(c), subjectClassID)
+}
+
 func (h *AssignmentHandler) authorizeStudentForSubmission(c *gin.Context, submissionID string) bool {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
@@ -964,8 +1109,18 @@ func (h *AssignmentHandler) authorizeStudentForSubmission(c *gin.Context, submis
 		return false
 	}
 	if submission.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: submission belongs to another user"})
-		return false
+		member := false
+		if submission.GroupID != nil {
+			member, err = h.service.IsSubmissionGroupMember(submission, userID)
+			if err != nil {
+				HandleError(c, err)
+				return false
+			}
+		}
+		if !member {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: submission belongs to another user"})
+			return false
+		}
 	}
 
 	assignment, err := h.service.GetAssignmentByID(submission.AssignmentID)
@@ -1021,7 +1176,7 @@ func (h *AssignmentHandler) authorizeTeacherForSubjectClass(c *gin.Context, subj
 	return true
 }
 
-func (h *AssignmentHandler) mapMySubmissionToResponse(s *domain.Submission) *dto.MySubmissionDTO {
+func (h *AssignmentHandler) mapMySubmissionToResponse(s *domain.Submission, rubric *domain.Rubric, userID string) *dto.MySubmissionDTO {
 	atts := make([]dto.MediaResponseDTO, 0, len(s.Attachments))
 	for _, a := range s.Attachments {
 		if attachment, ok := mapAttachmentMedia(a, s.SchoolID); ok {
@@ -1037,6 +1192,7 @@ func (h *AssignmentHandler) mapMySubmissionToResponse(s *domain.Submission) *dto
 			Feedback:     s.Assessment.Feedback,
 			AssessedAt:   formatAPITime(s.Assessment.AssessedAt),
 			AssessorName: s.Assessment.Assessor.FullName,
+			Rubric:       mapFilledRubric(rubric, s.Assessment.RubricScores),
 		}
 	}
 
@@ -1044,6 +1200,8 @@ func (h *AssignmentHandler) mapMySubmissionToResponse(s *domain.Submission) *dto
 		ID:           s.ID,
 		AssignmentID: s.AssignmentID,
 		SubmittedAt:  formatAPITime(s.SubmittedAt),
+		AttemptCount: s.AttemptCount,
+		Group:        mapSubmissionGroup(s, userID),
 		Attachments:  atts,
 		Assessment:   assessment,
 	}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *AssignmentHandler) GetLatePolicy(c *gin.Context) {
+	assignment, ok := h.getLatePolicyAssignment(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.service.GetLatePolicy(assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.LatePolicyEnvelopeDTO{Policy: mapLatePolicy(policy)})
+}
+
+func (h *AssignmentHandler) SaveLatePolicy(c *gin.Context) {
+	var input dto.LatePolicyDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	assignment, ok := h.getLatePolicyAssignment(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.service.SaveAssignmentLatePolicy(assignment, input, middleware.GetUserID(c))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.LatePolicyEnvelopeDTO{Policy: mapLatePolicy(policy)})
+}
+
+func (h *AssignmentHandler) DeleteLatePolicy(c *gin.Context) {
+	assignment, ok := h.getLatePolicyAssignment(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAssignmentLatePolicy(assignment.ID); err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Late policy removed"})
+}
+
+func (h *AssignmentHandler) GetCategoryLatePolicy(c *gin.Context) {
+	schoolID := h.getSchoolContext(c)
+	if schoolID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required (SchoolId header)"})
+		return
+	}
+
+	policy, err := h.service.GetCategoryLatePolicy(c.Param("categoryId"), schoolID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.LatePolicyEnvelopeDTO{Policy: mapLatePolicy(policy)})
+}
+
+func (h *AssignmentHandler) SaveCategoryLatePolicy(c *gin.Context) {
+	var input dto.LatePolicyDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	schoolID := h.getSchoolContext(c)
+	if schoolID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required (SchoolId header)"})
+		return
+	}
+
+	policy, err := h.service.SaveCategoryLatePolicy(c.Param("categoryId"), schoolID, input, middleware.GetUserID(c))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.LatePolicyEnvelopeDTO{Policy: mapLatePolicy(policy)})
+}
+
+func (h *AssignmentHandler) DeleteCategoryLatePolicy(c *gin.Context) {
+	schoolID := h.getSchoolContext(c)
+	if schoolID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required (SchoolId header)"})
+		return
+	}
+
+	if err := h.service.DeleteCategoryLatePolicy(c.Param("categoryId"), schoolID); err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Late policy removed"})
+}
+
+func (h *AssignmentHandler) WaiveLatePenalty(c *gin.Context) {
+	var input dto.WaiveLatePenaltyDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	submission, err := h.service.GetSubmissionByID(c.Param("submissionId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	assignment, err := h.service.GetAssignmentByID(submission.AssignmentID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
+		return
+	}
+
+	if err := h.service.SetLatePenaltyWaiver(submission, *input.Waived, middleware.GetUserID(c)); err != nil {
+		HandleError(c, err)
+		return
+	}
+	penalty, err := h.service.GetLatePenalty(submission)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.LatePenaltyWaiverResponseDTO{
+		SubmissionID:  submission.ID,
+		PenaltyWaived: submission.PenaltyWaived,
+		LatePenalty:   penalty,
+	})
+}
+
+// getLatePolicyAssignment loads the assignment of the request for the
+// teachers of its subject class and school admins.
+func (h *AssignmentHandler) getLatePolicyAssignment(c *gin.Context) (*domain.Assignment, bool) {
+	assignment, err := h.service.GetAssignmentByID(c.Param("assignmentId"))
+	if err != nil {
+		HandleError(c, err)
+		return nil, false
+	}
+	if !h.authorizeAssignmentMutation(c, assignment) {
+		return nil, false
+	}
+	return assignment, true
+}
+
+func mapLatePolicy(policy *domain.LatePolicy) *dto.LatePolicyResponseDTO {
+	if policy == nil {
+		return nil
+	}
+	source := "category"
+	if policy.AssignmentID != nil {
+		source = "assignment"
+	}
+	return &dto.LatePolicyResponseDTO{
+		PolicyID:      policy.ID,
+		Source:        source,
+		AssignmentID:  policy.AssignmentID,
+		CategoryID:    policy.CategoryID,
+		PercentPerDay: policy.PercentPerDay,
+		MaxPercent:    policy.MaxPercent,
+		GraceMinutes:  policy.GraceMinutes,
+		ZeroAfterDays: policy.ZeroAfterDays,
+		UpdatedAt:     formatAPITime(policy.UpdatedAt),
+	}
+}
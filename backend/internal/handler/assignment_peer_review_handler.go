@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *AssignmentHandler) GetPeerReview(c *gin.Context) {
+	assignment, ok := h.getPeerReviewAssignment(c)
+	if !ok {
+		return
+	}
+
+	setting, err := h.service.GetPeerReview(assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PeerReviewSettingsEnvelopeDTO{PeerReview: mapPeerReviewSettings(setting)})
+}
+
+func (h *AssignmentHandler) SavePeerReview(c *gin.Context) {
+	var input dto.PeerReviewSettingsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	assignment, ok := h.getPeerReviewAssignment(c)
+	if !ok {
+		return
+	}
+
+	setting, err := h.service.SavePeerReview(assignment, input, middleware.GetUserID(c))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PeerReviewSettingsEnvelopeDTO{PeerReview: mapPeerReviewSettings(setting)})
+}
+
+func (h *AssignmentHandler) DeletePeerReview(c *gin.Context) {
+	assignment, ok := h.getPeerReviewAssignment(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePeerReview(assignment.ID); err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Peer review removed"})
+}
+
+func (h *AssignmentHandler) AllocatePeerReviews(c *gin.Context) {
+	assignment, ok := h.getPeerReviewAssignment(c)
+	if !ok {
+		return
+	}
+
+	setting, count, err := h.service.AllocatePeerReviews(assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PeerReviewAllocationResponseDTO{
+		AssignmentID: assignment.ID,
+		AllocatedAt:  formatAPITime(*setting.AllocatedAt),
+		ReviewCount:  count,
+	})
+}
+
+func (h *AssignmentHandler) GetPeerReviews(c *gin.Context) {
+	assignment, ok := h.getPeerReviewAssignment(c)
+	if !ok {
+		return
+	}
+	setting, ok := h.getEnabledPeerReview(c, assignment)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.service.ListPeerReviews(assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	response := newPeerReviewListResponse(setting)
+	for _, review := range reviews {
+		item := mapPeerReview(review, setting, false)
+		item.SubmissionID = review.SubmissionID
+		authorID, authorName := h.service.AnonymizeStudent(assignment, review.Submission.UserID, review.Submission.User.FullName)
+		reviewerID, reviewerName := h.service.AnonymizeStudent(assignment, review.ReviewerID, review.Reviewer.FullName)
+		item.Author = &dto.PeerReviewPersonDTO{UserID: authorID, FullName: authorName}
+		item.Reviewer = &dto.PeerReviewPersonDTO{UserID: reviewerID, FullName: reviewerName}
+		response.Reviews = append(response.Reviews, item)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AssignmentHandler) GetMyPeerReviews(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	assignment, ok := h.getStudentAssignment(c, c.Param("assignmentId"))
+	if !ok {
+		return
+	}
+	setting, ok := h.getEnabledPeerReview(c, assignment)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.service.ListMyPeerReviews(assignment, userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	response := newPeerReviewListResponse(setting)
+	for _, review := range reviews {
+		response.Reviews = append(response.Reviews, mapPeerReview(review, setting, true))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AssignmentHandler) GetPeerReviewDetail(c *gin.Context) {
+	review, assignment, setting, ok := h.getReviewerPeerReview(c)
+	if !ok {
+		return
+	}
+
+	submission, err := h.service.GetPeerReviewSubmission(review, middleware.GetUserID(c))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	item := mapPeerReview(review, setting, true)
+	item.Submission = &dto.PeerReviewSubmissionDTO{Attachments: []dto.MediaResponseDTO{}}
+	for _, a := range submission.Attachments {
+		if attachment, ok := mapAttachmentMedia(a, assignment.SchoolID); ok {
+			item.Submission.Attachments = append(item.Submission.Attachments, attachment)
+		}
+	}
+	c.JSON(http.StatusOK, dto.PeerReviewDetailResponseDTO{
+		ReviewDeadline: formatAPITime(setting.ReviewDeadline),
+		Questions:      mapPeerReviewQuestions(setting.Questions),
+		Review:         item,
+	})
+}
+
+func (h *AssignmentHandler) SubmitPeerReview(c *gin.Context) {
+	var input dto.SubmitPeerReviewDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	review, _, setting, ok := h.getReviewerPeerReview(c)
+	if !ok {
+		return
+	}
+
+	review, err := h.service.SubmitPeerReview(review, setting, middleware.GetUserID(c), input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PeerReviewDetailResponseDTO{
+		ReviewDeadline: formatAPITime(setting.ReviewDeadline),
+		Questions:      mapPeerReviewQuestions(setting.Questions),
+		Review:         mapPeerReview(review, setting, true),
+	})
+}
+
+func (h *AssignmentHandler) GetReceivedPeerReviews(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	assignment, ok := h.getStudentAssignment(c, c.Param("assignmentId"))
+	if !ok {
+		return
+	}
+	setting, ok := h.getEnabledPeerReview(c, assignment)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.service.ListReceivedPeerReviews(assignment, userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	response := newPeerReviewListResponse(setting)
+	for _, review := range reviews {
+		response.Reviews = append(response.Reviews, mapPeerReview(review, setting, false))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// getPeerReviewAssignment loads the assignment of the request for the
+// teachers of its subject class and school admins.
+func (h *AssignmentHandler) getPeerReviewAssignment(c *gin.Context) (*domain.Assignment, bool) {
+	assignment, err := h.service.GetAssignmentByID(c.Param("assignmentId"))
+	if err != nil {
+		HandleError(c, err)
+		return nil, false
+	}
+	if !h.authorizeAssignmentMutation(c, assignment) {
+		return nil, false
+	}
+	return assignment, true
+}
+
+func (h *AssignmentHandler) getEnabledPeerReview(c *gin.Context, assignment *domain.Assignment) (*domain.PeerReviewSetting, bool) {
+	setting, err := h.service.GetPeerReview(assignment)
+	if err != nil {
+		HandleError(c, err)
+		return nil, false
+	}
+	if setting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Peer review is not enabled for this assignment"})
+		return nil, false
+	}
+	return setting, true
+}
+
+// getReviewerPeerReview loads the review of the request for the student who
+// was assigned to write it.
+func (h *AssignmentHandler) getReviewerPeerReview(c *gin.Context) (*domain.PeerReview, *domain.Assignment, *domain.PeerReviewSetting, bool) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, nil, nil, false
+	}
+	review, err := h.service.GetPeerReviewByID(c.Param("reviewId"))
+	if err != nil {
+		HandleError(c, err)
+		return nil, nil, nil, false
+	}
+	if review.ReviewerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: peer review belongs to another student"})
+		return nil, nil, nil, false
+	}
+	assignment, ok := h.getStudentAssignment(c, review.AssignmentID)
+	if !ok {
+		return nil, nil, nil, false
+	}
+	setting, ok := h.getEnabledPeerReview(c, assignment)
+	if !ok {
+		return nil, nil, nil, false
+	}
+	return review, assignment, setting, true
+}
+
+func newPeerReviewListResponse(setting *domain.PeerReviewSetting) dto.PeerReviewListResponseDTO {
+	return dto.PeerReviewListResponseDTO{
+		ReviewDeadline: formatAPITime(setting.ReviewDeadline),
+		Questions:      mapPeerReviewQuestions(setting.Questions),
+		Reviews:        []dto.PeerReviewResponseDTO{},
+	}
+}
+
+// mapPeerReview maps a review without naming its author or reviewer.
+// CanEdit is only meaningful for the reviewer.
+func mapPeerReview(review *domain.PeerReview, setting *domain.PeerReviewSetting, forReviewer bool) dto.PeerReviewResponseDTO {
+	response := dto.PeerReviewResponseDTO{
+		ReviewID: review.ID,
+		Status:   review.Status,
+		CanEdit:  forReviewer && time.Now().Before(setting.ReviewDeadline),
+		Answers:  make([]dto.PeerReviewAnswerDTO, 0, len(review.Answers)),
+	}
+	if review.SubmittedAt != nil {
+		submittedAt := formatAPITime(*review.SubmittedAt)
+		response.SubmittedAt = &submittedAt
+	}
+	for _, answer := range review.Answers {
+		response.Answers = append(response.Answers, dto.PeerReviewAnswerDTO{
+			QuestionID: answer.QuestionID,
+			Rating:     answer.Rating,
+			Text:       answer.Text,
+		})
+	}
+	return response
+}
+
+func mapPeerReviewSettings(setting *domain.PeerReviewSetting) *dto.PeerReviewSettingsResponseDTO {
+	if setting == nil {
+		return nil
+	}
+	response := &dto.PeerReviewSettingsResponseDTO{
+		AssignmentID:           setting.AssignmentID,
+		ReviewersPerSubmission: setting.ReviewersPerSubmission,
+		ReviewDeadline:         formatAPITime(setting.ReviewDeadline),
+		CompletionWeight:       setting.CompletionWeight,
+		Questions:              mapPeerReviewQuestions(setting.Questions),
+		UpdatedAt:              formatAPITime(setting.UpdatedAt),
+	}
+	if setting.AllocatedAt != nil {
+		allocatedAt := formatAPITime(*setting.AllocatedAt)
+		response.AllocatedAt = &allocatedAt
+	}
+	return response
+}
+
+func mapPeerReviewQuestions(questions []domain.PeerReviewQuestion) []dto.PeerReviewQuestionResponseDTO {
+	response := make([]dto.PeerReviewQuestionResponseDTO, 0, len(questions))
+	for _, question := range questions {
+		response = append(response, dto.PeerReviewQuestionResponseDTO{
+			QuestionID: question.ID,
+			Position:   question.Position,
+			Prompt:     question.Prompt,
+			Type:       question.Type,
+			MaxRating:  question.MaxRating,
+			Required:   question.Required,
+		})
+	}
+	return response
+}
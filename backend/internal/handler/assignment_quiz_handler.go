@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *AssignmentHandler) GetQuiz(c *gin.Context) {
+	assignment, err := h.service.GetAssignmentByID(c.Param("assignmentId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
+		return
+	}
+
+	quiz, err := h.service.GetQuiz(assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quiz)
+}
+
+func (h *AssignmentHandler) ReplaceQuiz(c *gin.Context) {
+	var input dto.QuizSettingsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	assignment, err := h.service.GetAssignmentByID(c.Param("assignmentId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if !h.authorizeAssignmentMutation(c, assignment) {
+		return
+	}
+
+	quiz, err := h.service.ReplaceQuiz(assignment, input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quiz)
+}
+
+func (h *AssignmentHandler) GetQuizAttempts(c *gin.Context) {
+	assignment, err := h.service.GetAssignmentByID(c.Param("assignmentId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
+		return
+	}
+
+	attempts, err := h.service.ListQuizAttempts(assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attempts)
+}
+
+func (h *AssignmentHandler) GetQuizAttemptDetail(c *gin.Context) {
+	attempt, assignment, ok := h.getTeacherQuizAttempt(c, c.Param("attemptId"))
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetQuizAttemptResult(attempt, assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AssignmentHandler) GradeQuizAttempt(c *gin.Context) {
+	var input dto.GradeQuizAttemptDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	attempt, assignment, ok := h.getTeacherQuizAttempt(c, c.Param("attemptId"))
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GradeQuizAttempt(attempt, assignment, userID, input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AssignmentHandler) StartQuiz(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	assignment, ok := h.getStudentAssignment(c, c.Param("assignmentId"))
+	if !ok {
+		return
+	}
+
+	attempt, err := h.service.StartQuiz(assignment, userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attempt)
+}
+
+func (h *AssignmentHandler) GetMyQuizAttempt(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	assignment, ok := h.getStudentAssignment(c, c.Param("assignmentId"))
+	if !ok {
+		return
+	}
+
+	attempt, err := h.service.GetMyQuizAttempt(assignment, userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MyQuizAttemptResponseDTO{Attempt: attempt})
+}
+
+func (h *AssignmentHandler) SaveQuizAnswers(c *gin.Context) {
+	var input dto.SaveQuizAnswersDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	attempt, assignment, ok := h.getStudentQuizAttempt(c, c.Param("attemptId"))
+	if !ok {
+		return
+	}
+
+	result, err := h.service.SaveQuizAnswers(attempt, assignment, userID, input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AssignmentHandler) SubmitQuizAttempt(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	attempt, assignment, ok := h.getStudentQuizAttempt(c, c.Param("attemptId"))
+	if !ok {
+		return
+	}
+
+	result, err := h.service.SubmitQuizAttempt(attempt, assignment, userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// getTeacherQuizAttempt loads an attempt of a quiz taught by the current
+// teacher.
+func (h *AssignmentHandler) getTeacherQuizAttempt(c *gin.Context, attemptID string) (*domain.QuizAttempt, *domain.Assignment, bool) {
+	attempt, err := h.service.GetQuizAttemptByID(attemptID)
+	if err != nil {
+		HandleError(c, err)
+		return nil, nil, false
+	}
+	assignment, err := h.service.GetAssignmentByID(attempt.AssignmentID)
+	if err != nil {
+		HandleError(c, err)
+		return nil, nil, false
+	}
+	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
+		return nil, nil, false
+	}
+	return attempt, assignment, true
+}
+
+// getStudentQuizAttempt loads an attempt of a quiz the current student is
+// enrolled in; the service checks that the attempt is theirs.
+func (h *AssignmentHandler) getStudentQuizAttempt(c *gin.Context, attemptID string) (*domain.QuizAttempt, *domain.Assignment, bool) {
+	attempt, err := h.service.GetQuizAttemptByID(attemptID)
+	if err != nil {
+		HandleError(c, err)
+		return nil, nil, false
+	}
+	assignment, ok := h.getStudentAssignment(c, attempt.AssignmentID)
+	if !ok {
+		return nil, nil, false
+	}
+	return attempt, assignment, true
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *AssignmentHandler) GetSubmissionVersions(c *gin.Context) {
+	submission, err := h.service.GetSubmissionByID(c.Param("submissionId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	assignment, err := h.service.GetAssignmentByID(submission.AssignmentID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if !h.authorizeTeacherForSubjectClass(c, assignment.SubjectClassID) {
+		return
+	}
+
+	versions, err := h.service.ListSubmissionVersions(submission, assignment)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	h.service.AnonymizeSubmission(assignment, submission)
+
+	var countedID *string
+	if submission.Assessment != nil {
+		countedID = submission.Assessment.VersionID
+	}
+	response := dto.SubmissionVersionHistoryDTO{
+		SubmissionID:  submission.ID,
+		StudentName:   submission.User.FullName,
+		MaxAttempts:   assignment.MaxAttempts,
+		GradingPolicy: assignment.GradingPolicy,
+		Versions:      make([]dto.SubmissionVersionResponseDTO, 0, len(versions)),
+	}
+	for _, version := range versions {
+		response.Versions = append(response.Versions, mapSubmissionVersion(version, submission.SchoolID, countedID))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func mapSubmissionVersion(version *domain.SubmissionVersion, schoolID string, countedID *string) dto.SubmissionVersionResponseDTO {
+	response := dto.SubmissionVersionResponseDTO{
+		VersionID:   version.ID,
+		Number:      version.Number,
+		SubmittedAt: formatAPITime(version.SubmittedAt),
+		IsLate:      version.IsLate,
+		Attachments: make([]dto.MediaResponseDTO, 0, len(version.Attachments)),
+		Score:       version.Score,
+		Feedback:    version.Feedback,
+		Counted:     countedID != nil && *countedID == version.ID,
+	}
+	for _, a := range version.Attachments {
+		if attachment, ok := mapAttachmentMedia(a, schoolID); ok {
+			response.Attachments = append(response.Attachments, attachment)
+		}
+	}
+	if version.Grader != nil {
+		graderName := version.Grader.FullName
+		response.GraderName = &graderName
+	}
+	if version.GradedAt != nil {
+		gradedAt := formatAPITime(*version.GradedAt)
+		response.GradedAt = &gradedAt
+	}
+	return response
+}
@@ -7,6 +7,7 @@ import (
 	"backend/internal/service"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -286,6 +287,66 @@ func (h *ChatHandler) ListMessages(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *ChatHandler) SearchMessages(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	query := dto.ChatMessageSearchQueryDTO{Query: c.Query("q")}
+	query.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	query.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if raw := strings.TrimSpace(c.Query("roomId")); raw != "" {
+		query.RoomID = &raw
+	}
+	if raw := strings.TrimSpace(c.Query("senderId")); raw != "" {
+		query.SenderID = &raw
+	}
+	var valid bool
+	if query.From, valid = parseChatQueryTime(c, "from"); !valid {
+		return
+	}
+	if query.To, valid = parseChatQueryTime(c, "to"); !valid {
+		return
+	}
+	if raw := c.Query("hasAttachment"); raw != "" {
+		hasAttachment, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hasAttachment"})
+			return
+		}
+		query.HasAttachment = &hasAttachment
+	}
+
+	response, err := h.service.SearchMessages(userID, schoolID, query)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// parseChatQueryTime reads an optional RFC3339 query parameter. It writes the
+// 400 response itself and reports false when the value is malformed.
+func parseChatQueryTime(c *gin.Context, name string) (*time.Time, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + " timestamp"})
+		return nil, false
+	}
+	return &parsed, true
+}
+
 func (h *ChatHandler) CreateMessage(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	schoolID, ok := getChatActiveSchoolID(c)
@@ -304,16 +365,157 @@ func (h *ChatHandler) CreateMessage(c *gin.Context) {
 		return
 	}
 
-	message, err := h.service.CreateMessage(userID, schoolID, c.Param("roomId"), input.Content, input.MediaIDs)
+	message, err := h.service.CreateMessage(userID, schoolID, c.Param("roomId"), input)
 	if err != nil {
 		HandleError(c, err)
 		return
 	}
-	h.broadcastNewMessage(userID, schoolID, c.Param("roomId"), *message)
-	h.broadcastRoomUpdated(userID, schoolID, c.Param("roomId"), "new_message")
+	h.PublishNewMessage(userID, schoolID, c.Param("roomId"), *message)
 	c.JSON(http.StatusCreated, message)
 }
 
+// PublishNewMessage broadcasts a newly created message, its thread summary and
+// the room update. The scheduled message dispatcher uses it as well.
+func (h *ChatHandler) PublishNewMessage(userID string, schoolID string, roomID string, message dto.ChatMessageDTO) {
+	h.broadcastNewMessage(userID, schoolID, roomID, message)
+	if message.ReplyTo != nil {
+		h.broadcastThreadUpdated(userID, schoolID, roomID, *message.ReplyTo)
+	}
+	h.broadcastRoomUpdated(userID, schoolID, roomID, "new_message")
+}
+
+func (h *ChatHandler) EditMessage(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.UpdateChatMessageDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	message, err := h.service.EditMessage(userID, schoolID, c.Param("roomId"), c.Param("messageId"), input.Content)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.broadcastMessageUpdated(userID, schoolID, c.Param("roomId"), *message)
+	h.broadcastRoomUpdated(userID, schoolID, c.Param("roomId"), "message_updated")
+	c.JSON(http.StatusOK, message)
+}
+
+func (h *ChatHandler) DeleteMessage(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	deleted, err := h.service.DeleteMessage(userID, schoolID, c.Param("roomId"), c.Param("messageId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.broadcastMessageDeleted(userID, schoolID, c.Param("roomId"), *deleted)
+	if deleted.ReplyTo != nil {
+		h.broadcastThreadUpdated(userID, schoolID, c.Param("roomId"), *deleted.ReplyTo)
+	}
+	h.broadcastRoomUpdated(userID, schoolID, c.Param("roomId"), "message_deleted")
+	c.JSON(http.StatusOK, deleted)
+}
+
+func (h *ChatHandler) GetThread(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	var before *time.Time
+	if raw := c.Query("before"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid before timestamp"})
+			return
+		}
+		before = &parsed
+	}
+
+	thread, err := h.service.GetThread(userID, schoolID, c.Param("roomId"), c.Param("messageId"), limit, before)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, thread)
+}
+
+func (h *ChatHandler) AddReaction(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.AddChatReactionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	reaction, err := h.service.AddReaction(userID, schoolID, c.Param("roomId"), c.Param("messageId"), input.Emoji)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.broadcastReactionUpdated(userID, schoolID, c.Param("roomId"), *reaction)
+	c.JSON(http.StatusOK, reaction)
+}
+
+func (h *ChatHandler) RemoveReaction(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	reaction, err := h.service.RemoveReaction(userID, schoolID, c.Param("roomId"), c.Param("messageId"), c.Param("emoji"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.broadcastReactionUpdated(userID, schoolID, c.Param("roomId"), *reaction)
+	c.JSON(http.StatusOK, reaction)
+}
+
 func (h *ChatHandler) broadcastNewMessage(userID string, schoolID string, roomID string, message dto.ChatMessageDTO) {
 	if h.hub == nil {
 		return
@@ -322,9 +524,13 @@ func (h *ChatHandler) broadcastNewMessage(userID string, schoolID string, roomID
 	if err != nil {
 		return
 	}
+	contentCards := h.recipientContentCards(schoolID, message, recipients)
 	for _, recipientID := range recipients {
 		payload := message
 		payload.IsMine = recipientID == message.SenderID
+		if contentCards != nil {
+			payload.ContentCard = contentCards[recipientID]
+		}
 		h.hub.BroadcastToUser(schoolID, recipientID, realtime.Event{
 			Type:     realtime.EventTypeNewMessage,
 			RoomID:   roomID,
@@ -334,6 +540,106 @@ func (h *ChatHandler) broadcastNewMessage(userID string, schoolID string, roomID
 	}
 }
 
+func (h *ChatHandler) broadcastMessageUpdated(userID string, schoolID string, roomID string, message dto.ChatMessageDTO) {
+	if h.hub == nil {
+		return
+	}
+	recipients, err := h.service.ListRealtimeRecipients(userID, schoolID, roomID)
+	if err != nil {
+		return
+	}
+	// Reactions are per-viewer (reactedByMe), so updates leave them out and
+	// clients keep the state they already have from reaction_updated.
+	message.Reactions = nil
+	contentCards := h.recipientContentCards(schoolID, message, recipients)
+	for _, recipientID := range recipients {
+		payload := message
+		payload.IsMine = recipientID == message.SenderID
+		if contentCards != nil {
+			payload.ContentCard = contentCards[recipientID]
+		}
+		h.hub.BroadcastToUser(schoolID, recipientID, realtime.Event{
+			Type:     realtime.EventTypeMessageUpdated,
+			RoomID:   roomID,
+			SchoolID: schoolID,
+			Payload:  payload,
+		})
+	}
+}
+
+// recipientContentCards resolves the content card of message for each
+// recipient, since the sender's card may show content others cannot open. It
+// returns nil when the message shares no content. On failure every recipient
+// gets an unavailable card rather than the sender's.
+func (h *ChatHandler) recipientContentCards(schoolID string, message dto.ChatMessageDTO, recipients []string) map[string]*dto.ChatContentCardDTO {
+	if message.ContentCard == nil {
+		return nil
+	}
+	cards, err := h.service.ResolveContentCards(schoolID, message.ContentCard.RefType, message.ContentCard.RefID, recipients)
+	if err != nil {
+		cards = make(map[string]*dto.ChatContentCardDTO, len(recipients))
+		for _, recipientID := range recipients {
+			cards[recipientID] = &dto.ChatContentCardDTO{
+				RefType: message.ContentCard.RefType,
+				RefID:   message.ContentCard.RefID,
+			}
+		}
+	}
+	return cards
+}
+
+func (h *ChatHandler) broadcastMessageDeleted(userID string, schoolID string, roomID string, deleted dto.ChatMessageDeletedDTO) {
+	if h.hub == nil {
+		return
+	}
+	recipients, err := h.service.ListRealtimeRecipients(userID, schoolID, roomID)
+	if err != nil {
+		return
+	}
+	h.hub.BroadcastToUsers(schoolID, recipients, realtime.Event{
+		Type:     realtime.EventTypeMessageDeleted,
+		RoomID:   roomID,
+		SchoolID: schoolID,
+		Payload:  deleted,
+	})
+}
+
+func (h *ChatHandler) broadcastThreadUpdated(userID string, schoolID string, roomID string, parentID string) {
+	if h.hub == nil {
+		return
+	}
+	summary, err := h.service.GetThreadSummary(userID, schoolID, roomID, parentID)
+	if err != nil {
+		return
+	}
+	recipients, err := h.service.ListRealtimeRecipients(userID, schoolID, roomID)
+	if err != nil {
+		return
+	}
+	h.hub.BroadcastToUsers(schoolID, recipients, realtime.Event{
+		Type:     realtime.EventTypeThreadUpdated,
+		RoomID:   roomID,
+		SchoolID: schoolID,
+		Payload:  summary,
+	})
+}
+
+func (h *ChatHandler) broadcastReactionUpdated(userID string, schoolID string, roomID string, reaction dto.ChatReactionUpdatedDTO) {
+	if h.hub == nil {
+		return
+	}
+	recipients, err := h.service.ListRealtimeRecipients(userID, schoolID, roomID)
+	if err != nil {
+		return
+	}
+	h.hub.BroadcastToUsers(schoolID, recipients, realtime.Event{
+		Type:     realtime.EventTypeReactionUpdated,
+		RoomID:   roomID,
+		SchoolID: schoolID,
+		Payload:  reaction,
+	})
+}
+
 func (h *ChatHandler) broadcastMessageRead(userID string, schoolID string, roomID string, receipt dto.ChatReadReceiptDTO) {
 	if h.hub == nil {
 		return
@@ -391,6 +697,87 @@ func (h *ChatHandler) GetReadSummary(c *gin.Context) {
 	c.JSON(http.StatusOK, summary)
 }
 
+func (h *ChatHandler) GetRoomSettings(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	settings, err := h.service.GetRoomSettings(userID, schoolID, c.Param("roomId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *ChatHandler) UpdateRoomSettings(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.UpdateChatRoomSettingsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	settings, err := h.service.UpdateRoomSettings(userID, schoolID, c.Param("roomId"), input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	// Only the member's own sessions need to refresh their badges.
+	if h.hub != nil {
+		h.hub.BroadcastToUser(schoolID, userID, realtime.Event{
+			Type:     realtime.EventTypeRoomUpdated,
+			RoomID:   settings.RoomID,
+			SchoolID: schoolID,
+			Payload: gin.H{
+				"reason": "settings_updated",
+			},
+		})
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *ChatHandler) ListOnlineMembers(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	roomID := c.Param("roomId")
+	members, err := h.service.ListRealtimeRecipients(userID, schoolID, roomID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ChatOnlineMembersResponseDTO{
+		RoomID:        roomID,
+		OnlineUserIDs: h.hub.OnlineUserIDs(schoolID, members),
+	})
+}
+
 func (h *ChatHandler) MarkRead(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	schoolID, ok := getChatActiveSchoolID(c)
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"backend/internal/realtime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *ChatHandler) ReportMessage(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.ReportChatMessageDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	report, err := h.service.ReportMessage(userID, schoolID, c.Param("roomId"), c.Param("messageId"), input.Reason)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+func (h *ChatHandler) ListReports(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	reports, err := h.service.ListReports(userID, schoolID, c.Query("status"), page, limit)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+func (h *ChatHandler) ResolveReport(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.ResolveChatReportDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	report, deleted, err := h.service.ResolveReport(userID, schoolID, c.Param("reportId"), input.Status, input.Note, input.RemoveMessage)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	if deleted != nil {
+		h.broadcastModerationDeleted(userID, schoolID, *deleted)
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *ChatHandler) MuteMember(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.MuteChatMemberDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	mute, err := h.service.MuteMember(userID, schoolID, c.Param("roomId"), input.UserID, input.DurationMinutes, input.Reason)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.sendMuteUpdated(schoolID, *mute)
+	c.JSON(http.StatusOK, mute)
+}
+
+func (h *ChatHandler) UnmuteMember(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	mute, err := h.service.UnmuteMember(userID, schoolID, c.Param("roomId"), c.Param("userId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.sendMuteUpdated(schoolID, *mute)
+	c.JSON(http.StatusOK, mute)
+}
+
+func (h *ChatHandler) GetBannedWords(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	words, err := h.service.GetBannedWords(userID, schoolID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, words)
+}
+
+func (h *ChatHandler) UpdateBannedWords(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.UpdateChatBannedWordsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	words, err := h.service.UpdateBannedWords(userID, schoolID, input.Words)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, words)
+}
+
+// broadcastModerationDeleted announces a message removed from the moderation
+// queue. The admin may not be a member of the room, so recipients are resolved
+// without the caller's room access. Clients use replyTo in the payload to
+// adjust thread reply counts.
+func (h *ChatHandler) broadcastModerationDeleted(userID string, schoolID string, deleted dto.ChatMessageDeletedDTO) {
+	if h.hub == nil {
+		return
+	}
+	recipients, err := h.service.ListModerationRecipients(userID, schoolID, deleted.RoomID)
+	if err != nil {
+		return
+	}
+	h.hub.BroadcastToUsers(schoolID, recipients, realtime.Event{
+		Type:     realtime.EventTypeMessageDeleted,
+		RoomID:   deleted.RoomID,
+		SchoolID: schoolID,
+		Payload:  deleted,
+	})
+	h.hub.BroadcastToUsers(schoolID, recipients, realtime.Event{
+		Type:     realtime.EventTypeRoomUpdated,
+		RoomID:   deleted.RoomID,
+		SchoolID: schoolID,
+		Payload: gin.H{
+			"reason": "message_deleted",
+		},
+	})
+}
+
+// sendMuteUpdated tells the affected member so their client can disable or
+// re-enable the composer.
+func (h *ChatHandler) sendMuteUpdated(schoolID string, mute dto.ChatMuteDTO) {
+	if h.hub == nil {
+		return
+	}
+	h.hub.BroadcastToUser(schoolID, mute.UserID, realtime.Event{
+		Type:     realtime.EventTypeMuteUpdated,
+		RoomID:   mute.RoomID,
+		SchoolID: schoolID,
+		Payload:  mute,
+	})
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"backend/internal/realtime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *ChatHandler) ListPinnedMessages(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	pins, err := h.service.ListPinnedMessages(userID, schoolID, c.Param("roomId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pins)
+}
+
+func (h *ChatHandler) PinMessage(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	pin, err := h.service.PinMessage(userID, schoolID, c.Param("roomId"), c.Param("messageId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.broadcastPinUpdated(userID, schoolID, c.Param("roomId"), *pin)
+	c.JSON(http.StatusOK, pin)
+}
+
+func (h *ChatHandler) UnpinMessage(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	pin, err := h.service.UnpinMessage(userID, schoolID, c.Param("roomId"), c.Param("messageId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.broadcastPinUpdated(userID, schoolID, c.Param("roomId"), *pin)
+	c.JSON(http.StatusOK, pin)
+}
+
+func (h *ChatHandler) UpdatePostingMode(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.UpdateChatPostingModeDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	mode, err := h.service.UpdatePostingMode(userID, schoolID, c.Param("roomId"), input.PostingMode)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	h.broadcastRoomUpdated(userID, schoolID, c.Param("roomId"), "posting_mode_updated")
+	c.JSON(http.StatusOK, mode)
+}
+
+func (h *ChatHandler) broadcastPinUpdated(userID string, schoolID string, roomID string, pin dto.ChatPinUpdatedDTO) {
+	if h.hub == nil {
+		return
+	}
+	recipients, err := h.service.ListRealtimeRecipients(userID, schoolID, roomID)
+	if err != nil {
+		return
+	}
+	h.hub.BroadcastToUsers(schoolID, recipients, realtime.Event{
+		Type:     realtime.EventTypePinUpdated,
+		RoomID:   roomID,
+		SchoolID: schoolID,
+		Payload:  pin,
+	})
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChatRetentionHandler struct {
+	service service.ChatRetentionService
+}
+
+func NewChatRetentionHandler(service service.ChatRetentionService) *ChatRetentionHandler {
+	return &ChatRetentionHandler{service: service}
+}
+
+func (h *ChatRetentionHandler) GetPolicy(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	policy, err := h.service.GetPolicy(schoolID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *ChatRetentionHandler) UpdatePolicy(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.UpdateChatRetentionPolicyDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	policy, err := h.service.UpdatePolicy(userID, schoolID, input.RetentionDays)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *ChatRetentionHandler) ExportTranscript(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	from, ok := parseChatQueryTime(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseChatQueryTime(c, "to")
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "json")))
+	transcript, err := h.service.ExportTranscript(userID, schoolID, c.Param("roomId"), format, from, to)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	if format == "html" {
+		body, err := service.RenderChatTranscriptHTML(transcript)
+		if err != nil {
+			HandleError(c, err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"chat-transcript-%s.html\"", transcript.RoomID))
+		c.Data(http.StatusOK, "text/html; charset=utf-8", body)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"chat-transcript-%s.json\"", transcript.RoomID))
+	c.JSON(http.StatusOK, transcript)
+}
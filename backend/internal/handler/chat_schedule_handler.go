@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChatScheduleHandler struct {
+	service service.ChatScheduleService
+}
+
+func NewChatScheduleHandler(service service.ChatScheduleService) *ChatScheduleHandler {
+	return &ChatScheduleHandler{service: service}
+}
+
+func (h *ChatScheduleHandler) Schedule(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.CreateChatScheduledMessageDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	scheduled, err := h.service.Schedule(userID, schoolID, c.Param("roomId"), input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, scheduled)
+}
+
+func (h *ChatScheduleHandler) List(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var roomID *string
+	if value := strings.TrimSpace(c.Query("roomId")); value != "" {
+		roomID = &value
+	}
+	scheduled, err := h.service.List(userID, schoolID, roomID, c.Query("status"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, scheduled)
+}
+
+func (h *ChatScheduleHandler) Update(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	var input dto.UpdateChatScheduledMessageDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+
+	scheduled, err := h.service.Update(userID, schoolID, c.Param("scheduledId"), input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, scheduled)
+}
+
+func (h *ChatScheduleHandler) Cancel(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	schoolID, ok := getChatActiveSchoolID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+		return
+	}
+
+	scheduled, err := h.service.Cancel(userID, schoolID, c.Param("scheduledId"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, scheduled)
+}
@@ -48,6 +48,347 @@ func HandleError(c *gin.Context, err error) {
 		return
 	}
 
+	if strings.Contains(errStr, "assignment extension reason is required") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Extension reason is required"})
+		return
+	}
+
+	if strings.Contains(errStr, "assignment has no deadline to extend") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assignment has no deadline to extend"})
+		return
+	}
+
+	if strings.Contains(errStr, "assignment extension request is still pending") {
+		c.JSON(http.StatusConflict, gin.H{"error": "An extension request for this assignment is still pending"})
+		return
+	}
+
+	if strings.Contains(errStr, "assignment extension request is no longer pending") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Extension request has already been reviewed"})
+		return
+	}
+
+	if strings.Contains(errStr, "extended deadline is required") ||
+		strings.Contains(errStr, "extended deadline must be after") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Extended deadline must be in the future and after the assignment deadline"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid assignment extension status") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid extension status"})
+		return
+	}
+
+	if strings.Contains(errStr, "score is computed from the rubric") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Score of a rubric assignment is computed from its criteria"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid rubric assessment: assignment has no rubric") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assignment has no rubric"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid rubric assessment") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pick one level of the rubric for every criterion"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid rubric:") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rubric criteria and levels need titles, points cannot be negative and the total must be above zero"})
+		return
+	}
+
+	if strings.Contains(errStr, "rubric is locked by graded assessments") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Rubric has already been used to grade submissions and cannot be changed"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid question:") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Question needs a prompt, positive points and a valid answer key for its type"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid question type") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question type"})
+		return
+	}
+
+	if strings.Contains(errStr, "question is locked by quiz attempts") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Question has already been answered in a quiz and cannot be changed"})
+		return
+	}
+
+	if strings.Contains(errStr, "question subject cannot change") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Question subject cannot change while the question is used in quizzes"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz is locked by student attempts") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Quiz has already been started by students and cannot be changed"})
+		return
+	}
+
+	if strings.Contains(errStr, "assignment is not a quiz") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assignment is not a quiz"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz has no questions") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiz has no questions"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz settings are required") || strings.Contains(errStr, "quiz settings require a quiz assignment") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiz settings are required for quiz assignments only"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz assignments cannot use a rubric") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiz assignments cannot use a rubric"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz attempt time is over") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Quiz time is over"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz attempt is already submitted") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Quiz attempt has already been submitted"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz attempt is still in progress") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Quiz attempt is still in progress"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid quiz answer") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Answers must belong to the quiz questions and match their type"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid quiz grade") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Grades must belong to the quiz questions and stay within their points"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz scores are computed from quiz answers") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiz scores are computed from quiz answers; grade the quiz attempt instead"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz assignments are submitted through quiz attempts") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiz assignments are submitted through quiz attempts"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz submissions cannot be changed") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Quiz submissions cannot be changed"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz assignments have a single attempt") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiz assignments have a single attempt graded by its latest submission"})
+		return
+	}
+
+	if strings.Contains(errStr, "submission attempt limit reached") {
+		c.JSON(http.StatusConflict, gin.H{"error": "You have used all submission attempts for this assignment"})
+		return
+	}
+
+	if strings.Contains(errStr, "grading policy is locked by graded submissions") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Grading policy cannot change after submission versions have been graded"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz assignments cannot be group assignments") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiz assignments cannot be group assignments"})
+		return
+	}
+
+	if strings.Contains(errStr, "group mode cannot change after students have submitted") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Group mode cannot change after students have submitted"})
+		return
+	}
+
+	if strings.Contains(errStr, "student is not in a group") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Join a group before submitting this group assignment"})
+		return
+	}
+
+	if strings.Contains(errStr, "student already belongs to another group submission") {
+		c.JSON(http.StatusConflict, gin.H{"error": "You are already counted on another group's submission for this assignment"})
+		return
+	}
+
+	if strings.Contains(errStr, "submission is not a group submission") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Score adjustments only apply to group submissions"})
+		return
+	}
+
+	if strings.Contains(errStr, "student group name is required") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Group name is required"})
+		return
+	}
+
+	if strings.Contains(errStr, "student group has more members than its size limit") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Group has more members than its size limit"})
+		return
+	}
+
+	if strings.Contains(errStr, "student group is full") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Group is full"})
+		return
+	}
+
+	if strings.Contains(errStr, "student is already in a group") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Leave your current group before joining another one"})
+		return
+	}
+
+	if strings.Contains(errStr, "student group cannot be deleted because it has submissions") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Group cannot be deleted because it has submissions"})
+		return
+	}
+
+	if strings.Contains(errStr, "peer review is not available for quiz assignments") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Peer review is not available for quiz assignments"})
+		return
+	}
+
+	if strings.Contains(errStr, "peer review is not available for group assignments") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Peer review is not available for group assignments"})
+		return
+	}
+
+	if strings.Contains(errStr, "peer review requires an assignment deadline") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set an assignment deadline before enabling peer review"})
+		return
+	}
+
+	if strings.Contains(errStr, "review deadline must be after the assignment deadline") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Review deadline must be after the assignment deadline"})
+		return
+	}
+
+	if strings.Contains(errStr, "peer review form is locked after allocation") {
+		c.JSON(http.StatusConflict, gin.H{"error": "The review form and reviewer count cannot change after reviews are allocated"})
+		return
+	}
+
+	if strings.Contains(errStr, "peer review is already allocated") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Peer reviews are already allocated"})
+		return
+	}
+
+	if strings.Contains(errStr, "peer review needs at least two submissions") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Peer review needs at least two submissions"})
+		return
+	}
+
+	if strings.Contains(errStr, "peer review deadline has passed") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The peer review deadline has passed"})
+		return
+	}
+
+	if strings.Contains(errStr, "peer review answer is required") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Answer every required review question"})
+		return
+	}
+
+	if strings.Contains(errStr, "peer review rating is out of range") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rating is outside the question's scale"})
+		return
+	}
+
+	if strings.Contains(errStr, "duplicate peer review answer") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Each review question can only be answered once"})
+		return
+	}
+
+	if strings.Contains(errStr, "publish time is required for scheduled items") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set a publish time to schedule this item"})
+		return
+	}
+
+	if strings.Contains(errStr, "publish time must be in the future") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Publish time must be in the future"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid publish status") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Publish status must be draft, scheduled or published"})
+		return
+	}
+
+	if strings.Contains(errStr, "published items cannot be unpublished") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Published items cannot be moved back to draft or scheduled"})
+		return
+	}
+
+	if strings.Contains(errStr, "assignment is not published") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The requested data was not found"})
+		return
+	}
+
+	if strings.Contains(errStr, "bulk grading is not available for quiz assignments") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiz assignments are graded from their attempts, not from a file"})
+		return
+	}
+
+	if strings.Contains(errStr, "bulk grading is not available for rubric assignments") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rubric assignments must be graded per criterion, not from a file"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid grade csv") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The grade file must be a CSV with email and score columns and at least one row"})
+		return
+	}
+
+	if strings.Contains(errStr, "grade import rows are required") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Add at least one grade row to import"})
+		return
+	}
+
+	if strings.Contains(errStr, "bulk grading is not available while identities are hidden") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Release student identities before grading from a file"})
+		return
+	}
+
+	if strings.Contains(errStr, "score adjustments are not available while identities are hidden") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Release student identities before adjusting member scores"})
+		return
+	}
+
+	if strings.Contains(errStr, "quiz assignments cannot be graded anonymously") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiz assignments cannot use anonymous grading"})
+		return
+	}
+
+	if strings.Contains(errStr, "anonymous grading is locked by graded submissions") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Anonymous grading cannot change after submissions are graded"})
+		return
+	}
+
+	if strings.Contains(errStr, "assignment is not graded anonymously") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This assignment does not use anonymous grading"})
+		return
+	}
+
+	if strings.Contains(errStr, "identities are already released") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Student identities are already released"})
+		return
+	}
+
+	if strings.Contains(errStr, "identities can be released once every submission is graded") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Grade every submission before releasing student identities"})
+		return
+	}
+
 	if strings.Contains(errStr, "feed content is required") {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Feed content is required"})
 		return
@@ -68,6 +409,196 @@ func HandleError(c *gin.Context, err error) {
 		return
 	}
 
+	if strings.Contains(errStr, "chat search query is required") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kata kunci pencarian wajib diisi"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat search query exceeds") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kata kunci pencarian maksimal 200 karakter"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat search date range") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tanggal awal pencarian harus sebelum tanggal akhir"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat message contains banned words") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pesan mengandung kata yang dilarang di sekolah ini"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat member is muted") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Kamu sedang dibisukan di ruang ini"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat room moderator required") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Hanya moderator ruang yang dapat melakukan aksi ini"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat cannot report own message") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kamu tidak dapat melaporkan pesanmu sendiri"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat report reason exceeds") || strings.Contains(errStr, "chat mute reason exceeds") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Alasan maksimal 500 karakter"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat message already reported") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Kamu sudah melaporkan pesan ini"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat report already resolved") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Laporan sudah ditangani"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat report status") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status laporan tidak valid"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat cannot mute self") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kamu tidak dapat membisukan dirimu sendiri"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat cannot mute moderator") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Moderator ruang tidak dapat dibisukan"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat mute duration") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Durasi bisu harus antara 1 menit dan 30 hari"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat mute target") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pengguna bukan anggota aktif ruang ini"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat banned words exceed") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Daftar kata terlarang maksimal 500 kata"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat banned word exceeds") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kata terlarang maksimal 50 karakter"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat notification mute must end in the future") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Waktu bisu notifikasi harus di masa depan"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat retention days") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Masa simpan chat harus antara 30 dan 3650 hari, atau kosong untuk menyimpan selamanya"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat transcript format") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format transkrip harus json atau html"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat transcript date range") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rentang tanggal transkrip tidak valid"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat room posting is limited to moderators") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Hanya moderator yang dapat mengirim pesan di ruang ini"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat pinned messages exceed") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Maksimal 20 pesan dapat disematkan di satu ruang"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat posting mode") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Mode pengiriman harus everyone atau moderators"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat content reference") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Referensi konten tidak valid; refType dan refId harus diisi bersamaan"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat content reference not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Konten yang dibagikan tidak ditemukan"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat content access denied") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Kamu tidak memiliki akses ke konten yang dibagikan"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat scheduled send time must be in the future") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Waktu kirim harus di masa depan"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat scheduled send time exceeds") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pesan hanya dapat dijadwalkan paling lambat 90 hari ke depan"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat scheduled messages exceed") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Maksimal 50 pesan terjadwal yang menunggu per sekolah"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat scheduled message is no longer pending") {
+		c.JSON(http.StatusConflict, gin.H{"error": "Pesan terjadwal sudah dikirim atau dibatalkan"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat scheduled status") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status harus pending, sent, cancelled, failed, atau all"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat message edit window has expired") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Batas waktu untuk mengubah atau menghapus pesan sudah lewat"})
+		return
+	}
+
+	if strings.Contains(errStr, "only the sender can edit a chat message") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Hanya pengirim yang dapat mengubah pesan"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat message delete not allowed") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Kamu tidak dapat menghapus pesan ini"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat reply target") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pesan yang dibalas tidak ditemukan di ruang ini"})
+		return
+	}
+
+	if strings.Contains(errStr, "chat reaction emoji is required") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Emoji reaksi wajib diisi"})
+		return
+	}
+
+	if strings.Contains(errStr, "invalid chat reaction emoji") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reaksi harus berupa satu emoji"})
+		return
+	}
+
 	if strings.Contains(errStr, "chat message attachments exceed") {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Maksimal 5 lampiran per pesan"})
 		return
@@ -197,6 +728,13 @@ func HandleError(c *gin.Context, err error) {
 
 	if strings.Contains(errStr, "invalid media attachment") ||
 		strings.Contains(errStr, "invalid assignment category") ||
+		strings.Contains(errStr, "invalid assignment rubric") ||
+		strings.Contains(errStr, "invalid quiz questions") ||
+		strings.Contains(errStr, "invalid submission version") ||
+		strings.Contains(errStr, "invalid student group member") ||
+		strings.Contains(errStr, "invalid group submission member") ||
+		strings.Contains(errStr, "invalid peer review question") ||
+		strings.Contains(errStr, "invalid question subject") ||
 		strings.Contains(errStr, "invalid assessment weight subject") ||
 		strings.Contains(errStr, "invalid assessment weight category") {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or non-existent data reference"})
@@ -9,8 +9,10 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type MaterialHandler struct {
@@ -55,6 +57,8 @@ func (h *MaterialHandler) Create(c *gin.Context) {
 			Title:          input.Title,
 			Description:    input.Description,
 			Type:           domain.MaterialType(input.Type),
+			PublishStatus:  input.PublishStatus,
+			PublishAt:      input.PublishAt,
 			CreatedBy:      userID,
 		}
 
@@ -85,6 +89,15 @@ func (h *MaterialHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Required fields: schoolId, subjectClassId, materialTitle, materialType"})
 		return
 	}
+	var publishAt *time.Time
+	if raw := c.PostForm("publishAt"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "publishAt must be an RFC3339 timestamp"})
+			return
+		}
+		publishAt = &parsed
+	}
 	if !h.validateRequestSchool(c, schoolID) {
 		return
 	}
@@ -98,6 +111,8 @@ func (h *MaterialHandler) Create(c *gin.Context) {
 		Title:          title,
 		Description:    description,
 		Type:           domain.MaterialType(materialType),
+		PublishStatus:  c.PostForm("publishStatus"),
+		PublishAt:      publishAt,
 		CreatedBy:      userID,
 	}
 
@@ -232,6 +247,18 @@ func (h *MaterialHandler) authorizeMaterialMutation(c *gin.Context, material *do
 	return false
 }
 
+// canSeeUnpublished reports whether the user may see draft and scheduled
+// materials of the subject class: admins and the teacher of the class.
+func (h *MaterialHandler) canSeeUnpublished(c *gin.Context, subjectClassID string) (bool, error) {
+	if h.hasActiveRole(c, "admin") {
+		return true, nil
+	}
+	if !h.hasActiveRole(c, "teacher") {
+		return false, nil
+	}
+	return h.subjectClassService.TeacherOwnsSubjectClass(middleware.GetUserID(c), h.getSchoolContext(c), subjectClassID)
+}
+
 func (h *MaterialHandler) authorizeTeacherForSubjectClass(c *gin.Context, subjectClassID string) bool {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
@@ -270,7 +297,13 @@ func (h *MaterialHandler) FindAll(c *gin.Context) {
 		return
 	}
 
-	materials, total, err := h.service.FindAll(search, subjectClassID, page, limit)
+	// Students only see published materials
+	includeUnpublished, err := h.canSeeUnpublished(c, subjectClassID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	materials, total, err := h.service.FindAll(search, subjectClassID, !includeUnpublished, page, limit)
 	if err != nil {
 		HandleError(c, err)
 		return
@@ -326,6 +359,17 @@ func (h *MaterialHandler) GetByID(c *gin.Context) {
 	if !h.authorizeUserForSubjectClassAccess(c, mat.SubjectClassID) {
 		return
 	}
+	if mat.PublishStatus != domain.PublishStatusPublished {
+		includeUnpublished, err := h.canSeeUnpublished(c, mat.SubjectClassID)
+		if err != nil {
+			HandleError(c, err)
+			return
+		}
+		if !includeUnpublished {
+			HandleError(c, gorm.ErrRecordNotFound)
+			return
+		}
+	}
 	c.JSON(http.StatusOK, h.mapToResponse(mat))
 }
 
@@ -376,6 +420,13 @@ func (h *MaterialHandler) Update(c *gin.Context) {
 	if input.Type != nil {
 		mat.Type = domain.MaterialType(*input.Type)
 	}
+	if input.PublishStatus != nil || input.PublishAt != nil {
+		mat.PublishStatus = ""
+		if input.PublishStatus != nil {
+			mat.PublishStatus = *input.PublishStatus
+		}
+		mat.PublishAt = input.PublishAt
+	}
 
 	if err := h.service.Update(mat, input.MediaIDs, middleware.GetUserID(c), h.hasActiveRole(c, "admin")); err != nil {
 		HandleError(c, err)
@@ -419,6 +470,9 @@ func (h *MaterialHandler) mapToResponse(m *domain.Material) dto.MaterialResponse
 		Description:    m.Description,
 		Type:           string(m.Type),
 		CreatorName:    m.Creator.FullName,
+		PublishStatus:  m.PublishStatus,
+		PublishAt:      m.PublishAt,
+		PublishedAt:    m.PublishedAt,
 		CreatedAt:      formatAPITime(m.CreatedAt),
 		Attachments:    atts,
 	}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuestionHandler struct {
+	service service.QuestionService
+}
+
+func NewQuestionHandler(service service.QuestionService) *QuestionHandler {
+	return &QuestionHandler{service: service}
+}
+
+func (h *QuestionHandler) Create(c *gin.Context) {
+	var input dto.CreateQuestionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	schoolID, userID, ok := getQuestionContext(c)
+	if !ok {
+		return
+	}
+
+	question, err := h.service.Create(userID, schoolID, input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, question)
+}
+
+func (h *QuestionHandler) List(c *gin.Context) {
+	schoolID, _, ok := getQuestionContext(c)
+	if !ok {
+		return
+	}
+	subjectID := c.Query("subjectId")
+	if subjectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "subjectId is required"})
+		return
+	}
+
+	questions, err := h.service.List(schoolID, subjectID, c.Query("type"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, questions)
+}
+
+func (h *QuestionHandler) Get(c *gin.Context) {
+	schoolID, _, ok := getQuestionContext(c)
+	if !ok {
+		return
+	}
+
+	question, err := h.service.Get(c.Param("id"), schoolID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+func (h *QuestionHandler) Replace(c *gin.Context) {
+	var input dto.CreateQuestionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	schoolID, userID, ok := getQuestionContext(c)
+	if !ok {
+		return
+	}
+
+	question, err := h.service.Replace(c.Param("id"), userID, schoolID, hasQuestionAdminRole(c), input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+func (h *QuestionHandler) Delete(c *gin.Context) {
+	schoolID, userID, ok := getQuestionContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Param("id"), userID, schoolID, hasQuestionAdminRole(c)); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Question deleted"})
+}
+
+func getQuestionContext(c *gin.Context) (string, string, bool) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", "", false
+	}
+
+	if rawSchoolID, exists := c.Get("school_id"); exists {
+		if schoolID, ok := rawSchoolID.(string); ok && schoolID != "" {
+			return schoolID, userID, true
+		}
+	}
+	if schoolID := c.GetHeader("SchoolId"); schoolID != "" {
+		return schoolID, userID, true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+	return "", "", false
+}
+
+func hasQuestionAdminRole(c *gin.Context) bool {
+	if raw, exists := c.Get("user_roles"); exists {
+		if roles, ok := raw.([]string); ok {
+			for _, role := range roles {
+				if role == "admin" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RubricHandler struct {
+	service service.RubricService
+}
+
+func NewRubricHandler(service service.RubricService) *RubricHandler {
+	return &RubricHandler{service: service}
+}
+
+func (h *RubricHandler) Create(c *gin.Context) {
+	var input dto.CreateRubricDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	schoolID, userID, ok := getRubricContext(c)
+	if !ok {
+		return
+	}
+
+	rubric, err := h.service.Create(userID, schoolID, hasRubricAdminRole(c), input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapRubricResponse(rubric))
+}
+
+func (h *RubricHandler) List(c *gin.Context) {
+	schoolID, userID, ok := getRubricContext(c)
+	if !ok {
+		return
+	}
+
+	rubrics, err := h.service.List(userID, schoolID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	response := make([]dto.RubricResponseDTO, 0, len(rubrics))
+	for _, rubric := range rubrics {
+		response = append(response, mapRubricResponse(rubric))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RubricHandler) Get(c *gin.Context) {
+	schoolID, userID, ok := getRubricContext(c)
+	if !ok {
+		return
+	}
+
+	rubric, err := h.service.Get(c.Param("id"), userID, schoolID, hasRubricAdminRole(c))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapRubricResponse(rubric))
+}
+
+func (h *RubricHandler) Replace(c *gin.Context) {
+	var input dto.CreateRubricDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	schoolID, userID, ok := getRubricContext(c)
+	if !ok {
+		return
+	}
+
+	rubric, err := h.service.Replace(c.Param("id"), userID, schoolID, hasRubricAdminRole(c), input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapRubricResponse(rubric))
+}
+
+func (h *RubricHandler) Delete(c *gin.Context) {
+	schoolID, userID, ok := getRubricContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Param("id"), userID, schoolID, hasRubricAdminRole(c)); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rubric deleted"})
+}
+
+func getRubricContext(c *gin.Context) (string, string, bool) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", "", false
+	}
+
+	if rawSchoolID, exists := c.Get("school_id"); exists {
+		if schoolID, ok := rawSchoolID.(string); ok && schoolID != "" {
+			return schoolID, userID, true
+		}
+	}
+	if schoolID := c.GetHeader("SchoolId"); schoolID != "" {
+		return schoolID, userID, true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+	return "", "", false
+}
+
+func hasRubricAdminRole(c *gin.Context) bool {
+	if raw, exists := c.Get("user_roles"); exists {
+		if roles, ok := raw.([]string); ok {
+			for _, role := range roles {
+				if role == "admin" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func mapRubricResponse(rubric *domain.Rubric) dto.RubricResponseDTO {
+	scope := service.RubricScopePersonal
+	if rubric.OwnerID == nil {
+		scope = service.RubricScopeSchool
+	}
+	response := dto.RubricResponseDTO{
+		RubricID:    rubric.ID,
+		Title:       rubric.Title,
+		Description: rubric.Description,
+		Scope:       scope,
+		OwnerID:     rubric.OwnerID,
+		Criteria:    make([]dto.RubricCriterionDTO, 0, len(rubric.Criteria)),
+		CreatedAt:   formatAPITime(rubric.CreatedAt),
+		UpdatedAt:   formatAPITime(rubric.UpdatedAt),
+	}
+	for _, criterion := range rubric.Criteria {
+		mapped := dto.RubricCriterionDTO{
+			CriterionID: criterion.ID,
+			Title:       criterion.Title,
+			Description: criterion.Description,
+			MaxPoints:   rubricCriterionMaxPoints(criterion),
+			Levels:      mapRubricLevels(criterion.Levels),
+		}
+		response.MaxPoints += mapped.MaxPoints
+		response.Criteria = append(response.Criteria, mapped)
+	}
+	return response
+}
+
+// mapFilledRubric lays the rubric scores of one assessment over its rubric.
+// Criteria without a score keep nil selections.
+func mapFilledRubric(rubric *domain.Rubric, scores []domain.AssessmentRubricScore) *dto.FilledRubricDTO {
+	if rubric == nil || len(scores) == 0 {
+		return nil
+	}
+	byCriterion := make(map[string]domain.AssessmentRubricScore, len(scores))
+	for _, score := range scores {
+		byCriterion[score.CriterionID] = score
+	}
+
+	response := &dto.FilledRubricDTO{
+		RubricID: rubric.ID,
+		Title:    rubric.Title,
+		Criteria: make([]dto.FilledRubricCriterionDTO, 0, len(rubric.Criteria)),
+	}
+	for _, criterion := range rubric.Criteria {
+		mapped := dto.FilledRubricCriterionDTO{
+			CriterionID: criterion.ID,
+			Title:       criterion.Title,
+			Description: criterion.Description,
+			MaxPoints:   rubricCriterionMaxPoints(criterion),
+			Levels:      mapRubricLevels(criterion.Levels),
+		}
+		if score, ok := byCriterion[criterion.ID]; ok {
+			levelID := score.LevelID
+			points := score.Points
+			comment := score.Comment
+			mapped.SelectedLevelID = &levelID
+			mapped.Points = &points
+			mapped.Comment = &comment
+			response.EarnedPoints += points
+		}
+		response.MaxPoints += mapped.MaxPoints
+		response.Criteria = append(response.Criteria, mapped)
+	}
+	return response
+}
+
+func mapRubricLevels(levels []domain.RubricLevel) []dto.RubricLevelDTO {
+	result := make([]dto.RubricLevelDTO, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, dto.RubricLevelDTO{
+			LevelID:     level.ID,
+			Title:       level.Title,
+			Description: level.Description,
+			Points:      level.Points,
+		})
+	}
+	return result
+}
+
+func rubricCriterionMaxPoints(criterion domain.RubricCriterion) float64 {
+	var best float64
+	for _, level := range criterion.Levels {
+		if level.Points > best {
+			best = level.Points
+		}
+	}
+	return best
+}
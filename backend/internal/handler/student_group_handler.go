@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StudentGroupHandler struct {
+	service service.StudentGroupService
+}
+
+func NewStudentGroupHandler(service service.StudentGroupService) *StudentGroupHandler {
+	return &StudentGroupHandler{service: service}
+}
+
+func (h *StudentGroupHandler) Create(c *gin.Context) {
+	var input dto.CreateStudentGroupDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	schoolID, userID, ok := getStudentGroupContext(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.service.Create(userID, schoolID, getStudentGroupRoles(c), input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapStudentGroupResponse(group))
+}
+
+func (h *StudentGroupHandler) ListBySubjectClass(c *gin.Context) {
+	schoolID, userID, ok := getStudentGroupContext(c)
+	if !ok {
+		return
+	}
+	subjectClassID := c.Param("subjectClassId")
+
+	groups, err := h.service.ListBySubjectClass(subjectClassID, userID, schoolID, getStudentGroupRoles(c))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	response := dto.SubjectClassStudentGroupsDTO{
+		SubjectClassID: subjectClassID,
+		Groups:         make([]dto.StudentGroupResponseDTO, 0, len(groups)),
+	}
+	for _, group := range groups {
+		for _, member := range group.Members {
+			if member.UserID == userID {
+				groupID := group.ID
+				response.MyGroupID = &groupID
+			}
+		}
+		response.Groups = append(response.Groups, mapStudentGroupResponse(group))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *StudentGroupHandler) Update(c *gin.Context) {
+	var input dto.UpdateStudentGroupDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	schoolID, userID, ok := getStudentGroupContext(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.service.Update(c.Param("id"), userID, schoolID, getStudentGroupRoles(c), input)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapStudentGroupResponse(group))
+}
+
+func (h *StudentGroupHandler) Delete(c *gin.Context) {
+	schoolID, userID, ok := getStudentGroupContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Param("id"), userID, schoolID, getStudentGroupRoles(c)); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Student group deleted"})
+}
+
+func (h *StudentGroupHandler) SetMembers(c *gin.Context) {
+	var input dto.SetStudentGroupMembersDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		HandleBindingError(c, err)
+		return
+	}
+	schoolID, userID, ok := getStudentGroupContext(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.service.SetMembers(c.Param("id"), userID, schoolID, getStudentGroupRoles(c), input.MemberIDs)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapStudentGroupResponse(group))
+}
+
+func (h *StudentGroupHandler) Join(c *gin.Context) {
+	schoolID, userID, ok := getStudentGroupContext(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.service.Join(c.Param("id"), userID, schoolID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapStudentGroupResponse(group))
+}
+
+func (h *StudentGroupHandler) Leave(c *gin.Context) {
+	schoolID, userID, ok := getStudentGroupContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Leave(c.Param("id"), userID, schoolID); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left student group"})
+}
+
+func getStudentGroupContext(c *gin.Context) (string, string, bool) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", "", false
+	}
+
+	if rawSchoolID, exists := c.Get("school_id"); exists {
+		if schoolID, ok := rawSchoolID.(string); ok && schoolID != "" {
+			return schoolID, userID, true
+		}
+	}
+	if schoolID := c.GetHeader("SchoolId"); schoolID != "" {
+		return schoolID, userID, true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
+	return "", "", false
+}
+
+func getStudentGroupRoles(c *gin.Context) []string {
+	if raw, exists := c.Get("user_roles"); exists {
+		if roles, ok := raw.([]string); ok {
+			return roles
+		}
+	}
+	return nil
+}
+
+func mapStudentGroupResponse(group *domain.StudentGroup) dto.StudentGroupResponseDTO {
+	members := make([]dto.StudentGroupMemberResponseDTO, 0, len(group.Members))
+	for _, member := range group.Members {
+		members = append(members, dto.StudentGroupMemberResponseDTO{
+			UserID:      member.UserID,
+			StudentName: member.User.FullName,
+			JoinedAt:    formatAPITime(member.CreatedAt),
+		})
+	}
+	return dto.StudentGroupResponseDTO{
+		ID:             group.ID,
+		SubjectClassID: group.SubjectClassID,
+		Name:           group.Name,
+		MaxSize:        group.MaxSize,
+		SelfJoin:       group.SelfJoin,
+		MemberCount:    len(members),
+		Members:        members,
+		CreatedAt:      formatAPITime(group.CreatedAt),
+		UpdatedAt:      formatAPITime(group.UpdatedAt),
+	}
+}
@@ -1,35 +1,80 @@
 package realtime
 
 import (
-	"sync"
+	"encoding/json"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
 const (
-	pongWait     = 60 * time.Second
-	pingInterval = 45 * time.Second
+	pongWait      = 60 * time.Second
+	pingInterval  = 45 * time.Second
+	writeWait     = 10 * time.Second
+	sendQueueSize = 64
+)
+
+// Client transports. Both share the hub's queueing, replay, and topic
+// filtering; they differ only in how events reach the browser.
+const (
+	ClientTransportWebSocket = "websocket"
+	ClientTransportSSE       = "sse"
 )
 
 type Client struct {
-	UserID   string
-	SchoolID string
-	hub      *Hub
-	conn     *websocket.Conn
-	writeMu  sync.Mutex
+	UserID    string
+	SchoolID  string
+	Transport string
+	// ResumeAfter is the last sequence number the client saw before it
+	// reconnected, or zero for a fresh connection.
+	ResumeAfter uint64
+	// PresenceAudience lists the users who share a room with this user and
+	// are told when they come online or go offline. It is resolved at
+	// connect time; room changes apply on the next connection.
+	PresenceAudience []string
+
+	hub  *Hub
+	conn *websocket.Conn
+	send chan Event
+
+	// Owned by the hub goroutine once registered.
+	lastSeen time.Time
+	topics   map[string]bool
+	// Owned by the ReadLoop goroutine.
+	typingSentAt map[string]time.Time
 }
 
 func NewClient(hub *Hub, conn *websocket.Conn, userID string, schoolID string) *Client {
 	return &Client{
-		UserID:   userID,
-		SchoolID: schoolID,
-		hub:      hub,
-		conn:     conn,
+		UserID:    userID,
+		SchoolID:  schoolID,
+		Transport: ClientTransportWebSocket,
+		hub:       hub,
+		conn:      conn,
+		send:      make(chan Event, sendQueueSize),
+		topics:    allTopics(),
+
+		typingSentAt: make(map[string]time.Time),
 	}
 }
 
-func (c *Client) ReadLoop() {
+// NewSSEClient creates a receive-only client for a Server-Sent Events stream.
+// It has no socket; the SSE handler drains its queue directly.
+func NewSSEClient(hub *Hub, userID string, schoolID string) *Client {
+	return &Client{
+		UserID:    userID,
+		SchoolID:  schoolID,
+		Transport: ClientTransportSSE,
+		hub:       hub,
+		send:      make(chan Event, sendQueueSize),
+		topics:    allTopics(),
+	}
+}
+
+// ReadLoop reads client commands until the peer disconnects and passes each
+// one to handle. It must run on the handler goroutine; WriteLoop owns every
+// write to the socket.
+func (c *Client) ReadLoop(handle func(*Client, Command)) {
 	defer c.hub.Unregister(c)
 
 	c.conn.SetReadLimit(1024)
@@ -38,41 +83,85 @@ func (c *Client) ReadLoop() {
 		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	})
 
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var command Command
+		if err := json.Unmarshal(data, &command); err != nil || command.Type == "" {
+			c.hub.SendToClient(c, commandErrorEvent(c, "", "invalid command"))
+			continue
+		}
+		handle(c, command)
+	}
+}
+
+// WriteLoop writes any replayed events first, then drains the outbound queue
+// and sends keep-alive pings. The hub closes the queue when the client is
+// removed, which ends the loop and the underlying connection.
+func (c *Client) WriteLoop(replay []Event) {
 	ticker := time.NewTicker(pingInterval)
-	defer ticker.Stop()
+	defer func() {
+		ticker.Stop()
+		_ = c.conn.Close()
+	}()
 
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		for {
-			if _, _, err := c.conn.NextReader(); err != nil {
-				return
-			}
+	for _, event := range replay {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+		if err := c.conn.WriteJSON(event); err != nil {
+			return
 		}
-	}()
+	}
 
 	for {
 		select {
-		case <-done:
-			return
+		case event, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteJSON(event); err != nil {
+				return
+			}
 		case <-ticker.C:
-			c.writeMu.Lock()
-			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second))
-			c.writeMu.Unlock()
-			if err != nil {
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
 				return
 			}
 		}
 	}
 }
 
-func (c *Client) WriteEvent(event Event) error {
-	c.writeMu.Lock()
-	defer c.writeMu.Unlock()
-	_ = c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-	return c.conn.WriteJSON(event)
+// SetTopics replaces the topics the client receives. It must be called before
+// the client is registered; afterwards use Hub.Subscribe and Hub.Unsubscribe.
+func (c *Client) SetTopics(topics map[string]bool) {
+	c.topics = topics
+}
+
+// subscribed reports whether the client wants the event. Events without a
+// topic are control messages and are always delivered.
+func (c *Client) subscribed(event Event) bool {
+	return event.Topic == "" || c.topics == nil || c.topics[event.Topic]
 }
 
-func (c *Client) Close() {
-	_ = c.conn.Close()
+// enqueue never blocks the hub; it reports false when the queue is full.
+func (c *Client) enqueue(event Event) bool {
+	select {
+	case c.send <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+func commandErrorEvent(c *Client, command string, message string) Event {
+	return Event{
+		Type:     EventTypeCommandError,
+		SchoolID: c.SchoolID,
+		Payload: CommandErrorPayload{
+			Command: command,
+			Error:   message,
+		},
+	}
 }
@@ -2,13 +2,72 @@ package realtime
 
 type Event struct {
 	Type     string `json:"type"`
+	Seq      uint64 `json:"seq,omitempty"`
+	Topic    string `json:"topic,omitempty"`
 	RoomID   string `json:"roomId"`
 	SchoolID string `json:"schoolId"`
 	Payload  any    `json:"payload"`
 }
 
 const (
-	EventTypeNewMessage  = "new_message"
-	EventTypeMessageRead = "message_read"
-	EventTypeRoomUpdated = "room_updated"
+	EventTypeNewMessage      = "new_message"
+	EventTypeMessageRead     = "message_read"
+	EventTypeRoomUpdated     = "room_updated"
+	EventTypeMessageUpdated  = "message_updated"
+	EventTypeMessageDeleted  = "message_deleted"
+	EventTypeThreadUpdated   = "thread_updated"
+	EventTypeReactionUpdated = "reaction_updated"
+	EventTypeMuteUpdated     = "mute_updated"
+	EventTypePinUpdated      = "pin_updated"
+	EventTypeTyping          = "typing"
+	EventTypePresenceUpdated = "presence_updated"
+	EventTypeCommandError    = "command_error"
+	EventTypeResyncRequired  = "resync_required"
+
+	EventTypeNotificationCreated  = "notification_created"
+	EventTypeFeedPosted           = "feed_posted"
+	EventTypeSubmissionGraded     = "submission_graded"
+	EventTypeSubscriptionsUpdated = "subscriptions_updated"
+)
+
+// Command is a message sent by the client over the chat socket.
+type Command struct {
+	Type   string   `json:"type"`
+	RoomID string   `json:"roomId,omitempty"`
+	Topics []string `json:"topics,omitempty"`
+}
+
+const (
+	CommandTypingStart = "typing.start"
+	CommandTypingStop  = "typing.stop"
+	CommandHeartbeat   = "heartbeat"
+	CommandSubscribe   = "subscribe"
+	CommandUnsubscribe = "unsubscribe"
 )
+
+type TypingPayload struct {
+	RoomID           string `json:"roomId"`
+	UserID           string `json:"userId"`
+	IsTyping         bool   `json:"isTyping"`
+	ExpiresInSeconds int    `json:"expiresInSeconds,omitempty"`
+}
+
+type PresencePayload struct {
+	UserID     string `json:"userId"`
+	Online     bool   `json:"online"`
+	LastSeenAt string `json:"lastSeenAt"`
+}
+
+type CommandErrorPayload struct {
+	Command string `json:"command"`
+	Error   string `json:"error"`
+}
+
+type ResyncPayload struct {
+	LastSeq    uint64 `json:"lastSeq"`
+	CurrentSeq uint64 `json:"currentSeq"`
+}
+
+type SubscriptionsPayload struct {
+	Topics []string `json:"topics"`
+}
@@ -1,45 +1,128 @@
 package realtime
 
+import "time"
+
+const (
+	broadcastQueueSize    = 256
+	presenceTTL           = 90 * time.Second
+	presenceSweepInterval = 15 * time.Second
+)
+
 type Hub struct {
-	register   chan *Client
+	register   chan registration
 	unregister chan *Client
 	broadcast  chan broadcastRequest
+	heartbeat  chan *Client
+	presence   chan presenceQuery
+	stats      chan chan HubStats
+	subscribe  chan subscriptionRequest
 	clients    map[string]map[string]map[*Client]bool
+	online     map[string]map[string]bool
+	audiences  map[string]map[string][]string
+	logs       map[string]map[string]*eventLog
+
+	droppedEvents         uint64
+	slowClientDisconnects uint64
 }
 
 type broadcastRequest struct {
 	schoolID string
 	userIDs  []string
+	client   *Client
 	event    Event
+	// allSchools delivers to the users in every school where they are
+	// connected or still have a replay log, for events not tied to a school.
+	allSchools bool
+}
+
+type subscriptionRequest struct {
+	client    *Client
+	topics    []string
+	subscribe bool
+}
+
+type registration struct {
+	client *Client
+	reply  chan []Event
+}
+
+type presenceQuery struct {
+	schoolID string
+	userIDs  []string
+	reply    chan []string
+}
+
+type HubStats struct {
+	ConnectedClients      int            `json:"connectedClients"`
+	ClientsBySchool       map[string]int `json:"clientsBySchool"`
+	ClientsByTransport    map[string]int `json:"clientsByTransport"`
+	DroppedEvents         uint64         `json:"droppedEvents"`
+	SlowClientDisconnects uint64         `json:"slowClientDisconnects"`
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		register:   make(chan *Client),
+		register:   make(chan registration),
 		unregister: make(chan *Client),
-		broadcast:  make(chan broadcastRequest, 32),
+		broadcast:  make(chan broadcastRequest, broadcastQueueSize),
+		heartbeat:  make(chan *Client, broadcastQueueSize),
+		presence:   make(chan presenceQuery),
+		stats:      make(chan chan HubStats),
+		subscribe:  make(chan subscriptionRequest, broadcastQueueSize),
 		clients:    make(map[string]map[string]map[*Client]bool),
+		online:     make(map[string]map[string]bool),
+		audiences:  make(map[string]map[string][]string),
+		logs:       make(map[string]map[string]*eventLog),
 	}
 }
 
 func (h *Hub) Run() {
+	sweep := time.NewTicker(presenceSweepInterval)
+	defer sweep.Stop()
+
 	for {
 		select {
-		case client := <-h.register:
-			h.addClient(client)
+		case request := <-h.register:
+			h.addClient(request.client)
+			request.reply <- h.replayFor(request.client)
+			h.refreshPresence(request.client.SchoolID, request.client.UserID)
 		case client := <-h.unregister:
 			h.removeClient(client)
+			h.refreshPresence(client.SchoolID, client.UserID)
 		case request := <-h.broadcast:
+			if request.client != nil {
+				h.sendToClient(request.client, request.event)
+				continue
+			}
+			if request.allSchools {
+				h.broadcastToUsersInAllSchools(request)
+				continue
+			}
 			h.broadcastToUsers(request)
+		case client := <-h.heartbeat:
+			h.touch(client)
+		case query := <-h.presence:
+			query.reply <- h.onlineUserIDs(query.schoolID, query.userIDs)
+		case <-sweep.C:
+			h.sweepPresence()
+			h.sweepLogs()
+		case reply := <-h.stats:
+			reply <- h.snapshot()
+		case request := <-h.subscribe:
+			h.updateSubscriptions(request)
 		}
 	}
 }
 
-func (h *Hub) Register(client *Client) {
+// Register adds the client to the hub and returns the events it missed since
+// ResumeAfter, or a single resync_required event when the gap is too old.
+func (h *Hub) Register(client *Client) []Event {
 	if h == nil || client == nil {
-		return
+		return nil
 	}
-	h.register <- client
+	reply := make(chan []Event, 1)
+	h.register <- registration{client: client, reply: reply}
+	return <-reply
 }
 
 func (h *Hub) Unregister(client *Client) {
@@ -71,6 +154,77 @@ func (h *Hub) BroadcastToUser(schoolID string, userID string, event Event) {
 	}
 }
 
+// BroadcastToUserInAllSchools delivers an event that is not scoped to a school,
+// such as a personal notification, to every school connection of the user.
+func (h *Hub) BroadcastToUserInAllSchools(userID string, event Event) {
+	if h == nil || userID == "" {
+		return
+	}
+	h.broadcast <- broadcastRequest{
+		userIDs:    []string{userID},
+		event:      event,
+		allSchools: true,
+	}
+}
+
+// SendToClient queues an event for one connection only, such as a reply to a
+// command sent over that socket.
+func (h *Hub) SendToClient(client *Client, event Event) {
+	if h == nil || client == nil {
+		return
+	}
+	h.broadcast <- broadcastRequest{client: client, event: event}
+}
+
+// Heartbeat marks the client's user as present in its school.
+func (h *Hub) Heartbeat(client *Client) {
+	if h == nil || client == nil {
+		return
+	}
+	h.heartbeat <- client
+}
+
+// Subscribe adds topics to the client and confirms the resulting set with a
+// subscriptions_updated event.
+func (h *Hub) Subscribe(client *Client, topics []string) {
+	if h == nil || client == nil {
+		return
+	}
+	h.subscribe <- subscriptionRequest{client: client, topics: topics, subscribe: true}
+}
+
+// Unsubscribe removes topics from the client and confirms the resulting set.
+func (h *Hub) Unsubscribe(client *Client, topics []string) {
+	if h == nil || client == nil {
+		return
+	}
+	h.subscribe <- subscriptionRequest{client: client, topics: topics}
+}
+
+// OnlineUserIDs filters userIDs down to the users with a live, recently
+// heartbeating connection in the school.
+func (h *Hub) OnlineUserIDs(schoolID string, userIDs []string) []string {
+	if h == nil || schoolID == "" || len(userIDs) == 0 {
+		return make([]string, 0)
+	}
+	reply := make(chan []string, 1)
+	h.presence <- presenceQuery{
+		schoolID: schoolID,
+		userIDs:  uniqueStrings(userIDs),
+		reply:    reply,
+	}
+	return <-reply
+}
+
+func (h *Hub) Stats() HubStats {
+	if h == nil {
+		return HubStats{ClientsBySchool: make(map[string]int), ClientsByTransport: make(map[string]int)}
+	}
+	reply := make(chan HubStats, 1)
+	h.stats <- reply
+	return <-reply
+}
+
 func (h *Hub) addClient(client *Client) {
 	if h.clients[client.SchoolID] == nil {
 		h.clients[client.SchoolID] = make(map[string]map[*Client]bool)
@@ -78,7 +232,22 @@ func (h *Hub) addClient(client *Client) {
 	if h.clients[client.SchoolID][client.UserID] == nil {
 		h.clients[client.SchoolID][client.UserID] = make(map[*Client]bool)
 	}
+	client.lastSeen = time.Now()
 	h.clients[client.SchoolID][client.UserID][client] = true
+	if h.audiences[client.SchoolID] == nil {
+		h.audiences[client.SchoolID] = make(map[string][]string)
+	}
+	h.audiences[client.SchoolID][client.UserID] = client.PresenceAudience
+
+	log := h.userLog(client.SchoolID, client.UserID)
+	if log == nil {
+		if h.logs[client.SchoolID] == nil {
+			h.logs[client.SchoolID] = make(map[string]*eventLog)
+		}
+		log = newEventLog()
+		h.logs[client.SchoolID][client.UserID] = log
+	}
+	log.disconnectedAt = time.Time{}
 }
 
 func (h *Hub) removeClient(client *Client) {
@@ -92,28 +261,240 @@ func (h *Hub) removeClient(client *Client) {
 	}
 	if _, ok := connections[client]; ok {
 		delete(connections, client)
-		client.Close()
+		close(client.send)
 	}
 	if len(connections) == 0 {
 		delete(users, client.UserID)
+		if log := h.userLog(client.SchoolID, client.UserID); log != nil {
+			log.disconnectedAt = time.Now()
+		}
 	}
 	if len(users) == 0 {
 		delete(h.clients, client.SchoolID)
 	}
 }
 
+// broadcastToUsers only enqueues; a client whose queue is full is too slow to
+// keep up and is disconnected so it can reconnect and resume. Replayable
+// events are sequenced per user and logged even while the user is offline,
+// as long as their log is still retained.
 func (h *Hub) broadcastToUsers(request broadcastRequest) {
 	users := h.clients[request.schoolID]
-	if users == nil {
-		return
-	}
 	for _, userID := range request.userIDs {
+		event := request.event
+		event.Topic = topicOf(event.Type)
+		if replayableEventTypes[event.Type] {
+			log := h.userLog(request.schoolID, userID)
+			if log == nil {
+				continue
+			}
+			event = log.append(event)
+		}
 		for client := range users[userID] {
-			if err := client.WriteEvent(request.event); err != nil {
-				h.removeClient(client)
+			if !client.subscribed(event) {
+				continue
+			}
+			if !client.enqueue(event) {
+				h.disconnectSlowClient(client)
+			}
+		}
+	}
+}
+
+func (h *Hub) broadcastToUsersInAllSchools(request broadcastRequest) {
+	schoolIDs := make([]string, 0, len(h.logs))
+	for schoolID := range h.logs {
+		schoolIDs = append(schoolIDs, schoolID)
+	}
+	for _, schoolID := range schoolIDs {
+		for _, userID := range request.userIDs {
+			if h.userLog(schoolID, userID) == nil {
+				continue
+			}
+			event := request.event
+			event.SchoolID = schoolID
+			h.broadcastToUsers(broadcastRequest{schoolID: schoolID, userIDs: []string{userID}, event: event})
+		}
+	}
+}
+
+func (h *Hub) sendToClient(client *Client, event Event) {
+	if !h.clients[client.SchoolID][client.UserID][client] {
+		return
+	}
+	if !client.enqueue(event) {
+		h.disconnectSlowClient(client)
+	}
+}
+
+func (h *Hub) disconnectSlowClient(client *Client) {
+	h.droppedEvents++
+	h.slowClientDisconnects++
+	h.removeClient(client)
+	h.refreshPresence(client.SchoolID, client.UserID)
+}
+
+func (h *Hub) updateSubscriptions(request subscriptionRequest) {
+	client := request.client
+	if !h.clients[client.SchoolID][client.UserID][client] {
+		return
+	}
+	if client.topics == nil {
+		client.topics = allTopics()
+	}
+	for _, topic := range request.topics {
+		if request.subscribe {
+			client.topics[topic] = true
+		} else {
+			delete(client.topics, topic)
+		}
+	}
+	h.sendToClient(client, Event{
+		Type:     EventTypeSubscriptionsUpdated,
+		SchoolID: client.SchoolID,
+		Payload:  SubscriptionsPayload{Topics: sortedTopics(client.topics)},
+	})
+}
+
+func (h *Hub) touch(client *Client) {
+	if !h.clients[client.SchoolID][client.UserID][client] {
+		return
+	}
+	client.lastSeen = time.Now()
+	h.refreshPresence(client.SchoolID, client.UserID)
+}
+
+func (h *Hub) sweepPresence() {
+	for schoolID, users := range h.online {
+		for userID := range users {
+			h.refreshPresence(schoolID, userID)
+		}
+	}
+}
+
+// refreshPresence recomputes one user's presence and tells the connected
+// users who share a room with them when it changes.
+func (h *Hub) refreshPresence(schoolID string, userID string) {
+	lastSeen, online := h.userPresence(schoolID, userID)
+	audience := h.audiences[schoolID][userID]
+	if h.clients[schoolID][userID] == nil {
+		delete(h.audiences[schoolID], userID)
+		if len(h.audiences[schoolID]) == 0 {
+			delete(h.audiences, schoolID)
+		}
+	}
+	if online == h.online[schoolID][userID] {
+		return
+	}
+	if online {
+		if h.online[schoolID] == nil {
+			h.online[schoolID] = make(map[string]bool)
+		}
+		h.online[schoolID][userID] = true
+	} else {
+		delete(h.online[schoolID], userID)
+		if len(h.online[schoolID]) == 0 {
+			delete(h.online, schoolID)
+		}
+		if lastSeen.IsZero() {
+			lastSeen = time.Now()
+		}
+	}
+
+	event := Event{
+		Type:     EventTypePresenceUpdated,
+		SchoolID: schoolID,
+		Payload: PresencePayload{
+			UserID:     userID,
+			Online:     online,
+			LastSeenAt: lastSeen.UTC().Format(time.RFC3339),
+		},
+	}
+	for _, otherUserID := range audience {
+		if otherUserID == userID || h.clients[schoolID][otherUserID] == nil {
+			continue
+		}
+		h.broadcastToUsers(broadcastRequest{schoolID: schoolID, userIDs: []string{otherUserID}, event: event})
+	}
+}
+
+func (h *Hub) userPresence(schoolID string, userID string) (time.Time, bool) {
+	var lastSeen time.Time
+	for client := range h.clients[schoolID][userID] {
+		if client.lastSeen.After(lastSeen) {
+			lastSeen = client.lastSeen
+		}
+	}
+	return lastSeen, !lastSeen.IsZero() && time.Since(lastSeen) < presenceTTL
+}
+
+func (h *Hub) onlineUserIDs(schoolID string, userIDs []string) []string {
+	result := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if h.online[schoolID][userID] {
+			result = append(result, userID)
+		}
+	}
+	return result
+}
+
+func (h *Hub) userLog(schoolID string, userID string) *eventLog {
+	return h.logs[schoolID][userID]
+}
+
+func (h *Hub) replayFor(client *Client) []Event {
+	if client.ResumeAfter == 0 {
+		return nil
+	}
+	log := h.userLog(client.SchoolID, client.UserID)
+	missed, ok := log.since(client.ResumeAfter)
+	if !ok {
+		return []Event{resyncRequiredEvent(client.SchoolID, client.ResumeAfter, log.lastSeq)}
+	}
+	replay := make([]Event, 0, len(missed))
+	for _, event := range missed {
+		if client.subscribed(event) {
+			replay = append(replay, event)
+		}
+	}
+	return replay
+}
+
+// sweepLogs forgets the replay log of users who have been disconnected longer
+// than replayRetention; they will get resync_required if they come back.
+func (h *Hub) sweepLogs() {
+	for schoolID, logs := range h.logs {
+		for userID, log := range logs {
+			if log.disconnectedAt.IsZero() || time.Since(log.disconnectedAt) < replayRetention {
+				continue
+			}
+			delete(logs, userID)
+		}
+		if len(logs) == 0 {
+			delete(h.logs, schoolID)
+		}
+	}
+}
+
+func (h *Hub) snapshot() HubStats {
+	stats := HubStats{
+		ClientsBySchool:       make(map[string]int, len(h.clients)),
+		ClientsByTransport:    make(map[string]int),
+		DroppedEvents:         h.droppedEvents,
+		SlowClientDisconnects: h.slowClientDisconnects,
+	}
+	for schoolID, users := range h.clients {
+		count := 0
+		for _, connections := range users {
+			count += len(connections)
+			for client := range connections {
+				stats.ClientsByTransport[client.Transport]++
 			}
 		}
+		stats.ClientsBySchool[schoolID] = count
+		stats.ConnectedClients += count
 	}
+	return stats
 }
 
 func uniqueStrings(values []string) []string {
@@ -0,0 +1,208 @@
+package realtime
+
+import "testing"
+
+func newQueuedTestClient(hub *Hub, userID string, schoolID string) *Client {
+	return &Client{
+		UserID:    userID,
+		SchoolID:  schoolID,
+		Transport: ClientTransportWebSocket,
+		hub:       hub,
+		send:      make(chan Event, 1),
+	}
+}
+
+func TestHubBroadcastDisconnectsClientWhenQueueOverflows(t *testing.T) {
+	hub := NewHub()
+	slow := newQueuedTestClient(hub, "user-1", "school-1")
+	fast := newQueuedTestClient(hub, "user-2", "school-1")
+	hub.addClient(slow)
+	hub.addClient(fast)
+
+	hub.broadcastToUsers(broadcastRequest{schoolID: "school-1", userIDs: []string{"user-1", "user-2"}, event: Event{Type: EventTypeNewMessage}})
+	<-fast.send
+	hub.broadcastToUsers(broadcastRequest{schoolID: "school-1", userIDs: []string{"user-1", "user-2"}, event: Event{Type: EventTypeNewMessage}})
+
+	stats := hub.snapshot()
+	if stats.ConnectedClients != 1 || stats.ClientsBySchool["school-1"] != 1 {
+		t.Fatalf("expected only the fast client to remain connected, got %+v", stats)
+	}
+	if stats.DroppedEvents != 1 || stats.SlowClientDisconnects != 1 {
+		t.Fatalf("expected one dropped event and one disconnect, got %+v", stats)
+	}
+
+	<-slow.send
+	if _, ok := <-slow.send; ok {
+		t.Fatal("expected slow client queue to be closed")
+	}
+	if event := <-fast.send; event.Type != EventTypeNewMessage {
+		t.Fatalf("expected fast client to receive the second event, got %q", event.Type)
+	}
+}
+
+func TestHubStatsCountsClientsPerSchool(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	hub.Register(newQueuedTestClient(hub, "user-1", "school-1"))
+	hub.Register(newQueuedTestClient(hub, "user-1", "school-1"))
+	hub.Register(newQueuedTestClient(hub, "user-2", "school-2"))
+
+	stats := hub.Stats()
+	if stats.ConnectedClients != 3 {
+		t.Fatalf("expected 3 connected clients, got %d", stats.ConnectedClients)
+	}
+	if stats.ClientsBySchool["school-1"] != 2 || stats.ClientsBySchool["school-2"] != 1 {
+		t.Fatalf("unexpected per-school counts: %+v", stats.ClientsBySchool)
+	}
+}
+
+func TestHubPresenceNotifiesRoomPeersAndExpiresStaleHeartbeats(t *testing.T) {
+	hub := NewHub()
+	watcher := newQueuedTestClient(hub, "user-1", "school-1")
+	watcher.PresenceAudience = []string{"user-2"}
+	hub.addClient(watcher)
+	hub.refreshPresence("school-1", "user-1")
+
+	member := newQueuedTestClient(hub, "user-2", "school-1")
+	member.PresenceAudience = []string{"user-1"}
+	hub.addClient(member)
+	hub.refreshPresence("school-1", "user-2")
+
+	event := <-watcher.send
+	payload, ok := event.Payload.(PresencePayload)
+	if event.Type != EventTypePresenceUpdated || !ok || payload.UserID != "user-2" || !payload.Online {
+		t.Fatalf("expected user-2 online presence event, got %+v", event)
+	}
+	if online := hub.onlineUserIDs("school-1", []string{"user-1", "user-2", "user-3"}); len(online) != 2 {
+		t.Fatalf("expected two online users, got %v", online)
+	}
+
+	member.lastSeen = member.lastSeen.Add(-2 * presenceTTL)
+	hub.sweepPresence()
+
+	event = <-watcher.send
+	payload, ok = event.Payload.(PresencePayload)
+	if !ok || payload.UserID != "user-2" || payload.Online {
+		t.Fatalf("expected user-2 offline presence event, got %+v", event)
+	}
+	if online := hub.onlineUserIDs("school-1", []string{"user-2"}); len(online) != 0 {
+		t.Fatalf("expected stale user to be offline, got %v", online)
+	}
+}
+
+func TestHubPresenceSkipsUsersWithoutASharedRoom(t *testing.T) {
+	hub := NewHub()
+	stranger := newQueuedTestClient(hub, "user-1", "school-1")
+	hub.addClient(stranger)
+	hub.refreshPresence("school-1", "user-1")
+
+	member := newQueuedTestClient(hub, "user-2", "school-1")
+	member.PresenceAudience = []string{"user-3"}
+	hub.addClient(member)
+	hub.refreshPresence("school-1", "user-2")
+
+	hub.removeClient(member)
+	hub.refreshPresence("school-1", "user-2")
+
+	select {
+	case event := <-stranger.send:
+		t.Fatalf("expected no presence event for a user outside the rooms, got %+v", event)
+	default:
+	}
+	if _, ok := hub.audiences["school-1"]["user-2"]; ok {
+		t.Fatal("expected the audience to be dropped once the user disconnects")
+	}
+}
+
+func TestHubReplaysMissedEventsAfterReconnect(t *testing.T) {
+	hub := NewHub()
+	first := newQueuedTestClient(hub, "user-1", "school-1")
+	first.send = make(chan Event, 4)
+	hub.addClient(first)
+
+	hub.broadcastToUsers(broadcastRequest{schoolID: "school-1", userIDs: []string{"user-1"}, event: Event{Type: EventTypeNewMessage}})
+	seen := (<-first.send).Seq
+	if seen == 0 {
+		t.Fatal("expected replayable event to carry a sequence number")
+	}
+	hub.removeClient(first)
+
+	hub.broadcastToUsers(broadcastRequest{schoolID: "school-1", userIDs: []string{"user-1"}, event: Event{Type: EventTypeNewMessage}})
+	hub.broadcastToUsers(broadcastRequest{schoolID: "school-1", userIDs: []string{"user-1"}, event: Event{Type: EventTypeTyping}})
+	hub.broadcastToUsers(broadcastRequest{schoolID: "school-1", userIDs: []string{"user-1"}, event: Event{Type: EventTypeRoomUpdated}})
+
+	second := newQueuedTestClient(hub, "user-1", "school-1")
+	second.ResumeAfter = seen
+	hub.addClient(second)
+	replay := hub.replayFor(second)
+	if len(replay) != 2 || replay[0].Seq != seen+1 || replay[1].Seq != seen+2 {
+		t.Fatalf("expected the two missed replayable events in order, got %+v", replay)
+	}
+
+	stale := newQueuedTestClient(hub, "user-1", "school-1")
+	stale.ResumeAfter = seen + 100
+	replay = hub.replayFor(stale)
+	if len(replay) != 1 || replay[0].Type != EventTypeResyncRequired {
+		t.Fatalf("expected resync_required for an unknown sequence, got %+v", replay)
+	}
+}
+
+func TestEventLogRequiresResyncWhenGapWasEvicted(t *testing.T) {
+	log := newEventLog()
+	start := log.lastSeq
+	for i := 0; i < replayLogSize+1; i++ {
+		log.append(Event{Type: EventTypeNewMessage})
+	}
+
+	if _, ok := log.since(start); ok {
+		t.Fatal("expected evicted gap to require resync")
+	}
+	missed, ok := log.since(start + 1)
+	if !ok || len(missed) != replayLogSize {
+		t.Fatalf("expected full log replay, got %d events (ok=%v)", len(missed), ok)
+	}
+}
+
+func TestHubFiltersTopicsAndDeliversPersonalEventsInAllSchools(t *testing.T) {
+	hub := NewHub()
+	chatOnly := newQueuedTestClient(hub, "user-1", "school-1")
+	chatOnly.send = make(chan Event, 4)
+	chatOnly.SetTopics(map[string]bool{TopicChat: true})
+	everything := newQueuedTestClient(hub, "user-1", "school-2")
+	everything.send = make(chan Event, 4)
+	hub.addClient(chatOnly)
+	hub.addClient(everything)
+
+	hub.broadcastToUsersInAllSchools(broadcastRequest{userIDs: []string{"user-1"}, event: Event{Type: EventTypeNotificationCreated}, allSchools: true})
+
+	event := <-everything.send
+	if event.Type != EventTypeNotificationCreated || event.Topic != TopicNotifications || event.SchoolID != "school-2" {
+		t.Fatalf("expected a notification scoped to school-2, got %+v", event)
+	}
+	if len(chatOnly.send) != 0 {
+		t.Fatalf("expected chat-only client to skip notifications, got %d queued", len(chatOnly.send))
+	}
+
+	hub.updateSubscriptions(subscriptionRequest{client: chatOnly, topics: []string{TopicNotifications}, subscribe: true})
+	event = <-chatOnly.send
+	payload, ok := event.Payload.(SubscriptionsPayload)
+	if event.Type != EventTypeSubscriptionsUpdated || !ok || len(payload.Topics) != 2 {
+		t.Fatalf("expected confirmed chat and notifications topics, got %+v", event)
+	}
+
+	hub.broadcastToUsersInAllSchools(broadcastRequest{userIDs: []string{"user-1"}, event: Event{Type: EventTypeNotificationCreated}, allSchools: true})
+	if event := <-chatOnly.send; event.Type != EventTypeNotificationCreated {
+		t.Fatalf("expected subscribed client to receive the notification, got %q", event.Type)
+	}
+}
+
+func TestParseTopicsRejectsUnknownTopic(t *testing.T) {
+	if _, err := ParseTopics("chat,weather"); err == nil {
+		t.Fatal("expected unknown topic to be rejected")
+	}
+	topics, err := ParseTopics("")
+	if err != nil || len(topics) != len(knownTopics) {
+		t.Fatalf("expected empty topics to subscribe to everything, got %v (%v)", topics, err)
+	}
+}
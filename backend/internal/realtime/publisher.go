@@ -0,0 +1,38 @@
+package realtime
+
+import "backend/internal/dto"
+
+// Publisher adapts the hub to service.RealtimePublisher so services can push
+// events without depending on the realtime package.
+type Publisher struct {
+	hub *Hub
+}
+
+func NewPublisher(hub *Hub) *Publisher {
+	return &Publisher{hub: hub}
+}
+
+// NotificationCreated reaches the user in every school they are connected to,
+// since notifications are personal rather than school scoped.
+func (p *Publisher) NotificationCreated(userID string, notification dto.NotificationResponseDTO) {
+	p.hub.BroadcastToUserInAllSchools(userID, Event{
+		Type:    EventTypeNotificationCreated,
+		Payload: notification,
+	})
+}
+
+func (p *Publisher) FeedPosted(schoolID string, userIDs []string, feed dto.FeedPostedEventDTO) {
+	p.hub.BroadcastToUsers(schoolID, userIDs, Event{
+		Type:     EventTypeFeedPosted,
+		SchoolID: schoolID,
+		Payload:  feed,
+	})
+}
+
+func (p *Publisher) SubmissionGraded(schoolID string, userID string, grade dto.SubmissionGradedEventDTO) {
+	p.hub.BroadcastToUser(schoolID, userID, Event{
+		Type:     EventTypeSubmissionGraded,
+		SchoolID: schoolID,
+		Payload:  grade,
+	})
+}
@@ -0,0 +1,85 @@
+package realtime
+
+import "time"
+
+const (
+	replayLogSize   = 256
+	replayRetention = 15 * time.Minute
+)
+
+// replayableEventTypes are durable changes a reconnecting client may have
+// missed. Typing, presence, and command errors are only meaningful live.
+var replayableEventTypes = map[string]bool{
+	EventTypeNewMessage:          true,
+	EventTypeMessageRead:         true,
+	EventTypeRoomUpdated:         true,
+	EventTypeMessageUpdated:      true,
+	EventTypeMessageDeleted:      true,
+	EventTypeThreadUpdated:       true,
+	EventTypeReactionUpdated:     true,
+	EventTypeMuteUpdated:         true,
+	EventTypePinUpdated:          true,
+	EventTypeNotificationCreated: true,
+	EventTypeFeedPosted:          true,
+	EventTypeSubmissionGraded:    true,
+}
+
+// eventLog keeps the most recent replayable events sent to one user in one
+// school. Sequence numbers start from the creation time in microseconds so a
+// log recreated after eviction or a restart never reuses an older number.
+type eventLog struct {
+	lastSeq        uint64
+	events         []Event
+	disconnectedAt time.Time
+}
+
+func newEventLog() *eventLog {
+	return &eventLog{
+		lastSeq: uint64(time.Now().UnixMicro()),
+		events:  make([]Event, 0, replayLogSize),
+	}
+}
+
+func (l *eventLog) append(event Event) Event {
+	l.lastSeq++
+	event.Seq = l.lastSeq
+	if len(l.events) == replayLogSize {
+		copy(l.events, l.events[1:])
+		l.events = l.events[:replayLogSize-1]
+	}
+	l.events = append(l.events, event)
+	return event
+}
+
+// since returns the events after lastSeq, or false when the gap can no longer
+// be filled from the log.
+func (l *eventLog) since(lastSeq uint64) ([]Event, bool) {
+	if lastSeq > l.lastSeq {
+		return nil, false
+	}
+	if len(l.events) > 0 && lastSeq+1 < l.events[0].Seq {
+		return nil, false
+	}
+	if len(l.events) == 0 && lastSeq != l.lastSeq {
+		return nil, false
+	}
+
+	missed := make([]Event, 0)
+	for _, event := range l.events {
+		if event.Seq > lastSeq {
+			missed = append(missed, event)
+		}
+	}
+	return missed, true
+}
+
+func resyncRequiredEvent(schoolID string, lastSeq uint64, currentSeq uint64) Event {
+	return Event{
+		Type:     EventTypeResyncRequired,
+		SchoolID: schoolID,
+		Payload: ResyncPayload{
+			LastSeq:    lastSeq,
+			CurrentSeq: currentSeq,
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	sseKeepAliveInterval = 15 * time.Second
+	sseRetry             = 3 * time.Second
+)
+
+// Stream serves the same events as Chat over Server-Sent Events, for networks
+// whose proxies block WebSocket upgrades. The stream is receive-only: topics
+// come from the query string and every keep-alive also counts as a presence
+// heartbeat. Sequenced events carry their seq as the SSE id, so the browser's
+// automatic Last-Event-ID on reconnect resumes like lastSeq does.
+func (h *WebSocketHandler) Stream(c *gin.Context) {
+	lastSeq := c.GetHeader("Last-Event-ID")
+	if strings.TrimSpace(lastSeq) == "" {
+		lastSeq = c.Query("lastSeq")
+	}
+	request, ok := h.authorizeHandshake(c, lastSeq)
+	if !ok {
+		return
+	}
+
+	client := NewSSEClient(h.hub, request.userID, request.schoolID)
+	client.ResumeAfter = request.resumeAfter
+	client.SetTopics(request.topics)
+	client.PresenceAudience = request.audience
+	replay := h.hub.Register(client)
+	defer h.hub.Unregister(client)
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if _, err := fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry.Milliseconds()); err != nil {
+		return
+	}
+	for _, event := range replay {
+		if err := writeSSEEvent(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(sseKeepAliveInterval)
+	defer ticker.Stop()
+	done := c.Request.Context().Done()
+
+	for {
+		select {
+		case <-done:
+			return
+		case event, ok := <-client.send:
+			if !ok {
+				return
+			}
+			if err := writeSSEEvent(c.Writer, event); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-ticker.C:
+			if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+			h.hub.Heartbeat(client)
+		}
+	}
+}
+
+// writeSSEEvent writes one unnamed SSE message so browsers deliver every event
+// type to EventSource.onmessage, matching the WebSocket message shape.
+func writeSSEEvent(w io.Writer, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if event.Seq != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", event.Seq); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
@@ -0,0 +1,36 @@
+package realtime
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteSSEEventUsesSeqAsEventID(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSSEEvent(&buf, Event{Type: EventTypeNewMessage, Seq: 42, SchoolID: "school-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "id: 42\ndata: {\"type\":\"new_message\",\"seq\":42,") || !strings.HasSuffix(buf.String(), "\n\n") {
+		t.Fatalf("unexpected sequenced SSE frame %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeSSEEvent(&buf, Event{Type: EventTypeTyping}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "id:") {
+		t.Fatalf("expected live-only event without an id, got %q", buf.String())
+	}
+}
+
+func TestHubStatsCountsClientsPerTransport(t *testing.T) {
+	hub := NewHub()
+	hub.addClient(newQueuedTestClient(hub, "user-1", "school-1"))
+	hub.addClient(NewSSEClient(hub, "user-2", "school-1"))
+
+	stats := hub.snapshot()
+	if stats.ClientsByTransport[ClientTransportWebSocket] != 1 || stats.ClientsByTransport[ClientTransportSSE] != 1 {
+		t.Fatalf("unexpected per-transport counts: %+v", stats.ClientsByTransport)
+	}
+}
@@ -0,0 +1,98 @@
+package realtime
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Topics group event types so a client can choose which parts of the LMS it
+// wants pushed over its socket.
+const (
+	TopicChat          = "chat"
+	TopicNotifications = "notifications"
+	TopicFeed          = "feed"
+	TopicGrades        = "grades"
+)
+
+var eventTopics = map[string]string{
+	EventTypeNewMessage:          TopicChat,
+	EventTypeMessageRead:         TopicChat,
+	EventTypeRoomUpdated:         TopicChat,
+	EventTypeMessageUpdated:      TopicChat,
+	EventTypeMessageDeleted:      TopicChat,
+	EventTypeThreadUpdated:       TopicChat,
+	EventTypeReactionUpdated:     TopicChat,
+	EventTypeMuteUpdated:         TopicChat,
+	EventTypePinUpdated:          TopicChat,
+	EventTypeTyping:              TopicChat,
+	EventTypePresenceUpdated:     TopicChat,
+	EventTypeNotificationCreated: TopicNotifications,
+	EventTypeFeedPosted:          TopicFeed,
+	EventTypeSubmissionGraded:    TopicGrades,
+}
+
+var knownTopics = map[string]bool{
+	TopicChat:          true,
+	TopicNotifications: true,
+	TopicFeed:          true,
+	TopicGrades:        true,
+}
+
+// topicOf returns the topic an event type belongs to. Control events such as
+// command_error and resync_required have no topic and are always delivered.
+func topicOf(eventType string) string {
+	return eventTopics[eventType]
+}
+
+func allTopics() map[string]bool {
+	topics := make(map[string]bool, len(knownTopics))
+	for topic := range knownTopics {
+		topics[topic] = true
+	}
+	return topics
+}
+
+// ParseTopics reads a comma-separated topic list from the handshake. An empty
+// value subscribes to every topic.
+func ParseTopics(raw string) (map[string]bool, error) {
+	if strings.TrimSpace(raw) == "" {
+		return allTopics(), nil
+	}
+	topics, err := validateTopics(strings.Split(raw, ","))
+	if err != nil {
+		return nil, err
+	}
+	if len(topics) == 0 {
+		return allTopics(), nil
+	}
+	result := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		result[topic] = true
+	}
+	return result, nil
+}
+
+func validateTopics(values []string) ([]string, error) {
+	topics := make([]string, 0, len(values))
+	for _, value := range values {
+		topic := strings.ToLower(strings.TrimSpace(value))
+		if topic == "" {
+			continue
+		}
+		if !knownTopics[topic] {
+			return nil, fmt.Errorf("unknown topic: %s", topic)
+		}
+		topics = append(topics, topic)
+	}
+	return uniqueStrings(topics), nil
+}
+
+func sortedTopics(topics map[string]bool) []string {
+	result := make([]string, 0, len(topics))
+	for topic := range topics {
+		result = append(result, topic)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -4,13 +4,20 @@ import (
 	"backend/internal/service"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/websocket"
 )
 
+const (
+	typingThrottle = 3 * time.Second
+	typingExpiry   = 6 * time.Second
+)
+
 type WebSocketHandler struct {
 	hub         *Hub
 	chatService service.ChatService
@@ -29,17 +36,50 @@ func NewWebSocketHandler(hub *Hub, chatService service.ChatService) *WebSocketHa
 	}
 }
 
+// handshake is the validated connection request shared by the WebSocket and
+// SSE transports.
+type handshake struct {
+	userID      string
+	schoolID    string
+	resumeAfter uint64
+	topics      map[string]bool
+	audience    []string
+}
+
 func (h *WebSocketHandler) Chat(c *gin.Context) {
+	request, ok := h.authorizeHandshake(c, c.Query("lastSeq"))
+	if !ok {
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+
+	client := NewClient(h.hub, conn, request.userID, request.schoolID)
+	client.ResumeAfter = request.resumeAfter
+	client.SetTopics(request.topics)
+	client.PresenceAudience = request.audience
+	replay := h.hub.Register(client)
+	go client.WriteLoop(replay)
+	client.ReadLoop(h.handleCommand)
+}
+
+// authorizeHandshake validates the token, school context, resume position, and
+// topics of a realtime connection and resolves who sees its presence. It writes the error response itself and
+// reports false when the connection must not be opened.
+func (h *WebSocketHandler) authorizeHandshake(c *gin.Context, lastSeq string) (handshake, bool) {
 	tokenValue := extractHandshakeToken(c)
 	if tokenValue == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
+		return handshake{}, false
 	}
 
 	userID, err := parseUserIDFromToken(tokenValue)
 	if err != nil || userID == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-		return
+		return handshake{}, false
 	}
 
 	schoolID := strings.TrimSpace(c.Query("schoolId"))
@@ -48,27 +88,132 @@ func (h *WebSocketHandler) Chat(c *gin.Context) {
 	}
 	if schoolID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "School context required"})
-		return
+		return handshake{}, false
+	}
+
+	var resumeAfter uint64
+	if raw := strings.TrimSpace(lastSeq); raw != "" {
+		resumeAfter, err = strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lastSeq"})
+			return handshake{}, false
+		}
+	}
+
+	topics, err := ParseTopics(c.Query("topics"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topics"})
+		return handshake{}, false
 	}
 
 	allowed, err := h.chatService.CanAccessSchoolChat(userID, schoolID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify chat access"})
-		return
+		return handshake{}, false
 	}
 	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: chat school access denied"})
+		return handshake{}, false
+	}
+
+	audience, err := h.chatService.ListPresenceRecipients(userID, schoolID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load chat presence recipients"})
+		return handshake{}, false
+	}
+
+	return handshake{
+		userID:      userID,
+		schoolID:    schoolID,
+		resumeAfter: resumeAfter,
+		topics:      topics,
+		audience:    audience,
+	}, true
+}
+
+func (h *WebSocketHandler) handleCommand(client *Client, command Command) {
+	switch command.Type {
+	case CommandHeartbeat:
+		h.hub.Heartbeat(client)
+	case CommandTypingStart, CommandTypingStop:
+		h.handleTyping(client, command)
+	case CommandSubscribe, CommandUnsubscribe:
+		h.handleSubscription(client, command)
+	default:
+		h.hub.SendToClient(client, commandErrorEvent(client, command.Type, "unsupported command"))
+	}
+}
+
+func (h *WebSocketHandler) handleTyping(client *Client, command Command) {
+	roomID := strings.TrimSpace(command.RoomID)
+	if roomID == "" {
+		h.hub.SendToClient(client, commandErrorEvent(client, command.Type, "roomId is required"))
 		return
 	}
 
-	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	// Only rooms the client may access are throttled, so made-up room IDs
+	// cannot grow typingSentAt.
+	isTyping := command.Type == CommandTypingStart
+	if sentAt, ok := client.typingSentAt[roomID]; isTyping && ok && time.Since(sentAt) < typingThrottle {
+		return
+	}
+
+	allowed, _, err := h.chatService.CanAccessRoom(client.UserID, client.SchoolID, roomID)
+	if err != nil || !allowed {
+		h.hub.SendToClient(client, commandErrorEvent(client, command.Type, "chat room access denied"))
+		return
+	}
+	if isTyping {
+		client.typingSentAt[roomID] = time.Now()
+	} else {
+		delete(client.typingSentAt, roomID)
+	}
+	recipients, err := h.chatService.ListRealtimeRecipients(client.UserID, client.SchoolID, roomID)
 	if err != nil {
 		return
 	}
 
-	client := NewClient(h.hub, conn, userID, schoolID)
-	h.hub.Register(client)
-	client.ReadLoop()
+	others := make([]string, 0, len(recipients))
+	for _, recipientID := range recipients {
+		if recipientID != client.UserID {
+			others = append(others, recipientID)
+		}
+	}
+	payload := TypingPayload{
+		RoomID:   roomID,
+		UserID:   client.UserID,
+		IsTyping: isTyping,
+	}
+	if isTyping {
+		payload.ExpiresInSeconds = int(typingExpiry / time.Second)
+	}
+	h.hub.BroadcastToUsers(client.SchoolID, others, Event{
+		Type:     EventTypeTyping,
+		RoomID:   roomID,
+		SchoolID: client.SchoolID,
+		Payload:  payload,
+	})
+}
+
+func (h *WebSocketHandler) handleSubscription(client *Client, command Command) {
+	topics, err := validateTopics(command.Topics)
+	if err != nil {
+		h.hub.SendToClient(client, commandErrorEvent(client, command.Type, err.Error()))
+		return
+	}
+	if len(topics) == 0 {
+		h.hub.SendToClient(client, commandErrorEvent(client, command.Type, "topics is required"))
+		return
+	}
+	if command.Type == CommandSubscribe {
+		h.hub.Subscribe(client, topics)
+		return
+	}
+	h.hub.Unsubscribe(client, topics)
+}
+
+func (h *WebSocketHandler) Stats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.hub.Stats())
 }
 
 func extractHandshakeToken(c *gin.Context) string {
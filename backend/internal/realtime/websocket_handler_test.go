@@ -0,0 +1,52 @@
+package realtime
+
+import (
+	"backend/internal/repository"
+	"backend/internal/service"
+	"testing"
+	"time"
+)
+
+type typingChatServiceStub struct {
+	service.ChatService
+	allowedRoomID string
+	checks        int
+}
+
+func (s *typingChatServiceStub) CanAccessRoom(userID string, schoolID string, roomID string) (bool, *repository.ChatRoomRow, error) {
+	s.checks++
+	return roomID == s.allowedRoomID, nil, nil
+}
+
+func (s *typingChatServiceStub) ListRealtimeRecipients(string, string, string) ([]string, error) {
+	return []string{"user-1", "user-2"}, nil
+}
+
+func TestHandleTypingThrottlesOnlyAccessibleRooms(t *testing.T) {
+	hub := NewHub()
+	chat := &typingChatServiceStub{allowedRoomID: "room-1"}
+	h := NewWebSocketHandler(hub, chat)
+	client := newQueuedTestClient(hub, "user-1", "school-1")
+	client.typingSentAt = make(map[string]time.Time)
+
+	for i := 0; i < 2; i++ {
+		h.handleTyping(client, Command{Type: CommandTypingStart, RoomID: "room-unknown"})
+		request := <-hub.broadcast
+		if request.client != client || request.event.Type != EventTypeCommandError {
+			t.Fatalf("expected every denied typing command to be answered with an error, got %+v", request)
+		}
+	}
+	if _, ok := client.typingSentAt["room-unknown"]; ok {
+		t.Fatal("expected a denied room not to be recorded")
+	}
+
+	h.handleTyping(client, Command{Type: CommandTypingStart, RoomID: "room-1"})
+	if request := <-hub.broadcast; request.event.Type != EventTypeTyping || len(request.userIDs) != 1 || request.userIDs[0] != "user-2" {
+		t.Fatalf("expected typing to reach the other room member, got %+v", request)
+	}
+	checks := chat.checks
+	h.handleTyping(client, Command{Type: CommandTypingStart, RoomID: "room-1"})
+	if chat.checks != checks || len(hub.broadcast) != 0 {
+		t.Fatal("expected a repeated typing.start to be throttled before the access check")
+	}
+}
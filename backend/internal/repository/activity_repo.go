@@ -85,6 +85,7 @@ func (r *activityRepository) GetStudentAssignmentDue(userID string, schoolID str
 			AND e.enr_role = 'student'
 			AND e.left_at IS NULL
 			AND a.deleted_at IS NULL
+			AND a.asg_publish_status = 'published'
 			AND c.deleted_at IS NULL
 			AND a.asg_deadline >= ?
 			AND a.asg_deadline < ?
@@ -108,7 +109,7 @@ func (r *activityRepository) GetStudentMaterialCreated(userID string, schoolID s
 			'material_created' AS activity_type,
 			COALESCE(m.mat_title, 'Materi') AS title,
 			CONCAT('Materi baru ', sub.sub_name, ' · ', c.cls_title) AS description,
-			m.created_at AS event_at,
+			COALESCE(m.mat_published_at, m.created_at) AS event_at,
 			'normal' AS priority,
 			sub.sub_id AS subject_id,
 			COALESCE(sub.sub_name, '') AS subject_name,
@@ -135,9 +136,10 @@ func (r *activityRepository) GetStudentMaterialCreated(userID string, schoolID s
 			AND e.enr_role = 'student'
 			AND e.left_at IS NULL
 			AND m.deleted_at IS NULL
+			AND m.mat_publish_status = 'published'
 			AND c.deleted_at IS NULL
-			AND m.created_at >= ?
-			AND m.created_at < ?
+			AND COALESCE(m.mat_published_at, m.created_at) >= ?
+			AND COALESCE(m.mat_published_at, m.created_at) < ?
 	`, schoolID, schoolID, schoolID, userID, schoolID, schoolID, from, to).Scan(&rows).Error
 	return rows, err
 }
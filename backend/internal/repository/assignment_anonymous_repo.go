@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SetAssignmentAnonymousGrading turns anonymous grading on or off. Turning it
+// on hides identities again, so the release time is cleared either way.
+func (r *assignmentRepository) SetAssignmentAnonymousGrading(assignmentID string, enabled bool, key string) error {
+	result := r.db.Model(&domain.Assignment{}).Where("asg_id = ?", assignmentID).Updates(map[string]interface{}{
+		"asg_anonymous_grading":      enabled,
+		"asg_anonymous_key":          key,
+		"asg_identities_released_at": nil,
+	})
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (r *assignmentRepository) AssignmentHasAssessments(assignmentID string) (bool, error) {
+	var count int64
+	err := r.db.Table("edv.assessments asm").
+		Joins("JOIN edv.submissions s ON s.sbm_id = asm.asm_sbm_id AND s.deleted_at IS NULL").
+		Where("s.sbm_asg_id = ?", assignmentID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *assignmentRepository) CountUngradedSubmissions(assignmentID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Submission{}).
+		Where("sbm_asg_id = ?", assignmentID).
+		Where("NOT EXISTS (SELECT 1 FROM edv.assessments asm WHERE asm.asm_sbm_id = submissions.sbm_id)").
+		Count(&count).Error
+	return count, err
+}
+
+// ReleaseAssignmentIdentities records the release once; it reports false when
+// identities were already released or the assignment is not anonymous.
+func (r *assignmentRepository) ReleaseAssignmentIdentities(assignmentID string, now time.Time) (bool, error) {
+	result := r.db.Model(&domain.Assignment{}).
+		Where("asg_id = ? AND asg_anonymous_grading AND asg_identities_released_at IS NULL", assignmentID).
+		Update("asg_identities_released_at", now)
+	return result.RowsAffected > 0, result.Error
+}
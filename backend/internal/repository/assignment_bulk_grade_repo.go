@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+
+	"gorm.io/gorm"
+)
+
+// ListGradingRoster lists the students of the assignment's subject class by
+// name, each with the latest submission that counts for them, be it their own
+// or their group's, and the current grade of that submission.
+func (r *assignmentRepository) ListGradingRoster(assignmentID string) ([]dto.GradingRosterRow, error) {
+	var rows []dto.GradingRosterRow
+	err := r.db.Table("edv.assignments a").
+		Select(`
+			u.usr_id AS user_id,
+			u.usr_nama_lengkap AS full_name,
+			u.usr_email AS email,
+			grp.grp_name AS group_name,
+			latest_s.sbm_id AS submission_id,
+			latest_s.submitted_at AS submitted_at,
+			asm.asm_score AS score,
+			asm.asm_feedback AS feedback
+		`).
+		Joins("JOIN edv.subject_classes sc ON sc.scl_id = a.asg_scl_id").
+		Joins("JOIN edv.enrollments e ON e.enr_cls_id = sc.scl_cls_id AND e.enr_role = 'student' AND e.left_at IS NULL").
+		Joins("JOIN edv.school_users scu ON scu.scu_id = e.enr_scu_id AND scu.deleted_at IS NULL").
+		Joins("JOIN edv.users u ON u.usr_id = scu.scu_usr_id").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT latest_s.sbm_id, latest_s.sbm_grp_id, latest_s.submitted_at
+			FROM edv.submissions latest_s
+			WHERE latest_s.sbm_asg_id = a.asg_id
+				AND latest_s.deleted_at IS NULL
+				AND `+studentSubmissionCondition("latest_s")+`
+			ORDER BY latest_s.submitted_at DESC, latest_s.sbm_id DESC
+			LIMIT 1
+		) latest_s ON true`, gorm.Expr("u.usr_id"), gorm.Expr("u.usr_id")).
+		Joins(`LEFT JOIN LATERAL (
+			SELECT latest_asm.asm_score, latest_asm.asm_feedback
+			FROM edv.assessments latest_asm
+			WHERE latest_asm.asm_sbm_id = latest_s.sbm_id
+			ORDER BY latest_asm.assessed_at DESC, latest_asm.asm_id DESC
+			LIMIT 1
+		) asm ON true`).
+		Joins("LEFT JOIN edv.student_groups grp ON grp.grp_id = latest_s.sbm_grp_id").
+		Where("a.asg_id = ?", assignmentID).
+		Order("u.usr_nama_lengkap ASC, u.usr_email ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// SaveBulkGrades writes the version grades and upserts the assessments of a
+// grade import in one transaction, so a failure leaves no grade changed.
+func (r *assignmentRepository) SaveBulkGrades(versions []*domain.SubmissionVersion, assessments []*domain.Assessment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, version := range versions {
+			if err := gradeSubmissionVersion(tx, version); err != nil {
+				return err
+			}
+		}
+		for _, asm := range assessments {
+			if err := upsertAssessment(tx, asm); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+func (r *assignmentRepository) CreateExtension(ext *domain.AssignmentExtension) error {
+	return r.db.Create(ext).Error
+}
+
+func (r *assignmentRepository) GetExtensionByID(id string) (*domain.AssignmentExtension, error) {
+	var ext domain.AssignmentExtension
+	err := r.db.Preload("User").Preload("Reviewer").Where("asx_id = ?", id).First(&ext).Error
+	return &ext, err
+}
+
+func (r *assignmentRepository) GetLatestExtension(assignmentID string, userID string) (*domain.AssignmentExtension, error) {
+	var ext domain.AssignmentExtension
+	err := r.db.Preload("User").Preload("Reviewer").
+		Where("asx_asg_id = ? AND asx_usr_id = ?", assignmentID, userID).
+		Order("created_at desc").
+		First(&ext).Error
+	return &ext, err
+}
+
+func (r *assignmentRepository) HasPendingExtension(assignmentID string, userID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.AssignmentExtension{}).
+		Where("asx_asg_id = ? AND asx_usr_id = ? AND asx_status = ?", assignmentID, userID, domain.AssignmentExtensionPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *assignmentRepository) ListExtensionsByAssignment(assignmentID string, status string) ([]*domain.AssignmentExtension, error) {
+	var results []*domain.AssignmentExtension
+	query := r.db.Preload("User").Preload("Reviewer").Where("asx_asg_id = ?", assignmentID)
+	if status != "" {
+		query = query.Where("asx_status = ?", status)
+	}
+	err := query.Order("created_at desc").Find(&results).Error
+	return results, err
+}
+
+// ListApprovedExtensions returns approved extensions of assignmentIDs, newest
+// review first. A nil userIDs returns the extensions of every student.
+func (r *assignmentRepository) ListApprovedExtensions(assignmentIDs []string, userIDs []string) ([]*domain.AssignmentExtension, error) {
+	var results []*domain.AssignmentExtension
+	if len(assignmentIDs) == 0 {
+		return results, nil
+	}
+	query := r.db.Where("asx_asg_id IN ? AND asx_status = ?", assignmentIDs, domain.AssignmentExtensionApproved)
+	if userIDs != nil {
+		if len(userIDs) == 0 {
+			return results, nil
+		}
+		query = query.Where("asx_usr_id IN ?", userIDs)
+	}
+	err := query.Order("asx_reviewed_at desc").Find(&results).Error
+	return results, err
+}
+
+// ReviewExtension records the decision on a pending request. A request that
+// has already been reviewed is left untouched and reported as not found.
+func (r *assignmentRepository) ReviewExtension(ext *domain.AssignmentExtension) error {
+	result := r.db.Model(&domain.AssignmentExtension{}).
+		Where("asx_id = ? AND asx_status = ?", ext.ID, domain.AssignmentExtensionPending).
+		Updates(map[string]interface{}{
+			"asx_status":      ext.Status,
+			"asx_deadline":    ext.ExtendedDeadline,
+			"asx_review_note": ext.ReviewNote,
+			"asx_reviewed_by": ext.ReviewedBy,
+			"asx_reviewed_at": ext.ReviewedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *assignmentRepository) GetSubjectClassTeacherUserID(subjectClassID string) (string, error) {
+	var userID string
+	err := r.db.Table("edv.subject_classes sc").
+		Joins("JOIN edv.school_users scu ON scu.scu_id = sc.scl_scu_id AND scu.deleted_at IS NULL").
+		Where("sc.scl_id = ?", subjectClassID).
+		Pluck("scu.scu_usr_id", &userID).Error
+	return userID, err
+}
+
+// latestApprovedExtensionJoin joins the deadline of the newest approved
+// extension of the student in studentColumn as ext.asx_deadline. Comparisons
+// use GREATEST(a.asg_deadline, ext.asx_deadline) so a later assignment
+// deadline still wins over an older extension.
+func latestApprovedExtensionJoin(studentColumn string) string {
+	return `LEFT JOIN LATERAL (
+			SELECT latest_asx.asx_deadline
+			FROM edv.assignment_extensions latest_asx
+			WHERE latest_asx.asx_asg_id = a.asg_id
+				AND latest_asx.asx_usr_id = ` + studentColumn + `
+				AND latest_asx.asx_status = 'approved'
+			ORDER BY latest_asx.asx_reviewed_at DESC
+			LIMIT 1
+		) ext ON true`
+}
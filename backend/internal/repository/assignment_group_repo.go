@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetGroupSubmission returns the submission of a group for an assignment,
+// including a deleted one that a new turn-in restores.
+func (r *assignmentRepository) GetGroupSubmission(assignmentID string, groupID string) (*domain.Submission, error) {
+	var sbm domain.Submission
+	err := r.db.Unscoped().Where("sbm_asg_id = ? AND sbm_grp_id = ?", assignmentID, groupID).First(&sbm).Error
+	return &sbm, err
+}
+
+// StudentHasAssignmentSubmission reports whether the student owns a
+// submission of the assignment or is counted on a group submission of it.
+func (r *assignmentRepository) StudentHasAssignmentSubmission(assignmentID string, userID string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&domain.Submission{}).
+		Where("sbm_asg_id = ?", assignmentID).
+		Where("sbm_usr_id = ? OR (deleted_at IS NULL AND EXISTS (SELECT 1 FROM edv.group_submission_members gsm WHERE gsm.gsm_sbm_id = submissions.sbm_id AND gsm.gsm_usr_id = ?))", userID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SyncGroupSubmissionMembers makes the current members of the group the
+// students the submission counts for. Students already counted on another
+// submission of the assignment, such as the one of their previous group, are
+// left out. Members who stay keep their score adjustment.
+func (r *assignmentRepository) SyncGroupSubmissionMembers(submissionID string, assignmentID string, groupID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var userIDs []string
+		if err := tx.Table("edv.student_group_members gpm").
+			Where("gpm.gpm_grp_id = ?", groupID).
+			Where(`NOT EXISTS (
+				SELECT 1
+				FROM edv.group_submission_members other_gsm
+				JOIN edv.submissions other_s ON other_s.sbm_id = other_gsm.gsm_sbm_id AND other_s.deleted_at IS NULL
+				WHERE other_gsm.gsm_usr_id = gpm.gpm_usr_id
+					AND other_s.sbm_asg_id = ?
+					AND other_s.sbm_id <> ?
+			)`, assignmentID, submissionID).
+			Pluck("gpm.gpm_usr_id", &userIDs).Error; err != nil {
+			return err
+		}
+
+		stale := tx.Where("gsm_sbm_id = ?", submissionID)
+		if len(userIDs) > 0 {
+			stale = stale.Where("gsm_usr_id NOT IN ?", userIDs)
+		}
+		if err := stale.Delete(&domain.GroupSubmissionMember{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		members := make([]domain.GroupSubmissionMember, 0, len(userIDs))
+		for _, userID := range userIDs {
+			members = append(members, domain.GroupSubmissionMember{SubmissionID: submissionID, UserID: userID})
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "gsm_sbm_id"}, {Name: "gsm_usr_id"}},
+			DoNothing: true,
+		}).Omit("User").Create(&members).Error
+	})
+}
+
+func (r *assignmentRepository) UpdateGroupScoreAdjustments(submissionID string, members []domain.GroupSubmissionMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, member := range members {
+			result := tx.Model(&domain.GroupSubmissionMember{}).
+				Where("gsm_sbm_id = ? AND gsm_usr_id = ?", submissionID, member.UserID).
+				Updates(map[string]interface{}{
+					"gsm_score_adjustment": member.ScoreAdjustment,
+					"gsm_adjustment_note":  member.AdjustmentNote,
+					"updated_at":           gorm.Expr("now()"),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
+// AssignmentHasSubmissions counts deleted submissions too, since a new
+// turn-in restores them.
+func (r *assignmentRepository) AssignmentHasSubmissions(assignmentID string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&domain.Submission{}).Where("sbm_asg_id = ?", assignmentID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *assignmentRepository) SetAssignmentGroupMode(assignmentID string, groupMode bool) error {
+	result := r.db.Model(&domain.Assignment{}).Where("asg_id = ?", assignmentID).Update("asg_group_mode", groupMode)
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+// studentSubmissionCondition matches the submissions in alias that count for
+// a student: their own individual submissions and the group submissions they
+// are counted on. It takes the student ID twice.
+func studentSubmissionCondition(alias string) string {
+	return `((` + alias + `.sbm_grp_id IS NULL AND ` + alias + `.sbm_usr_id = ?) OR (` + alias + `.sbm_grp_id IS NOT NULL AND EXISTS (
+			SELECT 1
+			FROM edv.group_submission_members member_gsm
+			WHERE member_gsm.gsm_sbm_id = ` + alias + `.sbm_id
+				AND member_gsm.gsm_usr_id = ?
+		)))`
+}
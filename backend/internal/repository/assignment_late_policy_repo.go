@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *assignmentRepository) GetAssignmentLatePolicy(assignmentID string) (*domain.LatePolicy, error) {
+	var policy domain.LatePolicy
+	err := r.db.Where("lpl_asg_id = ?", assignmentID).First(&policy).Error
+	return &policy, err
+}
+
+func (r *assignmentRepository) GetCategoryLatePolicy(categoryID string) (*domain.LatePolicy, error) {
+	var policy domain.LatePolicy
+	err := r.db.Where("lpl_asc_id = ? AND lpl_asg_id IS NULL", categoryID).First(&policy).Error
+	return &policy, err
+}
+
+// GetEffectiveLatePolicy returns the policy of the assignment, falling back
+// to the policy of its category.
+func (r *assignmentRepository) GetEffectiveLatePolicy(assignmentID string, categoryID string) (*domain.LatePolicy, error) {
+	var policy domain.LatePolicy
+	err := r.db.
+		Where("lpl_asg_id = ? OR (lpl_asc_id = ? AND lpl_asg_id IS NULL)", assignmentID, categoryID).
+		Order("lpl_asg_id nulls last").
+		First(&policy).Error
+	return &policy, err
+}
+
+// SaveLatePolicy replaces the policy of policy.AssignmentID, or of
+// policy.CategoryID when it has no assignment.
+func (r *assignmentRepository) SaveLatePolicy(policy *domain.LatePolicy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"})
+		if policy.AssignmentID != nil {
+			query = query.Where("lpl_asg_id = ?", *policy.AssignmentID)
+		} else {
+			query = query.Where("lpl_asc_id = ? AND lpl_asg_id IS NULL", policy.CategoryID)
+		}
+
+		var existing domain.LatePolicy
+		err := query.First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(policy).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+		return tx.Model(&domain.LatePolicy{}).
+			Where("lpl_id = ?", existing.ID).
+			Updates(map[string]interface{}{
+				"lpl_percent_per_day": policy.PercentPerDay,
+				"lpl_max_percent":     policy.MaxPercent,
+				"lpl_grace_minutes":   policy.GraceMinutes,
+				"lpl_zero_after_days": policy.ZeroAfterDays,
+				"lpl_updated_by":      policy.UpdatedBy,
+				"updated_at":          gorm.Expr("now()"),
+			}).Error
+	})
+}
+
+func (r *assignmentRepository) DeleteAssignmentLatePolicy(assignmentID string) error {
+	result := r.db.Where("lpl_asg_id = ?", assignmentID).Delete(&domain.LatePolicy{})
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (r *assignmentRepository) DeleteCategoryLatePolicy(categoryID string) error {
+	result := r.db.Where("lpl_asc_id = ? AND lpl_asg_id IS NULL", categoryID).Delete(&domain.LatePolicy{})
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+// SetSubmissionPenaltyWaived stores the waiver fields of sbm, clearing them
+// when the waiver is lifted.
+func (r *assignmentRepository) SetSubmissionPenaltyWaived(sbm *domain.Submission) error {
+	result := r.db.Model(&domain.Submission{}).
+		Where("sbm_id = ?", sbm.ID).
+		Updates(map[string]interface{}{
+			"sbm_penalty_waived":    sbm.PenaltyWaived,
+			"sbm_penalty_waived_by": sbm.PenaltyWaivedBy,
+			"sbm_penalty_waived_at": sbm.PenaltyWaivedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *assignmentRepository) ListLatePenaltyInputs(submissionIDs []string) ([]dto.LatePenaltyInputRow, error) {
+	return listLatePenaltyInputs(r.db, submissionIDs)
+}
+
+// listLatePenaltyInputs loads what the late penalty of each submission
+// depends on. The submission time is the one of the graded version that
+// counts, if any, and the policy is the assignment policy or else the policy
+// of the assignment's category.
+func listLatePenaltyInputs(db *gorm.DB, submissionIDs []string) ([]dto.LatePenaltyInputRow, error) {
+	var rows []dto.LatePenaltyInputRow
+	if len(submissionIDs) == 0 {
+		return rows, nil
+	}
+	err := db.Table("edv.submissions s").
+		Select(`
+			s.sbm_id AS submission_id,
+			COALESCE(sbv.sbv_submitted_at, s.submitted_at) AS submitted_at,
+			a.asg_deadline AS deadline,
+			ext.asx_deadline AS extended_deadline,
+			s.sbm_penalty_waived AS waived,
+			lpl.lpl_id AS policy_id,
+			lpl.lpl_percent_per_day AS percent_per_day,
+			lpl.lpl_max_percent AS max_percent,
+			lpl.lpl_grace_minutes AS grace_minutes,
+			lpl.lpl_zero_after_days AS zero_after_days
+		`).
+		Joins("JOIN edv.assignments a ON a.asg_id = s.sbm_asg_id").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT counted_asm.asm_sbv_id
+			FROM edv.assessments counted_asm
+			WHERE counted_asm.asm_sbm_id = s.sbm_id
+			ORDER BY counted_asm.assessed_at DESC, counted_asm.asm_id DESC
+			LIMIT 1
+		) asm ON true`).
+		Joins("LEFT JOIN edv.submission_versions sbv ON sbv.sbv_id = asm.asm_sbv_id").
+		Joins(latestApprovedExtensionJoin("s.sbm_usr_id")).
+		Joins(`LEFT JOIN LATERAL (
+			SELECT *
+			FROM edv.late_policies applied_lpl
+			WHERE applied_lpl.lpl_asg_id = a.asg_id
+				OR (applied_lpl.lpl_asc_id = a.asg_asc_id AND applied_lpl.lpl_asg_id IS NULL)
+			ORDER BY applied_lpl.lpl_asg_id NULLS LAST
+			LIMIT 1
+		) lpl ON true`).
+		Where("s.sbm_id IN ?", submissionIDs).
+		Scan(&rows).Error
+	return rows, err
+}
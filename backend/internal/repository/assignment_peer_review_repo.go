@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *assignmentRepository) GetPeerReviewSetting(assignmentID string) (*domain.PeerReviewSetting, error) {
+	var setting domain.PeerReviewSetting
+	err := r.db.
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("prq_position asc")
+		}).
+		Where("prs_asg_id = ?", assignmentID).
+		First(&setting).Error
+	return &setting, err
+}
+
+// SavePeerReviewSetting creates or updates the peer review of
+// setting.AssignmentID. The form is replaced by setting.Questions only when
+// replaceQuestions is set.
+func (r *assignmentRepository) SavePeerReviewSetting(setting *domain.PeerReviewSetting, replaceQuestions bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing domain.PeerReviewSetting
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("prs_asg_id = ?", setting.AssignmentID).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(setting).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		setting.ID = existing.ID
+		if err := tx.Model(&domain.PeerReviewSetting{}).
+			Where("prs_id = ?", existing.ID).
+			Updates(map[string]interface{}{
+				"prs_reviewers_per_submission": setting.ReviewersPerSubmission,
+				"prs_review_deadline":          setting.ReviewDeadline,
+				"prs_completion_weight":        setting.CompletionWeight,
+				"prs_updated_by":               setting.UpdatedBy,
+				"updated_at":                   gorm.Expr("now()"),
+			}).Error; err != nil {
+			return err
+		}
+		if !replaceQuestions {
+			return nil
+		}
+
+		if err := tx.Where("prq_prs_id = ?", existing.ID).Delete(&domain.PeerReviewQuestion{}).Error; err != nil {
+			return err
+		}
+		for i := range setting.Questions {
+			setting.Questions[i].ID = ""
+			setting.Questions[i].SettingID = existing.ID
+		}
+		if len(setting.Questions) == 0 {
+			return nil
+		}
+		return tx.Create(&setting.Questions).Error
+	})
+}
+
+// DeletePeerReviewSetting turns off the peer review of an assignment,
+// dropping its form and any allocated reviews.
+func (r *assignmentRepository) DeletePeerReviewSetting(assignmentID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var setting domain.PeerReviewSetting
+		if err := tx.Where("prs_asg_id = ?", assignmentID).First(&setting).Error; err != nil {
+			return err
+		}
+		reviewIDs := tx.Model(&domain.PeerReview{}).Select("prv_id").Where("prv_asg_id = ?", assignmentID)
+		if err := tx.Where("pra_prv_id IN (?)", reviewIDs).Delete(&domain.PeerReviewAnswer{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("prv_asg_id = ?", assignmentID).Delete(&domain.PeerReview{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("prq_prs_id = ?", setting.ID).Delete(&domain.PeerReviewQuestion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&setting).Error
+	})
+}
+
+// ListDuePeerReviewSettings returns the peer reviews that were not allocated
+// yet although the assignment deadline has passed.
+func (r *assignmentRepository) ListDuePeerReviewSettings(now time.Time) ([]*domain.PeerReviewSetting, error) {
+	var settings []*domain.PeerReviewSetting
+	err := r.db.
+		Joins("JOIN edv.assignments a ON a.asg_id = prs_asg_id AND a.deleted_at IS NULL").
+		Where("prs_allocated_at IS NULL").
+		Where("a.asg_deadline <= ?", now).
+		Order("a.asg_deadline asc").
+		Find(&settings).Error
+	return settings, err
+}
+
+// ListPeerReviewableSubmissions returns the turned-in work of an assignment
+// that takes part in the peer review.
+func (r *assignmentRepository) ListPeerReviewableSubmissions(assignmentID string) ([]*domain.Submission, error) {
+	var submissions []*domain.Submission
+	err := r.db.
+		Where("sbm_asg_id = ? AND sbm_grp_id IS NULL", assignmentID).
+		Order("submitted_at asc, sbm_id asc").
+		Find(&submissions).Error
+	return submissions, err
+}
+
+// AllocatePeerReviews stores the reviews of a peer review and marks it
+// allocated. It reports false when the peer review was allocated already.
+func (r *assignmentRepository) AllocatePeerReviews(settingID string, reviews []domain.PeerReview, allocatedAt time.Time) (bool, error) {
+	allocated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var setting domain.PeerReviewSetting
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("prs_id = ?", settingID).
+			First(&setting).Error; err != nil {
+			return err
+		}
+		if setting.AllocatedAt != nil {
+			return nil
+		}
+
+		if len(reviews) > 0 {
+			if err := tx.Create(&reviews).Error; err != nil {
+				return err
+			}
+		}
+		allocated = true
+		return tx.Model(&domain.PeerReviewSetting{}).
+			Where("prs_id = ?", settingID).
+			Update("prs_allocated_at", allocatedAt).Error
+	})
+	return allocated, err
+}
+
+func (r *assignmentRepository) GetPeerReviewByID(id string) (*domain.PeerReview, error) {
+	var review domain.PeerReview
+	err := r.db.
+		Preload("Answers").
+		Where("prv_id = ?", id).
+		First(&review).Error
+	return &review, err
+}
+
+func (r *assignmentRepository) ListPeerReviewsByAssignment(assignmentID string) ([]*domain.PeerReview, error) {
+	var reviews []*domain.PeerReview
+	err := r.db.
+		Preload("Answers").
+		Preload("Reviewer").
+		Preload("Submission.User").
+		Where("prv_asg_id = ?", assignmentID).
+		Order("prv_sbm_id asc, created_at asc").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *assignmentRepository) ListPeerReviewsByReviewer(assignmentID string, reviewerID string) ([]*domain.PeerReview, error) {
+	var reviews []*domain.PeerReview
+	err := r.db.
+		Preload("Answers").
+		Where("prv_asg_id = ? AND prv_reviewer_id = ?", assignmentID, reviewerID).
+		Order("created_at asc, prv_id asc").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// ListReceivedPeerReviews returns the submitted reviews of a submission.
+func (r *assignmentRepository) ListReceivedPeerReviews(submissionID string) ([]*domain.PeerReview, error) {
+	var reviews []*domain.PeerReview
+	err := r.db.
+		Preload("Answers").
+		Where("prv_sbm_id = ? AND prv_status = ?", submissionID, domain.PeerReviewSubmitted).
+		Order("prv_submitted_at asc, prv_id asc").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// SubmitPeerReview replaces the answers of a review and marks it submitted.
+func (r *assignmentRepository) SubmitPeerReview(review *domain.PeerReview, answers []domain.PeerReviewAnswer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.PeerReview{}).
+			Where("prv_id = ?", review.ID).
+			Updates(map[string]interface{}{
+				"prv_status":       review.Status,
+				"prv_submitted_at": review.SubmittedAt,
+				"updated_at":       gorm.Expr("now()"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("pra_prv_id = ?", review.ID).Delete(&domain.PeerReviewAnswer{}).Error; err != nil {
+			return err
+		}
+		for i := range answers {
+			answers[i].ReviewID = review.ID
+		}
+		if len(answers) == 0 {
+			return nil
+		}
+		return tx.Create(&answers).Error
+	})
+}
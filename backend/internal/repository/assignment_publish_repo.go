@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (r *assignmentRepository) SetAssignmentPublishState(assignmentID string, status string, publishAt *time.Time, publishedAt *time.Time) error {
+	result := r.db.Model(&domain.Assignment{}).Where("asg_id = ?", assignmentID).Updates(map[string]interface{}{
+		"asg_publish_status": status,
+		"asg_publish_at":     publishAt,
+		"asg_published_at":   publishedAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *assignmentRepository) ListDueScheduledAssignments(now time.Time) ([]*domain.Assignment, error) {
+	var assignments []*domain.Assignment
+	err := r.db.
+		Where("asg_publish_status = ? AND asg_publish_at <= ?", domain.PublishStatusScheduled, now).
+		Order("asg_publish_at ASC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// PublishAssignment publishes a scheduled assignment whose publish time has
+// passed. It reports false when another worker or a teacher edit got there
+// first, so each assignment fans out its notifications once.
+func (r *assignmentRepository) PublishAssignment(assignmentID string, now time.Time) (bool, error) {
+	result := r.db.Model(&domain.Assignment{}).
+		Where("asg_id = ? AND asg_publish_status = ? AND asg_publish_at <= ?", assignmentID, domain.PublishStatusScheduled, now).
+		Updates(map[string]interface{}{
+			"asg_publish_status": domain.PublishStatusPublished,
+			"asg_published_at":   now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
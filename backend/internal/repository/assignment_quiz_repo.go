@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReplaceQuiz stores the quiz settings of asg and swaps its questions for
+// questionIDs, in that order.
+func (r *assignmentRepository) ReplaceQuiz(asg *domain.Assignment, questionIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Assignment{}).
+			Where("asg_id = ?", asg.ID).
+			Updates(map[string]interface{}{
+				"asg_type":               domain.AssignmentTypeQuiz,
+				"asg_time_limit_minutes": asg.TimeLimitMinutes,
+				"asg_shuffle_questions":  asg.ShuffleQuestions,
+				"asg_shuffle_options":    asg.ShuffleOptions,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("qzq_asg_id = ?", asg.ID).Delete(&domain.QuizQuestion{}).Error; err != nil {
+			return err
+		}
+		questions := make([]domain.QuizQuestion, 0, len(questionIDs))
+		for i, questionID := range questionIDs {
+			questions = append(questions, domain.QuizQuestion{
+				AssignmentID: asg.ID,
+				QuestionID:   questionID,
+				Position:     i + 1,
+			})
+		}
+		return tx.Create(&questions).Error
+	})
+}
+
+func (r *assignmentRepository) ListQuizQuestions(assignmentID string) ([]domain.QuizQuestion, error) {
+	var results []domain.QuizQuestion
+	err := r.db.
+		Preload("Question.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("qop_position asc")
+		}).
+		Where("qzq_asg_id = ?", assignmentID).
+		Order("qzq_position asc").
+		Find(&results).Error
+	return results, err
+}
+
+func (r *assignmentRepository) CountQuizAttempts(assignmentID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.QuizAttempt{}).Where("qza_asg_id = ?", assignmentID).Count(&count).Error
+	return count, err
+}
+
+// CreateQuizAttempt reports false when the student already has an attempt,
+// such as one started concurrently.
+func (r *assignmentRepository) CreateQuizAttempt(attempt *domain.QuizAttempt) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "qza_asg_id"}, {Name: "qza_usr_id"}},
+		DoNothing: true,
+	}).Omit("Answers").Create(attempt)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *assignmentRepository) GetQuizAttemptByID(id string) (*domain.QuizAttempt, error) {
+	var attempt domain.QuizAttempt
+	err := r.db.Preload("User").Preload("Answers").Where("qza_id = ?", id).First(&attempt).Error
+	return &attempt, err
+}
+
+func (r *assignmentRepository) GetQuizAttempt(assignmentID string, userID string) (*domain.QuizAttempt, error) {
+	var attempt domain.QuizAttempt
+	err := r.db.Preload("User").Preload("Answers").
+		Where("qza_asg_id = ? AND qza_usr_id = ?", assignmentID, userID).
+		First(&attempt).Error
+	return &attempt, err
+}
+
+func (r *assignmentRepository) ListQuizAttempts(assignmentID string) ([]*domain.QuizAttempt, error) {
+	var results []*domain.QuizAttempt
+	err := r.db.Preload("User").Preload("Answers").
+		Where("qza_asg_id = ?", assignmentID).
+		Order("qza_started_at asc").
+		Find(&results).Error
+	return results, err
+}
+
+// SaveQuizAnswers upserts the answers of an attempt that is still in
+// progress.
+func (r *assignmentRepository) SaveQuizAnswers(attemptID string, answers []domain.QuizAnswer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var attempt domain.QuizAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("qza_id = ? AND qza_status = ?", attemptID, domain.QuizAttemptInProgress).
+			First(&attempt).Error; err != nil {
+			return err
+		}
+		return upsertQuizAnswers(tx, attemptID, answers, "qzn_option_ids", "qzn_text", "qzn_number", "qzn_boolean", "updated_at")
+	})
+}
+
+// SubmitQuizAttempt closes an attempt that is still in progress, stores the
+// automatic grading of its answers and upserts sbm as the student's
+// submission for the quiz.
+func (r *assignmentRepository) SubmitQuizAttempt(attempt *domain.QuizAttempt, sbm *domain.Submission) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := upsertSubmission(tx, sbm); err != nil {
+			return err
+		}
+		result := tx.Model(&domain.QuizAttempt{}).
+			Where("qza_id = ? AND qza_status = ?", attempt.ID, domain.QuizAttemptInProgress).
+			Updates(map[string]interface{}{
+				"qza_status":       attempt.Status,
+				"qza_submitted_at": attempt.SubmittedAt,
+				"qza_sbm_id":       sbm.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		attempt.SubmissionID = &sbm.ID
+		return upsertQuizAnswers(tx, attempt.ID, attempt.Answers, "qzn_points", "qzn_is_correct", "updated_at")
+	})
+}
+
+// GradeQuizAttempt stores the teacher's points and feedback for the answers
+// of a submitted attempt along with its new status.
+func (r *assignmentRepository) GradeQuizAttempt(attempt *domain.QuizAttempt) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.QuizAttempt{}).
+			Where("qza_id = ? AND qza_status <> ?", attempt.ID, domain.QuizAttemptInProgress).
+			Update("qza_status", attempt.Status)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return upsertQuizAnswers(tx, attempt.ID, attempt.Answers, "qzn_points", "qzn_is_correct", "qzn_feedback", "qzn_graded_by", "qzn_graded_at", "updated_at")
+	})
+}
+
+func upsertQuizAnswers(tx *gorm.DB, attemptID string, answers []domain.QuizAnswer, columns ...string) error {
+	if len(answers) == 0 {
+		return nil
+	}
+	for i := range answers {
+		answers[i].ID = ""
+		answers[i].AttemptID = attemptID
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "qzn_qza_id"}, {Name: "qzn_qst_id"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).Create(&answers).Error
+}
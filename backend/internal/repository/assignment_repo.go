@@ -17,7 +17,7 @@ type AssignmentRepository interface {
 
 	// Assignment
 	CreateAssignment(asg *domain.Assignment) error
-	GetAssignmentsBySubjectClass(subjectClassID string, search string, page int, limit int) ([]*domain.Assignment, int64, error)
+	GetAssignmentsBySubjectClass(subjectClassID string, search string, publishedOnly bool, page int, limit int) ([]*domain.Assignment, int64, error)
 	GetAssignmentByID(id string) (*domain.Assignment, error)
 	GetAssignmentWithSubmissions(id string) (*domain.Assignment, error)
 	GetAssignmentsWithSubmissionsBySubjectClass(subjectClassID string, schoolID string) ([]*domain.Assignment, error)
@@ -26,7 +26,10 @@ type AssignmentRepository interface {
 	GetStudentAssignmentInbox(userID string, schoolID string) ([]dto.StudentAssignmentInboxItemDTO, error)
 	CountStudentsInClass(classID string) (int, error)
 	GetClassIDBySubjectClass(subjectClassID string) (string, error)
+	GetSubjectIDBySubjectClass(subjectClassID string) (string, error)
 	UpdateAssignment(asg *domain.Assignment) error
+	SetAssignmentRubric(assignmentID string, rubricID *string) error
+	AssignmentHasRubricScores(assignmentID string) (bool, error)
 	DeleteAssignment(id string) error
 
 	// Submission
@@ -37,12 +40,89 @@ type AssignmentRepository interface {
 	UpdateSubmission(sbm *domain.Submission) error
 	DeleteSubmission(id string) error
 
+	// Extension
+	CreateExtension(ext *domain.AssignmentExtension) error
+	GetExtensionByID(id string) (*domain.AssignmentExtension, error)
+	GetLatestExtension(assignmentID string, userID string) (*domain.AssignmentExtension, error)
+	HasPendingExtension(assignmentID string, userID string) (bool, error)
+	ListExtensionsByAssignment(assignmentID string, status string) ([]*domain.AssignmentExtension, error)
+	ListApprovedExtensions(assignmentIDs []string, userIDs []string) ([]*domain.AssignmentExtension, error)
+	ReviewExtension(ext *domain.AssignmentExtension) error
+	GetSubjectClassTeacherUserID(subjectClassID string) (string, error)
+
+	// Quiz
+	ReplaceQuiz(asg *domain.Assignment, questionIDs []string) error
+	ListQuizQuestions(assignmentID string) ([]domain.QuizQuestion, error)
+	CountQuizAttempts(assignmentID string) (int64, error)
+	CreateQuizAttempt(attempt *domain.QuizAttempt) (bool, error)
+	GetQuizAttemptByID(id string) (*domain.QuizAttempt, error)
+	GetQuizAttempt(assignmentID string, userID string) (*domain.QuizAttempt, error)
+	ListQuizAttempts(assignmentID string) ([]*domain.QuizAttempt, error)
+	SaveQuizAnswers(attemptID string, answers []domain.QuizAnswer) error
+	SubmitQuizAttempt(attempt *domain.QuizAttempt, sbm *domain.Submission) error
+	GradeQuizAttempt(attempt *domain.QuizAttempt) error
+
+	// Submission versions
+	CreateSubmissionVersion(version *domain.SubmissionVersion) error
+	CountSubmissionVersions(sbm *domain.Submission) (int64, error)
+	ListSubmissionVersions(submissionID string) ([]*domain.SubmissionVersion, error)
+	GetSubmissionVersion(id string) (*domain.SubmissionVersion, error)
+	GradeSubmissionVersion(version *domain.SubmissionVersion) error
+	AssignmentHasGradedVersions(assignmentID string) (bool, error)
+	SetAssignmentMaxAttempts(assignmentID string, maxAttempts *int) error
+
+	// Group submissions
+	GetGroupSubmission(assignmentID string, groupID string) (*domain.Submission, error)
+	StudentHasAssignmentSubmission(assignmentID string, userID string) (bool, error)
+	SyncGroupSubmissionMembers(submissionID string, assignmentID string, groupID string) error
+	UpdateGroupScoreAdjustments(submissionID string, members []domain.GroupSubmissionMember) error
+	AssignmentHasSubmissions(assignmentID string) (bool, error)
+	SetAssignmentGroupMode(assignmentID string, groupMode bool) error
+
+	// Publishing
+	SetAssignmentPublishState(assignmentID string, status string, publishAt *time.Time, publishedAt *time.Time) error
+	ListDueScheduledAssignments(now time.Time) ([]*domain.Assignment, error)
+	PublishAssignment(assignmentID string, now time.Time) (bool, error)
+
+	// Anonymous grading
+	SetAssignmentAnonymousGrading(assignmentID string, enabled bool, key string) error
+	AssignmentHasAssessments(assignmentID string) (bool, error)
+	CountUngradedSubmissions(assignmentID string) (int64, error)
+	ReleaseAssignmentIdentities(assignmentID string, now time.Time) (bool, error)
+
+	// Peer review
+	GetPeerReviewSetting(assignmentID string) (*domain.PeerReviewSetting, error)
+	SavePeerReviewSetting(setting *domain.PeerReviewSetting, replaceQuestions bool) error
+	DeletePeerReviewSetting(assignmentID string) error
+	ListDuePeerReviewSettings(now time.Time) ([]*domain.PeerReviewSetting, error)
+	ListPeerReviewableSubmissions(assignmentID string) ([]*domain.Submission, error)
+	AllocatePeerReviews(settingID string, reviews []domain.PeerReview, allocatedAt time.Time) (bool, error)
+	GetPeerReviewByID(id string) (*domain.PeerReview, error)
+	ListPeerReviewsByAssignment(assignmentID string) ([]*domain.PeerReview, error)
+	ListPeerReviewsByReviewer(assignmentID string, reviewerID string) ([]*domain.PeerReview, error)
+	ListReceivedPeerReviews(submissionID string) ([]*domain.PeerReview, error)
+	SubmitPeerReview(review *domain.PeerReview, answers []domain.PeerReviewAnswer) error
+
+	// Late policies
+	GetAssignmentLatePolicy(assignmentID string) (*domain.LatePolicy, error)
+	GetCategoryLatePolicy(categoryID string) (*domain.LatePolicy, error)
+	GetEffectiveLatePolicy(assignmentID string, categoryID string) (*domain.LatePolicy, error)
+	SaveLatePolicy(policy *domain.LatePolicy) error
+	DeleteAssignmentLatePolicy(assignmentID string) error
+	DeleteCategoryLatePolicy(categoryID string) error
+	SetSubmissionPenaltyWaived(sbm *domain.Submission) error
+	ListLatePenaltyInputs(submissionIDs []string) ([]dto.LatePenaltyInputRow, error)
+
 	// Assessment
 	UpsertAssessment(asm *domain.Assessment) error
 	GetAssessmentBySubmission(sbmID string) (*domain.Assessment, error)
 	UpdateAssessment(asm *domain.Assessment) error
 	DeleteAssessment(submissionID string) error
 
+	// Bulk grading
+	ListGradingRoster(assignmentID string) ([]dto.GradingRosterRow, error)
+	SaveBulkGrades(versions []*domain.SubmissionVersion, assessments []*domain.Assessment) error
+
 	// Weights
 	SetWeight(weight *domain.AssessmentWeight) error
 	GetWeightsBySubject(subID string) ([]*domain.AssessmentWeight, error)
@@ -80,7 +160,7 @@ func (r *assignmentRepository) CreateAssignment(asg *domain.Assignment) error {
 	return r.db.Create(asg).Error
 }
 
-func (r *assignmentRepository) GetAssignmentsBySubjectClass(subjectClassID string, search string, page int, limit int) ([]*domain.Assignment, int64, error) {
+func (r *assignmentRepository) GetAssignmentsBySubjectClass(subjectClassID string, search string, publishedOnly bool, page int, limit int) ([]*domain.Assignment, int64, error) {
 	var results []*domain.Assignment
 	var total int64
 
@@ -93,6 +173,9 @@ func (r *assignmentRepository) GetAssignmentsBySubjectClass(subjectClassID strin
 	if search != "" {
 		query = query.Where("asg_title ILIKE ? OR asg_desc ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
+	if publishedOnly {
+		query = query.Where("asg_publish_status = ?", domain.PublishStatusPublished)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -116,6 +199,7 @@ func (r *assignmentRepository) GetAssignmentWithSubmissions(id string) (*domain.
 		Preload("SubjectClass.Subject").
 		Preload("SubjectClass.Class").
 		Preload("Submissions.User").
+		Preload("Submissions.Group").
 		Preload("Submissions.Assessment.Assessor").
 		Where("asg_id = ?", id).First(&asg).Error
 	return &asg, err
@@ -130,6 +214,7 @@ func (r *assignmentRepository) GetAssignmentsWithSubmissionsBySubjectClass(subje
 			return db.Where("sbm_sch_id = ?", schoolID).Order("submitted_at asc")
 		}).
 		Preload("Submissions.User").
+		Preload("Submissions.Group").
 		Preload("Submissions.Assessment.Assessor").
 		Where("asg_scl_id = ? AND asg_sch_id = ?", subjectClassID, schoolID).
 		Order("created_at desc").
@@ -150,10 +235,18 @@ func (r *assignmentRepository) GetTeacherSubmissionInbox(userID string, schoolID
 			c.cls_title AS class_name,
 			c.cls_code AS class_code,
 			a.asg_deadline AS deadline,
+			a.asg_group_mode AS is_group_assignment,
+			CASE WHEN a.asg_group_mode THEN (SELECT COUNT(*) FROM edv.student_groups g WHERE g.grp_scl_id = sc.scl_id) ELSE 0 END AS group_count,
 			COUNT(s.sbm_id) AS submission_count,
+			COALESCE(SUM(CASE
+				WHEN s.sbm_id IS NULL THEN 0
+				WHEN s.sbm_grp_id IS NULL THEN 1
+				ELSE (SELECT COUNT(*) FROM edv.group_submission_members gsm WHERE gsm.gsm_sbm_id = s.sbm_id)
+			END), 0) AS student_count,
 			COUNT(CASE WHEN asm.asm_sbm_id IS NULL THEN s.sbm_id END) AS pending_count,
 			COUNT(CASE WHEN asm.asm_sbm_id IS NOT NULL THEN s.sbm_id END) AS graded_count,
-			COUNT(CASE WHEN a.asg_deadline IS NOT NULL AND s.submitted_at > a.asg_deadline THEN s.sbm_id END) AS late_count
+			COUNT(CASE WHEN a.asg_deadline IS NOT NULL AND s.submitted_at > GREATEST(a.asg_deadline, ext.asx_deadline) THEN s.sbm_id END) AS late_count,
+			(a.asg_anonymous_grading AND a.asg_identities_released_at IS NULL) AS identities_hidden
 		`).
 		Joins("JOIN edv.subject_classes sc ON sc.scl_id = a.asg_scl_id").
 		Joins("JOIN edv.classes c ON c.cls_id = sc.scl_cls_id").
@@ -162,12 +255,13 @@ func (r *assignmentRepository) GetTeacherSubmissionInbox(userID string, schoolID
 		Joins("JOIN edv.enrollments teacher_e ON teacher_e.enr_cls_id = sc.scl_cls_id AND teacher_e.enr_scu_id = sc.scl_scu_id").
 		Joins("LEFT JOIN edv.submissions s ON s.sbm_asg_id = a.asg_id AND s.sbm_sch_id = ? AND s.deleted_at IS NULL", schoolID).
 		Joins("LEFT JOIN (SELECT DISTINCT asm_sbm_id FROM edv.assessments) asm ON asm.asm_sbm_id = s.sbm_id").
+		Joins(latestApprovedExtensionJoin("s.sbm_usr_id")).
 		Where("a.asg_sch_id = ? AND a.deleted_at IS NULL", schoolID).
 		Where("teacher_scu.scu_usr_id = ? AND teacher_scu.scu_sch_id = ? AND teacher_scu.deleted_at IS NULL", userID, schoolID).
 		Where("teacher_e.enr_sch_id = ? AND teacher_e.enr_role = ? AND teacher_e.left_at IS NULL", schoolID, "teacher").
 		Where("c.cls_sch_id = ? AND c.deleted_at IS NULL", schoolID).
 		Where("sub.sub_sch_id = ?", schoolID).
-		Group("a.asg_id, sc.scl_id, a.asg_title, sub.sub_name, sub.sub_code, sub.sub_color, c.cls_title, c.cls_code, a.asg_deadline").
+		Group("a.asg_id, sc.scl_id, a.asg_title, sub.sub_name, sub.sub_code, sub.sub_color, c.cls_title, c.cls_code, a.asg_deadline, a.asg_group_mode, a.asg_anonymous_grading, a.asg_identities_released_at").
 		Having("COUNT(s.sbm_id) > 0").
 		Order("pending_count DESC, a.asg_deadline ASC NULLS LAST, a.asg_title ASC").
 		Scan(&rows).Error
@@ -188,10 +282,12 @@ func (r *assignmentRepository) GetTeacherAssignmentInbox(userID string, schoolID
 			c.cls_code AS class_code,
 			COALESCE(ac.asc_name, '') AS category_name,
 			a.asg_deadline AS deadline,
+			a.asg_publish_status AS publish_status,
+			a.asg_publish_at AS publish_at,
 			COUNT(s.sbm_id) AS submission_count,
 			COUNT(CASE WHEN asm.asm_sbm_id IS NULL THEN s.sbm_id END) AS pending_count,
 			COUNT(CASE WHEN asm.asm_sbm_id IS NOT NULL THEN s.sbm_id END) AS graded_count,
-			COUNT(CASE WHEN a.asg_deadline IS NOT NULL AND s.submitted_at > a.asg_deadline THEN s.sbm_id END) AS late_count
+			COUNT(CASE WHEN a.asg_deadline IS NOT NULL AND s.submitted_at > GREATEST(a.asg_deadline, ext.asx_deadline) THEN s.sbm_id END) AS late_count
 		`).
 		Joins("JOIN edv.subject_classes sc ON sc.scl_id = a.asg_scl_id").
 		Joins("JOIN edv.classes c ON c.cls_id = sc.scl_cls_id").
@@ -201,12 +297,13 @@ func (r *assignmentRepository) GetTeacherAssignmentInbox(userID string, schoolID
 		Joins("LEFT JOIN edv.assignment_categories ac ON ac.asc_id = a.asg_asc_id").
 		Joins("LEFT JOIN edv.submissions s ON s.sbm_asg_id = a.asg_id AND s.sbm_sch_id = ? AND s.deleted_at IS NULL", schoolID).
 		Joins("LEFT JOIN (SELECT DISTINCT asm_sbm_id FROM edv.assessments) asm ON asm.asm_sbm_id = s.sbm_id").
+		Joins(latestApprovedExtensionJoin("s.sbm_usr_id")).
 		Where("a.asg_sch_id = ? AND a.deleted_at IS NULL", schoolID).
 		Where("teacher_scu.scu_usr_id = ? AND teacher_scu.scu_sch_id = ? AND teacher_scu.deleted_at IS NULL", userID, schoolID).
 		Where("teacher_e.enr_sch_id = ? AND teacher_e.enr_role = ? AND teacher_e.left_at IS NULL", schoolID, "teacher").
 		Where("c.cls_sch_id = ? AND c.deleted_at IS NULL", schoolID).
 		Where("sub.sub_sch_id = ?", schoolID).
-		Group("a.asg_id, sc.scl_id, a.asg_title, sub.sub_name, sub.sub_code, sub.sub_color, c.cls_title, c.cls_code, ac.asc_name, a.asg_deadline").
+		Group("a.asg_id, sc.scl_id, a.asg_title, sub.sub_name, sub.sub_code, sub.sub_color, c.cls_title, c.cls_code, ac.asc_name, a.asg_deadline, a.asg_publish_status, a.asg_publish_at").
 		Order("pending_count DESC, a.asg_deadline ASC NULLS LAST, a.asg_title ASC").
 		Scan(&rows).Error
 	return rows, err
@@ -220,6 +317,7 @@ func (r *assignmentRepository) GetStudentAssignmentInbox(userID string, schoolID
 			a.asg_id AS assignment_id,
 			sc.scl_id AS subject_class_id,
 				a.asg_title AS assignment_title,
+				COALESCE(a.asg_type, 'file') AS assignment_type,
 				sub.sub_name AS subject_name,
 				sub.sub_code AS subject_code,
 				COALESCE(sub.sub_color, '') AS subject_color,
@@ -229,11 +327,15 @@ func (r *assignmentRepository) GetStudentAssignmentInbox(userID string, schoolID
 			a.asg_deadline AS deadline,
 			s.sbm_id AS submission_id,
 			s.submitted_at AS submitted_at,
-			asm.asm_score AS score,
+			CASE
+				WHEN asm.asm_score IS NULL THEN NULL
+				ELSE LEAST(100, GREATEST(0, asm.asm_score + COALESCE(gsm.gsm_score_adjustment, 0)))
+			END AS score,
 			(s.sbm_id IS NOT NULL) AS is_submitted,
 			(asm.asm_sbm_id IS NOT NULL) AS is_graded,
-			(a.asg_deadline IS NOT NULL AND a.asg_deadline < ? AND s.sbm_id IS NULL) AS is_overdue,
-			(a.asg_deadline IS NOT NULL AND s.submitted_at IS NOT NULL AND s.submitted_at > a.asg_deadline) AS is_submitted_late
+			ext.asx_deadline AS extended_deadline,
+			(a.asg_deadline IS NOT NULL AND GREATEST(a.asg_deadline, ext.asx_deadline) < ? AND s.sbm_id IS NULL) AS is_overdue,
+			(a.asg_deadline IS NOT NULL AND s.submitted_at IS NOT NULL AND s.submitted_at > GREATEST(a.asg_deadline, ext.asx_deadline)) AS is_submitted_late
 		`, now).
 		Joins("JOIN edv.subject_classes sc ON sc.scl_id = a.asg_scl_id").
 		Joins("JOIN edv.classes c ON c.cls_id = sc.scl_cls_id").
@@ -245,12 +347,12 @@ func (r *assignmentRepository) GetStudentAssignmentInbox(userID string, schoolID
 			SELECT *
 			FROM edv.submissions latest_s
 			WHERE latest_s.sbm_asg_id = a.asg_id
-				AND latest_s.sbm_usr_id = ?
+				AND `+studentSubmissionCondition("latest_s")+`
 				AND latest_s.sbm_sch_id = ?
 				AND latest_s.deleted_at IS NULL
 			ORDER BY latest_s.submitted_at DESC, latest_s.sbm_id DESC
 			LIMIT 1
-		) s ON true`, userID, schoolID).
+		) s ON true`, userID, userID, schoolID).
 		Joins(`LEFT JOIN LATERAL (
 			SELECT *
 			FROM edv.assessments latest_asm
@@ -258,7 +360,10 @@ func (r *assignmentRepository) GetStudentAssignmentInbox(userID string, schoolID
 			ORDER BY latest_asm.assessed_at DESC, latest_asm.asm_id DESC
 			LIMIT 1
 		) asm ON true`).
+		Joins("LEFT JOIN edv.group_submission_members gsm ON gsm.gsm_sbm_id = s.sbm_id AND gsm.gsm_usr_id = ?", userID).
+		Joins(latestApprovedExtensionJoin("?"), userID).
 		Where("a.asg_sch_id = ? AND a.deleted_at IS NULL", schoolID).
+		Where("a.asg_publish_status = ?", domain.PublishStatusPublished).
 		Where("c.cls_sch_id = ? AND c.deleted_at IS NULL", schoolID).
 		Where("sub.sub_sch_id = ?", schoolID).
 		Where("e.enr_sch_id = ? AND e.enr_role = ? AND e.left_at IS NULL", schoolID, "student").
@@ -284,6 +389,14 @@ func (r *assignmentRepository) GetClassIDBySubjectClass(subjectClassID string) (
 	return classID, err
 }
 
+func (r *assignmentRepository) GetSubjectIDBySubjectClass(subjectClassID string) (string, error) {
+	var subjectID string
+	err := r.db.Model(&domain.SubjectClass{}).
+		Where("scl_id = ?", subjectClassID).
+		Pluck("scl_sub_id", &subjectID).Error
+	return subjectID, err
+}
+
 func (r *assignmentRepository) UpdateAssignment(asg *domain.Assignment) error {
 	result := r.db.Model(&domain.Assignment{}).Where("asg_id = ?", asg.ID).Updates(asg)
 	if result.RowsAffected == 0 {
@@ -292,6 +405,24 @@ func (r *assignmentRepository) UpdateAssignment(asg *domain.Assignment) error {
 	return result.Error
 }
 
+func (r *assignmentRepository) SetAssignmentRubric(assignmentID string, rubricID *string) error {
+	result := r.db.Model(&domain.Assignment{}).Where("asg_id = ?", assignmentID).Update("asg_rbr_id", rubricID)
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (r *assignmentRepository) AssignmentHasRubricScores(assignmentID string) (bool, error) {
+	var count int64
+	err := r.db.Table("edv.assessment_rubric_scores ars").
+		Joins("JOIN edv.assessments asm ON asm.asm_id = ars.ars_asm_id").
+		Joins("JOIN edv.submissions s ON s.sbm_id = asm.asm_sbm_id").
+		Where("s.sbm_asg_id = ?", assignmentID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (r *assignmentRepository) DeleteAssignment(id string) error {
 	result := r.db.Where("asg_id = ?", id).Delete(&domain.Assignment{})
 	if result.RowsAffected == 0 {
@@ -301,18 +432,25 @@ func (r *assignmentRepository) DeleteAssignment(id string) error {
 }
 
 func (r *assignmentRepository) UpsertSubmission(sbm *domain.Submission) error {
+	return upsertSubmission(r.db, sbm)
+}
+
+// upsertSubmission revives the student's earlier submission of the same
+// assignment, soft-deleted or not, or creates a new one.
+func upsertSubmission(tx *gorm.DB, sbm *domain.Submission) error {
 	var existing domain.Submission
 	// cek apakah user sudah pernah submit assignment ini sebelumnya
-	err := r.db.Unscoped().Where("sbm_asg_id = ? AND sbm_usr_id = ?", sbm.AssignmentID, sbm.UserID).First(&existing).Error
+	err := tx.Unscoped().Where("sbm_asg_id = ? AND sbm_usr_id = ?", sbm.AssignmentID, sbm.UserID).First(&existing).Error
 
 	if err == nil {
 		sbm.ID = existing.ID
 		sbm.DeletedAt = gorm.DeletedAt{} //reset deleted_at
-		return r.db.Save(sbm).Error
+		// The late penalty waiver outlives resubmissions.
+		return tx.Omit("PenaltyWaived", "PenaltyWaivedBy", "PenaltyWaivedAt").Save(sbm).Error
 	}
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return r.db.Create(sbm).Error
+		return tx.Create(sbm).Error
 	}
 
 	return err
@@ -320,21 +458,31 @@ func (r *assignmentRepository) UpsertSubmission(sbm *domain.Submission) error {
 
 func (r *assignmentRepository) GetSubmissionsByAssignment(asgID string) ([]*domain.Submission, error) {
 	var results []*domain.Submission
-	err := r.db.Preload("User").Where("sbm_asg_id = ?", asgID).Order("submitted_at asc").Find(&results).Error
+	err := r.db.Preload("User").Preload("Group").Where("sbm_asg_id = ?", asgID).Order("submitted_at asc").Find(&results).Error
 	return results, err
 }
 
 func (r *assignmentRepository) GetSubmissionByID(id string) (*domain.Submission, error) {
 	var sbm domain.Submission
-	err := r.db.Preload("User").Preload("Assessment.Assessor").Where("sbm_id = ?", id).First(&sbm).Error
+	err := r.db.Preload("User").
+		Preload("Group").
+		Preload("Members.User").
+		Preload("Assessment.Assessor").
+		Preload("Assessment.RubricScores").
+		Where("sbm_id = ?", id).
+		First(&sbm).Error
 	return &sbm, err
 }
 
 func (r *assignmentRepository) GetMySubmissionByAssignment(assignmentID string, userID string, schoolID string) (*domain.Submission, error) {
 	var sbm domain.Submission
 	query := r.db.Preload("User").
+		Preload("Group").
+		Preload("Members.User").
 		Preload("Assessment.Assessor").
-		Where("sbm_asg_id = ? AND sbm_usr_id = ?", assignmentID, userID)
+		Preload("Assessment.RubricScores").
+		Where("sbm_asg_id = ?", assignmentID).
+		Where(studentSubmissionCondition("submissions"), userID, userID)
 
 	if schoolID != "" {
 		query = query.Where("sbm_sch_id = ?", schoolID)
@@ -345,7 +493,7 @@ func (r *assignmentRepository) GetMySubmissionByAssignment(assignmentID string,
 }
 
 func (r *assignmentRepository) UpdateSubmission(sbm *domain.Submission) error {
-	result := r.db.Model(&domain.Submission{}).Where("sbm_id = ?", sbm.ID).Updates(sbm)
+	result := r.db.Model(&domain.Submission{}).Where("sbm_id = ?", sbm.ID).Omit("Group", "Members").Updates(sbm)
 	if result.RowsAffected == 0 {
 		return gorm.ErrRecordNotFound
 	}
@@ -368,47 +516,60 @@ func (r *assignmentRepository) DeleteSubmission(id string) error {
 
 func (r *assignmentRepository) UpsertAssessment(asm *domain.Assessment) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		var existing []domain.Assessment
-		if err := tx.
-			Where("asm_sbm_id = ?", asm.SubmissionID).
-			Order("assessed_at desc, asm_id desc").
-			Find(&existing).Error; err != nil {
+		return upsertAssessment(tx, asm)
+	})
+}
+
+// upsertAssessment updates the newest grade of asm.SubmissionID, dropping any
+// duplicates, or creates one when the submission has none yet.
+func upsertAssessment(tx *gorm.DB, asm *domain.Assessment) error {
+	var existing []domain.Assessment
+	if err := tx.
+		Where("asm_sbm_id = ?", asm.SubmissionID).
+		Order("assessed_at desc, asm_id desc").
+		Find(&existing).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if len(existing) == 0 {
+		asm.AssessedAt = now
+		if err := tx.Omit("RubricScores").Create(asm).Error; err != nil {
 			return err
 		}
+		return replaceAssessmentRubricScores(tx, asm.ID, asm.RubricScores)
+	}
 
-		now := time.Now()
-		if len(existing) == 0 {
-			asm.AssessedAt = now
-			return tx.Create(asm).Error
-		}
+	keepID := existing[0].ID
+	if err := tx.Model(&domain.Assessment{}).
+		Where("asm_id = ?", keepID).
+		Updates(map[string]any{
+			"asm_score":    asm.Score,
+			"asm_feedback": asm.Feedback,
+			"assessed_by":  asm.AssessedBy,
+			"assessed_at":  now,
+			"asm_sbm_id":   asm.SubmissionID,
+			"asm_sbv_id":   asm.VersionID,
+		}).Error; err != nil {
+		return err
+	}
 
-		keepID := existing[0].ID
-		if err := tx.Model(&domain.Assessment{}).
-			Where("asm_id = ?", keepID).
-			Updates(map[string]any{
-				"asm_score":    asm.Score,
-				"asm_feedback": asm.Feedback,
-				"assessed_by":  asm.AssessedBy,
-				"assessed_at":  now,
-				"asm_sbm_id":   asm.SubmissionID,
-			}).Error; err != nil {
+	if len(existing) > 1 {
+		duplicateIDs := make([]string, 0, len(existing)-1)
+		for _, item := range existing[1:] {
+			duplicateIDs = append(duplicateIDs, item.ID)
+		}
+		if err := tx.Where("ars_asm_id IN ?", duplicateIDs).Delete(&domain.AssessmentRubricScore{}).Error; err != nil {
 			return err
 		}
-
-		if len(existing) > 1 {
-			duplicateIDs := make([]string, 0, len(existing)-1)
-			for _, item := range existing[1:] {
-				duplicateIDs = append(duplicateIDs, item.ID)
-			}
-			if err := tx.Where("asm_id IN ?", duplicateIDs).Delete(&domain.Assessment{}).Error; err != nil {
-				return err
-			}
+		if err := tx.Where("asm_id IN ?", duplicateIDs).Delete(&domain.Assessment{}).Error; err != nil {
+			return err
 		}
+	}
 
-		asm.ID = keepID
-		asm.AssessedAt = now
-		return nil
-	})
+	asm.ID = keepID
+	asm.AssessedAt = now
+	return replaceAssessmentRubricScores(tx, asm.ID, asm.RubricScores)
 }
 
 func (r *assignmentRepository) GetAssessmentBySubmission(sbmID string) (*domain.Assessment, error) {
@@ -417,20 +578,72 @@ func (r *assignmentRepository) GetAssessmentBySubmission(sbmID string) (*domain.
 	return &asm, err
 }
 
+// UpdateAssessment updates the non-zero fields of asm. When asm.RubricScores
+// is set, the score is written as is and the rubric scores are replaced.
 func (r *assignmentRepository) UpdateAssessment(asm *domain.Assessment) error {
-	result := r.db.Model(&domain.Assessment{}).Where("asm_sbm_id = ?", asm.SubmissionID).Updates(asm)
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
-	return result.Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Assessment{}).Where("asm_sbm_id = ?", asm.SubmissionID).Omit("RubricScores").Updates(asm)
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if result.Error != nil || asm.RubricScores == nil {
+			return result.Error
+		}
+
+		var existing domain.Assessment
+		if err := tx.Where("asm_sbm_id = ?", asm.SubmissionID).First(&existing).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Assessment{}).Where("asm_id = ?", existing.ID).Update("asm_score", asm.Score).Error; err != nil {
+			return err
+		}
+		return replaceAssessmentRubricScores(tx, existing.ID, asm.RubricScores)
+	})
 }
 
+// DeleteAssessment removes the grade of a submission along with the grades of
+// its versions.
 func (r *assignmentRepository) DeleteAssessment(submissionID string) error {
-	result := r.db.Where("asm_sbm_id = ?", submissionID).Delete(&domain.Assessment{})
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.SubmissionVersion{}).
+			Where("sbv_sbm_id = ?", submissionID).
+			Updates(map[string]interface{}{
+				"sbv_score":     nil,
+				"sbv_feedback":  "",
+				"sbv_graded_by": nil,
+				"sbv_graded_at": nil,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ars_asm_id IN (?)", tx.Model(&domain.Assessment{}).Select("asm_id").Where("asm_sbm_id = ?", submissionID)).
+			Delete(&domain.AssessmentRubricScore{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("asm_sbm_id = ?", submissionID).Delete(&domain.Assessment{})
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return result.Error
+	})
+}
+
+// replaceAssessmentRubricScores swaps the rubric scores of an assessment for
+// scores. A nil scores leaves the stored ones untouched.
+func replaceAssessmentRubricScores(tx *gorm.DB, assessmentID string, scores []domain.AssessmentRubricScore) error {
+	if scores == nil {
+		return nil
 	}
-	return result.Error
+	if err := tx.Where("ars_asm_id = ?", assessmentID).Delete(&domain.AssessmentRubricScore{}).Error; err != nil {
+		return err
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+	for i := range scores {
+		scores[i].ID = ""
+		scores[i].AssessmentID = assessmentID
+	}
+	return tx.Create(&scores).Error
 }
 
 func (r *assignmentRepository) SetWeight(weight *domain.AssessmentWeight) error {
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateSubmissionVersion stores version as the next turn-in of its
+// submission. The submission row is locked so concurrent turn-ins get
+// distinct numbers.
+func (r *assignmentRepository) CreateSubmissionVersion(version *domain.SubmissionVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var sbm domain.Submission
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("sbm_id = ?", version.SubmissionID).
+			First(&sbm).Error; err != nil {
+			return err
+		}
+
+		var last int
+		if err := tx.Model(&domain.SubmissionVersion{}).
+			Where("sbv_sbm_id = ?", version.SubmissionID).
+			Select("COALESCE(MAX(sbv_number), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		version.Number = last + 1
+		return tx.Omit("Grader").Create(version).Error
+	})
+}
+
+// CountSubmissionVersions counts the turn-ins made for the assignment of sbm
+// by its group, or by its student when it is not a group submission,
+// including those of a deleted submission.
+func (r *assignmentRepository) CountSubmissionVersions(sbm *domain.Submission) (int64, error) {
+	query := r.db.Table("edv.submission_versions sbv").
+		Joins("JOIN edv.submissions s ON s.sbm_id = sbv.sbv_sbm_id").
+		Where("s.sbm_asg_id = ?", sbm.AssignmentID)
+	if sbm.GroupID != nil {
+		query = query.Where("s.sbm_grp_id = ?", *sbm.GroupID)
+	} else {
+		query = query.Where("s.sbm_usr_id = ?", sbm.UserID)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+func (r *assignmentRepository) ListSubmissionVersions(submissionID string) ([]*domain.SubmissionVersion, error) {
+	var results []*domain.SubmissionVersion
+	err := r.db.Preload("Grader").
+		Where("sbv_sbm_id = ?", submissionID).
+		Order("sbv_number asc").
+		Find(&results).Error
+	return results, err
+}
+
+func (r *assignmentRepository) GetSubmissionVersion(id string) (*domain.SubmissionVersion, error) {
+	var version domain.SubmissionVersion
+	err := r.db.Where("sbv_id = ?", id).First(&version).Error
+	return &version, err
+}
+
+func (r *assignmentRepository) GradeSubmissionVersion(version *domain.SubmissionVersion) error {
+	return gradeSubmissionVersion(r.db, version)
+}
+
+func gradeSubmissionVersion(tx *gorm.DB, version *domain.SubmissionVersion) error {
+	result := tx.Model(&domain.SubmissionVersion{}).
+		Where("sbv_id = ?", version.ID).
+		Updates(map[string]interface{}{
+			"sbv_score":     version.Score,
+			"sbv_feedback":  version.Feedback,
+			"sbv_graded_by": version.GradedBy,
+			"sbv_graded_at": version.GradedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *assignmentRepository) AssignmentHasGradedVersions(assignmentID string) (bool, error) {
+	var count int64
+	err := r.db.Table("edv.submission_versions sbv").
+		Joins("JOIN edv.submissions s ON s.sbm_id = sbv.sbv_sbm_id").
+		Where("s.sbm_asg_id = ? AND sbv.sbv_score IS NOT NULL", assignmentID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *assignmentRepository) SetAssignmentMaxAttempts(assignmentID string, maxAttempts *int) error {
+	result := r.db.Model(&domain.Assignment{}).Where("asg_id = ?", assignmentID).Update("asg_max_attempts", maxAttempts)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"strings"
+	"testing"
+)
+
+func TestCountSubmissionVersionsCountsGroupAttempts(t *testing.T) {
+	groupID := "group-1"
+	tests := []struct {
+		name  string
+		sbm   *domain.Submission
+		where string
+		arg   string
+	}{
+		{"group", &domain.Submission{AssignmentID: "assignment-1", UserID: "student-1", GroupID: &groupID}, "s.sbm_grp_id = $2", "group-1"},
+		{"individual", &domain.Submission{AssignmentID: "assignment-1", UserID: "student-1"}, "s.sbm_usr_id = $2", "student-1"},
+	}
+	for _, tc := range tests {
+		db, conn := newRecordingDB(t)
+		repo := NewAssignmentRepository(db)
+
+		if _, err := repo.CountSubmissionVersions(tc.sbm); err != nil {
+			t.Fatalf("%s: CountSubmissionVersions() error = %v", tc.name, err)
+		}
+		statement := conn.statements[0]
+		if query := compactSQL(statement.sql); !strings.Contains(query, tc.where) {
+			t.Fatalf("%s: expected attempts counted by %q: %s", tc.name, tc.where, query)
+		}
+		if len(statement.args) != 2 || statement.args[0] != "assignment-1" || statement.args[1] != tc.arg {
+			t.Fatalf("%s: expected assignment and %s to be bound, got %v", tc.name, tc.arg, statement.args)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatModerationRepository interface {
+	CreateReport(report *domain.ChatMessageReport) (bool, error)
+	GetReport(reportID string, schoolID string) (*domain.ChatMessageReport, error)
+	GetReportRow(reportID string, schoolID string) (*ChatReportRow, error)
+	ListReports(schoolID string, status string, page int, limit int) ([]ChatReportRow, int64, error)
+	ResolveReport(reportID string, schoolID string, status string, resolvedBy string, note *string) error
+	UpsertMute(mute *domain.ChatRoomMute) error
+	DeleteMute(roomID string, userID string) error
+	GetActiveMute(roomID string, userID string) (*domain.ChatRoomMute, error)
+	ListActiveMutes(userID string, schoolID string) (map[string]time.Time, error)
+	ListBannedWords(schoolID string) ([]string, error)
+	ReplaceBannedWords(schoolID string, createdBy string, words []string) error
+}
+
+type chatModerationRepository struct {
+	db *gorm.DB
+}
+
+type ChatReportRow struct {
+	ReportID       string     `gorm:"column:report_id"`
+	RoomID         string     `gorm:"column:room_id"`
+	RoomName       string     `gorm:"column:room_name"`
+	RoomType       string     `gorm:"column:room_type"`
+	MessageID      string     `gorm:"column:message_id"`
+	MessageContent string     `gorm:"column:message_content"`
+	MessageDeleted bool       `gorm:"column:message_deleted"`
+	SenderID       string     `gorm:"column:sender_id"`
+	SenderName     string     `gorm:"column:sender_name"`
+	ReporterID     string     `gorm:"column:reporter_id"`
+	ReporterName   string     `gorm:"column:reporter_name"`
+	Reason         string     `gorm:"column:reason"`
+	Status         string     `gorm:"column:status"`
+	ReportCount    int        `gorm:"column:report_count"`
+	ResolvedBy     *string    `gorm:"column:resolved_by"`
+	ResolvedAt     *time.Time `gorm:"column:resolved_at"`
+	ResolutionNote *string    `gorm:"column:resolution_note"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+}
+
+func NewChatModerationRepository(db *gorm.DB) ChatModerationRepository {
+	return &chatModerationRepository{db: db}
+}
+
+// CreateReport stores the report and reports false when the reporter already
+// reported the same message.
+func (r *chatModerationRepository) CreateReport(report *domain.ChatMessageReport) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "crp_msg_id"}, {Name: "crp_reporter_id"}},
+		DoNothing: true,
+	}).Create(report)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *chatModerationRepository) GetReport(reportID string, schoolID string) (*domain.ChatMessageReport, error) {
+	var report domain.ChatMessageReport
+	err := r.db.Where("crp_id = ? AND crp_sch_id = ?", reportID, schoolID).First(&report).Error
+	return &report, err
+}
+
+func (r *chatModerationRepository) GetReportRow(reportID string, schoolID string) (*ChatReportRow, error) {
+	var row ChatReportRow
+	err := r.db.Raw(chatReportRowSelect()+`
+		WHERE crp.crp_id = ?
+			AND crp.crp_sch_id = ?
+		LIMIT 1
+	`, reportID, schoolID).Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	if row.ReportID == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &row, nil
+}
+
+// ListReports returns the school's reports, newest first. An empty status
+// lists every status.
+func (r *chatModerationRepository) ListReports(schoolID string, status string, page int, limit int) ([]ChatReportRow, int64, error) {
+	var total int64
+	if err := r.db.Model(&domain.ChatMessageReport{}).
+		Where("crp_sch_id = ? AND (? = '' OR crp_status = ?)", schoolID, status, status).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []ChatReportRow
+	err := r.db.Raw(chatReportRowSelect()+`
+		WHERE crp.crp_sch_id = ?
+			AND (? = '' OR crp.crp_status = ?)
+		ORDER BY crp.created_at DESC
+		LIMIT ? OFFSET ?
+	`, schoolID, status, status, limit, (page-1)*limit).Scan(&rows).Error
+	return rows, total, err
+}
+
+func (r *chatModerationRepository) ResolveReport(reportID string, schoolID string, status string, resolvedBy string, note *string) error {
+	result := r.db.Exec(`
+		UPDATE edv.chat_message_reports
+		SET crp_status = ?,
+			crp_resolved_by = ?,
+			crp_resolved_at = NOW(),
+			crp_resolution_note = ?
+		WHERE crp_id = ?
+			AND crp_sch_id = ?
+			AND crp_status = 'open'
+	`, status, resolvedBy, note, reportID, schoolID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpsertMute replaces any existing mute of the user in the room.
+func (r *chatModerationRepository) UpsertMute(mute *domain.ChatRoomMute) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cmu_room_id"}, {Name: "cmu_usr_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"cmu_muted_by", "cmu_reason", "cmu_muted_until", "created_at"}),
+	}).Create(mute).Error
+}
+
+func (r *chatModerationRepository) DeleteMute(roomID string, userID string) error {
+	result := r.db.Exec(`
+		DELETE FROM edv.chat_room_mutes
+		WHERE cmu_room_id = ?
+			AND cmu_usr_id = ?
+			AND cmu_muted_until > NOW()
+	`, roomID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *chatModerationRepository) GetActiveMute(roomID string, userID string) (*domain.ChatRoomMute, error) {
+	var mute domain.ChatRoomMute
+	err := r.db.Where("cmu_room_id = ? AND cmu_usr_id = ? AND cmu_muted_until > NOW()", roomID, userID).First(&mute).Error
+	return &mute, err
+}
+
+// ListActiveMutes maps room IDs in the school to the end of the user's mute.
+func (r *chatModerationRepository) ListActiveMutes(userID string, schoolID string) (map[string]time.Time, error) {
+	var rows []struct {
+		RoomID     string    `gorm:"column:room_id"`
+		MutedUntil time.Time `gorm:"column:muted_until"`
+	}
+	err := r.db.Raw(`
+		SELECT cmu.cmu_room_id AS room_id, cmu.cmu_muted_until AS muted_until
+		FROM edv.chat_room_mutes cmu
+		JOIN edv.chat_rooms cr
+			ON cr.room_id = cmu.cmu_room_id
+			AND cr.room_sch_id = ?
+		WHERE cmu.cmu_usr_id = ?
+			AND cmu.cmu_muted_until > NOW()
+	`, schoolID, userID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		result[row.RoomID] = row.MutedUntil
+	}
+	return result, nil
+}
+
+func (r *chatModerationRepository) ListBannedWords(schoolID string) ([]string, error) {
+	var words []string
+	err := r.db.Model(&domain.ChatBannedWord{}).
+		Where("cbw_sch_id = ?", schoolID).
+		Order("cbw_word ASC").
+		Pluck("cbw_word", &words).Error
+	return words, err
+}
+
+func (r *chatModerationRepository) ReplaceBannedWords(schoolID string, createdBy string, words []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cbw_sch_id = ?", schoolID).Delete(&domain.ChatBannedWord{}).Error; err != nil {
+			return err
+		}
+		if len(words) == 0 {
+			return nil
+		}
+		rows := make([]domain.ChatBannedWord, 0, len(words))
+		for _, word := range words {
+			rows = append(rows, domain.ChatBannedWord{
+				SchoolID:  schoolID,
+				Word:      word,
+				CreatedBy: createdBy,
+			})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func chatReportRowSelect() string {
+	return `
+		SELECT
+			crp.crp_id AS report_id,
+			cr.room_id AS room_id,
+			cr.room_name AS room_name,
+			cr.room_type AS room_type,
+			msg.msg_id AS message_id,
+			msg.msg_content AS message_content,
+			msg.deleted_at IS NOT NULL AS message_deleted,
+			msg.msg_usr_id AS sender_id,
+			COALESCE(sender.usr_nama_lengkap, 'Pengguna') AS sender_name,
+			crp.crp_reporter_id AS reporter_id,
+			COALESCE(reporter.usr_nama_lengkap, 'Pengguna') AS reporter_name,
+			crp.crp_reason AS reason,
+			crp.crp_status AS status,
+			(
+				SELECT COUNT(*)
+				FROM edv.chat_message_reports other
+				WHERE other.crp_msg_id = crp.crp_msg_id
+			)::int AS report_count,
+			crp.crp_resolved_by AS resolved_by,
+			crp.crp_resolved_at AS resolved_at,
+			crp.crp_resolution_note AS resolution_note,
+			crp.created_at AS created_at
+		FROM edv.chat_message_reports crp
+		JOIN edv.chat_messages msg ON msg.msg_id = crp.crp_msg_id
+		JOIN edv.chat_rooms cr ON cr.room_id = crp.crp_room_id
+		LEFT JOIN edv.users sender ON sender.usr_id = msg.msg_usr_id
+		LEFT JOIN edv.users reporter ON reporter.usr_id = crp.crp_reporter_id
+	`
+}
@@ -24,22 +24,56 @@ type ChatRepository interface {
 	UserIsRoomAdmin(userID string, roomID string) (bool, error)
 	CreateGroupRoomWithMembers(schoolID string, roomName string, creatorID string, memberUserIDs []string) (*domain.ChatRoom, error)
 	UpdateGroupRoomName(roomID string, schoolID string, roomName string) error
+	UpdateRoomPostingMode(roomID string, schoolID string, postingMode string) error
 	LeaveGroupRoom(roomID string, schoolID string, userID string) error
 	AddGroupRoomMembers(roomID string, schoolID string, memberUserIDs []string) error
 	RemoveGroupRoomMember(roomID string, schoolID string, targetUserID string) error
+	SyncClassRooms(classID string) error
+	CloseSubjectClassRoom(subjectClassID string) error
+	ListActiveClassIDs() ([]string, error)
 	ListMessages(roomID string, limit int, before *time.Time) ([]ChatMessageRow, error)
-	CreateMessageWithAttachments(message *domain.ChatMessage, mediaIDs []string) error
+	ListThreadReplies(parentID string, roomID string, limit int, before *time.Time) ([]ChatMessageRow, error)
+	SearchMessages(filter ChatMessageSearchFilter) ([]ChatMessageSearchRow, int64, error)
+	CreateMessageWithAttachments(message *domain.ChatMessage, mediaIDs []string, mentionUserIDs []string) error
+	CreateScheduledMessage(scheduled *domain.ChatScheduledMessage, message *domain.ChatMessage, mediaIDs []string, mentionUserIDs []string) error
 	GetMessageByID(messageID string, roomID string) (*ChatMessageRow, error)
+	UpdateMessageContent(messageID string, roomID string, editorID string, content string) error
+	SoftDeleteMessage(messageID string, roomID string, deletedBy string) error
+	UserIsSchoolAdmin(userID string, schoolID string) (bool, error)
 	ListMessageAttachments(messageIDs []string) (map[string][]ChatAttachmentRow, error)
+	AddReaction(messageID string, userID string, emoji string) error
+	RemoveReaction(messageID string, userID string, emoji string) error
+	CountReaction(messageID string, emoji string) (int64, error)
+	ListMessageReactions(messageIDs []string, userID string) (map[string][]ChatReactionRow, error)
+	PinMessage(pin *domain.ChatPinnedMessage, limit int64) (bool, error)
+	UnpinMessage(roomID string, messageID string) error
+	GetPin(roomID string, messageID string) (*domain.ChatPinnedMessage, error)
+	ListPinnedMessages(roomID string) ([]ChatPinnedMessageRow, error)
+	ListPinnedMessageIDs(messageIDs []string) (map[string]bool, error)
 	UpsertReadReceipt(roomID string, userID string, messageID *string) error
 	GetReadReceipt(roomID string, userID string) (*ChatReadReceiptRow, error)
 	ListSchoolReadMembers(roomID string, schoolID string) ([]ChatReadMemberRow, error)
 	ListRoomReadMembers(roomID string, schoolID string) ([]ChatReadMemberRow, error)
 	ListSchoolRecipientUserIDs(schoolID string) ([]string, error)
 	ListRoomRecipientUserIDs(roomID string, schoolID string) ([]string, error)
+	ListRoomPeerUserIDs(userID string, schoolID string) ([]string, error)
 	UnreadCount(roomID string, userID string) (int64, error)
+	UnreadMentionCount(roomID string, userID string) (int64, error)
+	GetRoomSetting(roomID string, userID string) (*domain.ChatRoomSetting, error)
+	UpsertRoomSetting(setting *domain.ChatRoomSetting) error
+	ListRoomSettings(userID string, schoolID string) (map[string]domain.ChatRoomSetting, error)
+	ListMentionTargets(roomID string, schoolID string, userIDs []string) ([]ChatMentionTargetRow, error)
+	ListContentCards(schoolID string, refs []ChatContentRef) ([]ChatContentCardRow, error)
+	ListContentViewers(schoolID string, refs []ChatContentRef, userIDs []string) ([]ChatContentViewerRow, error)
 }
 
+// Search snippet markers. They are control characters so they cannot collide
+// with message content and survive until the service escapes the snippet.
+const (
+	ChatSearchMatchStart = "\x02"
+	ChatSearchMatchStop  = "\x03"
+)
+
 type chatRepository struct {
 	db *gorm.DB
 }
@@ -57,6 +91,7 @@ type ChatRoomRow struct {
 	LastSenderName         *string    `gorm:"column:last_sender_name"`
 	LastContent            *string    `gorm:"column:last_content"`
 	LastType               *string    `gorm:"column:last_type"`
+	LastRefType            *string    `gorm:"column:last_ref_type"`
 	LastAttachmentCount    int        `gorm:"column:last_attachment_count"`
 	LastAttachmentMimeType *string    `gorm:"column:last_attachment_mime_type"`
 	LastAttachmentFileName *string    `gorm:"column:last_attachment_file_name"`
@@ -64,6 +99,7 @@ type ChatRoomRow struct {
 	DMTargetUserID         *string    `gorm:"column:dm_target_user_id"`
 	DMTargetName           *string    `gorm:"column:dm_target_name"`
 	DMTargetEmail          *string    `gorm:"column:dm_target_email"`
+	PostingMode            string     `gorm:"column:posting_mode"`
 }
 
 type ChatMemberRow struct {
@@ -85,6 +121,7 @@ type ChatGroupInfoRow struct {
 	CreatorRoles      *string   `gorm:"column:creator_roles"`
 	CreatedAt         time.Time `gorm:"column:created_at"`
 	ActiveMemberCount int       `gorm:"column:active_member_count"`
+	PostingMode       string    `gorm:"column:posting_mode"`
 }
 
 type ChatGroupMemberRow struct {
@@ -97,14 +134,87 @@ type ChatGroupMemberRow struct {
 }
 
 type ChatMessageRow struct {
-	MessageID  string    `gorm:"column:message_id"`
-	RoomID     string    `gorm:"column:room_id"`
-	SenderID   string    `gorm:"column:sender_id"`
-	SenderName string    `gorm:"column:sender_name"`
-	SenderRole string    `gorm:"column:sender_role"`
-	Content    string    `gorm:"column:content"`
-	Type       string    `gorm:"column:message_type"`
-	CreatedAt  time.Time `gorm:"column:created_at"`
+	MessageID  string     `gorm:"column:message_id"`
+	RoomID     string     `gorm:"column:room_id"`
+	SenderID   string     `gorm:"column:sender_id"`
+	SenderName string     `gorm:"column:sender_name"`
+	SenderRole string     `gorm:"column:sender_role"`
+	Content    string     `gorm:"column:content"`
+	Type       string     `gorm:"column:message_type"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	EditedAt   *time.Time `gorm:"column:edited_at"`
+	ReplyTo    *string    `gorm:"column:reply_to"`
+	ReplyCount int        `gorm:"column:reply_count"`
+	RefType    *string    `gorm:"column:ref_type"`
+	RefID      *string    `gorm:"column:ref_id"`
+}
+
+// ChatContentRef identifies LMS content shared into a chat message.
+type ChatContentRef struct {
+	Type string
+	ID   string
+}
+
+// ChatContentCardRow is the preview of shared content. Title holds the feed
+// content for feed posts, which have no title.
+type ChatContentCardRow struct {
+	RefType        string     `gorm:"column:ref_type"`
+	RefID          string     `gorm:"column:ref_id"`
+	Title          string     `gorm:"column:title"`
+	ClassID        string     `gorm:"column:class_id"`
+	ClassName      string     `gorm:"column:class_name"`
+	SubjectClassID *string    `gorm:"column:subject_class_id"`
+	SubjectName    *string    `gorm:"column:subject_name"`
+	Deadline       *time.Time `gorm:"column:deadline"`
+}
+
+// ChatContentViewerRow grants one user access to one shared content item.
+// Audience is the most privileged way the user reaches it: admin, teacher or
+// student.
+type ChatContentViewerRow struct {
+	RefType  string `gorm:"column:ref_type"`
+	RefID    string `gorm:"column:ref_id"`
+	UserID   string `gorm:"column:user_id"`
+	Audience string `gorm:"column:audience"`
+}
+
+// ChatMessageSearchFilter narrows SearchMessages to rooms the user can read in
+// one school. Nil filters are ignored.
+type ChatMessageSearchFilter struct {
+	UserID        string
+	SchoolID      string
+	Query         string
+	RoomID        *string
+	SenderID      *string
+	From          *time.Time
+	To            *time.Time
+	HasAttachment *bool
+	Limit         int
+	Offset        int
+}
+
+type ChatMessageSearchRow struct {
+	MessageID       string    `gorm:"column:message_id"`
+	RoomID          string    `gorm:"column:room_id"`
+	RoomName        string    `gorm:"column:room_name"`
+	RoomType        string    `gorm:"column:room_type"`
+	DMTargetName    *string   `gorm:"column:dm_target_name"`
+	DMTargetEmail   *string   `gorm:"column:dm_target_email"`
+	SenderID        string    `gorm:"column:sender_id"`
+	SenderName      string    `gorm:"column:sender_name"`
+	Type            string    `gorm:"column:message_type"`
+	Snippet         string    `gorm:"column:snippet"`
+	AttachmentCount int       `gorm:"column:attachment_count"`
+	ReplyTo         *string   `gorm:"column:reply_to"`
+	CreatedAt       time.Time `gorm:"column:created_at"`
+}
+
+// ChatPinnedMessageRow is a pinned message with who pinned it and when.
+type ChatPinnedMessageRow struct {
+	ChatMessageRow
+	PinnedBy     string    `gorm:"column:pinned_by"`
+	PinnedByName string    `gorm:"column:pinned_by_name"`
+	PinnedAt     time.Time `gorm:"column:pinned_at"`
 }
 
 type ChatAttachmentRow struct {
@@ -117,6 +227,13 @@ type ChatAttachmentRow struct {
 	URL          string `gorm:"column:url"`
 }
 
+type ChatReactionRow struct {
+	MessageID   string `gorm:"column:message_id"`
+	Emoji       string `gorm:"column:emoji"`
+	Count       int    `gorm:"column:count"`
+	ReactedByMe bool   `gorm:"column:reacted_by_me"`
+}
+
 type ChatReadReceiptRow struct {
 	RoomID            string     `gorm:"column:room_id"`
 	LastReadMessageID *string    `gorm:"column:last_read_message_id"`
@@ -131,6 +248,13 @@ type ChatReadMemberRow struct {
 	LastReadAt        *time.Time `gorm:"column:last_read_at"`
 }
 
+type ChatMentionTargetRow struct {
+	UserID      string     `gorm:"column:user_id"`
+	Roles       string     `gorm:"column:roles"`
+	NotifyLevel *string    `gorm:"column:notify_level"`
+	MutedUntil  *time.Time `gorm:"column:muted_until"`
+}
+
 func NewChatRepository(db *gorm.DB) ChatRepository {
 	return &chatRepository{db: db}
 }
@@ -171,6 +295,17 @@ func (r *chatRepository) ListSchoolRooms(userID string, schoolID string, search
 							AND crm.left_at IS NULL
 					)
 				)
+				OR (
+					cr.room_type = 'group'
+					AND cr.room_ref_type IN ('class', 'subject')
+					AND EXISTS (
+						SELECT 1
+						FROM edv.chat_room_members crm
+						WHERE crm.crm_room_id = cr.room_id
+							AND crm.crm_usr_id = ?
+							AND crm.left_at IS NULL
+					)
+				)
 				OR (
 					cr.room_type = 'dm'
 					AND cr.room_ref_type IS NULL
@@ -185,7 +320,7 @@ func (r *chatRepository) ListSchoolRooms(userID string, schoolID string, search
 				)
 			)
 		ORDER BY COALESCE(lm.created_at, cr.created_at) DESC
-	`, userID, userID, schoolID, search, searchPattern, searchPattern, searchPattern, searchPattern, schoolID, userID, userID).Scan(&rows).Error
+	`, userID, userID, schoolID, search, searchPattern, searchPattern, searchPattern, searchPattern, schoolID, userID, userID, userID).Scan(&rows).Error
 	return rows, err
 }
 
@@ -381,7 +516,8 @@ func (r *chatRepository) GetGroupInfo(roomID string, schoolID string) (*ChatGrou
 			creator.usr_email AS creator_email,
 			creator_roles.roles AS creator_roles,
 			cr.created_at,
-			COUNT(active_members.crm_id)::int AS active_member_count
+			COUNT(active_members.crm_id)::int AS active_member_count,
+			COALESCE(cr.room_posting_mode, 'everyone') AS posting_mode
 		FROM edv.chat_rooms cr
 		JOIN edv.schools s
 			ON s.sch_id = cr.room_sch_id
@@ -407,7 +543,7 @@ func (r *chatRepository) GetGroupInfo(roomID string, schoolID string) (*ChatGrou
 			AND cr.room_ref_type IS NULL
 			AND cr.room_ref_id IS NULL
 			AND cr.deleted_at IS NULL
-		GROUP BY cr.room_id, cr.room_name, cr.room_type, s.sch_id, s.sch_name, creator.usr_id, creator.usr_nama_lengkap, creator.usr_email, creator_roles.roles, cr.created_at
+		GROUP BY cr.room_id, cr.room_name, cr.room_type, s.sch_id, s.sch_name, creator.usr_id, creator.usr_nama_lengkap, creator.usr_email, creator_roles.roles, cr.created_at, cr.room_posting_mode
 		LIMIT 1
 	`, roomID, schoolID).Scan(&info).Error
 	if err != nil {
@@ -554,6 +690,25 @@ func (r *chatRepository) UpdateGroupRoomName(roomID string, schoolID string, roo
 	return nil
 }
 
+// UpdateRoomPostingMode applies to every room type except direct messages.
+func (r *chatRepository) UpdateRoomPostingMode(roomID string, schoolID string, postingMode string) error {
+	result := r.db.Exec(`
+		UPDATE edv.chat_rooms
+		SET room_posting_mode = ?
+		WHERE room_id = ?
+			AND room_sch_id = ?
+			AND room_type = 'group'
+			AND deleted_at IS NULL
+	`, postingMode, roomID, schoolID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *chatRepository) LeaveGroupRoom(roomID string, schoolID string, userID string) error {
 	now := time.Now()
 	return r.db.Transaction(func(tx *gorm.DB) error {
@@ -816,35 +971,146 @@ func promoteOldestMember(tx *gorm.DB, roomID string, excludeUserID string) (stri
 	return newAdminID, nil
 }
 
+// SyncClassRooms makes sure the class room and one room per subject class
+// exist for the class, then aligns their membership with the active
+// enrollments: enrolled users join, unenrolled users get left_at, class
+// teachers moderate the class room, and the assigned teacher moderates the
+// subject class room.
+func (r *chatRepository) SyncClassRooms(classID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO edv.chat_rooms (room_sch_id, room_name, room_type, room_ref_type, room_ref_id, created_by, created_at)
+			SELECT cls.cls_sch_id, LEFT(cls.cls_title, 150), 'group', 'class', cls.cls_id, cls.created_by, NOW()
+			FROM edv.classes cls
+			WHERE cls.cls_id = ?
+				AND cls.deleted_at IS NULL
+			ON CONFLICT (room_sch_id, room_ref_type, room_ref_id)
+			DO UPDATE SET room_name = EXCLUDED.room_name, deleted_at = NULL
+		`, classID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`
+			INSERT INTO edv.chat_rooms (room_sch_id, room_name, room_type, room_ref_type, room_ref_id, created_by, created_at)
+			SELECT cls.cls_sch_id, LEFT(cls.cls_title || ' - ' || sub.sub_name, 150), 'group', 'subject', scl.scl_id, cls.created_by, NOW()
+			FROM edv.subject_classes scl
+			JOIN edv.classes cls
+				ON cls.cls_id = scl.scl_cls_id
+				AND cls.deleted_at IS NULL
+			JOIN edv.subjects sub ON sub.sub_id = scl.scl_sub_id
+			WHERE scl.scl_cls_id = ?
+			ON CONFLICT (room_sch_id, room_ref_type, room_ref_id)
+			DO UPDATE SET room_name = EXCLUDED.room_name, deleted_at = NULL
+		`, classID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`
+			UPDATE edv.classes cls
+			SET cls_chat_room_id = cr.room_id
+			FROM edv.chat_rooms cr
+			WHERE cls.cls_id = ?
+				AND cr.room_sch_id = cls.cls_sch_id
+				AND cr.room_ref_type = 'class'
+				AND cr.room_ref_id = cls.cls_id
+				AND cls.cls_chat_room_id IS DISTINCT FROM cr.room_id
+		`, classID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`
+			UPDATE edv.subject_classes scl
+			SET scl_chat_room_id = cr.room_id
+			FROM edv.chat_rooms cr
+			WHERE scl.scl_cls_id = ?
+				AND cr.room_ref_type = 'subject'
+				AND cr.room_ref_id = scl.scl_id
+				AND scl.scl_chat_room_id IS DISTINCT FROM cr.room_id
+		`, classID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			WITH desired AS (`+classRoomDesiredMembers()+`)
+			UPDATE edv.chat_room_members crm
+			SET left_at = NOW()
+			FROM edv.chat_rooms cr
+			WHERE cr.room_id = crm.crm_room_id
+				AND crm.left_at IS NULL
+				AND (
+					(cr.room_ref_type = 'class' AND cr.room_ref_id = ?)
+					OR (
+						cr.room_ref_type = 'subject'
+						AND cr.room_ref_id IN (SELECT scl_id FROM edv.subject_classes WHERE scl_cls_id = ?)
+					)
+				)
+				AND NOT EXISTS (
+					SELECT 1
+					FROM desired d
+					WHERE d.room_id = crm.crm_room_id
+						AND d.usr_id = crm.crm_usr_id
+				)
+		`, classID, classID, classID, classID, classID).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			WITH desired AS (`+classRoomDesiredMembers()+`)
+			INSERT INTO edv.chat_room_members (crm_room_id, crm_usr_id, crm_enr_id, crm_role, joined_at)
+			SELECT DISTINCT ON (room_id, usr_id) room_id, usr_id, enr_id, role, NOW()
+			FROM desired
+			ORDER BY room_id, usr_id, role ASC
+			ON CONFLICT (crm_room_id, crm_usr_id)
+			DO UPDATE SET
+				crm_enr_id = EXCLUDED.crm_enr_id,
+				crm_role = EXCLUDED.crm_role,
+				joined_at = CASE
+					WHEN edv.chat_room_members.left_at IS NULL THEN edv.chat_room_members.joined_at
+					ELSE EXCLUDED.joined_at
+				END,
+				left_at = NULL
+		`, classID, classID, classID).Error
+	})
+}
+
+// CloseSubjectClassRoom retires the room of a removed subject class: every
+// member leaves and the room is soft-deleted so it drops out of room lists.
+func (r *chatRepository) CloseSubjectClassRoom(subjectClassID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE edv.chat_room_members crm
+			SET left_at = NOW()
+			FROM edv.chat_rooms cr
+			WHERE cr.room_id = crm.crm_room_id
+				AND cr.room_ref_type = 'subject'
+				AND cr.room_ref_id = ?
+				AND crm.left_at IS NULL
+		`, subjectClassID).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			UPDATE edv.chat_rooms
+			SET deleted_at = NOW()
+			WHERE room_ref_type = 'subject'
+				AND room_ref_id = ?
+				AND deleted_at IS NULL
+		`, subjectClassID).Error
+	})
+}
+
+// ListActiveClassIDs returns every class that is not deleted, in all schools.
+func (r *chatRepository) ListActiveClassIDs() ([]string, error) {
+	var classIDs []string
+	err := r.db.Raw(`
+		SELECT cls_id
+		FROM edv.classes
+		WHERE deleted_at IS NULL
+		ORDER BY cls_id
+	`).Scan(&classIDs).Error
+	return classIDs, err
+}
+
 func (r *chatRepository) ListMessages(roomID string, limit int, before *time.Time) ([]ChatMessageRow, error) {
 	var rows []ChatMessageRow
 	query := `
 		SELECT *
-		FROM (
-			SELECT
-				msg.msg_id AS message_id,
-				msg.msg_room_id AS room_id,
-				msg.msg_usr_id AS sender_id,
-				COALESCE(u.usr_nama_lengkap, 'Pengguna') AS sender_name,
-				COALESCE(sender_role.role_name, 'member') AS sender_role,
-				msg.msg_content AS content,
-				msg.msg_type AS message_type,
-				msg.created_at AS created_at
-			FROM edv.chat_messages msg
-			JOIN edv.users u ON u.usr_id = msg.msg_usr_id AND u.deleted_at IS NULL
-			LEFT JOIN LATERAL (
-				SELECT r.rol_name AS role_name
-				FROM edv.school_users scu
-				JOIN edv.user_roles ur ON ur.urol_scu_id = scu.scu_id
-				JOIN edv.roles r ON r.rol_id = ur.urol_rol_id
-				JOIN edv.chat_rooms cr_role ON cr_role.room_id = msg.msg_room_id
-				WHERE scu.scu_usr_id = msg.msg_usr_id
-					AND scu.scu_sch_id = cr_role.room_sch_id
-					AND scu.deleted_at IS NULL
-					AND r.rol_name IN ('admin', 'teacher', 'student')
-				ORDER BY CASE r.rol_name WHEN 'admin' THEN 1 WHEN 'teacher' THEN 2 WHEN 'student' THEN 3 ELSE 4 END
-				LIMIT 1
-			) sender_role ON true
+		FROM (` + chatMessageRowSelect() + `
 			WHERE msg.msg_room_id = ?
 				AND msg.msg_type IN ('text', 'file')
 				AND msg.deleted_at IS NULL
@@ -862,54 +1128,217 @@ func (r *chatRepository) ListMessages(roomID string, limit int, before *time.Tim
 	return rows, err
 }
 
-func (r *chatRepository) CreateMessageWithAttachments(message *domain.ChatMessage, mediaIDs []string) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(message).Error; err != nil {
-			return err
-		}
-		now := time.Now()
-		for _, mediaID := range mediaIDs {
-			attachment := domain.ChatAttachment{
-				MessageID: message.ID,
-				MediaID:   mediaID,
-				CreatedAt: now,
-			}
-			if err := tx.Create(&attachment).Error; err != nil {
-				return err
-			}
-		}
-		return nil
-	})
+func (r *chatRepository) ListThreadReplies(parentID string, roomID string, limit int, before *time.Time) ([]ChatMessageRow, error) {
+	var rows []ChatMessageRow
+	query := `
+		SELECT *
+		FROM (` + chatMessageRowSelect() + `
+			WHERE msg.msg_room_id = ?
+				AND msg.msg_reply_to = ?
+				AND msg.msg_type IN ('text', 'file')
+				AND msg.deleted_at IS NULL
+				AND (?::timestamptz IS NULL OR msg.created_at < ?)
+			ORDER BY msg.created_at DESC
+			LIMIT ?
+		) page
+		ORDER BY created_at ASC
+	`
+	var beforeValue any
+	if before != nil {
+		beforeValue = *before
+	}
+	err := r.db.Raw(query, roomID, parentID, beforeValue, beforeValue, limit).Scan(&rows).Error
+	return rows, err
 }
 
-func (r *chatRepository) GetMessageByID(messageID string, roomID string) (*ChatMessageRow, error) {
-	var row ChatMessageRow
+// SearchMessages runs a full-text search over msg_search using the simple
+// text search config, which only lowercases and so works for Indonesian and
+// English alike. Snippets mark matches with ChatSearchMatchStart and
+// ChatSearchMatchStop so callers can escape the content before highlighting.
+func (r *chatRepository) SearchMessages(filter ChatMessageSearchFilter) ([]ChatMessageSearchRow, int64, error) {
+	from := `
+		FROM edv.chat_messages msg
+		JOIN edv.chat_rooms cr
+			ON cr.room_id = msg.msg_room_id
+			AND cr.room_sch_id = ?
+			AND cr.room_type IN ('group', 'dm')
+			AND cr.deleted_at IS NULL
+		JOIN edv.users u ON u.usr_id = msg.msg_usr_id AND u.deleted_at IS NULL
+		CROSS JOIN websearch_to_tsquery('simple', ?) query
+		WHERE msg.msg_search @@ query
+			AND msg.msg_type IN ('text', 'file')
+			AND msg.deleted_at IS NULL
+			AND (
+				(cr.room_ref_type = 'school' AND cr.room_ref_id = cr.room_sch_id)
+				OR (
+					cr.room_ref_type IS DISTINCT FROM 'school'
+					AND EXISTS (
+						SELECT 1
+						FROM edv.chat_room_members crm
+						WHERE crm.crm_room_id = cr.room_id
+							AND crm.crm_usr_id = ?
+							AND crm.left_at IS NULL
+					)
+				)
+			)
+	`
+	args := []any{filter.SchoolID, filter.Query, filter.UserID}
+	if filter.RoomID != nil {
+		from += " AND msg.msg_room_id = ?"
+		args = append(args, *filter.RoomID)
+	}
+	if filter.SenderID != nil {
+		from += " AND msg.msg_usr_id = ?"
+		args = append(args, *filter.SenderID)
+	}
+	if filter.From != nil {
+		from += " AND msg.created_at >= ?"
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		from += " AND msg.created_at <= ?"
+		args = append(args, *filter.To)
+	}
+	if filter.HasAttachment != nil {
+		exists := "EXISTS"
+		if !*filter.HasAttachment {
+			exists = "NOT EXISTS"
+		}
+		from += " AND " + exists + " (SELECT 1 FROM edv.chat_attachments ca WHERE ca.cat_msg_id = msg.msg_id)"
+	}
+
+	var total int64
+	if err := r.db.Raw("SELECT COUNT(*) "+from, args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	var rows []ChatMessageSearchRow
+	headlineOptions := "StartSel=" + ChatSearchMatchStart + ", StopSel=" + ChatSearchMatchStop +
+		`, MaxWords=25, MinWords=8, MaxFragments=2, FragmentDelimiter=" ... "`
+	selectArgs := append([]any{filter.UserID, filter.UserID, headlineOptions}, args...)
+	selectArgs = append(selectArgs, filter.Limit, filter.Offset)
 	err := r.db.Raw(`
 		SELECT
 			msg.msg_id AS message_id,
 			msg.msg_room_id AS room_id,
+			cr.room_name AS room_name,
+			cr.room_type AS room_type,
+			(
+				SELECT dm_user.usr_nama_lengkap
+				FROM edv.chat_room_members crm
+				JOIN edv.users dm_user ON dm_user.usr_id = crm.crm_usr_id
+				WHERE cr.room_type = 'dm'
+					AND crm.crm_room_id = cr.room_id
+					AND crm.crm_usr_id <> ?
+				ORDER BY crm.joined_at ASC
+				LIMIT 1
+			) AS dm_target_name,
+			(
+				SELECT dm_user.usr_email
+				FROM edv.chat_room_members crm
+				JOIN edv.users dm_user ON dm_user.usr_id = crm.crm_usr_id
+				WHERE cr.room_type = 'dm'
+					AND crm.crm_room_id = cr.room_id
+					AND crm.crm_usr_id <> ?
+				ORDER BY crm.joined_at ASC
+				LIMIT 1
+			) AS dm_target_email,
 			msg.msg_usr_id AS sender_id,
 			COALESCE(u.usr_nama_lengkap, 'Pengguna') AS sender_name,
-			COALESCE(sender_role.role_name, 'member') AS sender_role,
-			msg.msg_content AS content,
 			msg.msg_type AS message_type,
+			ts_headline(
+				'simple',
+				msg.msg_content,
+				query,
+				?
+			) AS snippet,
+			(
+				SELECT COUNT(*)
+				FROM edv.chat_attachments ca
+				WHERE ca.cat_msg_id = msg.msg_id
+			)::int AS attachment_count,
+			msg.msg_reply_to AS reply_to,
 			msg.created_at AS created_at
-		FROM edv.chat_messages msg
-		JOIN edv.users u ON u.usr_id = msg.msg_usr_id AND u.deleted_at IS NULL
-		LEFT JOIN LATERAL (
-			SELECT r.rol_name AS role_name
-			FROM edv.school_users scu
-			JOIN edv.user_roles ur ON ur.urol_scu_id = scu.scu_id
-			JOIN edv.roles r ON r.rol_id = ur.urol_rol_id
-			JOIN edv.chat_rooms cr_role ON cr_role.room_id = msg.msg_room_id
-			WHERE scu.scu_usr_id = msg.msg_usr_id
-				AND scu.scu_sch_id = cr_role.room_sch_id
-				AND scu.deleted_at IS NULL
-				AND r.rol_name IN ('admin', 'teacher', 'student')
-			ORDER BY CASE r.rol_name WHEN 'admin' THEN 1 WHEN 'teacher' THEN 2 WHEN 'student' THEN 3 ELSE 4 END
-			LIMIT 1
-		) sender_role ON true
-	WHERE msg.msg_id = ?
+		`+from+`
+		ORDER BY ts_rank(msg.msg_search, query) DESC, msg.created_at DESC
+		LIMIT ? OFFSET ?
+	`, selectArgs...).Scan(&rows).Error
+	return rows, total, err
+}
+
+func (r *chatRepository) CreateMessageWithAttachments(message *domain.ChatMessage, mediaIDs []string, mentionUserIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createMessageWithAttachments(tx, message, mediaIDs, mentionUserIDs)
+	})
+}
+
+// CreateScheduledMessage posts message for scheduled and marks scheduled sent
+// in the same transaction, so a crash between the two cannot leave a posted
+// message that the dispatcher would post again. The update only matches while
+// scheduled is still in the sending state it was claimed with; if another
+// dispatcher has reclaimed it since, nothing is posted and
+// ErrChatScheduledMessageReclaimed is returned.
+func (r *chatRepository) CreateScheduledMessage(scheduled *domain.ChatScheduledMessage, message *domain.ChatMessage, mediaIDs []string, mentionUserIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createMessageWithAttachments(tx, message, mediaIDs, mentionUserIDs); err != nil {
+			return err
+		}
+		result := tx.Exec(`
+			UPDATE edv.chat_scheduled_messages
+			SET csm_status = ?,
+				csm_msg_id = ?,
+				csm_sent_at = ?,
+				csm_error = NULL,
+				updated_at = now()
+			WHERE csm_id = ?
+				AND csm_status = ?
+				AND updated_at = ?
+		`, domain.ChatScheduledSent, message.ID, message.CreatedAt, scheduled.ID, domain.ChatScheduledSending, scheduled.UpdatedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrChatScheduledMessageReclaimed
+		}
+		return nil
+	})
+}
+
+func createMessageWithAttachments(tx *gorm.DB, message *domain.ChatMessage, mediaIDs []string, mentionUserIDs []string) error {
+	if err := tx.Create(message).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, mediaID := range mediaIDs {
+		attachment := domain.ChatAttachment{
+			MessageID: message.ID,
+			MediaID:   mediaID,
+			CreatedAt: now,
+		}
+		if err := tx.Create(&attachment).Error; err != nil {
+			return err
+		}
+	}
+	for _, mentionUserID := range mentionUserIDs {
+		mention := domain.ChatMessageMention{
+			MessageID: message.ID,
+			UserID:    mentionUserID,
+			CreatedAt: now,
+		}
+		if err := tx.Create(&mention).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *chatRepository) GetMessageByID(messageID string, roomID string) (*ChatMessageRow, error) {
+	var row ChatMessageRow
+	err := r.db.Raw(chatMessageRowSelect()+`
+		WHERE msg.msg_id = ?
 			AND msg.msg_room_id = ?
 			AND msg.msg_type IN ('text', 'file')
 			AND msg.deleted_at IS NULL
@@ -924,6 +1353,249 @@ func (r *chatRepository) GetMessageByID(messageID string, roomID string) (*ChatM
 	return &row, nil
 }
 
+func (r *chatRepository) UpdateMessageContent(messageID string, roomID string, editorID string, content string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var message domain.ChatMessage
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("msg_id = ? AND msg_room_id = ?", messageID, roomID).
+			First(&message).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		edit := domain.ChatMessageEdit{
+			MessageID:       messageID,
+			EditedBy:        editorID,
+			PreviousContent: message.Content,
+			CreatedAt:       now,
+		}
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			UPDATE edv.chat_messages
+			SET msg_content = ?, edited_at = ?
+			WHERE msg_id = ?
+				AND deleted_at IS NULL
+		`, content, now, messageID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *chatRepository) SoftDeleteMessage(messageID string, roomID string, deletedBy string) error {
+	result := r.db.Exec(`
+		UPDATE edv.chat_messages
+		SET deleted_at = ?, deleted_by = ?
+		WHERE msg_id = ?
+			AND msg_room_id = ?
+			AND deleted_at IS NULL
+	`, time.Now(), deletedBy, messageID, roomID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *chatRepository) UserIsSchoolAdmin(userID string, schoolID string) (bool, error) {
+	var count int64
+	err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM edv.school_users scu
+		JOIN edv.users u ON u.usr_id = scu.scu_usr_id AND u.deleted_at IS NULL
+		JOIN edv.user_roles ur ON ur.urol_scu_id = scu.scu_id
+		JOIN edv.roles r ON r.rol_id = ur.urol_rol_id
+		WHERE scu.scu_usr_id = ?
+			AND scu.scu_sch_id = ?
+			AND scu.deleted_at IS NULL
+			AND r.rol_name = 'admin'
+	`, userID, schoolID).Scan(&count).Error
+	return count > 0, err
+}
+
+func (r *chatRepository) AddReaction(messageID string, userID string, emoji string) error {
+	reaction := domain.ChatMessageReaction{
+		MessageID: messageID,
+		UserID:    userID,
+		Emoji:     emoji,
+		CreatedAt: time.Now(),
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cmr_msg_id"}, {Name: "cmr_usr_id"}, {Name: "cmr_emoji"}},
+		DoNothing: true,
+	}).Create(&reaction).Error
+}
+
+func (r *chatRepository) RemoveReaction(messageID string, userID string, emoji string) error {
+	result := r.db.Where("cmr_msg_id = ? AND cmr_usr_id = ? AND cmr_emoji = ?", messageID, userID, emoji).
+		Delete(&domain.ChatMessageReaction{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *chatRepository) CountReaction(messageID string, emoji string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.ChatMessageReaction{}).
+		Where("cmr_msg_id = ? AND cmr_emoji = ?", messageID, emoji).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *chatRepository) ListMessageReactions(messageIDs []string, userID string) (map[string][]ChatReactionRow, error) {
+	result := make(map[string][]ChatReactionRow, len(messageIDs))
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []ChatReactionRow
+	err := r.db.Raw(`
+		SELECT
+			cmr.cmr_msg_id AS message_id,
+			cmr.cmr_emoji AS emoji,
+			COUNT(*)::int AS count,
+			BOOL_OR(cmr.cmr_usr_id = ?) AS reacted_by_me
+		FROM edv.chat_message_reactions cmr
+		WHERE cmr.cmr_msg_id IN ?
+		GROUP BY cmr.cmr_msg_id, cmr.cmr_emoji
+		ORDER BY MIN(cmr.created_at) ASC
+	`, userID, messageIDs).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.MessageID] = append(result[row.MessageID], row)
+	}
+	return result, nil
+}
+
+// PinMessage reports false when the message was already pinned.
+// PinMessage pins a message unless the room already has limit pins. The room
+// row stays locked while the pins are counted and inserted, so concurrent pins
+// cannot pass the limit together. It reports false when the message was
+// already pinned.
+func (r *chatRepository) PinMessage(pin *domain.ChatPinnedMessage, limit int64) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var lockedRoomID string
+		if err := tx.Raw(`
+			SELECT room_id
+			FROM edv.chat_rooms
+			WHERE room_id = ?
+			FOR UPDATE
+		`, pin.RoomID).Scan(&lockedRoomID).Error; err != nil {
+			return err
+		}
+		if lockedRoomID == "" {
+			return gorm.ErrRecordNotFound
+		}
+
+		count, err := countPinnedMessages(tx, pin.RoomID)
+		if err != nil {
+			return err
+		}
+		if count >= limit {
+			return fmt.Errorf("chat pinned messages exceed %d", limit)
+		}
+
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "cpm_msg_id"}},
+			DoNothing: true,
+		}).Create(pin)
+		if result.Error != nil {
+			return result.Error
+		}
+		created = result.RowsAffected > 0
+		return nil
+	})
+	return created, err
+}
+
+func (r *chatRepository) UnpinMessage(roomID string, messageID string) error {
+	result := r.db.Where("cpm_room_id = ? AND cpm_msg_id = ?", roomID, messageID).Delete(&domain.ChatPinnedMessage{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// countPinnedMessages ignores pins of deleted messages.
+func countPinnedMessages(tx *gorm.DB, roomID string) (int64, error) {
+	var count int64
+	err := tx.Raw(`
+		SELECT COUNT(*)
+		FROM edv.chat_pinned_messages cpm
+		JOIN edv.chat_messages msg
+			ON msg.msg_id = cpm.cpm_msg_id
+			AND msg.deleted_at IS NULL
+		WHERE cpm.cpm_room_id = ?
+	`, roomID).Scan(&count).Error
+	return count, err
+}
+
+func (r *chatRepository) GetPin(roomID string, messageID string) (*domain.ChatPinnedMessage, error) {
+	var pin domain.ChatPinnedMessage
+	err := r.db.Where("cpm_room_id = ? AND cpm_msg_id = ?", roomID, messageID).First(&pin).Error
+	return &pin, err
+}
+
+// ListPinnedMessages returns the room's pinned messages, most recently pinned
+// first. Pins of deleted messages are skipped.
+func (r *chatRepository) ListPinnedMessages(roomID string) ([]ChatPinnedMessageRow, error) {
+	var rows []ChatPinnedMessageRow
+	err := r.db.Raw(`
+		SELECT
+			msg_rows.*,
+			cpm.cpm_pinned_by AS pinned_by,
+			COALESCE(pinner.usr_nama_lengkap, 'Pengguna') AS pinned_by_name,
+			cpm.created_at AS pinned_at
+		FROM edv.chat_pinned_messages cpm
+		JOIN (`+chatMessageRowSelect()+`
+			WHERE msg.msg_room_id = ?
+				AND msg.msg_type IN ('text', 'file')
+				AND msg.deleted_at IS NULL
+		) msg_rows ON msg_rows.message_id = cpm.cpm_msg_id
+		LEFT JOIN edv.users pinner ON pinner.usr_id = cpm.cpm_pinned_by
+		WHERE cpm.cpm_room_id = ?
+		ORDER BY cpm.created_at DESC
+	`, roomID, roomID).Scan(&rows).Error
+	return rows, err
+}
+
+func (r *chatRepository) ListPinnedMessageIDs(messageIDs []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(messageIDs))
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+	var pinnedIDs []string
+	err := r.db.Model(&domain.ChatPinnedMessage{}).
+		Where("cpm_msg_id IN ?", messageIDs).
+		Pluck("cpm_msg_id", &pinnedIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, messageID := range pinnedIDs {
+		result[messageID] = true
+	}
+	return result, nil
+}
+
 func (r *chatRepository) ListMessageAttachments(messageIDs []string) (map[string][]ChatAttachmentRow, error) {
 	result := make(map[string][]ChatAttachmentRow, len(messageIDs))
 	if len(messageIDs) == 0 {
@@ -1105,7 +1777,54 @@ func (r *chatRepository) ListRoomRecipientUserIDs(roomID string, schoolID string
 	return userIDs, nil
 }
 
+// ListRoomPeerUserIDs returns the other active members of every room userID
+// belongs to in the school. The school room is left out: everyone shares it.
+func (r *chatRepository) ListRoomPeerUserIDs(userID string, schoolID string) ([]string, error) {
+	var rows []struct {
+		UserID string `gorm:"column:user_id"`
+	}
+	err := r.db.Raw(`
+		SELECT DISTINCT u.usr_id AS user_id
+		FROM edv.chat_room_members self
+		JOIN edv.chat_rooms cr
+			ON cr.room_id = self.crm_room_id
+			AND cr.room_sch_id = ?
+			AND cr.deleted_at IS NULL
+			AND cr.room_ref_type IS DISTINCT FROM 'school'
+		JOIN edv.chat_room_members crm
+			ON crm.crm_room_id = cr.room_id
+			AND crm.crm_usr_id <> self.crm_usr_id
+			AND crm.left_at IS NULL
+		JOIN edv.school_users scu
+			ON scu.scu_usr_id = crm.crm_usr_id
+			AND scu.scu_sch_id = cr.room_sch_id
+			AND scu.deleted_at IS NULL
+		JOIN edv.users u
+			ON u.usr_id = crm.crm_usr_id
+			AND u.deleted_at IS NULL
+		WHERE self.crm_usr_id = ?
+			AND self.left_at IS NULL
+	`, schoolID, userID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		userIDs = append(userIDs, row.UserID)
+	}
+	return userIDs, nil
+}
+
 func (r *chatRepository) UnreadCount(roomID string, userID string) (int64, error) {
+	return r.countUnread(roomID, userID, false)
+}
+
+// UnreadMentionCount counts unread messages in the room that mention the user.
+func (r *chatRepository) UnreadMentionCount(roomID string, userID string) (int64, error) {
+	return r.countUnread(roomID, userID, true)
+}
+
+func (r *chatRepository) countUnread(roomID string, userID string, mentionsOnly bool) (int64, error) {
 	var count int64
 	err := r.db.Raw(`
 		SELECT COUNT(*)
@@ -1137,10 +1856,287 @@ func (r *chatRepository) UnreadCount(roomID string, userID string) (int64, error
 					ELSE rct.last_read_at
 				END
 			)
-	`, userID, roomID, userID).Scan(&count).Error
+			AND (
+				? = false
+				OR EXISTS (
+					SELECT 1
+					FROM edv.chat_message_mentions cmn
+					WHERE cmn.cmn_msg_id = msg.msg_id
+						AND cmn.cmn_usr_id = ?
+				)
+			)
+	`, userID, roomID, userID, mentionsOnly, userID).Scan(&count).Error
 	return count, err
 }
 
+func (r *chatRepository) GetRoomSetting(roomID string, userID string) (*domain.ChatRoomSetting, error) {
+	var setting domain.ChatRoomSetting
+	err := r.db.Where("crs_room_id = ? AND crs_usr_id = ?", roomID, userID).First(&setting).Error
+	return &setting, err
+}
+
+func (r *chatRepository) UpsertRoomSetting(setting *domain.ChatRoomSetting) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "crs_room_id"}, {Name: "crs_usr_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"crs_notify_level", "crs_muted_until", "updated_at"}),
+	}).Create(setting).Error
+}
+
+// ListRoomSettings maps room IDs in the school to the user's saved settings.
+func (r *chatRepository) ListRoomSettings(userID string, schoolID string) (map[string]domain.ChatRoomSetting, error) {
+	var rows []domain.ChatRoomSetting
+	err := r.db.Raw(`
+		SELECT crs.*
+		FROM edv.chat_room_settings crs
+		JOIN edv.chat_rooms cr
+			ON cr.room_id = crs.crs_room_id
+			AND cr.room_sch_id = ?
+		WHERE crs.crs_usr_id = ?
+	`, schoolID, userID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]domain.ChatRoomSetting, len(rows))
+	for _, row := range rows {
+		result[row.RoomID] = row
+	}
+	return result, nil
+}
+
+// ListMentionTargets returns the school roles and room settings of mentioned
+// users. Callers must already have filtered userIDs to the room's recipients.
+func (r *chatRepository) ListMentionTargets(roomID string, schoolID string, userIDs []string) ([]ChatMentionTargetRow, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var rows []ChatMentionTargetRow
+	err := r.db.Raw(`
+		SELECT
+			u.usr_id AS user_id,
+			COALESCE(string_agg(DISTINCT rol.rol_name, ',' ORDER BY rol.rol_name), '') AS roles,
+			crs.crs_notify_level AS notify_level,
+			crs.crs_muted_until AS muted_until
+		FROM edv.users u
+		JOIN edv.school_users scu
+			ON scu.scu_usr_id = u.usr_id
+			AND scu.scu_sch_id = ?
+			AND scu.deleted_at IS NULL
+		LEFT JOIN edv.user_roles ur ON ur.urol_scu_id = scu.scu_id
+		LEFT JOIN edv.roles rol ON rol.rol_id = ur.urol_rol_id
+		LEFT JOIN edv.chat_room_settings crs
+			ON crs.crs_room_id = ?
+			AND crs.crs_usr_id = u.usr_id
+		WHERE u.usr_id IN ?
+			AND u.deleted_at IS NULL
+		GROUP BY u.usr_id, crs.crs_notify_level, crs.crs_muted_until
+	`, schoolID, roomID, userIDs).Scan(&rows).Error
+	return rows, err
+}
+
+// ListContentCards resolves the preview of shared materials, assignments and
+// feed posts in the school. Deleted content and content of deleted classes is
+// left out. Drafts and scheduled items are included for their teachers;
+// ListContentViewers keeps them from students.
+func (r *chatRepository) ListContentCards(schoolID string, refs []ChatContentRef) ([]ChatContentCardRow, error) {
+	idsByType := chatContentRefIDs(refs)
+	var rows []ChatContentCardRow
+	if ids := idsByType[string(domain.SourceMaterial)]; len(ids) > 0 {
+		var materials []ChatContentCardRow
+		if err := r.db.Raw(`
+			SELECT
+				'material' AS ref_type,
+				mat.mat_id AS ref_id,
+				mat.mat_title AS title,
+				c.cls_id AS class_id,
+				c.cls_title AS class_name,
+				sc.scl_id AS subject_class_id,
+				sub.sub_name AS subject_name,
+				NULL::timestamptz AS deadline
+			FROM edv.materials mat
+			JOIN edv.subject_classes sc ON sc.scl_id = mat.mat_scl_id
+			JOIN edv.subjects sub ON sub.sub_id = sc.scl_sub_id
+			JOIN edv.classes c ON c.cls_id = sc.scl_cls_id AND c.deleted_at IS NULL
+			WHERE mat.mat_id IN ?
+				AND mat.mat_sch_id = ?
+				AND mat.deleted_at IS NULL
+		`, ids, schoolID).Scan(&materials).Error; err != nil {
+			return nil, err
+		}
+		rows = append(rows, materials...)
+	}
+	if ids := idsByType[string(domain.SourceAssignment)]; len(ids) > 0 {
+		var assignments []ChatContentCardRow
+		if err := r.db.Raw(`
+			SELECT
+				'assignment' AS ref_type,
+				asg.asg_id AS ref_id,
+				asg.asg_title AS title,
+				c.cls_id AS class_id,
+				c.cls_title AS class_name,
+				sc.scl_id AS subject_class_id,
+				sub.sub_name AS subject_name,
+				asg.asg_deadline AS deadline
+			FROM edv.assignments asg
+			JOIN edv.subject_classes sc ON sc.scl_id = asg.asg_scl_id
+			JOIN edv.subjects sub ON sub.sub_id = sc.scl_sub_id
+			JOIN edv.classes c ON c.cls_id = sc.scl_cls_id AND c.deleted_at IS NULL
+			WHERE asg.asg_id IN ?
+				AND asg.asg_sch_id = ?
+				AND asg.deleted_at IS NULL
+		`, ids, schoolID).Scan(&assignments).Error; err != nil {
+			return nil, err
+		}
+		rows = append(rows, assignments...)
+	}
+	if ids := idsByType[string(domain.SourceFeed)]; len(ids) > 0 {
+		var feeds []ChatContentCardRow
+		if err := r.db.Raw(`
+			SELECT
+				'feed' AS ref_type,
+				fds.fds_id AS ref_id,
+				fds.fds_content AS title,
+				c.cls_id AS class_id,
+				c.cls_title AS class_name
+			FROM edv.feeds fds
+			JOIN edv.classes c ON c.cls_id = fds.fds_cls_id AND c.deleted_at IS NULL
+			WHERE fds.fds_id IN ?
+				AND fds.fds_sch_id = ?
+				AND fds.deleted_at IS NULL
+		`, ids, schoolID).Scan(&feeds).Error; err != nil {
+			return nil, err
+		}
+		rows = append(rows, feeds...)
+	}
+	return rows, nil
+}
+
+// ListContentViewers returns which of userIDs can open each shared content
+// item, following the same rules as comments on that content: school admins
+// see everything in the school, teachers see the subject classes they teach
+// (or, for feed posts, the classes they teach in) and students see the classes
+// they are enrolled in. Students only see published materials and assignments.
+func (r *chatRepository) ListContentViewers(schoolID string, refs []ChatContentRef, userIDs []string) ([]ChatContentViewerRow, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	idsByType := chatContentRefIDs(refs)
+	var rows []ChatContentViewerRow
+	subjectClassSources := []struct {
+		refType string
+		query   string
+	}{
+		{string(domain.SourceMaterial), `SELECT mat_id AS ref_id, mat_scl_id AS scl_id, mat_publish_status = 'published' AS published FROM edv.materials WHERE mat_id IN ? AND mat_sch_id = ? AND deleted_at IS NULL`},
+		{string(domain.SourceAssignment), `SELECT asg_id AS ref_id, asg_scl_id AS scl_id, asg_publish_status = 'published' AS published FROM edv.assignments WHERE asg_id IN ? AND asg_sch_id = ? AND deleted_at IS NULL`},
+	}
+	for _, source := range subjectClassSources {
+		ids := idsByType[source.refType]
+		if len(ids) == 0 {
+			continue
+		}
+		var viewers []ChatContentViewerRow
+		if err := r.db.Raw(`
+			SELECT
+				'`+source.refType+`' AS ref_type,
+				content.ref_id,
+				scu.scu_usr_id AS user_id,
+				CASE
+					WHEN `+chatContentAdminExists+` THEN 'admin'
+					WHEN sc.scl_scu_id = scu.scu_id AND `+chatContentEnrolledExists("sc.scl_cls_id", "teacher")+` THEN 'teacher'
+					ELSE 'student'
+				END AS audience
+			FROM (`+source.query+`) content
+			JOIN edv.subject_classes sc ON sc.scl_id = content.scl_id
+			JOIN edv.school_users scu
+				ON scu.scu_sch_id = ?
+				AND scu.scu_usr_id IN ?
+				AND scu.deleted_at IS NULL
+			WHERE `+chatContentAdminExists+`
+				OR (sc.scl_scu_id = scu.scu_id AND `+chatContentEnrolledExists("sc.scl_cls_id", "teacher")+`)
+				OR (content.published AND `+chatContentEnrolledExists("sc.scl_cls_id", "student")+`)
+		`, ids, schoolID, schoolID, userIDs).Scan(&viewers).Error; err != nil {
+			return nil, err
+		}
+		rows = append(rows, viewers...)
+	}
+
+	if ids := idsByType[string(domain.SourceFeed)]; len(ids) > 0 {
+		teachesClass := `EXISTS (
+			SELECT 1
+			FROM edv.subject_classes teach
+			WHERE teach.scl_cls_id = fds.fds_cls_id
+				AND teach.scl_scu_id = scu.scu_id
+				AND ` + chatContentEnrolledExists("fds.fds_cls_id", "teacher") + `
+		)`
+		var viewers []ChatContentViewerRow
+		if err := r.db.Raw(`
+			SELECT
+				'feed' AS ref_type,
+				fds.fds_id AS ref_id,
+				scu.scu_usr_id AS user_id,
+				CASE
+					WHEN `+chatContentAdminExists+` THEN 'admin'
+					WHEN `+teachesClass+` THEN 'teacher'
+					ELSE 'student'
+				END AS audience
+			FROM edv.feeds fds
+			JOIN edv.school_users scu
+				ON scu.scu_sch_id = fds.fds_sch_id
+				AND scu.scu_usr_id IN ?
+				AND scu.deleted_at IS NULL
+			WHERE fds.fds_id IN ?
+				AND fds.fds_sch_id = ?
+				AND fds.deleted_at IS NULL
+				AND (
+					`+chatContentAdminExists+`
+					OR `+teachesClass+`
+					OR `+chatContentEnrolledExists("fds.fds_cls_id", "student")+`
+				)
+		`, userIDs, ids, schoolID).Scan(&viewers).Error; err != nil {
+			return nil, err
+		}
+		rows = append(rows, viewers...)
+	}
+	return rows, nil
+}
+
+// chatContentAdminExists matches school users aliased scu holding the admin
+// role.
+const chatContentAdminExists = `EXISTS (
+	SELECT 1
+	FROM edv.user_roles ur
+	JOIN edv.roles rol ON rol.rol_id = ur.urol_rol_id
+	WHERE ur.urol_scu_id = scu.scu_id
+		AND rol.rol_name = 'admin'
+)`
+
+// chatContentEnrolledExists matches school users aliased scu with an active
+// enrollment in classColumn under role. role is a fixed literal, never user
+// input.
+func chatContentEnrolledExists(classColumn string, role string) string {
+	return `EXISTS (
+		SELECT 1
+		FROM edv.enrollments enr
+		JOIN edv.classes enr_cls ON enr_cls.cls_id = enr.enr_cls_id AND enr_cls.deleted_at IS NULL
+		WHERE enr.enr_cls_id = ` + classColumn + `
+			AND enr.enr_scu_id = scu.scu_id
+			AND enr.enr_role = '` + role + `'
+			AND enr.left_at IS NULL
+	)`
+}
+
+func chatContentRefIDs(refs []ChatContentRef) map[string][]string {
+	idsByType := make(map[string][]string)
+	seen := make(map[ChatContentRef]bool, len(refs))
+	for _, ref := range refs {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		idsByType[ref.Type] = append(idsByType[ref.Type], ref.ID)
+	}
+	return idsByType
+}
+
 func chatRoomListSelect() string {
 	return chatRoomContextSelect() + `
 		LEFT JOIN LATERAL (
@@ -1149,6 +2145,7 @@ func chatRoomListSelect() string {
 				msg.msg_usr_id,
 				msg.msg_content,
 				msg.msg_type,
+				msg.msg_ref_type,
 				msg.created_at,
 				COUNT(ca.cat_id)::int AS attachment_count,
 				MIN(m.med_mime_type) AS attachment_mime_type,
@@ -1161,7 +2158,7 @@ func chatRoomListSelect() string {
 			WHERE msg.msg_room_id = cr.room_id
 				AND msg.msg_type IN ('text', 'file')
 				AND msg.deleted_at IS NULL
-			GROUP BY msg.msg_id, msg.msg_usr_id, msg.msg_content, msg.msg_type, msg.created_at
+			GROUP BY msg.msg_id, msg.msg_usr_id, msg.msg_content, msg.msg_type, msg.msg_ref_type, msg.created_at
 			ORDER BY msg.created_at DESC
 			LIMIT 1
 		) lm ON true
@@ -1184,13 +2181,15 @@ func chatRoomContextSelect() string {
 			last_sender.usr_nama_lengkap AS last_sender_name,
 			lm.msg_content AS last_content,
 			lm.msg_type AS last_type,
+			lm.msg_ref_type::text AS last_ref_type,
 			COALESCE(lm.attachment_count, 0) AS last_attachment_count,
 			lm.attachment_mime_type AS last_attachment_mime_type,
 			lm.attachment_file_name AS last_attachment_file_name,
 			lm.created_at AS last_message_at,
 			dm_target.usr_id AS dm_target_user_id,
 			dm_target.usr_nama_lengkap AS dm_target_name,
-			dm_target.usr_email AS dm_target_email
+			dm_target.usr_email AS dm_target_email,
+			COALESCE(cr.room_posting_mode, 'everyone') AS posting_mode
 		FROM edv.chat_rooms cr
 		JOIN edv.schools s ON s.sch_id = cr.room_sch_id
 		LEFT JOIN LATERAL (
@@ -1207,3 +2206,108 @@ func chatRoomContextSelect() string {
 		) dm_target ON cr.room_type = 'dm'
 	`
 }
+
+// classRoomDesiredMembers selects the expected (room_id, usr_id, enr_id, role)
+// rows of a class's chat rooms. It takes the class ID three times.
+func classRoomDesiredMembers() string {
+	return `
+		SELECT
+			cr.room_id,
+			scu.scu_usr_id AS usr_id,
+			enr.enr_id,
+			CASE WHEN enr.enr_role = 'teacher' THEN 'admin' ELSE 'member' END AS role
+		FROM edv.enrollments enr
+		JOIN edv.school_users scu
+			ON scu.scu_id = enr.enr_scu_id
+			AND scu.deleted_at IS NULL
+		JOIN edv.chat_rooms cr
+			ON cr.room_sch_id = enr.enr_sch_id
+			AND cr.room_ref_type = 'class'
+			AND cr.room_ref_id = enr.enr_cls_id
+			AND cr.deleted_at IS NULL
+		WHERE enr.enr_cls_id = ?
+			AND enr.left_at IS NULL
+		UNION
+		SELECT
+			cr.room_id,
+			scu.scu_usr_id AS usr_id,
+			enr.enr_id,
+			CASE WHEN scl.scl_scu_id = enr.enr_scu_id THEN 'admin' ELSE 'member' END AS role
+		FROM edv.subject_classes scl
+		JOIN edv.enrollments enr
+			ON enr.enr_cls_id = scl.scl_cls_id
+			AND enr.left_at IS NULL
+		JOIN edv.school_users scu
+			ON scu.scu_id = enr.enr_scu_id
+			AND scu.deleted_at IS NULL
+		JOIN edv.chat_rooms cr
+			ON cr.room_ref_type = 'subject'
+			AND cr.room_ref_id = scl.scl_id
+			AND cr.deleted_at IS NULL
+		WHERE scl.scl_cls_id = ?
+		UNION
+		SELECT
+			cr.room_id,
+			scu.scu_usr_id AS usr_id,
+			NULL::uuid AS enr_id,
+			'admin' AS role
+		FROM edv.subject_classes scl
+		JOIN edv.school_users scu
+			ON scu.scu_id = scl.scl_scu_id
+			AND scu.deleted_at IS NULL
+		JOIN edv.chat_rooms cr
+			ON cr.room_ref_type = 'subject'
+			AND cr.room_ref_id = scl.scl_id
+			AND cr.deleted_at IS NULL
+		WHERE scl.scl_cls_id = ?
+			AND NOT EXISTS (
+				SELECT 1
+				FROM edv.enrollments enr
+				WHERE enr.enr_cls_id = scl.scl_cls_id
+					AND enr.enr_scu_id = scl.scl_scu_id
+					AND enr.left_at IS NULL
+			)
+	`
+}
+
+// chatMessageRowSelect selects ChatMessageRow columns for messages aliased as
+// msg; callers append the WHERE clause.
+func chatMessageRowSelect() string {
+	return `
+		SELECT
+			msg.msg_id AS message_id,
+			msg.msg_room_id AS room_id,
+			msg.msg_usr_id AS sender_id,
+			COALESCE(u.usr_nama_lengkap, 'Pengguna') AS sender_name,
+			COALESCE(sender_role.role_name, 'member') AS sender_role,
+			msg.msg_content AS content,
+			msg.msg_type AS message_type,
+			msg.created_at AS created_at,
+			msg.edited_at AS edited_at,
+			msg.msg_reply_to AS reply_to,
+			msg.msg_ref_type::text AS ref_type,
+			msg.msg_ref_id AS ref_id,
+			(
+				SELECT COUNT(*)
+				FROM edv.chat_messages reply
+				WHERE reply.msg_reply_to = msg.msg_id
+					AND reply.msg_type IN ('text', 'file')
+					AND reply.deleted_at IS NULL
+			)::int AS reply_count
+		FROM edv.chat_messages msg
+		JOIN edv.users u ON u.usr_id = msg.msg_usr_id AND u.deleted_at IS NULL
+		LEFT JOIN LATERAL (
+			SELECT r.rol_name AS role_name
+			FROM edv.school_users scu
+			JOIN edv.user_roles ur ON ur.urol_scu_id = scu.scu_id
+			JOIN edv.roles r ON r.rol_id = ur.urol_rol_id
+			JOIN edv.chat_rooms cr_role ON cr_role.room_id = msg.msg_room_id
+			WHERE scu.scu_usr_id = msg.msg_usr_id
+				AND scu.scu_sch_id = cr_role.room_sch_id
+				AND scu.deleted_at IS NULL
+				AND r.rol_name IN ('admin', 'teacher', 'student')
+			ORDER BY CASE r.rol_name WHEN 'admin' THEN 1 WHEN 'teacher' THEN 2 WHEN 'student' THEN 3 ELSE 4 END
+			LIMIT 1
+		) sender_role ON true
+	`
+}
@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// recordingConn stands in for the database: it records every statement run
+// through it, whether it ran inside a transaction, and answers queries with
+// the rows of the first matching entry in results.
+type recordingConn struct {
+	statements []recordedStatement
+	results    []recordedResult
+	inTx       bool
+	committed  bool
+}
+
+type recordedStatement struct {
+	sql  string
+	args []interface{}
+	inTx bool
+}
+
+// recordedResult answers queries containing match with one column of values.
+type recordedResult struct {
+	match  string
+	column string
+	values []driver.Value
+}
+
+type recordingConnector struct {
+	conn *recordingConn
+}
+
+func (c recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func (c recordingConnector) Driver() driver.Driver {
+	return recordingDriver{}
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("open through recordingConnector")
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.inTx = true
+	return c, nil
+}
+
+func (c *recordingConn) Commit() error {
+	c.inTx = false
+	c.committed = true
+	return nil
+}
+
+func (c *recordingConn) Rollback() error {
+	c.inTx = false
+	return nil
+}
+
+func (c *recordingConn) record(query string, args []driver.NamedValue) {
+	values := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	c.statements = append(c.statements, recordedStatement{sql: query, args: values, inTx: c.inTx})
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.record(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.record(query, args)
+	for _, result := range c.results {
+		if strings.Contains(compactSQL(query), result.match) {
+			return &recordingRows{column: result.column, values: result.values}, nil
+		}
+	}
+	return &recordingRows{column: "result"}, nil
+}
+
+type recordingRows struct {
+	column string
+	values []driver.Value
+}
+
+func (r *recordingRows) Columns() []string {
+	return []string{r.column}
+}
+
+func (r *recordingRows) Close() error {
+	return nil
+}
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0] = r.values[0]
+	r.values = r.values[1:]
+	return nil
+}
+
+func newRecordingDB(t *testing.T, results ...recordedResult) (*gorm.DB, *recordingConn) {
+	t.Helper()
+	conn := &recordingConn{results: results}
+	sqlDB := sql.OpenDB(recordingConnector{conn: conn})
+	sqlDB.SetMaxOpenConns(1)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("open recording db: %v", err)
+	}
+	return db, conn
+}
+
+var sqlSpace = regexp.MustCompile(`\s+`)
+
+func compactSQL(query string) string {
+	return strings.TrimSpace(sqlSpace.ReplaceAllString(query, " "))
+}
+
+func TestClassRoomDesiredMembersRoles(t *testing.T) {
+	parts := strings.Split(compactSQL(classRoomDesiredMembers()), " UNION ")
+	if len(parts) != 3 {
+		t.Fatalf("expected class, subject and subject teacher members, got %d parts", len(parts))
+	}
+	classMembers, subjectMembers, subjectTeachers := parts[0], parts[1], parts[2]
+
+	if !strings.Contains(classMembers, "CASE WHEN enr.enr_role = 'teacher' THEN 'admin' ELSE 'member' END AS role") ||
+		!strings.Contains(classMembers, "cr.room_ref_type = 'class'") {
+		t.Fatalf("expected class room teachers to be admins and students members: %s", classMembers)
+	}
+	if !strings.Contains(subjectMembers, "CASE WHEN scl.scl_scu_id = enr.enr_scu_id THEN 'admin' ELSE 'member' END AS role") ||
+		!strings.Contains(subjectMembers, "cr.room_ref_type = 'subject'") {
+		t.Fatalf("expected only the assigned teacher to moderate the subject room: %s", subjectMembers)
+	}
+	if !strings.Contains(subjectTeachers, "'admin' AS role") ||
+		!strings.Contains(subjectTeachers, "scu.scu_id = scl.scl_scu_id") {
+		t.Fatalf("expected the assigned teacher to join the subject room without an enrollment: %s", subjectTeachers)
+	}
+	for name, part := range map[string]string{"class": classMembers, "subject": subjectMembers} {
+		if !strings.Contains(part, "enr.left_at IS NULL") {
+			t.Fatalf("expected %s members to exclude unenrolled users: %s", name, part)
+		}
+		if !strings.Contains(part, "scu.deleted_at IS NULL") {
+			t.Fatalf("expected %s members to exclude removed school users: %s", name, part)
+		}
+	}
+	if !strings.Contains(subjectTeachers, "NOT EXISTS") {
+		t.Fatalf("expected enrolled subject teachers to come from the enrollment rows: %s", subjectTeachers)
+	}
+	if got := strings.Count(classRoomDesiredMembers(), "?"); got != 3 {
+		t.Fatalf("expected 3 class ID placeholders, got %d", got)
+	}
+}
+
+func TestSyncClassRoomsAddsAndRemovesMembers(t *testing.T) {
+	db, conn := newRecordingDB(t)
+	repo := NewChatRepository(db)
+
+	if err := repo.SyncClassRooms("class-1"); err != nil {
+		t.Fatalf("SyncClassRooms() error = %v", err)
+	}
+	if !conn.committed {
+		t.Fatal("expected the sync to commit a transaction")
+	}
+	if len(conn.statements) < 2 {
+		t.Fatalf("expected a remove and an add statement, got %d statements", len(conn.statements))
+	}
+	for _, statement := range conn.statements {
+		if !statement.inTx {
+			t.Fatalf("expected every statement inside the transaction: %s", compactSQL(statement.sql))
+		}
+		for _, arg := range statement.args {
+			if arg != "class-1" {
+				t.Fatalf("expected only the class ID to be bound, got %v in %s", arg, compactSQL(statement.sql))
+			}
+		}
+	}
+
+	remove := compactSQL(conn.statements[len(conn.statements)-2].sql)
+	if !strings.HasPrefix(remove, "WITH desired AS") ||
+		!strings.Contains(remove, "SET left_at = NOW()") ||
+		!strings.Contains(remove, "AND NOT EXISTS ( SELECT 1 FROM desired d WHERE d.room_id = crm.crm_room_id AND d.usr_id = crm.crm_usr_id )") {
+		t.Fatalf("expected members missing from desired to leave: %s", remove)
+	}
+
+	add := compactSQL(conn.statements[len(conn.statements)-1].sql)
+	if !strings.HasPrefix(add, "WITH desired AS") ||
+		!strings.Contains(add, "INSERT INTO edv.chat_room_members") ||
+		!strings.Contains(add, "crm_role = EXCLUDED.crm_role") ||
+		!strings.Contains(add, "left_at = NULL") {
+		t.Fatalf("expected desired members to join or rejoin with their role: %s", add)
+	}
+	// A user can be desired as both admin and member of one room; "admin"
+	// sorts first and wins.
+	if !strings.Contains(add, "ORDER BY room_id, usr_id, role ASC") {
+		t.Fatalf("expected the admin role to win duplicates: %s", add)
+	}
+}
+
+func TestCloseSubjectClassRoom(t *testing.T) {
+	db, conn := newRecordingDB(t)
+	repo := NewChatRepository(db)
+
+	if err := repo.CloseSubjectClassRoom("subject-class-1"); err != nil {
+		t.Fatalf("CloseSubjectClassRoom() error = %v", err)
+	}
+	if !conn.committed || len(conn.statements) != 2 {
+		t.Fatalf("expected two statements in one transaction, got %d (committed %v)", len(conn.statements), conn.committed)
+	}
+	leave := compactSQL(conn.statements[0].sql)
+	if !strings.Contains(leave, "UPDATE edv.chat_room_members") || !strings.Contains(leave, "SET left_at = NOW()") {
+		t.Fatalf("expected every member to leave first: %s", leave)
+	}
+	remove := compactSQL(conn.statements[1].sql)
+	if !strings.Contains(remove, "UPDATE edv.chat_rooms SET deleted_at = NOW()") {
+		t.Fatalf("expected the room to be soft-deleted: %s", remove)
+	}
+	for _, statement := range conn.statements {
+		if len(statement.args) != 1 || statement.args[0] != "subject-class-1" {
+			t.Fatalf("expected the subject class ID to be bound, got %v", statement.args)
+		}
+	}
+}
+
+func TestPinMessageCountsUnderRoomLock(t *testing.T) {
+	db, conn := newRecordingDB(t,
+		recordedResult{match: "FROM edv.chat_rooms WHERE room_id = $1 FOR UPDATE", column: "room_id", values: []driver.Value{"room-1"}},
+		recordedResult{match: "SELECT COUNT(*)", column: "count", values: []driver.Value{int64(3)}},
+		recordedResult{match: "INSERT INTO", column: "cpm_id", values: []driver.Value{"pin-1"}},
+	)
+	repo := NewChatRepository(db)
+
+	created, err := repo.PinMessage(&domain.ChatPinnedMessage{RoomID: "room-1", MessageID: "message-1", PinnedBy: "user-1"}, 20)
+	if err != nil || !created {
+		t.Fatalf("expected the pin to be created, got created %v err %v", created, err)
+	}
+	if !conn.committed || len(conn.statements) != 3 {
+		t.Fatalf("expected lock, count and insert in one transaction, got %d statements (committed %v)", len(conn.statements), conn.committed)
+	}
+	steps := []string{"FOR UPDATE", "SELECT COUNT(*)", "INSERT INTO"}
+	for i, statement := range conn.statements {
+		if !statement.inTx || !strings.Contains(compactSQL(statement.sql), steps[i]) {
+			t.Fatalf("expected step %d to run %q in the transaction, got %s", i, steps[i], compactSQL(statement.sql))
+		}
+	}
+}
+
+func TestPinMessageRejectsPinsPastLimit(t *testing.T) {
+	db, conn := newRecordingDB(t,
+		recordedResult{match: "FROM edv.chat_rooms WHERE room_id = $1 FOR UPDATE", column: "room_id", values: []driver.Value{"room-1"}},
+		recordedResult{match: "SELECT COUNT(*)", column: "count", values: []driver.Value{int64(20)}},
+	)
+	repo := NewChatRepository(db)
+
+	created, err := repo.PinMessage(&domain.ChatPinnedMessage{RoomID: "room-1", MessageID: "message-1", PinnedBy: "user-1"}, 20)
+	if err == nil || err.Error() != "chat pinned messages exceed 20" || created {
+		t.Fatalf("expected the pin limit error, got created %v err %v", created, err)
+	}
+	if conn.committed {
+		t.Fatal("expected the transaction to roll back")
+	}
+	for _, statement := range conn.statements {
+		if strings.Contains(statement.sql, "INSERT INTO") {
+			t.Fatalf("expected no insert past the limit: %s", compactSQL(statement.sql))
+		}
+	}
+}
+
+func TestListRoomPeerUserIDsSkipsSchoolRoom(t *testing.T) {
+	db, conn := newRecordingDB(t,
+		recordedResult{match: "SELECT DISTINCT u.usr_id AS user_id", column: "user_id", values: []driver.Value{"user-2"}},
+	)
+	repo := NewChatRepository(db)
+
+	peers, err := repo.ListRoomPeerUserIDs("user-1", "school-1")
+	if err != nil || len(peers) != 1 || peers[0] != "user-2" {
+		t.Fatalf("expected user-2 as the only peer, got %v err %v", peers, err)
+	}
+	query := compactSQL(conn.statements[0].sql)
+	if !strings.Contains(query, "cr.room_ref_type IS DISTINCT FROM 'school'") ||
+		!strings.Contains(query, "crm.crm_usr_id <> self.crm_usr_id") ||
+		!strings.Contains(query, "self.left_at IS NULL") {
+		t.Fatalf("expected active peers outside the school room: %s", query)
+	}
+	if args := conn.statements[0].args; len(args) != 2 || args[0] != "school-1" || args[1] != "user-1" {
+		t.Fatalf("expected school and user to be bound, got %v", args)
+	}
+}
+
+func TestCreateScheduledMessageMarksSentInTheInsertTransaction(t *testing.T) {
+	db, conn := newRecordingDB(t,
+		recordedResult{match: "INSERT INTO", column: "msg_id", values: []driver.Value{"message-1"}},
+	)
+	repo := NewChatRepository(db)
+	claimedAt := time.Date(2026, 6, 26, 3, 0, 0, 0, time.UTC)
+	scheduled := &domain.ChatScheduledMessage{ID: "scheduled-1", UpdatedAt: claimedAt}
+
+	err := repo.CreateScheduledMessage(scheduled, &domain.ChatMessage{RoomID: "room-1", UserID: "user-1", Content: "hi", Type: "text"}, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateScheduledMessage() error = %v", err)
+	}
+	if !conn.committed || len(conn.statements) != 2 {
+		t.Fatalf("expected insert and mark sent in one transaction, got %d statements (committed %v)", len(conn.statements), conn.committed)
+	}
+	for _, statement := range conn.statements {
+		if !statement.inTx {
+			t.Fatalf("expected every statement inside the transaction: %s", compactSQL(statement.sql))
+		}
+	}
+	update := conn.statements[1]
+	if query := compactSQL(update.sql); !strings.Contains(query, "UPDATE edv.chat_scheduled_messages") ||
+		!strings.Contains(query, "AND csm_status = $5 AND updated_at = $6") {
+		t.Fatalf("expected the sent update to be guarded by the claim: %s", query)
+	}
+	args := update.args
+	if args[0] != domain.ChatScheduledSent || args[1] != "message-1" || args[3] != "scheduled-1" ||
+		args[4] != domain.ChatScheduledSending || args[5] != claimedAt {
+		t.Fatalf("unexpected sent update arguments %v", args)
+	}
+}
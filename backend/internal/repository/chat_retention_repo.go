@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatRetentionRepository interface {
+	GetPolicy(schoolID string) (*domain.ChatRetentionPolicy, error)
+	UpsertPolicy(policy *domain.ChatRetentionPolicy) error
+	ListEnforcedPolicies() ([]domain.ChatRetentionPolicy, error)
+	PurgeMessages(schoolID string, cutoff time.Time, limit int) (int64, []string, error)
+	ListOrphanMediaIDs(mediaIDs []string) ([]string, error)
+	RecordPurge(schoolID string, purgedAt time.Time, purgedMessages int64) error
+	ListTranscriptMessages(roomID string, from *time.Time, to *time.Time, limit int) ([]ChatTranscriptRow, error)
+}
+
+type chatRetentionRepository struct {
+	db *gorm.DB
+}
+
+type ChatTranscriptRow struct {
+	MessageID   string     `gorm:"column:message_id"`
+	SenderID    string     `gorm:"column:sender_id"`
+	SenderName  string     `gorm:"column:sender_name"`
+	SenderEmail string     `gorm:"column:sender_email"`
+	Content     string     `gorm:"column:content"`
+	Type        string     `gorm:"column:message_type"`
+	ReplyTo     *string    `gorm:"column:reply_to"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	EditedAt    *time.Time `gorm:"column:edited_at"`
+	DeletedAt   *time.Time `gorm:"column:deleted_at"`
+	DeletedBy   *string    `gorm:"column:deleted_by"`
+}
+
+func NewChatRetentionRepository(db *gorm.DB) ChatRetentionRepository {
+	return &chatRetentionRepository{db: db}
+}
+
+func (r *chatRetentionRepository) GetPolicy(schoolID string) (*domain.ChatRetentionPolicy, error) {
+	var policy domain.ChatRetentionPolicy
+	err := r.db.Where("crr_sch_id = ?", schoolID).First(&policy).Error
+	return &policy, err
+}
+
+func (r *chatRetentionRepository) UpsertPolicy(policy *domain.ChatRetentionPolicy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "crr_sch_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"crr_retention_days", "crr_updated_by", "updated_at"}),
+	}).Create(policy).Error
+}
+
+// ListEnforcedPolicies returns the policies of active schools that have a
+// retention period set.
+func (r *chatRetentionRepository) ListEnforcedPolicies() ([]domain.ChatRetentionPolicy, error) {
+	var policies []domain.ChatRetentionPolicy
+	err := r.db.Raw(`
+		SELECT crr.*
+		FROM edv.chat_retention_policies crr
+		JOIN edv.schools s
+			ON s.sch_id = crr.crr_sch_id
+			AND s.deleted_at IS NULL
+		WHERE crr.crr_retention_days IS NOT NULL
+			AND crr.crr_retention_days > 0
+	`).Scan(&policies).Error
+	return policies, err
+}
+
+// PurgeMessages permanently deletes up to limit messages in the school created
+// before cutoff, together with their attachments, reactions, edits, mentions,
+// reports and pins. A thread parent is kept while any of its replies is still
+// inside the retention period. It returns the number of purged messages and
+// the media IDs they referenced so the caller can clean up storage.
+func (r *chatRetentionRepository) PurgeMessages(schoolID string, cutoff time.Time, limit int) (int64, []string, error) {
+	var purged int64
+	var mediaIDs []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var messageIDs []string
+		if err := tx.Raw(`
+			SELECT msg.msg_id
+			FROM edv.chat_messages msg
+			JOIN edv.chat_rooms cr
+				ON cr.room_id = msg.msg_room_id
+				AND cr.room_sch_id = ?
+			WHERE msg.created_at < ?
+				AND NOT EXISTS (
+					SELECT 1
+					FROM edv.chat_messages reply
+					WHERE reply.msg_reply_to = msg.msg_id
+						AND reply.created_at >= ?
+				)
+			ORDER BY msg.created_at ASC
+			LIMIT ?
+		`, schoolID, cutoff, cutoff, limit).Scan(&messageIDs).Error; err != nil {
+			return err
+		}
+		if len(messageIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Raw(`
+			SELECT DISTINCT media_id
+			FROM (
+				SELECT ca.cat_med_id AS media_id
+				FROM edv.chat_attachments ca
+				WHERE ca.cat_msg_id IN ?
+				UNION
+				SELECT msg.msg_med_id AS media_id
+				FROM edv.chat_messages msg
+				WHERE msg.msg_id IN ?
+					AND msg.msg_med_id IS NOT NULL
+			) refs
+		`, messageIDs, messageIDs).Scan(&mediaIDs).Error; err != nil {
+			return err
+		}
+
+		statements := []string{
+			`DELETE FROM edv.chat_attachments WHERE cat_msg_id IN ?`,
+			`DELETE FROM edv.chat_message_reactions WHERE cmr_msg_id IN ?`,
+			`DELETE FROM edv.chat_message_edits WHERE cme_msg_id IN ?`,
+			`DELETE FROM edv.chat_message_mentions WHERE cmn_msg_id IN ?`,
+			`DELETE FROM edv.chat_message_reports WHERE crp_msg_id IN ?`,
+			`DELETE FROM edv.chat_pinned_messages WHERE cpm_msg_id IN ?`,
+			`UPDATE edv.chat_read_receipts SET last_read_msg_id = NULL WHERE last_read_msg_id IN ?`,
+			`UPDATE edv.chat_scheduled_messages SET csm_msg_id = NULL WHERE csm_msg_id IN ?`,
+			`UPDATE edv.chat_scheduled_messages SET csm_reply_to = NULL WHERE csm_reply_to IN ?`,
+			// Replies left for a later batch are expired too; detach them so
+			// the parent can go first.
+			`UPDATE edv.chat_messages SET msg_reply_to = NULL WHERE msg_reply_to IN ?`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement, messageIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Exec(`DELETE FROM edv.chat_messages WHERE msg_id IN ?`, messageIDs)
+		if result.Error != nil {
+			return result.Error
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return purged, mediaIDs, nil
+}
+
+// ListOrphanMediaIDs filters mediaIDs to media no longer referenced by chat,
+// LMS attachments or a school logo.
+func (r *chatRetentionRepository) ListOrphanMediaIDs(mediaIDs []string) ([]string, error) {
+	if len(mediaIDs) == 0 {
+		return nil, nil
+	}
+	var orphanIDs []string
+	err := r.db.Raw(`
+		SELECT m.med_id
+		FROM edv.medias m
+		WHERE m.med_id IN ?
+			AND m.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM edv.chat_attachments ca WHERE ca.cat_med_id = m.med_id)
+			AND NOT EXISTS (SELECT 1 FROM edv.chat_messages msg WHERE msg.msg_med_id = m.med_id)
+			AND NOT EXISTS (SELECT 1 FROM edv.attachments att WHERE att.att_med_id = m.med_id)
+			AND NOT EXISTS (SELECT 1 FROM edv.schools s WHERE s.sch_logo = m.med_id)
+	`, mediaIDs).Scan(&orphanIDs).Error
+	return orphanIDs, err
+}
+
+func (r *chatRetentionRepository) RecordPurge(schoolID string, purgedAt time.Time, purgedMessages int64) error {
+	return r.db.Exec(`
+		UPDATE edv.chat_retention_policies
+		SET crr_last_purged_at = ?,
+			crr_last_purged_messages = ?
+		WHERE crr_sch_id = ?
+	`, purgedAt, purgedMessages, schoolID).Error
+}
+
+// ListTranscriptMessages returns every message of the room in chronological
+// order, including deleted ones, for investigation exports.
+func (r *chatRetentionRepository) ListTranscriptMessages(roomID string, from *time.Time, to *time.Time, limit int) ([]ChatTranscriptRow, error) {
+	var rows []ChatTranscriptRow
+	err := r.db.Raw(`
+		SELECT
+			msg.msg_id AS message_id,
+			msg.msg_usr_id AS sender_id,
+			COALESCE(u.usr_nama_lengkap, 'Pengguna') AS sender_name,
+			COALESCE(u.usr_email, '') AS sender_email,
+			msg.msg_content AS content,
+			msg.msg_type AS message_type,
+			msg.msg_reply_to AS reply_to,
+			msg.created_at AS created_at,
+			msg.edited_at AS edited_at,
+			msg.deleted_at AS deleted_at,
+			msg.deleted_by AS deleted_by
+		FROM edv.chat_messages msg
+		LEFT JOIN edv.users u ON u.usr_id = msg.msg_usr_id
+		WHERE msg.msg_room_id = ?
+			AND (?::timestamptz IS NULL OR msg.created_at >= ?::timestamptz)
+			AND (?::timestamptz IS NULL OR msg.created_at < ?::timestamptz)
+		ORDER BY msg.created_at ASC, msg.msg_id ASC
+		LIMIT ?
+	`, roomID, from, from, to, to, limit).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrChatScheduledMessageReclaimed reports that a scheduled message changed
+// since it was claimed, so the claim no longer owns it.
+var ErrChatScheduledMessageReclaimed = errors.New("chat scheduled message was reclaimed")
+
+type ChatScheduleRepository interface {
+	Create(message *domain.ChatScheduledMessage) error
+	GetByID(id string, userID string, schoolID string) (*domain.ChatScheduledMessage, error)
+	List(userID string, schoolID string, roomID *string, status string) ([]domain.ChatScheduledMessage, error)
+	CountPending(userID string, schoolID string) (int64, error)
+	UpdatePending(id string, content string, sendAt time.Time) error
+	CancelPending(id string, userID string, schoolID string) error
+	ClaimDue(now time.Time, staleBefore time.Time, limit int) ([]domain.ChatScheduledMessage, error)
+	MarkFailed(id string, reason string) error
+}
+
+type chatScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewChatScheduleRepository(db *gorm.DB) ChatScheduleRepository {
+	return &chatScheduleRepository{db: db}
+}
+
+func (r *chatScheduleRepository) Create(message *domain.ChatScheduledMessage) error {
+	return r.db.Create(message).Error
+}
+
+func (r *chatScheduleRepository) GetByID(id string, userID string, schoolID string) (*domain.ChatScheduledMessage, error) {
+	var message domain.ChatScheduledMessage
+	err := r.db.
+		Where("csm_id = ? AND csm_usr_id = ? AND csm_sch_id = ?", id, userID, schoolID).
+		First(&message).Error
+	return &message, err
+}
+
+// List returns the user's scheduled messages in the school, soonest first. An
+// empty status returns every status.
+func (r *chatScheduleRepository) List(userID string, schoolID string, roomID *string, status string) ([]domain.ChatScheduledMessage, error) {
+	var messages []domain.ChatScheduledMessage
+	query := r.db.Where("csm_usr_id = ? AND csm_sch_id = ?", userID, schoolID)
+	if roomID != nil {
+		query = query.Where("csm_room_id = ?", *roomID)
+	}
+	if status != "" {
+		query = query.Where("csm_status = ?", status)
+	}
+	err := query.Order("csm_send_at ASC").Order("created_at ASC").Find(&messages).Error
+	return messages, err
+}
+
+func (r *chatScheduleRepository) CountPending(userID string, schoolID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.ChatScheduledMessage{}).
+		Where("csm_usr_id = ? AND csm_sch_id = ? AND csm_status = ?", userID, schoolID, domain.ChatScheduledPending).
+		Count(&count).Error
+	return count, err
+}
+
+// UpdatePending only touches messages the dispatcher has not claimed yet.
+func (r *chatScheduleRepository) UpdatePending(id string, content string, sendAt time.Time) error {
+	result := r.db.Exec(`
+		UPDATE edv.chat_scheduled_messages
+		SET csm_content = ?,
+			csm_send_at = ?,
+			updated_at = now()
+		WHERE csm_id = ?
+			AND csm_status = ?
+	`, content, sendAt, id, domain.ChatScheduledPending)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *chatScheduleRepository) CancelPending(id string, userID string, schoolID string) error {
+	result := r.db.Exec(`
+		UPDATE edv.chat_scheduled_messages
+		SET csm_status = ?,
+			updated_at = now()
+		WHERE csm_id = ?
+			AND csm_usr_id = ?
+			AND csm_sch_id = ?
+			AND csm_status = ?
+	`, domain.ChatScheduledCancelled, id, userID, schoolID, domain.ChatScheduledPending)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ClaimDue moves up to limit due pending messages to sending and returns them.
+// Messages left in sending since before staleBefore, by a dispatcher that
+// stopped mid-send, are claimed again. SKIP LOCKED keeps two API instances
+// from claiming the same message.
+func (r *chatScheduleRepository) ClaimDue(now time.Time, staleBefore time.Time, limit int) ([]domain.ChatScheduledMessage, error) {
+	var messages []domain.ChatScheduledMessage
+	err := r.db.Raw(`
+		UPDATE edv.chat_scheduled_messages csm
+		SET csm_status = ?,
+			updated_at = now()
+		WHERE csm.csm_id IN (
+			SELECT due.csm_id
+			FROM edv.chat_scheduled_messages due
+			WHERE (
+					due.csm_status = ?
+					AND due.csm_send_at <= ?
+				) OR (
+					due.csm_status = ?
+					AND due.updated_at <= ?
+				)
+			ORDER BY due.csm_send_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING csm.*
+	`, domain.ChatScheduledSending, domain.ChatScheduledPending, now, domain.ChatScheduledSending, staleBefore, limit).Scan(&messages).Error
+	return messages, err
+}
+
+func (r *chatScheduleRepository) MarkFailed(id string, reason string) error {
+	return r.db.Exec(`
+		UPDATE edv.chat_scheduled_messages
+		SET csm_status = ?,
+			csm_error = ?,
+			updated_at = now()
+		WHERE csm_id = ?
+	`, domain.ChatScheduledFailed, reason, id).Error
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"backend/internal/domain"
 	"time"
 
 	"gorm.io/gorm"
@@ -41,6 +42,7 @@ func (r *dashboardRepository) GetPendingAssignmentsCount(userID string) (int, er
 		Joins("JOIN edv.enrollments e ON sc.scl_cls_id = e.enr_cls_id").
 		Joins("JOIN edv.school_users su ON e.enr_scu_id = su.scu_id AND su.deleted_at IS NULL").
 		Where("su.scu_usr_id = ? AND e.left_at IS NULL AND a.asg_deadline > ? AND a.deleted_at IS NULL", userID, time.Now()).
+		Where("a.asg_publish_status = ?", domain.PublishStatusPublished).
 		Where("NOT EXISTS (SELECT 1 FROM edv.submissions s WHERE s.sbm_asg_id = a.asg_id AND s.sbm_usr_id = ? AND s.deleted_at IS NULL)", userID).
 		Count(&count).Error
 	return int(count), err
@@ -64,6 +66,7 @@ func (r *dashboardRepository) GetUpcomingDeadlines(userID string, limit int) ([]
 			AND e.left_at IS NULL
 			AND a.asg_deadline > ?
 			AND a.deleted_at IS NULL
+			AND a.asg_publish_status = 'published'
 		ORDER BY a.asg_deadline ASC
 		LIMIT ?
 	`, userID, userID, time.Now(), limit).Scan(&results).Error
@@ -92,6 +95,7 @@ func (r *dashboardRepository) GetMaterialProgress(userID string) (completed int,
 		JOIN edv.school_users su ON e.enr_scu_id = su.scu_id AND su.deleted_at IS NULL
 		LEFT JOIN edv.material_progress mp ON m.mat_id = mp.map_mat_id AND mp.map_usr_id = ?
 		WHERE su.scu_usr_id = ? AND e.left_at IS NULL AND m.deleted_at IS NULL
+			AND m.mat_publish_status = 'published'
 	`, userID, userID).Row().Scan(&completed, &total)
 	return
 }
@@ -158,6 +162,7 @@ func (r *dashboardRepository) GetSubmissionRateByTeacher(schoolUserID string) (f
 			AND c.deleted_at IS NULL
 			AND a.asg_sch_id = teacher_e.enr_sch_id
 			AND a.deleted_at IS NULL
+			AND a.asg_publish_status = 'published'
 	`, schoolUserID).Scan(&rate).Error
 	return rate, err
 }
@@ -198,6 +203,7 @@ func (r *dashboardRepository) GetClassPerformance(schoolUserID string) ([]map[st
 		LEFT JOIN edv.assignments a ON sc.scl_id = a.asg_scl_id
 			AND a.asg_sch_id = teacher_e.enr_sch_id
 			AND a.deleted_at IS NULL
+			AND a.asg_publish_status = 'published'
 		LEFT JOIN edv.submissions s ON a.asg_id = s.sbm_asg_id
 			AND s.sbm_usr_id = student_scu.scu_usr_id
 			AND s.sbm_sch_id = teacher_e.enr_sch_id
@@ -12,6 +12,9 @@ type GradeRepository interface {
 	GetStudentsBySubjectClass(subjectClassID string) ([]*domain.User, error)
 	GetStudentGradebookClass(userID string, schoolID string, classID string) (*dto.StudentGradebookClassRow, error)
 	GetStudentGradebookRows(userID string, schoolID string, classID string) ([]dto.StudentGradebookRow, error)
+	GetLatePenaltyInputs(submissionIDs []string) ([]dto.LatePenaltyInputRow, error)
+	GetGroupScoreAdjustments(userID string, submissionIDs []string) ([]domain.GroupSubmissionMember, error)
+	GetPeerReviewCompletion(userID string, assignmentIDs []string) ([]dto.PeerReviewCompletionRow, error)
 }
 
 type gradeRepository struct {
@@ -30,7 +33,7 @@ func (r *gradeRepository) GetAssessmentsByStudentAndSubject(userID string, subje
 		Joins("JOIN edv.assignments ON assignments.asg_id = submissions.sbm_asg_id").
 		Joins("JOIN edv.subject_classes ON subject_classes.scl_id = assignments.asg_scl_id").
 		Preload("Submission.Assignment.Category").
-		Where("submissions.sbm_usr_id = ?", userID).
+		Where(studentSubmissionCondition("submissions"), userID, userID).
 		Where("subject_classes.scl_sub_id = ?", subjectID).
 		Where("submissions.deleted_at IS NULL").
 		Where("assignments.deleted_at IS NULL").
@@ -93,16 +96,19 @@ func (r *gradeRepository) GetStudentGradebookRows(userID string, schoolID string
 			a.asg_deadline AS deadline,
 			s.sbm_id AS submission_id,
 			s.submitted_at AS submitted_at,
-			asm.asm_score AS score,
+			CASE
+				WHEN asm.asm_score IS NULL THEN NULL
+				ELSE LEAST(100, GREATEST(0, asm.asm_score + COALESCE(gsm.gsm_score_adjustment, 0)))
+			END AS score,
 			asm.asm_feedback AS feedback,
 			asm.assessed_at AS assessed_at,
 			assessor.usr_nama_lengkap AS assessor_name
 		`).
 		Joins("JOIN edv.classes c ON c.cls_id = sc.scl_cls_id").
 		Joins("JOIN edv.subjects sub ON sub.sub_id = sc.scl_sub_id").
-		Joins("LEFT JOIN edv.assignments a ON a.asg_scl_id = sc.scl_id AND a.asg_sch_id = ? AND a.deleted_at IS NULL", schoolID).
+		Joins("LEFT JOIN edv.assignments a ON a.asg_scl_id = sc.scl_id AND a.asg_sch_id = ? AND a.deleted_at IS NULL AND a.asg_publish_status = ?", schoolID, domain.PublishStatusPublished).
 		Joins("LEFT JOIN edv.assignment_categories ac ON ac.asc_id = a.asg_asc_id").
-		Joins("LEFT JOIN edv.submissions s ON s.sbm_asg_id = a.asg_id AND s.sbm_usr_id = ? AND s.sbm_sch_id = ? AND s.deleted_at IS NULL", userID, schoolID).
+		Joins("LEFT JOIN edv.submissions s ON s.sbm_asg_id = a.asg_id AND "+studentSubmissionCondition("s")+" AND s.sbm_sch_id = ? AND s.deleted_at IS NULL", userID, userID, schoolID).
 		Joins(`LEFT JOIN LATERAL (
 			SELECT *
 			FROM edv.assessments latest_asm
@@ -110,6 +116,7 @@ func (r *gradeRepository) GetStudentGradebookRows(userID string, schoolID string
 			ORDER BY latest_asm.assessed_at DESC, latest_asm.asm_id DESC
 			LIMIT 1
 		) asm ON true`).
+		Joins("LEFT JOIN edv.group_submission_members gsm ON gsm.gsm_sbm_id = s.sbm_id AND gsm.gsm_usr_id = ?", userID).
 		Joins("LEFT JOIN edv.users assessor ON assessor.usr_id = asm.assessed_by").
 		Where("sc.scl_cls_id = ?", classID).
 		Where("c.cls_sch_id = ? AND sub.sub_sch_id = ?", schoolID, schoolID).
@@ -118,3 +125,38 @@ func (r *gradeRepository) GetStudentGradebookRows(userID string, schoolID string
 		Scan(&rows).Error
 	return rows, err
 }
+
+func (r *gradeRepository) GetLatePenaltyInputs(submissionIDs []string) ([]dto.LatePenaltyInputRow, error) {
+	return listLatePenaltyInputs(r.db, submissionIDs)
+}
+
+func (r *gradeRepository) GetGroupScoreAdjustments(userID string, submissionIDs []string) ([]domain.GroupSubmissionMember, error) {
+	var members []domain.GroupSubmissionMember
+	if len(submissionIDs) == 0 {
+		return members, nil
+	}
+	err := r.db.Where("gsm_usr_id = ? AND gsm_sbm_id IN ?", userID, submissionIDs).Find(&members).Error
+	return members, err
+}
+
+// GetPeerReviewCompletion counts the reviews the student was assigned and
+// submitted on the allocated peer reviews that count toward the grade.
+func (r *gradeRepository) GetPeerReviewCompletion(userID string, assignmentIDs []string) ([]dto.PeerReviewCompletionRow, error) {
+	var rows []dto.PeerReviewCompletionRow
+	if len(assignmentIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.Table("edv.peer_review_settings prs").
+		Select(`
+			prs.prs_asg_id AS assignment_id,
+			prs.prs_completion_weight AS weight,
+			COUNT(prv.prv_id) AS assigned,
+			COUNT(prv.prv_id) FILTER (WHERE prv.prv_status = ?) AS completed
+		`, domain.PeerReviewSubmitted).
+		Joins("LEFT JOIN edv.peer_reviews prv ON prv.prv_asg_id = prs.prs_asg_id AND prv.prv_reviewer_id = ?", userID).
+		Where("prs.prs_asg_id IN ?", assignmentIDs).
+		Where("prs.prs_allocated_at IS NOT NULL AND prs.prs_completion_weight > 0").
+		Group("prs.prs_asg_id, prs.prs_completion_weight").
+		Scan(&rows).Error
+	return rows, err
+}
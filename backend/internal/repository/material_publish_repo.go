@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"time"
+)
+
+func (r *materialRepository) ListDueScheduled(now time.Time) ([]*domain.Material, error) {
+	var materials []*domain.Material
+	err := r.db.
+		Where("mat_publish_status = ? AND mat_publish_at <= ?", domain.PublishStatusScheduled, now).
+		Order("mat_publish_at ASC").
+		Find(&materials).Error
+	return materials, err
+}
+
+// Publish publishes a scheduled material whose publish time has passed. It
+// reports false when the material was already published or rescheduled.
+func (r *materialRepository) Publish(materialID string, now time.Time) (bool, error) {
+	result := r.db.Model(&domain.Material{}).
+		Where("mat_id = ? AND mat_publish_status = ? AND mat_publish_at <= ?", materialID, domain.PublishStatusScheduled, now).
+		Updates(map[string]interface{}{
+			"mat_publish_status": domain.PublishStatusPublished,
+			"mat_published_at":   now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -2,16 +2,22 @@ package repository
 
 import (
 	"backend/internal/domain"
+	"time"
+
 	"gorm.io/gorm"
 )
 
 type MaterialRepository interface {
 	Create(mat *domain.Material) error
-	FindAll(search string, subjectClassID string, page int, limit int) ([]*domain.Material, int64, error)
+	FindAll(search string, subjectClassID string, publishedOnly bool, page int, limit int) ([]*domain.Material, int64, error)
 	GetByID(id string) (*domain.Material, error)
 	Update(mat *domain.Material) error
 	Delete(id string) error
 
+	// Publishing
+	ListDueScheduled(now time.Time) ([]*domain.Material, error)
+	Publish(materialID string, now time.Time) (bool, error)
+
 	// Progress
 	UpsertProgress(prog *domain.MaterialProgress) error
 	GetProgress(userID, matID string) (*domain.MaterialProgress, error)
@@ -29,7 +35,7 @@ func (r *materialRepository) Create(mat *domain.Material) error {
 	return r.db.Create(mat).Error
 }
 
-func (r *materialRepository) FindAll(search string, subjectClassID string, page int, limit int) ([]*domain.Material, int64, error) {
+func (r *materialRepository) FindAll(search string, subjectClassID string, publishedOnly bool, page int, limit int) ([]*domain.Material, int64, error) {
 	var materials []*domain.Material
 	var total int64
 
@@ -45,6 +51,9 @@ func (r *materialRepository) FindAll(search string, subjectClassID string, page
 		searchTerm := "%" + search + "%"
 		query = query.Where("mat_title ILIKE ?", searchTerm)
 	}
+	if publishedOnly {
+		query = query.Where("mat_publish_status = ?", domain.PublishStatusPublished)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type QuestionRepository interface {
+	Create(question *domain.Question) error
+	GetByID(id string) (*domain.Question, error)
+	GetByIDs(ids []string) ([]*domain.Question, error)
+	List(schoolID string, subjectID string, questionType string) ([]*domain.Question, error)
+	Replace(question *domain.Question) error
+	Delete(id string) error
+	CountQuizzes(questionID string) (int64, error)
+	IsAnswered(questionID string) (bool, error)
+}
+
+type questionRepository struct {
+	db *gorm.DB
+}
+
+func NewQuestionRepository(db *gorm.DB) QuestionRepository {
+	return &questionRepository{db: db}
+}
+
+func (r *questionRepository) Create(question *domain.Question) error {
+	return r.db.Create(question).Error
+}
+
+func (r *questionRepository) GetByID(id string) (*domain.Question, error) {
+	var question domain.Question
+	err := preloadQuestionOptions(r.db).Where("qst_id = ?", id).First(&question).Error
+	return &question, err
+}
+
+func (r *questionRepository) GetByIDs(ids []string) ([]*domain.Question, error) {
+	var results []*domain.Question
+	if len(ids) == 0 {
+		return results, nil
+	}
+	err := preloadQuestionOptions(r.db).Where("qst_id IN ?", ids).Find(&results).Error
+	return results, err
+}
+
+// List returns the question bank of a subject, optionally narrowed to one
+// question type.
+func (r *questionRepository) List(schoolID string, subjectID string, questionType string) ([]*domain.Question, error) {
+	var results []*domain.Question
+	query := preloadQuestionOptions(r.db).
+		Where("qst_sch_id = ? AND qst_sub_id = ?", schoolID, subjectID)
+	if questionType != "" {
+		query = query.Where("qst_type = ?", questionType)
+	}
+	err := query.Order("created_at desc").Find(&results).Error
+	return results, err
+}
+
+// Replace updates the question fields and swaps its options for
+// question.Options, which get new IDs.
+func (r *questionRepository) Replace(question *domain.Question) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Question{}).
+			Where("qst_id = ?", question.ID).
+			Updates(map[string]interface{}{
+				"qst_sub_id":            question.SubjectID,
+				"qst_type":              question.Type,
+				"qst_prompt":            question.Prompt,
+				"qst_points":            question.Points,
+				"qst_boolean_answer":    question.BooleanAnswer,
+				"qst_accepted_answers":  question.AcceptedAnswers,
+				"qst_case_sensitive":    question.CaseSensitive,
+				"qst_numeric_answer":    question.NumericAnswer,
+				"qst_numeric_tolerance": question.NumericTolerance,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("qop_qst_id = ?", question.ID).Delete(&domain.QuestionOption{}).Error; err != nil {
+			return err
+		}
+		if len(question.Options) == 0 {
+			return nil
+		}
+		for i := range question.Options {
+			question.Options[i].QuestionID = question.ID
+		}
+		return tx.Create(&question.Options).Error
+	})
+}
+
+func (r *questionRepository) Delete(id string) error {
+	result := r.db.Where("qst_id = ?", id).Delete(&domain.Question{})
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+// CountQuizzes counts the live quiz assignments that ask the question.
+func (r *questionRepository) CountQuizzes(questionID string) (int64, error) {
+	var count int64
+	err := r.db.Table("edv.quiz_questions qzq").
+		Joins("JOIN edv.assignments a ON a.asg_id = qzq.qzq_asg_id AND a.deleted_at IS NULL").
+		Where("qzq.qzq_qst_id = ?", questionID).
+		Count(&count).Error
+	return count, err
+}
+
+// IsAnswered reports whether a quiz attempt has already asked the question.
+func (r *questionRepository) IsAnswered(questionID string) (bool, error) {
+	var count int64
+	err := r.db.Table("edv.quiz_questions qzq").
+		Joins("JOIN edv.quiz_attempts qza ON qza.qza_asg_id = qzq.qzq_asg_id").
+		Where("qzq.qzq_qst_id = ?", questionID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func preloadQuestionOptions(db *gorm.DB) *gorm.DB {
+	return db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("qop_position asc")
+	})
+}
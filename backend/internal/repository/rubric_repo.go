@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type RubricRepository interface {
+	Create(rubric *domain.Rubric) error
+	GetByID(id string) (*domain.Rubric, error)
+	List(schoolID string, userID string) ([]*domain.Rubric, error)
+	Replace(rubric *domain.Rubric) error
+	Delete(id string) error
+	CountAssignments(rubricID string) (int64, error)
+	IsUsedInAssessments(rubricID string) (bool, error)
+}
+
+type rubricRepository struct {
+	db *gorm.DB
+}
+
+func NewRubricRepository(db *gorm.DB) RubricRepository {
+	return &rubricRepository{db: db}
+}
+
+func (r *rubricRepository) Create(rubric *domain.Rubric) error {
+	return r.db.Create(rubric).Error
+}
+
+func (r *rubricRepository) GetByID(id string) (*domain.Rubric, error) {
+	var rubric domain.Rubric
+	err := r.preloadCriteria(r.db).Where("rbr_id = ?", id).First(&rubric).Error
+	return &rubric, err
+}
+
+// List returns the school-wide rubrics of schoolID plus the ones owned by
+// userID.
+func (r *rubricRepository) List(schoolID string, userID string) ([]*domain.Rubric, error) {
+	var results []*domain.Rubric
+	err := r.preloadCriteria(r.db).
+		Where("rbr_sch_id = ?", schoolID).
+		Where("rbr_owner_usr_id IS NULL OR rbr_owner_usr_id = ?", userID).
+		Order("rbr_title asc").
+		Find(&results).Error
+	return results, err
+}
+
+// Replace updates the rubric fields and swaps its criteria and levels for
+// rubric.Criteria, which get new IDs.
+func (r *rubricRepository) Replace(rubric *domain.Rubric) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Rubric{}).
+			Where("rbr_id = ?", rubric.ID).
+			Updates(map[string]interface{}{
+				"rbr_title":        rubric.Title,
+				"rbr_desc":         rubric.Description,
+				"rbr_owner_usr_id": rubric.OwnerID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("rbl_rbc_id IN (?)", tx.Model(&domain.RubricCriterion{}).Select("rbc_id").Where("rbc_rbr_id = ?", rubric.ID)).
+			Delete(&domain.RubricLevel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("rbc_rbr_id = ?", rubric.ID).Delete(&domain.RubricCriterion{}).Error; err != nil {
+			return err
+		}
+		for i := range rubric.Criteria {
+			rubric.Criteria[i].RubricID = rubric.ID
+		}
+		return tx.Create(&rubric.Criteria).Error
+	})
+}
+
+func (r *rubricRepository) Delete(id string) error {
+	result := r.db.Where("rbr_id = ?", id).Delete(&domain.Rubric{})
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (r *rubricRepository) CountAssignments(rubricID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Assignment{}).Where("asg_rbr_id = ?", rubricID).Count(&count).Error
+	return count, err
+}
+
+func (r *rubricRepository) IsUsedInAssessments(rubricID string) (bool, error) {
+	var count int64
+	err := r.db.Table("edv.assessment_rubric_scores ars").
+		Joins("JOIN edv.rubric_criteria rbc ON rbc.rbc_id = ars.ars_rbc_id").
+		Where("rbc.rbc_rbr_id = ?", rubricID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *rubricRepository) preloadCriteria(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Criteria", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rbc_position asc")
+		}).
+		Preload("Criteria.Levels", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rbl_position asc")
+		})
+}
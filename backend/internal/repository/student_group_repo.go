@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StudentGroupRepository interface {
+	Create(group *domain.StudentGroup) error
+	GetByID(id string) (*domain.StudentGroup, error)
+	ListBySubjectClass(subjectClassID string) ([]*domain.StudentGroup, error)
+	Update(group *domain.StudentGroup) error
+	Delete(id string) error
+	CountSubmissions(groupID string) (int64, error)
+	ReplaceMembers(group *domain.StudentGroup, userIDs []string) error
+	AddMember(group *domain.StudentGroup, userID string) (bool, error)
+	RemoveMember(groupID string, userID string) error
+	GetMembership(subjectClassID string, userID string) (*domain.StudentGroupMember, error)
+	IsMember(groupID string, userID string) (bool, error)
+}
+
+type studentGroupRepository struct {
+	db *gorm.DB
+}
+
+func NewStudentGroupRepository(db *gorm.DB) StudentGroupRepository {
+	return &studentGroupRepository{db: db}
+}
+
+func (r *studentGroupRepository) Create(group *domain.StudentGroup) error {
+	return r.db.Omit("Members").Create(group).Error
+}
+
+func (r *studentGroupRepository) GetByID(id string) (*domain.StudentGroup, error) {
+	var group domain.StudentGroup
+	err := preloadStudentGroupMembers(r.db).Where("grp_id = ?", id).First(&group).Error
+	return &group, err
+}
+
+func (r *studentGroupRepository) ListBySubjectClass(subjectClassID string) ([]*domain.StudentGroup, error) {
+	var results []*domain.StudentGroup
+	err := preloadStudentGroupMembers(r.db).
+		Where("grp_scl_id = ?", subjectClassID).
+		Order("grp_name asc").
+		Find(&results).Error
+	return results, err
+}
+
+func (r *studentGroupRepository) Update(group *domain.StudentGroup) error {
+	result := r.db.Model(&domain.StudentGroup{}).
+		Where("grp_id = ?", group.ID).
+		Updates(map[string]interface{}{
+			"grp_name":      group.Name,
+			"grp_max_size":  group.MaxSize,
+			"grp_self_join": group.SelfJoin,
+			"updated_at":    gorm.Expr("now()"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *studentGroupRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("gpm_grp_id = ?", id).Delete(&domain.StudentGroupMember{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("grp_id = ?", id).Delete(&domain.StudentGroup{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// CountSubmissions counts the submissions made for the group, including
+// deleted ones that the group can still restore.
+func (r *studentGroupRepository) CountSubmissions(groupID string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&domain.Submission{}).Where("sbm_grp_id = ?", groupID).Count(&count).Error
+	return count, err
+}
+
+// ReplaceMembers makes userIDs the members of group. Students in another
+// group of the subject class move to this one.
+func (r *studentGroupRepository) ReplaceMembers(group *domain.StudentGroup, userIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockStudentGroup(tx, group.ID); err != nil {
+			return err
+		}
+		if err := tx.Where("gpm_grp_id = ?", group.ID).Delete(&domain.StudentGroupMember{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		if err := tx.Where("gpm_scl_id = ? AND gpm_usr_id IN ?", group.SubjectClassID, userIDs).
+			Delete(&domain.StudentGroupMember{}).Error; err != nil {
+			return err
+		}
+
+		members := make([]domain.StudentGroupMember, 0, len(userIDs))
+		for _, userID := range userIDs {
+			members = append(members, domain.StudentGroupMember{
+				GroupID:        group.ID,
+				SubjectClassID: group.SubjectClassID,
+				UserID:         userID,
+			})
+		}
+		return tx.Omit("User").Create(&members).Error
+	})
+}
+
+// AddMember reports false when the group is already full.
+func (r *studentGroupRepository) AddMember(group *domain.StudentGroup, userID string) (bool, error) {
+	added := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockStudentGroup(tx, group.ID); err != nil {
+			return err
+		}
+		if group.MaxSize != nil {
+			var count int64
+			if err := tx.Model(&domain.StudentGroupMember{}).Where("gpm_grp_id = ?", group.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count >= int64(*group.MaxSize) {
+				return nil
+			}
+		}
+
+		member := domain.StudentGroupMember{
+			GroupID:        group.ID,
+			SubjectClassID: group.SubjectClassID,
+			UserID:         userID,
+		}
+		if err := tx.Omit("User").Create(&member).Error; err != nil {
+			return err
+		}
+		added = true
+		return nil
+	})
+	return added, err
+}
+
+func (r *studentGroupRepository) RemoveMember(groupID string, userID string) error {
+	result := r.db.Where("gpm_grp_id = ? AND gpm_usr_id = ?", groupID, userID).Delete(&domain.StudentGroupMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *studentGroupRepository) GetMembership(subjectClassID string, userID string) (*domain.StudentGroupMember, error) {
+	var member domain.StudentGroupMember
+	err := r.db.Where("gpm_scl_id = ? AND gpm_usr_id = ?", subjectClassID, userID).First(&member).Error
+	return &member, err
+}
+
+func (r *studentGroupRepository) IsMember(groupID string, userID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.StudentGroupMember{}).
+		Where("gpm_grp_id = ? AND gpm_usr_id = ?", groupID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func lockStudentGroup(tx *gorm.DB, groupID string) error {
+	var group domain.StudentGroup
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("grp_id = ?", groupID).First(&group).Error
+}
+
+func preloadStudentGroupMembers(db *gorm.DB) *gorm.DB {
+	return db.Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at asc")
+	}).Preload("Members.User")
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// identitiesHidden reports whether graders of the assignment see pseudonyms
+// instead of student names.
+func identitiesHidden(asg *domain.Assignment) bool {
+	return asg.AnonymousGrading && asg.IdentityReleasedAt == nil
+}
+
+func newAnonymousKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// anonymousPseudonym names id the same way on every request. The key is
+// secret, so the pseudonym cannot be traced back from known user IDs.
+func anonymousPseudonym(key string, label string, id string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(id))
+	return label + " " + strings.ToUpper(hex.EncodeToString(mac.Sum(nil))[:6])
+}
+
+// AnonymizeStudent returns the user ID and name to show graders for a
+// student of the assignment: blank and a pseudonym while identities are
+// hidden, the real ones otherwise.
+func (s *assignmentService) AnonymizeStudent(asg *domain.Assignment, userID string, fullName string) (string, string) {
+	if !identitiesHidden(asg) {
+		return userID, fullName
+	}
+	return "", anonymousPseudonym(asg.AnonymousKey, "Student", userID)
+}
+
+// AnonymizeSubmission replaces the students of sbm with pseudonyms while the
+// assignment's identities are hidden. A group submission keeps its group ID
+// but loses the group name and member list.
+func (s *assignmentService) AnonymizeSubmission(asg *domain.Assignment, sbm *domain.Submission) {
+	if !identitiesHidden(asg) {
+		return
+	}
+	userID, fullName := s.AnonymizeStudent(asg, sbm.UserID, sbm.User.FullName)
+	sbm.UserID = userID
+	sbm.User = domain.User{FullName: fullName}
+	if sbm.GroupID != nil {
+		sbm.Group = &domain.StudentGroup{Name: anonymousPseudonym(asg.AnonymousKey, "Group", *sbm.GroupID)}
+		sbm.Members = nil
+	}
+}
+
+// ReleaseIdentities shows the students of an anonymously graded assignment
+// once every submission has a grade.
+func (s *assignmentService) ReleaseIdentities(asg *domain.Assignment) error {
+	if !asg.AnonymousGrading {
+		return fmt.Errorf("assignment is not graded anonymously")
+	}
+	if asg.IdentityReleasedAt != nil {
+		return fmt.Errorf("identities are already released")
+	}
+	ungraded, err := s.repo.CountUngradedSubmissions(asg.ID)
+	if err != nil {
+		return err
+	}
+	if ungraded > 0 {
+		return fmt.Errorf("identities can be released once every submission is graded")
+	}
+
+	now := time.Now()
+	released, err := s.repo.ReleaseAssignmentIdentities(asg.ID, now)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return fmt.Errorf("identities are already released")
+	}
+	asg.IdentityReleasedAt = &now
+	return nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnonymizeSubmission(t *testing.T) {
+	groupID := "group-1"
+	asg := &domain.Assignment{AnonymousGrading: true, AnonymousKey: "key"}
+	newSubmission := func() *domain.Submission {
+		return &domain.Submission{
+			UserID:  "user-1",
+			User:    domain.User{FullName: "Siswa A"},
+			GroupID: &groupID,
+			Group:   &domain.StudentGroup{Name: "Kelompok 1"},
+			Members: []domain.GroupSubmissionMember{{UserID: "user-1"}},
+		}
+	}
+
+	s := &assignmentService{}
+	sbm := newSubmission()
+	s.AnonymizeSubmission(asg, sbm)
+	if !strings.HasPrefix(sbm.User.FullName, "Student ") || sbm.Group.Name == "Kelompok 1" || sbm.Members != nil {
+		t.Fatalf("expected identities hidden, got %q in %q", sbm.User.FullName, sbm.Group.Name)
+	}
+	again := newSubmission()
+	s.AnonymizeSubmission(asg, again)
+	if again.User.FullName != sbm.User.FullName {
+		t.Fatalf("expected a stable pseudonym, got %q and %q", sbm.User.FullName, again.User.FullName)
+	}
+	if other := anonymousPseudonym("other-key", "Student", "user-1"); other == sbm.User.FullName {
+		t.Fatal("expected the pseudonym to depend on the key")
+	}
+
+	released := time.Now()
+	asg.IdentityReleasedAt = &released
+	shown := newSubmission()
+	s.AnonymizeSubmission(asg, shown)
+	if shown.User.FullName != "Siswa A" || len(shown.Members) != 1 {
+		t.Fatalf("expected identities shown after release, got %q", shown.User.FullName)
+	}
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	gradeImportCreate    = "create"
+	gradeImportUpdate    = "update"
+	gradeImportUnchanged = "unchanged"
+	gradeImportSkip      = "skip"
+)
+
+var gradeExportHeader = []string{"email", "fullName", "group", "status", "submittedAt", "score", "feedback"}
+
+// parsedGradeRow is a grade CSV row before it is checked against the roster.
+// A nil Feedback keeps the current feedback.
+type parsedGradeRow struct {
+	RowNumber int
+	Email     string
+	Score     *float64
+	Feedback  *string
+	Errors    []string
+}
+
+// ExportGradesCSV writes the grading roster of an assignment as CSV, one row
+// per student. The file can be filled in and imported back.
+func (s *assignmentService) ExportGradesCSV(assignment *domain.Assignment) ([]byte, error) {
+	if err := s.ensureBulkGradable(assignment); err != nil {
+		return nil, err
+	}
+	roster, err := s.repo.ListGradingRoster(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(gradeExportHeader); err != nil {
+		return nil, err
+	}
+	for _, row := range roster {
+		status := "not_submitted"
+		submittedAt := ""
+		if row.SubmissionID != nil {
+			status = "submitted"
+			if row.SubmittedAt != nil {
+				submittedAt = formatAPITime(*row.SubmittedAt)
+			}
+		}
+		score := ""
+		if row.Score != nil {
+			status = "graded"
+			score = strconv.FormatFloat(*row.Score, 'f', -1, 64)
+		}
+		record := []string{
+			row.Email,
+			row.FullName,
+			stringValue(row.GroupName),
+			status,
+			submittedAt,
+			score,
+			stringValue(row.Feedback),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PreviewGradeImport checks a grade CSV against the assignment's roster and
+// reports what committing it would change, without writing anything.
+func (s *assignmentService) PreviewGradeImport(assignment *domain.Assignment, reader io.Reader) (*dto.GradeImportPreviewResponseDTO, error) {
+	if err := s.ensureBulkGradable(assignment); err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseGradeCSV(content)
+	if err != nil {
+		return nil, err
+	}
+	roster, err := s.repo.ListGradingRoster(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return validateGradeRows(roster, rows), nil
+}
+
+// CommitGradeImport validates the previewed rows again and writes their
+// grades in one transaction. When a row is invalid nothing is written and the
+// response lists the invalid rows next to the error. Each row's feedback
+// replaces the current one, so an empty feedback clears it; the preview
+// already fills in the current feedback when the file had no feedback column.
+func (s *assignmentService) CommitGradeImport(assignment *domain.Assignment, rows []dto.GradeImportRowDTO, graderID string) (*dto.GradeImportCommitResponseDTO, error) {
+	if err := s.ensureBulkGradable(assignment); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("grade import rows are required")
+	}
+
+	parsed := make([]parsedGradeRow, 0, len(rows))
+	for _, row := range rows {
+		feedback := strings.TrimSpace(row.Feedback)
+		parsed = append(parsed, parsedGradeRow{
+			RowNumber: row.RowNumber,
+			Email:     strings.ToLower(strings.TrimSpace(row.Email)),
+			Score:     row.Score,
+			Feedback:  &feedback,
+		})
+	}
+	roster, err := s.repo.ListGradingRoster(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	preview := validateGradeRows(roster, parsed)
+	if preview.InvalidCount > 0 {
+		response := &dto.GradeImportCommitResponseDTO{InvalidCount: preview.InvalidCount}
+		for _, row := range preview.Rows {
+			if row.Status == "invalid" {
+				response.Rows = append(response.Rows, row)
+			}
+		}
+		return response, errors.New("grade import has invalid rows")
+	}
+
+	response := &dto.GradeImportCommitResponseDTO{}
+	var versions []*domain.SubmissionVersion
+	var assessments []*domain.Assessment
+	planned := map[string]bool{}
+	now := time.Now()
+	for _, row := range preview.Rows {
+		switch row.Action {
+		case gradeImportSkip:
+			response.SkippedCount++
+			continue
+		case gradeImportUnchanged:
+			response.UnchangedCount++
+			continue
+		}
+		// Members of a group share one submission, graded once.
+		if planned[row.SubmissionID] {
+			continue
+		}
+		planned[row.SubmissionID] = true
+
+		sbm, err := s.repo.GetSubmissionByID(row.SubmissionID)
+		if err != nil {
+			return nil, err
+		}
+		submissionVersions, err := s.repo.ListSubmissionVersions(sbm.ID)
+		if err != nil {
+			return nil, err
+		}
+		asm := &domain.Assessment{
+			SubmissionID: sbm.ID,
+			Score:        *row.Score,
+			Feedback:     row.Feedback,
+			AssessedBy:   graderID,
+		}
+		changed := true
+		if len(submissionVersions) > 0 {
+			target := submissionVersions[len(submissionVersions)-1]
+			changed = applyVersionGrade(asm, sbm.Assessment, target, submissionVersions, assignment.GradingPolicy, now)
+			versions = append(versions, target)
+		}
+		if changed {
+			assessments = append(assessments, asm)
+		}
+		if row.Action == gradeImportCreate {
+			response.CreatedCount++
+		} else {
+			response.UpdatedCount++
+		}
+	}
+
+	if err := s.repo.SaveBulkGrades(versions, assessments); err != nil {
+		return nil, err
+	}
+	for _, asm := range assessments {
+		s.notifySubmissionGraded(asm)
+	}
+	return response, nil
+}
+
+// ensureBulkGradable rejects assignments whose scores do not come from a
+// single number: quizzes are scored from their answers and rubric grades
+// need a level per criterion. The roster names students, so anonymous
+// assignments wait until identities are released.
+func (s *assignmentService) ensureBulkGradable(assignment *domain.Assignment) error {
+	if assignment.Type == domain.AssignmentTypeQuiz {
+		return fmt.Errorf("bulk grading is not available for quiz assignments")
+	}
+	if identitiesHidden(assignment) {
+		return fmt.Errorf("bulk grading is not available while identities are hidden")
+	}
+	rubric, err := s.GetAssignmentRubric(assignment)
+	if err != nil {
+		return err
+	}
+	if rubric != nil {
+		return fmt.Errorf("bulk grading is not available for rubric assignments")
+	}
+	return nil
+}
+
+// parseGradeCSV reads a grade CSV. The email and score columns are required;
+// without a feedback column the current feedback is kept.
+func parseGradeCSV(content []byte) ([]parsedGradeRow, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid grade csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("invalid grade csv: file is empty")
+	}
+
+	header := make(map[string]int)
+	for idx, value := range records[0] {
+		header[strings.ToLower(strings.TrimSpace(value))] = idx
+	}
+	for _, name := range []string{"email", "score"} {
+		if _, ok := header[name]; !ok {
+			return nil, fmt.Errorf("invalid grade csv: column %s is required", name)
+		}
+	}
+	feedbackIndex, hasFeedback := header["feedback"]
+
+	rows := make([]parsedGradeRow, 0, len(records)-1)
+	for index, record := range records[1:] {
+		if isEmptyCSVRecord(record) {
+			continue
+		}
+		row := parsedGradeRow{
+			RowNumber: index + 2,
+			Email:     strings.ToLower(csvValue(record, header["email"])),
+		}
+		if value := csvValue(record, header["score"]); value != "" {
+			score, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				row.Errors = append(row.Errors, "Score must be a number.")
+			} else {
+				row.Score = &score
+			}
+		}
+		if hasFeedback {
+			feedback := csvValue(record, feedbackIndex)
+			row.Feedback = &feedback
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("invalid grade csv: file has no data rows")
+	}
+	return rows, nil
+}
+
+// validateGradeRows matches rows to the roster by email and works out the
+// action of each. Rows of one group submission must agree on the grade.
+func validateGradeRows(roster []dto.GradingRosterRow, rows []parsedGradeRow) *dto.GradeImportPreviewResponseDTO {
+	students := make(map[string]dto.GradingRosterRow, len(roster))
+	for _, student := range roster {
+		students[strings.ToLower(student.Email)] = student
+	}
+	emailCounts := map[string]int{}
+	for _, row := range rows {
+		if row.Email != "" {
+			emailCounts[row.Email]++
+		}
+	}
+
+	type submissionGrade struct {
+		score    float64
+		feedback string
+	}
+	grades := map[string]submissionGrade{}
+	conflicts := map[string]bool{}
+
+	response := &dto.GradeImportPreviewResponseDTO{
+		Rows: make([]dto.GradeImportRowDTO, 0, len(rows)),
+	}
+	for _, row := range rows {
+		item := dto.GradeImportRowDTO{
+			RowNumber: row.RowNumber,
+			Email:     row.Email,
+			Score:     row.Score,
+			Errors:    append([]string{}, row.Errors...),
+		}
+		student, onRoster := students[row.Email]
+		if row.Email == "" {
+			item.Errors = append(item.Errors, "Email is required.")
+		} else if !onRoster {
+			item.Errors = append(item.Errors, "Student is not enrolled in this class.")
+		}
+		if emailCounts[row.Email] > 1 {
+			item.Errors = append(item.Errors, "Duplicate email in the import file.")
+		}
+
+		if onRoster {
+			item.FullName = student.FullName
+			item.SubmissionID = stringValue(student.SubmissionID)
+			item.CurrentScore = student.Score
+			item.CurrentFeedback = stringValue(student.Feedback)
+		}
+		item.Feedback = item.CurrentFeedback
+		if row.Feedback != nil {
+			item.Feedback = *row.Feedback
+		}
+
+		switch {
+		case row.Score == nil:
+			item.Action = gradeImportSkip
+		case *row.Score < 0 || *row.Score > 100:
+			item.Errors = append(item.Errors, "Score must be between 0 and 100.")
+		case onRoster && student.SubmissionID == nil:
+			item.Errors = append(item.Errors, "Student has no submission to grade.")
+		case onRoster:
+			if grade, ok := grades[item.SubmissionID]; ok {
+				if grade.score != *row.Score || grade.feedback != item.Feedback {
+					conflicts[item.SubmissionID] = true
+				}
+			} else {
+				grades[item.SubmissionID] = submissionGrade{score: *row.Score, feedback: item.Feedback}
+			}
+			switch {
+			case item.CurrentScore == nil:
+				item.Action = gradeImportCreate
+			case *item.CurrentScore == *row.Score && item.CurrentFeedback == item.Feedback:
+				item.Action = gradeImportUnchanged
+			default:
+				item.Action = gradeImportUpdate
+			}
+		}
+		response.Rows = append(response.Rows, item)
+	}
+
+	for i := range response.Rows {
+		item := &response.Rows[i]
+		if item.SubmissionID != "" && conflicts[item.SubmissionID] && item.Score != nil {
+			item.Errors = append(item.Errors, "Rows of the same group submission have different grades.")
+		}
+		if len(item.Errors) > 0 {
+			item.Status = "invalid"
+			response.InvalidCount++
+			continue
+		}
+		item.Status = "valid"
+		response.ValidCount++
+		switch item.Action {
+		case gradeImportCreate:
+			response.CreateCount++
+		case gradeImportUpdate:
+			response.UpdateCount++
+		}
+	}
+	return response
+}
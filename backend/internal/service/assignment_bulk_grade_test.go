@@ -0,0 +1,109 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"testing"
+)
+
+type bulkGradeRubricRepositoryStub struct {
+	repository.RubricRepository
+}
+
+func (bulkGradeRubricRepositoryStub) GetByID(id string) (*domain.Rubric, error) {
+	return &domain.Rubric{ID: id}, nil
+}
+
+func TestEnsureBulkGradableNamesTheBlockingType(t *testing.T) {
+	s := &assignmentService{rubricRepo: bulkGradeRubricRepositoryStub{}}
+	rubricID := "rubric-1"
+	tests := []struct {
+		name       string
+		assignment *domain.Assignment
+		want       string
+	}{
+		{"quiz", &domain.Assignment{Type: domain.AssignmentTypeQuiz}, "bulk grading is not available for quiz assignments"},
+		{"rubric", &domain.Assignment{RubricID: &rubricID}, "bulk grading is not available for rubric assignments"},
+	}
+	for _, tc := range tests {
+		err := s.ensureBulkGradable(tc.assignment)
+		if err == nil || err.Error() != tc.want {
+			t.Fatalf("%s: expected %q, got %v", tc.name, tc.want, err)
+		}
+	}
+	if err := s.ensureBulkGradable(&domain.Assignment{}); err != nil {
+		t.Fatalf("expected a plain assignment to be bulk gradable, got %v", err)
+	}
+}
+
+func TestParseGradeCSV(t *testing.T) {
+	rows, err := parseGradeCSV([]byte("Email,Score\nA@School.id,80\n,\nb@school.id,abc\nc@school.id,\n"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	if rows[0].Email != "a@school.id" || rows[0].Score == nil || *rows[0].Score != 80 || rows[0].Feedback != nil {
+		t.Fatalf("unexpected first row %+v", rows[0])
+	}
+	if rows[1].RowNumber != 4 || len(rows[1].Errors) != 1 {
+		t.Fatalf("expected a score error on row 4, got %+v", rows[1])
+	}
+	if rows[2].Score != nil {
+		t.Fatalf("expected a blank score, got %v", *rows[2].Score)
+	}
+
+	if _, err := parseGradeCSV([]byte("email,feedback\na@school.id,ok\n")); err == nil {
+		t.Fatal("expected an error without a score column")
+	}
+}
+
+func TestValidateGradeRows(t *testing.T) {
+	sbmA, sbmGroup := "sbm-a", "sbm-group"
+	graded, feedback := 70.0, "good"
+	roster := []dto.GradingRosterRow{
+		{Email: "a@school.id", SubmissionID: &sbmA, Score: &graded, Feedback: &feedback},
+		{Email: "b@school.id", SubmissionID: &sbmGroup},
+		{Email: "c@school.id", SubmissionID: &sbmGroup},
+		{Email: "d@school.id"},
+	}
+	score := func(v float64) *float64 { return &v }
+	rows := []parsedGradeRow{
+		{RowNumber: 2, Email: "a@school.id", Score: score(70)},
+		{RowNumber: 3, Email: "b@school.id", Score: score(90)},
+		{RowNumber: 4, Email: "c@school.id", Score: score(85)},
+		{RowNumber: 5, Email: "d@school.id", Score: score(60)},
+		{RowNumber: 6, Email: "x@school.id", Score: score(60)},
+		{RowNumber: 7, Email: "a@school.id"},
+	}
+
+	preview := validateGradeRows(roster, rows)
+	if preview.ValidCount != 0 || preview.InvalidCount != 6 {
+		t.Fatalf("expected every row invalid, got %d valid", preview.ValidCount)
+	}
+	if got := preview.Rows[1].Errors; len(got) != 1 {
+		t.Fatalf("expected a group conflict on row 3, got %v", got)
+	}
+	if got := preview.Rows[3].Errors; len(got) != 1 {
+		t.Fatalf("expected a missing submission on row 5, got %v", got)
+	}
+
+	rows = []parsedGradeRow{
+		{RowNumber: 2, Email: "a@school.id", Score: score(70)},
+		{RowNumber: 3, Email: "b@school.id", Score: score(90)},
+		{RowNumber: 4, Email: "c@school.id", Score: score(90)},
+		{RowNumber: 5, Email: "d@school.id"},
+	}
+	preview = validateGradeRows(roster, rows)
+	if preview.InvalidCount != 0 || preview.CreateCount != 2 || preview.UpdateCount != 0 {
+		t.Fatalf("unexpected counts %+v", preview)
+	}
+	if preview.Rows[0].Action != gradeImportUnchanged || preview.Rows[0].Feedback != "good" {
+		t.Fatalf("expected the kept grade unchanged, got %+v", preview.Rows[0])
+	}
+	if preview.Rows[3].Action != gradeImportSkip {
+		t.Fatalf("expected a blank score skipped, got %q", preview.Rows[3].Action)
+	}
+}
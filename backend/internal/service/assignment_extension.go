@@ -0,0 +1,210 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RequestExtension files a student's request for more time. A student can have
+// one pending request per assignment; a new request may follow once it has
+// been reviewed.
+func (s *assignmentService) RequestExtension(assignment *domain.Assignment, userID string, reason string) (*domain.AssignmentExtension, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("assignment extension reason is required")
+	}
+	if assignment.Deadline == nil {
+		return nil, fmt.Errorf("assignment has no deadline to extend")
+	}
+	pending, err := s.repo.HasPendingExtension(assignment.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, fmt.Errorf("assignment extension request is still pending")
+	}
+
+	ext := domain.AssignmentExtension{
+		SchoolID:     assignment.SchoolID,
+		AssignmentID: assignment.ID,
+		UserID:       userID,
+		Reason:       reason,
+		Status:       domain.AssignmentExtensionPending,
+	}
+	if err := s.repo.CreateExtension(&ext); err != nil {
+		return nil, err
+	}
+
+	// Best-effort: notify the teacher of the subject class
+	if teacherID, err := s.repo.GetSubjectClassTeacherUserID(assignment.SubjectClassID); err == nil && teacherID != "" {
+		_ = s.notifService.Create(&dto.CreateNotificationDTO{
+			UserID:    teacherID,
+			Type:      domain.NotifExtensionRequested,
+			Title:     "Permintaan perpanjangan tenggat",
+			Message:   assignment.Title,
+			Link:      fmt.Sprintf("/teacher/subjects/%s/assignments/%s", assignment.SubjectClassID, assignment.ID),
+			RelatedID: ext.ID,
+		})
+	}
+
+	return s.repo.GetExtensionByID(ext.ID)
+}
+
+func (s *assignmentService) GetExtensionByID(id string) (*domain.AssignmentExtension, error) {
+	return s.repo.GetExtensionByID(id)
+}
+
+// GetMyExtension returns the student's latest request, or nil when there is
+// none.
+func (s *assignmentService) GetMyExtension(assignmentID string, userID string) (*domain.AssignmentExtension, error) {
+	ext, err := s.repo.GetLatestExtension(assignmentID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ext, nil
+}
+
+func (s *assignmentService) ListExtensions(assignmentID string, status string) ([]*domain.AssignmentExtension, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	switch status {
+	case "", domain.AssignmentExtensionPending, domain.AssignmentExtensionApproved, domain.AssignmentExtensionRejected:
+	default:
+		return nil, fmt.Errorf("invalid assignment extension status")
+	}
+	return s.repo.ListExtensionsByAssignment(assignmentID, status)
+}
+
+// ReviewExtension approves or rejects a pending request. An approval needs an
+// extended deadline later than both the assignment deadline and now.
+func (s *assignmentService) ReviewExtension(ext *domain.AssignmentExtension, assignment *domain.Assignment, reviewerID string, input dto.ReviewAssignmentExtensionDTO) (*domain.AssignmentExtension, error) {
+	if ext.Status != domain.AssignmentExtensionPending {
+		return nil, fmt.Errorf("assignment extension request is no longer pending")
+	}
+
+	now := time.Now()
+	ext.Status = input.Status
+	ext.ExtendedDeadline = nil
+	if input.Status == domain.AssignmentExtensionApproved {
+		if err := validateExtendedDeadline(input.ExtendedDeadline, assignment.Deadline, now); err != nil {
+			return nil, err
+		}
+		ext.ExtendedDeadline = input.ExtendedDeadline
+	}
+	if input.ReviewNote != nil {
+		note := strings.TrimSpace(*input.ReviewNote)
+		if note != "" {
+			ext.ReviewNote = &note
+		}
+	}
+	ext.ReviewedBy = &reviewerID
+	ext.ReviewedAt = &now
+
+	if err := s.repo.ReviewExtension(ext); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("assignment extension request is no longer pending")
+		}
+		return nil, err
+	}
+
+	title := "Perpanjangan tenggat ditolak"
+	if ext.Status == domain.AssignmentExtensionApproved {
+		title = "Perpanjangan tenggat disetujui"
+	}
+	_ = s.notifService.Create(&dto.CreateNotificationDTO{
+		UserID:    ext.UserID,
+		Type:      domain.NotifExtensionReviewed,
+		Title:     title,
+		Message:   assignment.Title,
+		Link:      fmt.Sprintf("/student/subjects/%s/assignments/%s", assignment.SubjectClassID, assignment.ID),
+		RelatedID: ext.ID,
+	})
+
+	return s.repo.GetExtensionByID(ext.ID)
+}
+
+// GetEffectiveDeadline returns the deadline that applies to one student.
+func (s *assignmentService) GetEffectiveDeadline(assignment *domain.Assignment, userID string) (*time.Time, error) {
+	if assignment.Deadline == nil {
+		return nil, nil
+	}
+	extended, err := s.extendedDeadlines([]string{assignment.ID}, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	return effectiveDeadline(assignment.Deadline, extended[assignment.ID][userID]), nil
+}
+
+// markLateSubmissions sets IsLate on the submissions of assignments against
+// each student's effective deadline.
+func (s *assignmentService) markLateSubmissions(assignments ...*domain.Assignment) error {
+	assignmentIDs := make([]string, 0, len(assignments))
+	for _, asg := range assignments {
+		if asg.Deadline != nil && len(asg.Submissions) > 0 {
+			assignmentIDs = append(assignmentIDs, asg.ID)
+		}
+	}
+	extended, err := s.extendedDeadlines(assignmentIDs, nil)
+	if err != nil {
+		return err
+	}
+	for _, asg := range assignments {
+		for i := range asg.Submissions {
+			sbm := &asg.Submissions[i]
+			deadline := effectiveDeadline(asg.Deadline, extended[asg.ID][sbm.UserID])
+			sbm.IsLate = deadline != nil && sbm.SubmittedAt.After(*deadline)
+		}
+	}
+	return nil
+}
+
+// extendedDeadlines maps assignment ID and student ID to the deadline of the
+// student's latest approved extension.
+func (s *assignmentService) extendedDeadlines(assignmentIDs []string, userIDs []string) (map[string]map[string]*time.Time, error) {
+	result := make(map[string]map[string]*time.Time)
+	extensions, err := s.repo.ListApprovedExtensions(assignmentIDs, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ext := range extensions {
+		if result[ext.AssignmentID] == nil {
+			result[ext.AssignmentID] = make(map[string]*time.Time)
+		}
+		// Extensions are ordered newest review first.
+		if _, seen := result[ext.AssignmentID][ext.UserID]; !seen {
+			result[ext.AssignmentID][ext.UserID] = ext.ExtendedDeadline
+		}
+	}
+	return result, nil
+}
+
+// effectiveDeadline is the later of the assignment deadline and the student's
+// extended deadline, so moving the assignment deadline past an extension
+// still benefits that student.
+func effectiveDeadline(deadline *time.Time, extended *time.Time) *time.Time {
+	if deadline == nil {
+		return nil
+	}
+	if extended != nil && extended.After(*deadline) {
+		return extended
+	}
+	return deadline
+}
+
+func validateExtendedDeadline(extended *time.Time, deadline *time.Time, now time.Time) error {
+	if extended == nil {
+		return fmt.Errorf("extended deadline is required to approve an extension")
+	}
+	if !extended.After(now) || (deadline != nil && !extended.After(*deadline)) {
+		return fmt.Errorf("extended deadline must be after the assignment deadline and in the future")
+	}
+	return nil
+}
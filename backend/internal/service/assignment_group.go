@@ -0,0 +1,114 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"errors"
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// prepareGroupSubmission points a group-mode turn-in at the submission of the
+// actor's group. The first member to turn in owns the submission; later
+// turn-ins by any member update it.
+func (s *assignmentService) prepareGroupSubmission(sbm *domain.Submission, assignment *domain.Assignment) error {
+	membership, err := s.groupRepo.GetMembership(assignment.SubjectClassID, sbm.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("student is not in a group")
+		}
+		return err
+	}
+
+	existing, err := s.repo.GetGroupSubmission(assignment.ID, membership.GroupID)
+	switch {
+	case err == nil:
+		sbm.UserID = existing.UserID
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Students who moved groups after their old group turned in stay
+		// counted there.
+		submitted, err := s.repo.StudentHasAssignmentSubmission(assignment.ID, sbm.UserID)
+		if err != nil {
+			return err
+		}
+		if submitted {
+			return fmt.Errorf("student already belongs to another group submission")
+		}
+	default:
+		return err
+	}
+	sbm.GroupID = &membership.GroupID
+	return nil
+}
+
+// UpdateGroupScoreAdjustments sets individual adjustments to the group score
+// for members of a group submission. Members are not listed while grading is
+// anonymous, so adjustments wait until identities are released.
+func (s *assignmentService) UpdateGroupScoreAdjustments(sbm *domain.Submission, input []dto.GroupScoreAdjustmentDTO) error {
+	if sbm.GroupID == nil {
+		return fmt.Errorf("submission is not a group submission")
+	}
+	assignment, err := s.repo.GetAssignmentByID(sbm.AssignmentID)
+	if err != nil {
+		return err
+	}
+	if identitiesHidden(assignment) {
+		return fmt.Errorf("score adjustments are not available while identities are hidden")
+	}
+	counted := make(map[string]bool, len(sbm.Members))
+	for _, member := range sbm.Members {
+		counted[member.UserID] = true
+	}
+
+	members := make([]domain.GroupSubmissionMember, 0, len(input))
+	for _, item := range input {
+		if !counted[item.UserID] {
+			return fmt.Errorf("invalid group submission member")
+		}
+		members = append(members, domain.GroupSubmissionMember{
+			UserID:          item.UserID,
+			ScoreAdjustment: *item.Adjustment,
+			AdjustmentNote:  item.Note,
+		})
+	}
+	return s.repo.UpdateGroupScoreAdjustments(sbm.ID, members)
+}
+
+// submissionStudentIDs returns the students a submission counts for.
+func submissionStudentIDs(sbm *domain.Submission) []string {
+	if sbm.GroupID == nil {
+		return []string{sbm.UserID}
+	}
+	userIDs := make([]string, 0, len(sbm.Members))
+	for _, member := range sbm.Members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	return userIDs
+}
+
+// scoreGroupMembers fills in the score of every member of a graded group
+// submission.
+func scoreGroupMembers(sbm *domain.Submission) {
+	if sbm.Assessment == nil {
+		return
+	}
+	for i := range sbm.Members {
+		score := groupMemberScore(sbm.Assessment.Score, sbm.Members[i].ScoreAdjustment)
+		sbm.Members[i].Score = &score
+	}
+}
+
+func (s *assignmentService) IsSubmissionGroupMember(sbm *domain.Submission, userID string) (bool, error) {
+	if sbm.GroupID == nil {
+		return false, nil
+	}
+	return s.groupRepo.IsMember(*sbm.GroupID, userID)
+}
+
+// groupMemberScore returns a member's score on a group submission: the group
+// score plus the member's individual adjustment, kept within 0 and 100.
+func groupMemberScore(groupScore float64, adjustment float64) float64 {
+	return math.Max(0, math.Min(100, groupScore+adjustment))
+}
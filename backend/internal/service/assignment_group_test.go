@@ -0,0 +1,44 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"testing"
+)
+
+func TestGroupMemberScore(t *testing.T) {
+	cases := []struct {
+		name       string
+		score      float64
+		adjustment float64
+		expected   float64
+	}{
+		{"no adjustment", 80, 0, 80},
+		{"bonus", 80, 5.5, 85.5},
+		{"deduction", 80, -30, 50},
+		{"capped at 100", 95, 10, 100},
+		{"floored at 0", 20, -50, 0},
+	}
+	for _, tc := range cases {
+		if got := groupMemberScore(tc.score, tc.adjustment); got != tc.expected {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestSubmissionStudentIDs(t *testing.T) {
+	individual := &domain.Submission{UserID: "owner"}
+	if got := submissionStudentIDs(individual); len(got) != 1 || got[0] != "owner" {
+		t.Fatalf("expected the owner only, got %v", got)
+	}
+
+	groupID := "group"
+	group := &domain.Submission{
+		UserID:  "owner",
+		GroupID: &groupID,
+		Members: []domain.GroupSubmissionMember{{UserID: "owner"}, {UserID: "teammate"}},
+	}
+	got := submissionStudentIDs(group)
+	if len(got) != 2 || got[0] != "owner" || got[1] != "teammate" {
+		t.Fatalf("expected every counted member, got %v", got)
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GetLatePolicy returns the policy that applies to the assignment, which is
+// its own policy or else the policy of its category. It is nil when neither
+// has one.
+func (s *assignmentService) GetLatePolicy(assignment *domain.Assignment) (*domain.LatePolicy, error) {
+	policy, err := s.repo.GetEffectiveLatePolicy(assignment.ID, assignment.CategoryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return policy, err
+}
+
+func (s *assignmentService) SaveAssignmentLatePolicy(assignment *domain.Assignment, input dto.LatePolicyDTO, actorUserID string) (*domain.LatePolicy, error) {
+	policy := buildLatePolicy(input, assignment.SchoolID, actorUserID)
+	policy.AssignmentID = &assignment.ID
+	if err := s.repo.SaveLatePolicy(policy); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAssignmentLatePolicy(assignment.ID)
+}
+
+func (s *assignmentService) DeleteAssignmentLatePolicy(assignmentID string) error {
+	return s.repo.DeleteAssignmentLatePolicy(assignmentID)
+}
+
+func (s *assignmentService) GetCategoryLatePolicy(categoryID string, schoolID string) (*domain.LatePolicy, error) {
+	if err := s.validateAssignmentCategory(categoryID, schoolID); err != nil {
+		return nil, err
+	}
+	policy, err := s.repo.GetCategoryLatePolicy(categoryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return policy, err
+}
+
+func (s *assignmentService) SaveCategoryLatePolicy(categoryID string, schoolID string, input dto.LatePolicyDTO, actorUserID string) (*domain.LatePolicy, error) {
+	if err := s.validateAssignmentCategory(categoryID, schoolID); err != nil {
+		return nil, err
+	}
+	policy := buildLatePolicy(input, schoolID, actorUserID)
+	policy.CategoryID = &categoryID
+	if err := s.repo.SaveLatePolicy(policy); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCategoryLatePolicy(categoryID)
+}
+
+func (s *assignmentService) DeleteCategoryLatePolicy(categoryID string, schoolID string) error {
+	if err := s.validateAssignmentCategory(categoryID, schoolID); err != nil {
+		return err
+	}
+	return s.repo.DeleteCategoryLatePolicy(categoryID)
+}
+
+// GetLatePenalty returns the late penalty of a submission with its raw and
+// final score when graded, or nil when it has no penalty.
+func (s *assignmentService) GetLatePenalty(sbm *domain.Submission) (*dto.LatePenaltyDTO, error) {
+	rows, err := s.repo.ListLatePenaltyInputs([]string{sbm.ID})
+	if err != nil {
+		return nil, err
+	}
+	penalty := latePenaltiesBySubmission(rows)[sbm.ID]
+	if penalty != nil && sbm.Assessment != nil {
+		rawScore := sbm.Assessment.Score
+		finalScore := applyLatePenalty(rawScore, penalty)
+		penalty.RawScore = &rawScore
+		penalty.FinalScore = &finalScore
+	}
+	return penalty, nil
+}
+
+// SetLatePenaltyWaiver waives, or restores, the late penalty of a
+// submission.
+func (s *assignmentService) SetLatePenaltyWaiver(sbm *domain.Submission, waived bool, actorUserID string) error {
+	sbm.PenaltyWaived = waived
+	sbm.PenaltyWaivedBy = nil
+	sbm.PenaltyWaivedAt = nil
+	if waived {
+		now := time.Now()
+		sbm.PenaltyWaivedBy = &actorUserID
+		sbm.PenaltyWaivedAt = &now
+	}
+	return s.repo.SetSubmissionPenaltyWaived(sbm)
+}
+
+func buildLatePolicy(input dto.LatePolicyDTO, schoolID string, actorUserID string) *domain.LatePolicy {
+	return &domain.LatePolicy{
+		SchoolID:      schoolID,
+		PercentPerDay: *input.PercentPerDay,
+		MaxPercent:    input.MaxPercent,
+		GraceMinutes:  input.GraceMinutes,
+		ZeroAfterDays: input.ZeroAfterDays,
+		UpdatedBy:     actorUserID,
+	}
+}
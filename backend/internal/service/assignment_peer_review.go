@@ -0,0 +1,376 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultPeerReviewMaxRating is the scale of rating questions that do not
+// set one.
+const defaultPeerReviewMaxRating = 5
+
+// GetPeerReview returns the peer review of the assignment, or nil when it has
+// none.
+func (s *assignmentService) GetPeerReview(assignment *domain.Assignment) (*domain.PeerReviewSetting, error) {
+	setting, err := s.repo.GetPeerReviewSetting(assignment.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return setting, err
+}
+
+// SavePeerReview turns on or changes the peer review of a file assignment.
+// The form and the number of reviewers cannot change once the reviews are
+// allocated.
+func (s *assignmentService) SavePeerReview(assignment *domain.Assignment, input dto.PeerReviewSettingsDTO, actorUserID string) (*domain.PeerReviewSetting, error) {
+	if err := validatePeerReviewAssignment(assignment); err != nil {
+		return nil, err
+	}
+	if !input.ReviewDeadline.After(*assignment.Deadline) {
+		return nil, fmt.Errorf("review deadline must be after the assignment deadline")
+	}
+	current, err := s.GetPeerReview(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	setting := &domain.PeerReviewSetting{
+		SchoolID:               assignment.SchoolID,
+		AssignmentID:           assignment.ID,
+		ReviewersPerSubmission: input.ReviewersPerSubmission,
+		ReviewDeadline:         *input.ReviewDeadline,
+		UpdatedBy:              actorUserID,
+		Questions:              buildPeerReviewQuestions(input.Questions),
+	}
+	if input.CompletionWeight != nil {
+		setting.CompletionWeight = *input.CompletionWeight
+	}
+	replaceQuestions := true
+	if current != nil && current.AllocatedAt != nil {
+		if current.ReviewersPerSubmission != setting.ReviewersPerSubmission || !samePeerReviewForm(current.Questions, setting.Questions) {
+			return nil, fmt.Errorf("peer review form is locked after allocation")
+		}
+		replaceQuestions = false
+	}
+
+	if err := s.repo.SavePeerReviewSetting(setting, replaceQuestions); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPeerReviewSetting(assignment.ID)
+}
+
+func (s *assignmentService) DeletePeerReview(assignmentID string) error {
+	return s.repo.DeletePeerReviewSetting(assignmentID)
+}
+
+// AllocatePeerReviews assigns the reviewers of an assignment right away
+// instead of waiting for the background job after the deadline.
+func (s *assignmentService) AllocatePeerReviews(assignment *domain.Assignment) (*domain.PeerReviewSetting, int, error) {
+	if err := validatePeerReviewAssignment(assignment); err != nil {
+		return nil, 0, err
+	}
+	setting, err := s.repo.GetPeerReviewSetting(assignment.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if setting.AllocatedAt != nil {
+		return nil, 0, fmt.Errorf("peer review is already allocated")
+	}
+	submissions, err := s.repo.ListPeerReviewableSubmissions(assignment.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(submissions) < 2 {
+		return nil, 0, fmt.Errorf("peer review needs at least two submissions")
+	}
+
+	count, err := s.allocatePeerReviews(setting, assignment, submissions, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+	setting, err = s.repo.GetPeerReviewSetting(assignment.ID)
+	return setting, count, err
+}
+
+// AllocateDuePeerReviews allocates the reviewers of every assignment whose
+// deadline has passed and returns how many assignments were allocated. With
+// fewer than two submissions there is nobody to review and the peer review
+// is closed without reviews.
+func (s *assignmentService) AllocateDuePeerReviews(now time.Time) (int, error) {
+	settings, err := s.repo.ListDuePeerReviewSettings(now)
+	if err != nil {
+		return 0, err
+	}
+
+	allocated := 0
+	var errs []error
+	for _, setting := range settings {
+		assignment, err := s.repo.GetAssignmentByID(setting.AssignmentID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var submissions []*domain.Submission
+		if validatePeerReviewAssignment(assignment) == nil {
+			submissions, err = s.repo.ListPeerReviewableSubmissions(assignment.ID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		if _, err := s.allocatePeerReviews(setting, assignment, submissions, now); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		allocated++
+	}
+	return allocated, errors.Join(errs...)
+}
+
+func (s *assignmentService) allocatePeerReviews(setting *domain.PeerReviewSetting, assignment *domain.Assignment, submissions []*domain.Submission, now time.Time) (int, error) {
+	rng := rand.New(rand.NewSource(now.UnixNano()))
+	reviews := allocatePeerReviewers(submissions, setting.ReviewersPerSubmission, rng.Shuffle)
+	for i := range reviews {
+		reviews[i].SchoolID = assignment.SchoolID
+		reviews[i].AssignmentID = assignment.ID
+		reviews[i].Status = domain.PeerReviewAssigned
+	}
+
+	allocated, err := s.repo.AllocatePeerReviews(setting.ID, reviews, now)
+	if err != nil {
+		return 0, err
+	}
+	if !allocated {
+		return 0, fmt.Errorf("peer review is already allocated")
+	}
+
+	// Best-effort: tell every reviewer once that reviews are waiting.
+	notified := make(map[string]bool, len(reviews))
+	for _, review := range reviews {
+		if notified[review.ReviewerID] {
+			continue
+		}
+		notified[review.ReviewerID] = true
+		_ = s.notifService.Create(&dto.CreateNotificationDTO{
+			UserID:    review.ReviewerID,
+			Type:      domain.NotifPeerReviewAssigned,
+			Title:     "Tugas peer review baru",
+			Message:   assignment.Title,
+			Link:      fmt.Sprintf("/student/subjects/%s/assignments/%s", assignment.SubjectClassID, assignment.ID),
+			RelatedID: assignment.ID,
+		})
+	}
+	return len(reviews), nil
+}
+
+func (s *assignmentService) ListPeerReviews(assignment *domain.Assignment) ([]*domain.PeerReview, error) {
+	return s.repo.ListPeerReviewsByAssignment(assignment.ID)
+}
+
+func (s *assignmentService) ListMyPeerReviews(assignment *domain.Assignment, userID string) ([]*domain.PeerReview, error) {
+	return s.repo.ListPeerReviewsByReviewer(assignment.ID, userID)
+}
+
+func (s *assignmentService) GetPeerReviewByID(id string) (*domain.PeerReview, error) {
+	return s.repo.GetPeerReviewByID(id)
+}
+
+// GetPeerReviewSubmission loads the work a reviewer was assigned, with its
+// attachments.
+func (s *assignmentService) GetPeerReviewSubmission(review *domain.PeerReview, userID string) (*domain.Submission, error) {
+	if review.ReviewerID != userID {
+		return nil, fmt.Errorf("forbidden: peer review belongs to another student")
+	}
+	return s.GetSubmissionByID(review.SubmissionID)
+}
+
+// SubmitPeerReview stores the answers of a review. Reviewers can change
+// their answers until the review deadline.
+func (s *assignmentService) SubmitPeerReview(review *domain.PeerReview, setting *domain.PeerReviewSetting, userID string, input dto.SubmitPeerReviewDTO) (*domain.PeerReview, error) {
+	if review.ReviewerID != userID {
+		return nil, fmt.Errorf("forbidden: peer review belongs to another student")
+	}
+	now := time.Now()
+	if !now.Before(setting.ReviewDeadline) {
+		return nil, fmt.Errorf("peer review deadline has passed")
+	}
+	answers, err := buildPeerReviewAnswers(setting.Questions, input.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Status = domain.PeerReviewSubmitted
+	review.SubmittedAt = &now
+	if err := s.repo.SubmitPeerReview(review, answers); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPeerReviewByID(review.ID)
+}
+
+// ListReceivedPeerReviews returns the submitted reviews of the student's own
+// work on the assignment.
+func (s *assignmentService) ListReceivedPeerReviews(assignment *domain.Assignment, userID string) ([]*domain.PeerReview, error) {
+	sbm, err := s.repo.GetMySubmissionByAssignment(assignment.ID, userID, assignment.SchoolID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []*domain.PeerReview{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListReceivedPeerReviews(sbm.ID)
+}
+
+func validatePeerReviewAssignment(assignment *domain.Assignment) error {
+	if assignment.Type == domain.AssignmentTypeQuiz {
+		return fmt.Errorf("peer review is not available for quiz assignments")
+	}
+	if assignment.GroupMode {
+		return fmt.Errorf("peer review is not available for group assignments")
+	}
+	if assignment.Deadline == nil {
+		return fmt.Errorf("peer review requires an assignment deadline")
+	}
+	return nil
+}
+
+// allocatePeerReviewers gives every submission perSubmission reviewers among
+// the other authors. Submissions are shuffled into a circle and each one is
+// reviewed by the authors of the next perSubmission submissions, so everyone
+// also writes perSubmission reviews and nobody reviews their own work.
+func allocatePeerReviewers(submissions []*domain.Submission, perSubmission int, shuffle func(n int, swap func(i, j int))) []domain.PeerReview {
+	count := len(submissions)
+	if perSubmission > count-1 {
+		perSubmission = count - 1
+	}
+	if perSubmission <= 0 {
+		return nil
+	}
+
+	circle := append([]*domain.Submission(nil), submissions...)
+	shuffle(count, func(i, j int) {
+		circle[i], circle[j] = circle[j], circle[i]
+	})
+
+	reviews := make([]domain.PeerReview, 0, count*perSubmission)
+	for i, sbm := range circle {
+		for offset := 1; offset <= perSubmission; offset++ {
+			reviewer := circle[(i+offset)%count]
+			reviews = append(reviews, domain.PeerReview{
+				SubmissionID: sbm.ID,
+				ReviewerID:   reviewer.UserID,
+			})
+		}
+	}
+	return reviews
+}
+
+func buildPeerReviewQuestions(input []dto.PeerReviewQuestionInputDTO) []domain.PeerReviewQuestion {
+	questions := make([]domain.PeerReviewQuestion, 0, len(input))
+	for i, item := range input {
+		question := domain.PeerReviewQuestion{
+			Position: i + 1,
+			Prompt:   strings.TrimSpace(item.Prompt),
+			Type:     item.Type,
+			Required: item.Required,
+		}
+		if item.Type == domain.PeerReviewQuestionRating {
+			maxRating := defaultPeerReviewMaxRating
+			if item.MaxRating != nil {
+				maxRating = *item.MaxRating
+			}
+			question.MaxRating = &maxRating
+		}
+		questions = append(questions, question)
+	}
+	return questions
+}
+
+func samePeerReviewForm(current []domain.PeerReviewQuestion, next []domain.PeerReviewQuestion) bool {
+	if len(current) != len(next) {
+		return false
+	}
+	for i := range current {
+		if current[i].Prompt != next[i].Prompt ||
+			current[i].Type != next[i].Type ||
+			current[i].Required != next[i].Required ||
+			!sameOptionalInt(current[i].MaxRating, next[i].MaxRating) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildPeerReviewAnswers checks the answers against the form. Ratings must
+// be on the question's scale and every required question needs an answer.
+func buildPeerReviewAnswers(questions []domain.PeerReviewQuestion, input []dto.PeerReviewAnswerDTO) ([]domain.PeerReviewAnswer, error) {
+	byID := make(map[string]domain.PeerReviewQuestion, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+
+	answered := make(map[string]bool, len(input))
+	answers := make([]domain.PeerReviewAnswer, 0, len(input))
+	for _, item := range input {
+		question, ok := byID[item.QuestionID]
+		if !ok {
+			return nil, fmt.Errorf("invalid peer review question")
+		}
+		if answered[item.QuestionID] {
+			return nil, fmt.Errorf("duplicate peer review answer")
+		}
+
+		answer := domain.PeerReviewAnswer{QuestionID: question.ID}
+		switch question.Type {
+		case domain.PeerReviewQuestionRating:
+			if item.Rating == nil {
+				continue
+			}
+			if *item.Rating < 1 || question.MaxRating == nil || *item.Rating > *question.MaxRating {
+				return nil, fmt.Errorf("peer review rating is out of range")
+			}
+			rating := *item.Rating
+			answer.Rating = &rating
+		default:
+			if item.Text == nil || strings.TrimSpace(*item.Text) == "" {
+				continue
+			}
+			text := strings.TrimSpace(*item.Text)
+			answer.Text = &text
+		}
+		answered[item.QuestionID] = true
+		answers = append(answers, answer)
+	}
+
+	for _, question := range questions {
+		if question.Required && !answered[question.ID] {
+			return nil, fmt.Errorf("peer review answer is required")
+		}
+	}
+	return answers, nil
+}
+
+// peerReviewCompletionRate is the share of assigned reviews a student
+// submitted. A student without assigned reviews has nothing left to do.
+func peerReviewCompletionRate(completion *dto.PeerReviewCompletionDTO) float64 {
+	if completion.Assigned == 0 {
+		return 1
+	}
+	return float64(completion.Completed) / float64(completion.Assigned)
+}
+
+// applyPeerReviewCompletion blends the completion rate of the student's
+// reviews into an assignment score according to the completion weight.
+func applyPeerReviewCompletion(score float64, completion *dto.PeerReviewCompletionDTO) float64 {
+	if completion == nil || completion.Weight <= 0 {
+		return score
+	}
+	weight := completion.Weight / 100
+	return score*(1-weight) + peerReviewCompletionRate(completion)*100*weight
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestAllocatePeerReviewers(t *testing.T) {
+	submissions := make([]*domain.Submission, 0, 5)
+	for i := 0; i < 5; i++ {
+		submissions = append(submissions, &domain.Submission{
+			ID:     fmt.Sprintf("sbm-%d", i),
+			UserID: fmt.Sprintf("usr-%d", i),
+		})
+	}
+	author := make(map[string]string, len(submissions))
+	for _, sbm := range submissions {
+		author[sbm.ID] = sbm.UserID
+	}
+
+	rng := rand.New(rand.NewSource(7))
+	reviews := allocatePeerReviewers(submissions, 2, rng.Shuffle)
+	if len(reviews) != 10 {
+		t.Fatalf("expected 10 reviews, got %d", len(reviews))
+	}
+
+	received := make(map[string]int)
+	written := make(map[string]int)
+	pairs := make(map[string]bool)
+	for _, review := range reviews {
+		if author[review.SubmissionID] == review.ReviewerID {
+			t.Fatalf("%s reviews their own submission", review.ReviewerID)
+		}
+		pair := review.SubmissionID + "/" + review.ReviewerID
+		if pairs[pair] {
+			t.Fatalf("duplicate review %s", pair)
+		}
+		pairs[pair] = true
+		received[review.SubmissionID]++
+		written[review.ReviewerID]++
+	}
+	for _, sbm := range submissions {
+		if received[sbm.ID] != 2 || written[sbm.UserID] != 2 {
+			t.Fatalf("%s: expected 2 reviews each way, got %d received and %d written", sbm.ID, received[sbm.ID], written[sbm.UserID])
+		}
+	}
+}
+
+func TestAllocatePeerReviewersCapsReviewers(t *testing.T) {
+	submissions := []*domain.Submission{{ID: "a", UserID: "ua"}, {ID: "b", UserID: "ub"}}
+	rng := rand.New(rand.NewSource(1))
+	if got := allocatePeerReviewers(submissions, 3, rng.Shuffle); len(got) != 2 {
+		t.Fatalf("expected one reviewer per submission, got %d reviews", len(got))
+	}
+	if got := allocatePeerReviewers(submissions[:1], 3, rng.Shuffle); len(got) != 0 {
+		t.Fatalf("expected no reviews for a single submission, got %d", len(got))
+	}
+}
+
+func TestBuildPeerReviewAnswers(t *testing.T) {
+	maxRating := 5
+	questions := []domain.PeerReviewQuestion{
+		{ID: "rating", Type: domain.PeerReviewQuestionRating, MaxRating: &maxRating, Required: true},
+		{ID: "comment", Type: domain.PeerReviewQuestionText},
+	}
+	rating := 4
+	outOfRange := 6
+	blank := "  "
+
+	answers, err := buildPeerReviewAnswers(questions, []dto.PeerReviewAnswerDTO{
+		{QuestionID: "rating", Rating: &rating},
+		{QuestionID: "comment", Text: &blank},
+	})
+	if err != nil || len(answers) != 1 {
+		t.Fatalf("expected the rating answer only, got %v, %v", answers, err)
+	}
+	if _, err := buildPeerReviewAnswers(questions, []dto.PeerReviewAnswerDTO{{QuestionID: "rating", Rating: &outOfRange}}); err == nil {
+		t.Fatal("expected an out of range rating to fail")
+	}
+	if _, err := buildPeerReviewAnswers(questions, nil); err == nil {
+		t.Fatal("expected a missing required answer to fail")
+	}
+	if _, err := buildPeerReviewAnswers(questions, []dto.PeerReviewAnswerDTO{{QuestionID: "other", Rating: &rating}}); err == nil {
+		t.Fatal("expected an unknown question to fail")
+	}
+}
+
+func TestApplyPeerReviewCompletion(t *testing.T) {
+	cases := []struct {
+		name       string
+		completion *dto.PeerReviewCompletionDTO
+		expected   float64
+	}{
+		{"not counted", nil, 80},
+		{"all reviews done", &dto.PeerReviewCompletionDTO{Assigned: 2, Completed: 2, Weight: 10}, 82},
+		{"half the reviews", &dto.PeerReviewCompletionDTO{Assigned: 2, Completed: 1, Weight: 10}, 77},
+		{"no reviews done", &dto.PeerReviewCompletionDTO{Assigned: 2, Completed: 0, Weight: 20}, 64},
+		{"nothing assigned", &dto.PeerReviewCompletionDTO{Weight: 20}, 84},
+	}
+	for _, tc := range cases {
+		if got := applyPeerReviewCompletion(80, tc.completion); got < tc.expected-1e-9 || got > tc.expected+1e-9 {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
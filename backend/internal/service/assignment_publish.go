@@ -0,0 +1,59 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PublishDueAssignments publishes scheduled assignments whose publish time has
+// passed and notifies their students. It returns how many were published.
+func (s *assignmentService) PublishDueAssignments(now time.Time) (int, error) {
+	assignments, err := s.repo.ListDueScheduledAssignments(now)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var errs []error
+	for _, asg := range assignments {
+		ok, err := s.repo.PublishAssignment(asg.ID, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		asg.PublishStatus = domain.PublishStatusPublished
+		asg.PublishedAt = &now
+		s.notifyAssignmentPublished(asg)
+		published++
+	}
+	return published, errors.Join(errs...)
+}
+
+// notifyAssignmentPublished tells the students of the class about a newly
+// published assignment. Best-effort: failures are ignored.
+func (s *assignmentService) notifyAssignmentPublished(asg *domain.Assignment) {
+	classID, err := s.repo.GetClassIDBySubjectClass(asg.SubjectClassID)
+	if err != nil || classID == "" {
+		return
+	}
+	userIDs, err := s.enrRepo.GetStudentUserIDsByClass(classID)
+	if err != nil {
+		return
+	}
+	for _, uid := range userIDs {
+		_ = s.notifService.Create(&dto.CreateNotificationDTO{
+			UserID:    uid,
+			Type:      domain.NotifAssignmentCreated,
+			Title:     "Tugas baru",
+			Message:   asg.Title,
+			Link:      fmt.Sprintf("/student/subjects/%s/assignments/%s", asg.SubjectClassID, asg.ID),
+			RelatedID: asg.ID,
+		})
+	}
+}
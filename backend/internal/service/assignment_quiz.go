@@ -0,0 +1,767 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// numericAnswerEpsilon absorbs float rounding when comparing numeric answers.
+const numericAnswerEpsilon = 1e-9
+
+func (s *assignmentService) GetQuiz(assignment *domain.Assignment) (*dto.QuizResponseDTO, error) {
+	if assignment.Type != domain.AssignmentTypeQuiz {
+		return nil, fmt.Errorf("assignment is not a quiz")
+	}
+	questions, err := s.quizQuestions(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.CountQuizAttempts(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.QuizResponseDTO{
+		AssignmentID:     assignment.ID,
+		TimeLimitMinutes: assignment.TimeLimitMinutes,
+		ShuffleQuestions: assignment.ShuffleQuestions,
+		ShuffleOptions:   assignment.ShuffleOptions,
+		AttemptCount:     attempts,
+		Questions:        make([]dto.QuestionResponseDTO, 0, len(questions)),
+	}
+	for i := range questions {
+		response.TotalPoints += questions[i].Points
+		response.Questions = append(response.Questions, mapQuestionResponse(&questions[i]))
+	}
+	return response, nil
+}
+
+// ReplaceQuiz changes the settings and questions of a quiz. A quiz is locked
+// once a student has started it, so every attempt answers the same questions.
+func (s *assignmentService) ReplaceQuiz(assignment *domain.Assignment, input dto.QuizSettingsDTO) (*dto.QuizResponseDTO, error) {
+	if assignment.Type != domain.AssignmentTypeQuiz {
+		return nil, fmt.Errorf("assignment is not a quiz")
+	}
+	attempts, err := s.repo.CountQuizAttempts(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > 0 {
+		return nil, fmt.Errorf("quiz is locked by student attempts")
+	}
+	questionIDs, err := s.validateQuizQuestions(input.QuestionIDs, assignment.SubjectClassID, assignment.SchoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyQuizSettings(assignment, input)
+	if err := s.repo.ReplaceQuiz(assignment, questionIDs); err != nil {
+		return nil, err
+	}
+	return s.GetQuiz(assignment)
+}
+
+func (s *assignmentService) GetQuizAttemptByID(id string) (*domain.QuizAttempt, error) {
+	return s.repo.GetQuizAttemptByID(id)
+}
+
+// StartQuiz opens the student's attempt, or returns the one already started.
+// Questions and options are drawn in a random order per student when the quiz
+// shuffles them.
+func (s *assignmentService) StartQuiz(assignment *domain.Assignment, userID string) (*dto.QuizAttemptResponseDTO, error) {
+	if assignment.Type != domain.AssignmentTypeQuiz {
+		return nil, fmt.Errorf("assignment is not a quiz")
+	}
+	questions, err := s.quizQuestions(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := s.repo.GetQuizAttempt(assignment.ID, userID)
+	if err == nil {
+		return s.quizAttemptResponse(attempt, assignment, questions, false)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	deadline, err := s.GetEffectiveDeadline(assignment, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !assignment.AllowLateSubmission && deadline != nil && deadline.Before(now) {
+		return nil, fmt.Errorf("submission past due")
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("quiz has no questions")
+	}
+
+	rng := rand.New(rand.NewSource(now.UnixNano()))
+	questionOrder, optionOrder := drawQuizOrder(questions, assignment.ShuffleQuestions, assignment.ShuffleOptions, rng.Shuffle)
+	encodedQuestions, err := json.Marshal(questionOrder)
+	if err != nil {
+		return nil, err
+	}
+	encodedOptions, err := json.Marshal(optionOrder)
+	if err != nil {
+		return nil, err
+	}
+	attempt = &domain.QuizAttempt{
+		SchoolID:      assignment.SchoolID,
+		AssignmentID:  assignment.ID,
+		UserID:        userID,
+		Status:        domain.QuizAttemptInProgress,
+		QuestionOrder: string(encodedQuestions),
+		OptionOrder:   string(encodedOptions),
+		StartedAt:     now,
+	}
+	if assignment.TimeLimitMinutes != nil {
+		expiresAt := now.Add(time.Duration(*assignment.TimeLimitMinutes) * time.Minute)
+		attempt.ExpiresAt = &expiresAt
+	}
+	// A concurrent start keeps the first attempt; both requests return it.
+	if _, err := s.repo.CreateQuizAttempt(attempt); err != nil {
+		return nil, err
+	}
+	attempt, err = s.repo.GetQuizAttempt(assignment.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.quizAttemptResponse(attempt, assignment, questions, false)
+}
+
+// GetMyQuizAttempt returns the student's attempt, or nil before the quiz is
+// started.
+func (s *assignmentService) GetMyQuizAttempt(assignment *domain.Assignment, userID string) (*dto.QuizAttemptResponseDTO, error) {
+	if assignment.Type != domain.AssignmentTypeQuiz {
+		return nil, fmt.Errorf("assignment is not a quiz")
+	}
+	attempt, err := s.repo.GetQuizAttempt(assignment.ID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	questions, err := s.quizQuestions(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.quizAttemptResponse(attempt, assignment, questions, false)
+}
+
+// SaveQuizAnswers stores answers of an attempt in progress. Answers can be
+// saved any number of times until the attempt is submitted or times out.
+func (s *assignmentService) SaveQuizAnswers(attempt *domain.QuizAttempt, assignment *domain.Assignment, userID string, input dto.SaveQuizAnswersDTO) (*dto.QuizAttemptResponseDTO, error) {
+	if attempt.UserID != userID {
+		return nil, fmt.Errorf("forbidden: quiz attempt access denied")
+	}
+	questions, err := s.quizQuestions(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	attempt, err = s.closeExpiredQuizAttempt(attempt, assignment, questions, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != domain.QuizAttemptInProgress {
+		if attempt.ExpiresAt != nil && !time.Now().Before(*attempt.ExpiresAt) {
+			return nil, fmt.Errorf("quiz attempt time is over")
+		}
+		return nil, fmt.Errorf("quiz attempt is already submitted")
+	}
+
+	byID := quizQuestionsByID(attemptQuestions(attempt, questions))
+	answers := make([]domain.QuizAnswer, 0, len(input.Answers))
+	seen := make(map[string]bool, len(input.Answers))
+	for _, item := range input.Answers {
+		questionID := strings.ToLower(item.QuestionID)
+		question, ok := byID[questionID]
+		if !ok {
+			return nil, fmt.Errorf("invalid quiz answer: question is not part of the quiz")
+		}
+		if seen[questionID] {
+			return nil, fmt.Errorf("invalid quiz answer: question answered twice")
+		}
+		seen[questionID] = true
+		answer, err := buildQuizAnswer(question, item)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, answer)
+	}
+
+	if err := s.repo.SaveQuizAnswers(attempt.ID, answers); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("quiz attempt is already submitted")
+		}
+		return nil, err
+	}
+	attempt, err = s.repo.GetQuizAttemptByID(attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.quizAttemptResponse(attempt, assignment, questions, false)
+}
+
+// SubmitQuizAttempt hands in an attempt. Objective answers are scored at once;
+// when no essay is left to grade, the score is written as the assessment of
+// the student's submission.
+func (s *assignmentService) SubmitQuizAttempt(attempt *domain.QuizAttempt, assignment *domain.Assignment, userID string) (*dto.QuizAttemptResponseDTO, error) {
+	if attempt.UserID != userID {
+		return nil, fmt.Errorf("forbidden: quiz attempt access denied")
+	}
+	questions, err := s.quizQuestions(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	attempt, err = s.closeExpiredQuizAttempt(attempt, assignment, questions, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status == domain.QuizAttemptInProgress {
+		// A concurrent submit already closed the attempt; return its result.
+		if err := s.finalizeQuizAttempt(attempt, assignment, questions, time.Now()); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if attempt, err = s.repo.GetQuizAttemptByID(attempt.ID); err != nil {
+			return nil, err
+		}
+	}
+	return s.quizAttemptResponse(attempt, assignment, questions, false)
+}
+
+func (s *assignmentService) ListQuizAttempts(assignment *domain.Assignment) ([]dto.QuizAttemptResponseDTO, error) {
+	if assignment.Type != domain.AssignmentTypeQuiz {
+		return nil, fmt.Errorf("assignment is not a quiz")
+	}
+	questions, err := s.quizQuestions(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.ListQuizAttempts(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.QuizAttemptResponseDTO, 0, len(attempts))
+	for _, attempt := range attempts {
+		response, err := s.quizAttemptResponse(attempt, assignment, questions, true)
+		if err != nil {
+			return nil, err
+		}
+		response.Questions = nil
+		result = append(result, *response)
+	}
+	return result, nil
+}
+
+// GetQuizAttemptResult returns an attempt with the answer key, for teachers.
+func (s *assignmentService) GetQuizAttemptResult(attempt *domain.QuizAttempt, assignment *domain.Assignment) (*dto.QuizAttemptResponseDTO, error) {
+	questions, err := s.quizQuestions(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.quizAttemptResponse(attempt, assignment, questions, true)
+}
+
+// GradeQuizAttempt records the teacher's points for essays, or overrides the
+// automatic points of any answer. Once every answer has points the quiz score
+// is written as the assessment of the submission.
+func (s *assignmentService) GradeQuizAttempt(attempt *domain.QuizAttempt, assignment *domain.Assignment, graderID string, input dto.GradeQuizAttemptDTO) (*dto.QuizAttemptResponseDTO, error) {
+	questions, err := s.quizQuestions(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	attempt, err = s.closeExpiredQuizAttempt(attempt, assignment, questions, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status == domain.QuizAttemptInProgress || attempt.SubmissionID == nil {
+		return nil, fmt.Errorf("quiz attempt is still in progress")
+	}
+
+	ordered := attemptQuestions(attempt, questions)
+	byID := quizQuestionsByID(ordered)
+	answers := quizAnswersByQuestion(attempt.Answers)
+	now := time.Now()
+	seen := make(map[string]bool, len(input.Answers))
+	for _, item := range input.Answers {
+		questionID := strings.ToLower(item.QuestionID)
+		question, ok := byID[questionID]
+		if !ok {
+			return nil, fmt.Errorf("invalid quiz grade: question is not part of the quiz")
+		}
+		if seen[questionID] {
+			return nil, fmt.Errorf("invalid quiz grade: question graded twice")
+		}
+		seen[questionID] = true
+		points := *item.Points
+		if points < 0 || points > question.Points+numericAnswerEpsilon || math.IsNaN(points) {
+			return nil, fmt.Errorf("invalid quiz grade: points exceed question points")
+		}
+
+		answer, ok := answers[question.ID]
+		if !ok {
+			answer = &domain.QuizAnswer{QuestionID: question.ID, OptionIDs: "[]"}
+			answers[question.ID] = answer
+		}
+		correct := points >= question.Points
+		answer.Points = &points
+		answer.IsCorrect = &correct
+		answer.Feedback = nil
+		if feedback := strings.TrimSpace(item.Feedback); feedback != "" {
+			answer.Feedback = &feedback
+		}
+		answer.GradedBy = &graderID
+		answer.GradedAt = &now
+	}
+
+	attempt.Answers = make([]domain.QuizAnswer, 0, len(ordered))
+	for _, question := range ordered {
+		if answer, ok := answers[question.ID]; ok {
+			attempt.Answers = append(attempt.Answers, *answer)
+		}
+	}
+	attempt.Status = quizAttemptStatus(ordered, attempt.Answers)
+	if err := s.repo.GradeQuizAttempt(attempt); err != nil {
+		return nil, err
+	}
+
+	if attempt.Status == domain.QuizAttemptGraded {
+		asm := &domain.Assessment{
+			SubmissionID: *attempt.SubmissionID,
+			AssessedBy:   graderID,
+		}
+		if input.Feedback != nil {
+			asm.Feedback = strings.TrimSpace(*input.Feedback)
+		} else if existing, err := s.repo.GetAssessmentBySubmission(asm.SubmissionID); err == nil {
+			asm.Feedback = existing.Feedback
+		}
+		earned, maxPoints, _ := quizAttemptTotals(ordered, attempt.Answers)
+		asm.Score = percentScore(earned, maxPoints)
+		if err := s.saveAssessment(asm); err != nil {
+			return nil, err
+		}
+	}
+
+	attempt, err = s.repo.GetQuizAttemptByID(attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.quizAttemptResponse(attempt, assignment, questions, true)
+}
+
+// closeExpiredQuizAttempt submits an attempt whose time ran out as of its
+// expiry, with the answers saved until then.
+func (s *assignmentService) closeExpiredQuizAttempt(attempt *domain.QuizAttempt, assignment *domain.Assignment, questions []domain.Question, now time.Time) (*domain.QuizAttempt, error) {
+	if attempt.Status != domain.QuizAttemptInProgress || attempt.ExpiresAt == nil || now.Before(*attempt.ExpiresAt) {
+		return attempt, nil
+	}
+	if err := s.finalizeQuizAttempt(attempt, assignment, questions, *attempt.ExpiresAt); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return s.repo.GetQuizAttemptByID(attempt.ID)
+}
+
+// finalizeQuizAttempt scores the attempt, stores the student's submission and,
+// when nothing is left to grade by hand, the assessment. A concurrent submit
+// surfaces as gorm.ErrRecordNotFound.
+func (s *assignmentService) finalizeQuizAttempt(attempt *domain.QuizAttempt, assignment *domain.Assignment, questions []domain.Question, submittedAt time.Time) error {
+	ordered := attemptQuestions(attempt, questions)
+	saved := quizAnswersByQuestion(attempt.Answers)
+	answers := make([]domain.QuizAnswer, 0, len(ordered))
+	for _, question := range ordered {
+		answer := domain.QuizAnswer{QuestionID: question.ID, OptionIDs: "[]"}
+		if existing, ok := saved[question.ID]; ok {
+			answer = *existing
+		}
+		gradeQuizAnswer(question, &answer)
+		answers = append(answers, answer)
+	}
+	attempt.Answers = answers
+	attempt.SubmittedAt = &submittedAt
+	attempt.Status = quizAttemptStatus(ordered, answers)
+
+	sbm := domain.Submission{
+		SchoolID:     attempt.SchoolID,
+		AssignmentID: attempt.AssignmentID,
+		UserID:       attempt.UserID,
+		SubmittedAt:  submittedAt,
+	}
+	if err := s.repo.SubmitQuizAttempt(attempt, &sbm); err != nil {
+		return err
+	}
+
+	if attempt.Status != domain.QuizAttemptGraded {
+		return nil
+	}
+	earned, maxPoints, _ := quizAttemptTotals(ordered, answers)
+	return s.saveAssessment(&domain.Assessment{
+		SubmissionID: sbm.ID,
+		Score:        percentScore(earned, maxPoints),
+		AssessedBy:   assignment.CreatedBy,
+	})
+}
+
+// quizAttemptResponse closes a timed-out attempt first, so both students and
+// teachers see it as submitted.
+func (s *assignmentService) quizAttemptResponse(attempt *domain.QuizAttempt, assignment *domain.Assignment, questions []domain.Question, teacherView bool) (*dto.QuizAttemptResponseDTO, error) {
+	attempt, err := s.closeExpiredQuizAttempt(attempt, assignment, questions, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	response := mapQuizAttempt(attempt, attemptQuestions(attempt, questions), teacherView)
+	if teacherView {
+		response.StudentID, response.StudentName = s.AnonymizeStudent(assignment, response.StudentID, response.StudentName)
+	}
+	return response, nil
+}
+
+func (s *assignmentService) quizQuestions(assignmentID string) ([]domain.Question, error) {
+	rows, err := s.repo.ListQuizQuestions(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	questions := make([]domain.Question, 0, len(rows))
+	for _, row := range rows {
+		questions = append(questions, row.Question)
+	}
+	return questions, nil
+}
+
+// validateQuizQuestions checks that every question belongs to the question
+// bank of the subject taught in subjectClassID and returns the IDs in order.
+func (s *assignmentService) validateQuizQuestions(ids []string, subjectClassID string, schoolID string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("quiz settings are required")
+	}
+	subjectID, err := s.repo.GetSubjectIDBySubjectClass(subjectClassID)
+	if err != nil {
+		return nil, err
+	}
+
+	questionIDs := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if seen[id] {
+			return nil, fmt.Errorf("invalid quiz questions")
+		}
+		seen[id] = true
+		questionIDs = append(questionIDs, id)
+	}
+	questions, err := s.questionRepo.GetByIDs(questionIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(questions) != len(questionIDs) {
+		return nil, fmt.Errorf("invalid quiz questions")
+	}
+	for _, question := range questions {
+		if question.SchoolID != schoolID || question.SubjectID != subjectID {
+			return nil, fmt.Errorf("invalid quiz questions")
+		}
+	}
+	return questionIDs, nil
+}
+
+func applyQuizSettings(asg *domain.Assignment, input dto.QuizSettingsDTO) {
+	asg.Type = domain.AssignmentTypeQuiz
+	asg.TimeLimitMinutes = input.TimeLimitMinutes
+	asg.ShuffleQuestions = input.ShuffleQuestions
+	asg.ShuffleOptions = input.ShuffleOptions
+}
+
+// drawQuizOrder returns the question order of a new attempt and the option
+// order of each choice question.
+func drawQuizOrder(questions []domain.Question, shuffleQuestions bool, shuffleOptions bool, shuffle func(n int, swap func(i, j int))) ([]string, map[string][]string) {
+	questionOrder := make([]string, 0, len(questions))
+	optionOrder := make(map[string][]string)
+	for _, question := range questions {
+		questionOrder = append(questionOrder, question.ID)
+		if len(question.Options) == 0 {
+			continue
+		}
+		options := make([]string, 0, len(question.Options))
+		for _, option := range question.Options {
+			options = append(options, option.ID)
+		}
+		if shuffleOptions {
+			shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+		}
+		optionOrder[question.ID] = options
+	}
+	if shuffleQuestions {
+		shuffle(len(questionOrder), func(i, j int) { questionOrder[i], questionOrder[j] = questionOrder[j], questionOrder[i] })
+	}
+	return questionOrder, optionOrder
+}
+
+// attemptQuestions lays questions out in the order drawn for an attempt,
+// options included.
+func attemptQuestions(attempt *domain.QuizAttempt, questions []domain.Question) []domain.Question {
+	order := decodeStringList(attempt.QuestionOrder)
+	optionOrder := make(map[string][]string)
+	if strings.TrimSpace(attempt.OptionOrder) != "" {
+		_ = json.Unmarshal([]byte(attempt.OptionOrder), &optionOrder)
+	}
+
+	byID := quizQuestionsByID(questions)
+	ordered := make([]domain.Question, 0, len(order))
+	for _, id := range order {
+		question, ok := byID[id]
+		if !ok {
+			continue
+		}
+		if ids, ok := optionOrder[id]; ok {
+			options := make(map[string]domain.QuestionOption, len(question.Options))
+			for _, option := range question.Options {
+				options[option.ID] = option
+			}
+			question.Options = make([]domain.QuestionOption, 0, len(ids))
+			for _, optionID := range ids {
+				if option, ok := options[optionID]; ok {
+					question.Options = append(question.Options, option)
+				}
+			}
+		}
+		ordered = append(ordered, question)
+	}
+	return ordered
+}
+
+func quizQuestionsByID(questions []domain.Question) map[string]domain.Question {
+	result := make(map[string]domain.Question, len(questions))
+	for _, question := range questions {
+		result[question.ID] = question
+	}
+	return result
+}
+
+func quizAnswersByQuestion(answers []domain.QuizAnswer) map[string]*domain.QuizAnswer {
+	result := make(map[string]*domain.QuizAnswer, len(answers))
+	for i := range answers {
+		result[answers[i].QuestionID] = &answers[i]
+	}
+	return result
+}
+
+// buildQuizAnswer keeps the answer field of the question type only.
+func buildQuizAnswer(question domain.Question, input dto.QuizAnswerInputDTO) (domain.QuizAnswer, error) {
+	answer := domain.QuizAnswer{QuestionID: question.ID, OptionIDs: "[]"}
+	switch question.Type {
+	case domain.QuestionMultipleChoice, domain.QuestionMultiSelect:
+		valid := make(map[string]bool, len(question.Options))
+		for _, option := range question.Options {
+			valid[option.ID] = true
+		}
+		selected := make([]string, 0, len(input.OptionIDs))
+		seen := make(map[string]bool, len(input.OptionIDs))
+		for _, id := range input.OptionIDs {
+			id = strings.ToLower(id)
+			if !valid[id] {
+				return answer, fmt.Errorf("invalid quiz answer: option does not belong to question")
+			}
+			if !seen[id] {
+				seen[id] = true
+				selected = append(selected, id)
+			}
+		}
+		if question.Type == domain.QuestionMultipleChoice && len(selected) > 1 {
+			return answer, fmt.Errorf("invalid quiz answer: multiple choice takes one option")
+		}
+		encoded, err := json.Marshal(selected)
+		if err != nil {
+			return answer, err
+		}
+		answer.OptionIDs = string(encoded)
+	case domain.QuestionTrueFalse:
+		answer.BooleanAnswer = input.Boolean
+	case domain.QuestionNumeric:
+		answer.Number = input.Number
+	case domain.QuestionShortAnswer, domain.QuestionEssay:
+		answer.Text = input.Text
+	}
+	return answer, nil
+}
+
+// gradeQuizAnswer scores an answer automatically: full points when correct,
+// none otherwise. Essays are left for the teacher unless blank.
+func gradeQuizAnswer(question domain.Question, answer *domain.QuizAnswer) {
+	var correct bool
+	switch question.Type {
+	case domain.QuestionMultipleChoice, domain.QuestionMultiSelect:
+		expected := make(map[string]bool)
+		for _, option := range question.Options {
+			if option.IsCorrect {
+				expected[option.ID] = true
+			}
+		}
+		selected := decodeStringList(answer.OptionIDs)
+		correct = len(selected) == len(expected)
+		for _, id := range selected {
+			correct = correct && expected[id]
+		}
+	case domain.QuestionTrueFalse:
+		correct = answer.BooleanAnswer != nil && question.BooleanAnswer != nil && *answer.BooleanAnswer == *question.BooleanAnswer
+	case domain.QuestionShortAnswer:
+		correct = answer.Text != nil && matchesAcceptedAnswer(*answer.Text, decodeStringList(question.AcceptedAnswers), question.CaseSensitive)
+	case domain.QuestionNumeric:
+		correct = answer.Number != nil && question.NumericAnswer != nil &&
+			math.Abs(*answer.Number-*question.NumericAnswer) <= question.NumericTolerance+numericAnswerEpsilon
+	case domain.QuestionEssay:
+		if answer.Text != nil && strings.TrimSpace(*answer.Text) != "" {
+			answer.Points = nil
+			answer.IsCorrect = nil
+			return
+		}
+	}
+
+	var points float64
+	if correct {
+		points = question.Points
+	}
+	answer.Points = &points
+	answer.IsCorrect = &correct
+}
+
+// matchesAcceptedAnswer compares answers with surrounding and repeated
+// whitespace ignored.
+func matchesAcceptedAnswer(text string, accepted []string, caseSensitive bool) bool {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return false
+	}
+	for _, candidate := range accepted {
+		if caseSensitive && text == candidate {
+			return true
+		}
+		if !caseSensitive && strings.EqualFold(text, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func quizAttemptStatus(questions []domain.Question, answers []domain.QuizAnswer) string {
+	if _, _, pending := quizAttemptTotals(questions, answers); pending > 0 {
+		return domain.QuizAttemptNeedsGrading
+	}
+	return domain.QuizAttemptGraded
+}
+
+// quizAttemptTotals sums the points earned and available over questions.
+// Pending counts answers still waiting for points; unanswered questions of a
+// submitted attempt always carry points.
+func quizAttemptTotals(questions []domain.Question, answers []domain.QuizAnswer) (float64, float64, int) {
+	byQuestion := quizAnswersByQuestion(answers)
+	var earned, maxPoints float64
+	pending := 0
+	for _, question := range questions {
+		maxPoints += question.Points
+		answer, ok := byQuestion[question.ID]
+		if !ok {
+			continue
+		}
+		if answer.Points == nil {
+			pending++
+			continue
+		}
+		earned += *answer.Points
+	}
+	return earned, maxPoints, pending
+}
+
+// percentScore puts earned points on the 0-100 scale used by the gradebook,
+// rounded to two decimals.
+func percentScore(earned float64, maxPoints float64) float64 {
+	if maxPoints <= 0 {
+		return 0
+	}
+	return math.Round(earned/maxPoints*10000) / 100
+}
+
+// mapQuizAttempt hides points until the attempt is submitted and the answer
+// key from students.
+func mapQuizAttempt(attempt *domain.QuizAttempt, questions []domain.Question, teacherView bool) *dto.QuizAttemptResponseDTO {
+	submitted := attempt.Status != domain.QuizAttemptInProgress
+	response := &dto.QuizAttemptResponseDTO{
+		AttemptID:    attempt.ID,
+		AssignmentID: attempt.AssignmentID,
+		StudentID:    attempt.UserID,
+		StudentName:  attempt.User.FullName,
+		SubmissionID: attempt.SubmissionID,
+		Status:       attempt.Status,
+		StartedAt:    formatAPITime(attempt.StartedAt),
+		ExpiresAt:    formatAPITimePtr(attempt.ExpiresAt),
+		SubmittedAt:  formatAPITimePtr(attempt.SubmittedAt),
+		Questions:    make([]dto.QuizAttemptQuestionDTO, 0, len(questions)),
+	}
+	earned, maxPoints, pending := quizAttemptTotals(questions, attempt.Answers)
+	response.MaxPoints = maxPoints
+	if submitted {
+		response.EarnedPoints = earned
+		response.PendingCount = pending
+	}
+	if attempt.Status == domain.QuizAttemptGraded {
+		score := percentScore(earned, maxPoints)
+		response.Score = &score
+	}
+
+	answers := quizAnswersByQuestion(attempt.Answers)
+	for _, question := range questions {
+		item := dto.QuizAttemptQuestionDTO{
+			QuestionID: question.ID,
+			Type:       question.Type,
+			Prompt:     question.Prompt,
+			Points:     question.Points,
+			Options:    make([]dto.QuizAttemptOptionDTO, 0, len(question.Options)),
+		}
+		for _, option := range question.Options {
+			mapped := dto.QuizAttemptOptionDTO{OptionID: option.ID, Text: option.Text}
+			if teacherView {
+				isCorrect := option.IsCorrect
+				mapped.IsCorrect = &isCorrect
+			}
+			item.Options = append(item.Options, mapped)
+		}
+		if teacherView {
+			item.BooleanAnswer = question.BooleanAnswer
+			item.NumericAnswer = question.NumericAnswer
+			if question.Type == domain.QuestionShortAnswer {
+				item.AcceptedAnswers = decodeStringList(question.AcceptedAnswers)
+			}
+			if question.Type == domain.QuestionNumeric {
+				tolerance := question.NumericTolerance
+				item.NumericTolerance = &tolerance
+			}
+		}
+		if answer, ok := answers[question.ID]; ok {
+			mapped := &dto.QuizAnswerDTO{
+				OptionIDs: decodeStringList(answer.OptionIDs),
+				Text:      answer.Text,
+				Number:    answer.Number,
+				Boolean:   answer.BooleanAnswer,
+			}
+			if submitted {
+				mapped.Points = answer.Points
+				mapped.IsCorrect = answer.IsCorrect
+				mapped.Feedback = answer.Feedback
+			}
+			item.Answer = mapped
+		}
+		response.Questions = append(response.Questions, item)
+	}
+	return response
+}
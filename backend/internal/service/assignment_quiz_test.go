@@ -0,0 +1,74 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"testing"
+)
+
+func TestGradeQuizAnswer(t *testing.T) {
+	truth := true
+	answer := 2.5
+	numeric := domain.Question{Type: domain.QuestionNumeric, Points: 4, NumericAnswer: &answer, NumericTolerance: 0.1}
+	multi := domain.Question{Type: domain.QuestionMultiSelect, Points: 2, Options: []domain.QuestionOption{
+		{ID: "a", IsCorrect: true}, {ID: "b", IsCorrect: true}, {ID: "c"},
+	}}
+
+	cases := []struct {
+		name     string
+		question domain.Question
+		answer   domain.QuizAnswer
+		points   float64
+	}{
+		{"numeric within tolerance", numeric, domain.QuizAnswer{Number: floatPtr(2.6)}, 4},
+		{"numeric outside tolerance", numeric, domain.QuizAnswer{Number: floatPtr(2.7)}, 0},
+		{"multi-select all correct", multi, domain.QuizAnswer{OptionIDs: `["b","a"]`}, 2},
+		{"multi-select partly correct", multi, domain.QuizAnswer{OptionIDs: `["a"]`}, 0},
+		{"true/false", domain.Question{Type: domain.QuestionTrueFalse, Points: 1, BooleanAnswer: &truth}, domain.QuizAnswer{BooleanAnswer: &truth}, 1},
+		{"short answer", domain.Question{Type: domain.QuestionShortAnswer, Points: 3, AcceptedAnswers: `["Ibu Kota"]`}, domain.QuizAnswer{Text: stringPtr("  ibu   kota ")}, 3},
+		{"blank essay", domain.Question{Type: domain.QuestionEssay, Points: 5}, domain.QuizAnswer{}, 0},
+	}
+	for _, tc := range cases {
+		gradeQuizAnswer(tc.question, &tc.answer)
+		if tc.answer.Points == nil || *tc.answer.Points != tc.points {
+			t.Fatalf("%s: expected %v points, got %v", tc.name, tc.points, tc.answer.Points)
+		}
+	}
+
+	essay := domain.QuizAnswer{Text: stringPtr("Jawaban panjang")}
+	gradeQuizAnswer(domain.Question{Type: domain.QuestionEssay, Points: 5}, &essay)
+	if essay.Points != nil || essay.IsCorrect != nil {
+		t.Fatalf("expected an answered essay to wait for manual grading")
+	}
+}
+
+func TestDrawQuizOrder(t *testing.T) {
+	questions := []domain.Question{
+		{ID: "q1", Options: []domain.QuestionOption{{ID: "o1"}, {ID: "o2"}}},
+		{ID: "q2"},
+	}
+	reverse := func(n int, swap func(i, j int)) {
+		for i := 0; i < n/2; i++ {
+			swap(i, n-1-i)
+		}
+	}
+
+	questionOrder, optionOrder := drawQuizOrder(questions, true, false, reverse)
+	if questionOrder[0] != "q2" || questionOrder[1] != "q1" {
+		t.Fatalf("expected shuffled questions, got %v", questionOrder)
+	}
+	if optionOrder["q1"][0] != "o1" {
+		t.Fatalf("expected options to keep their order, got %v", optionOrder["q1"])
+	}
+
+	questionOrder, optionOrder = drawQuizOrder(questions, false, true, reverse)
+	if questionOrder[0] != "q1" || optionOrder["q1"][0] != "o2" {
+		t.Fatalf("expected only options to be shuffled, got %v %v", questionOrder, optionOrder)
+	}
+	if _, ok := optionOrder["q2"]; ok {
+		t.Fatalf("expected no option order for a question without options")
+	}
+}
+
+func floatPtr(value float64) *float64 { return &value }
+
+func stringPtr(value string) *string { return &value }
@@ -6,6 +6,8 @@ import (
 	"backend/internal/repository"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -17,8 +19,8 @@ type AssignmentService interface {
 	GetCategoriesBySchool(schoolID string) ([]*domain.AssignmentCategory, error)
 
 	// Assignment
-	CreateAssignment(asg *domain.Assignment, mediaIDs []string, actorUserID string, isAdmin bool) error
-	GetAssignmentsBySubjectClass(subjectClassID string, search string, page int, limit int) ([]*domain.Assignment, int64, error)
+	CreateAssignment(asg *domain.Assignment, mediaIDs []string, quiz *dto.QuizSettingsDTO, actorUserID string, isAdmin bool) error
+	GetAssignmentsBySubjectClass(subjectClassID string, search string, publishedOnly bool, page int, limit int) ([]*domain.Assignment, int64, error)
 	GetAssignmentByID(id string) (*domain.Assignment, error)
 	GetAssignmentWithSubmissions(id string) (*domain.Assignment, error)
 	GetSubjectClassSubmissions(subjectClassID string, schoolID string) ([]*domain.Assignment, error)
@@ -26,6 +28,7 @@ type AssignmentService interface {
 	GetTeacherAssignmentInbox(userID string, schoolID string) (*dto.TeacherAssignmentInboxResponseDTO, error)
 	GetStudentAssignmentInbox(userID string, schoolID string) (*dto.StudentAssignmentInboxResponseDTO, error)
 	GetAssignmentStatus(assignmentID string) (map[string]interface{}, error)
+	GetAssignmentRubric(assignment *domain.Assignment) (*domain.Rubric, error)
 	UpdateAssignment(id string, asg *domain.Assignment, mediaIDs []string, actorUserID string, isAdmin bool, validateCategory bool) error
 	DeleteAssignment(id string) error
 
@@ -36,11 +39,72 @@ type AssignmentService interface {
 	GetMySubmissionByAssignment(assignmentID string, userID string, schoolID string) (*domain.Submission, error)
 	UpdateSubmission(id string, mediaIDs []string, actorUserID string, isAdmin bool) error
 	DeleteSubmission(id string) error
+	ListSubmissionVersions(sbm *domain.Submission, assignment *domain.Assignment) ([]*domain.SubmissionVersion, error)
+
+	// Extension
+	RequestExtension(assignment *domain.Assignment, userID string, reason string) (*domain.AssignmentExtension, error)
+	GetExtensionByID(id string) (*domain.AssignmentExtension, error)
+	GetMyExtension(assignmentID string, userID string) (*domain.AssignmentExtension, error)
+	ListExtensions(assignmentID string, status string) ([]*domain.AssignmentExtension, error)
+	ReviewExtension(ext *domain.AssignmentExtension, assignment *domain.Assignment, reviewerID string, input dto.ReviewAssignmentExtensionDTO) (*domain.AssignmentExtension, error)
+	GetEffectiveDeadline(assignment *domain.Assignment, userID string) (*time.Time, error)
+
+	// Quiz
+	GetQuiz(assignment *domain.Assignment) (*dto.QuizResponseDTO, error)
+	ReplaceQuiz(assignment *domain.Assignment, input dto.QuizSettingsDTO) (*dto.QuizResponseDTO, error)
+	GetQuizAttemptByID(id string) (*domain.QuizAttempt, error)
+	StartQuiz(assignment *domain.Assignment, userID string) (*dto.QuizAttemptResponseDTO, error)
+	GetMyQuizAttempt(assignment *domain.Assignment, userID string) (*dto.QuizAttemptResponseDTO, error)
+	SaveQuizAnswers(attempt *domain.QuizAttempt, assignment *domain.Assignment, userID string, input dto.SaveQuizAnswersDTO) (*dto.QuizAttemptResponseDTO, error)
+	SubmitQuizAttempt(attempt *domain.QuizAttempt, assignment *domain.Assignment, userID string) (*dto.QuizAttemptResponseDTO, error)
+	ListQuizAttempts(assignment *domain.Assignment) ([]dto.QuizAttemptResponseDTO, error)
+	GetQuizAttemptResult(attempt *domain.QuizAttempt, assignment *domain.Assignment) (*dto.QuizAttemptResponseDTO, error)
+	GradeQuizAttempt(attempt *domain.QuizAttempt, assignment *domain.Assignment, graderID string, input dto.GradeQuizAttemptDTO) (*dto.QuizAttemptResponseDTO, error)
+
+	// Late policy
+	GetLatePolicy(assignment *domain.Assignment) (*domain.LatePolicy, error)
+	SaveAssignmentLatePolicy(assignment *domain.Assignment, input dto.LatePolicyDTO, actorUserID string) (*domain.LatePolicy, error)
+	DeleteAssignmentLatePolicy(assignmentID string) error
+	GetCategoryLatePolicy(categoryID string, schoolID string) (*domain.LatePolicy, error)
+	SaveCategoryLatePolicy(categoryID string, schoolID string, input dto.LatePolicyDTO, actorUserID string) (*domain.LatePolicy, error)
+	DeleteCategoryLatePolicy(categoryID string, schoolID string) error
+	GetLatePenalty(sbm *domain.Submission) (*dto.LatePenaltyDTO, error)
+	SetLatePenaltyWaiver(sbm *domain.Submission, waived bool, actorUserID string) error
+
+	// Peer review
+	GetPeerReview(assignment *domain.Assignment) (*domain.PeerReviewSetting, error)
+	SavePeerReview(assignment *domain.Assignment, input dto.PeerReviewSettingsDTO, actorUserID string) (*domain.PeerReviewSetting, error)
+	DeletePeerReview(assignmentID string) error
+	AllocatePeerReviews(assignment *domain.Assignment) (*domain.PeerReviewSetting, int, error)
+	AllocateDuePeerReviews(now time.Time) (int, error)
+	ListPeerReviews(assignment *domain.Assignment) ([]*domain.PeerReview, error)
+	ListMyPeerReviews(assignment *domain.Assignment, userID string) ([]*domain.PeerReview, error)
+	GetPeerReviewByID(id string) (*domain.PeerReview, error)
+	GetPeerReviewSubmission(review *domain.PeerReview, userID string) (*domain.Submission, error)
+	SubmitPeerReview(review *domain.PeerReview, setting *domain.PeerReviewSetting, userID string, input dto.SubmitPeerReviewDTO) (*domain.PeerReview, error)
+	ListReceivedPeerReviews(assignment *domain.Assignment, userID string) ([]*domain.PeerReview, error)
+
+	// Publishing
+	PublishDueAssignments(now time.Time) (int, error)
+
+	// Anonymous grading
+	AnonymizeStudent(asg *domain.Assignment, userID string, fullName string) (string, string)
+	AnonymizeSubmission(asg *domain.Assignment, sbm *domain.Submission)
+	ReleaseIdentities(asg *domain.Assignment) error
+
+	// Group submissions
+	UpdateGroupScoreAdjustments(sbm *domain.Submission, input []dto.GroupScoreAdjustmentDTO) error
+	IsSubmissionGroupMember(sbm *domain.Submission, userID string) (bool, error)
 
 	// Assessment
-	Assess(asm *domain.Assessment) error
-	UpdateAssessment(submissionID string, asm *domain.Assessment) error
+	Assess(asm *domain.Assessment, criteria []dto.AssessmentCriterionInputDTO, versionID string) error
+	UpdateAssessment(submissionID string, asm *domain.Assessment, criteria []dto.AssessmentCriterionInputDTO) error
 	DeleteAssessment(submissionID string) error
+
+	// Bulk grading
+	ExportGradesCSV(assignment *domain.Assignment) ([]byte, error)
+	PreviewGradeImport(assignment *domain.Assignment, reader io.Reader) (*dto.GradeImportPreviewResponseDTO, error)
+	CommitGradeImport(assignment *domain.Assignment, rows []dto.GradeImportRowDTO, graderID string) (*dto.GradeImportCommitResponseDTO, error)
 }
 
 type assignmentService struct {
@@ -49,15 +113,23 @@ type assignmentService struct {
 	mediaRepo    repository.MediaRepository
 	notifService NotificationService
 	enrRepo      repository.EnrollmentRepository
+	rubricRepo   repository.RubricRepository
+	questionRepo repository.QuestionRepository
+	groupRepo    repository.StudentGroupRepository
+	realtime     RealtimePublisher
 }
 
-func NewAssignmentService(repo repository.AssignmentRepository, attService AttachmentService, mediaRepo repository.MediaRepository, notifService NotificationService, enrRepo repository.EnrollmentRepository) AssignmentService {
+func NewAssignmentService(repo repository.AssignmentRepository, attService AttachmentService, mediaRepo repository.MediaRepository, notifService NotificationService, enrRepo repository.EnrollmentRepository, rubricRepo repository.RubricRepository, questionRepo repository.QuestionRepository, groupRepo repository.StudentGroupRepository, realtime RealtimePublisher) AssignmentService {
 	return &assignmentService{
 		repo:         repo,
 		attService:   attService,
 		mediaRepo:    mediaRepo,
 		notifService: notifService,
 		enrRepo:      enrRepo,
+		rubricRepo:   rubricRepo,
+		questionRepo: questionRepo,
+		groupRepo:    groupRepo,
+		realtime:     realtime,
 	}
 }
 
@@ -69,10 +141,64 @@ func (s *assignmentService) GetCategoriesBySchool(schoolID string) ([]*domain.As
 	return s.repo.GetCategoriesBySchool(schoolID)
 }
 
-func (s *assignmentService) CreateAssignment(asg *domain.Assignment, mediaIDs []string, actorUserID string, isAdmin bool) error {
+// CreateAssignment creates a file assignment, or a quiz when asg.Type is
+// quiz; quizzes need their settings and questions and cannot use a rubric.
+func (s *assignmentService) CreateAssignment(asg *domain.Assignment, mediaIDs []string, quiz *dto.QuizSettingsDTO, actorUserID string, isAdmin bool) error {
+	if asg.Type == "" {
+		asg.Type = domain.AssignmentTypeFile
+	}
+	if asg.GradingPolicy == "" {
+		asg.GradingPolicy = domain.GradingPolicyLatest
+	}
+	now := time.Now()
+	status, publishAt, err := resolvePublishState(asg.PublishStatus, asg.PublishAt, now)
+	if err != nil {
+		return err
+	}
+	asg.PublishStatus = status
+	asg.PublishAt = publishAt
+	asg.PublishedAt = nil
+	if status == domain.PublishStatusPublished {
+		asg.PublishedAt = &now
+	}
 	if err := s.validateAssignmentCategory(asg.CategoryID, asg.SchoolID); err != nil {
 		return err
 	}
+	if err := s.validateAssignmentRubric(asg.RubricID, asg.SchoolID, actorUserID, isAdmin); err != nil {
+		return err
+	}
+	var questionIDs []string
+	switch {
+	case asg.Type == domain.AssignmentTypeQuiz && quiz == nil:
+		return fmt.Errorf("quiz settings are required")
+	case asg.Type != domain.AssignmentTypeQuiz && quiz != nil:
+		return fmt.Errorf("quiz settings require a quiz assignment")
+	case asg.Type == domain.AssignmentTypeQuiz:
+		if asg.RubricID != nil {
+			return fmt.Errorf("quiz assignments cannot use a rubric")
+		}
+		if asg.MaxAttempts != nil || asg.GradingPolicy != domain.GradingPolicyLatest {
+			return fmt.Errorf("quiz assignments have a single attempt")
+		}
+		if asg.GroupMode {
+			return fmt.Errorf("quiz assignments cannot be group assignments")
+		}
+		if asg.AnonymousGrading {
+			return fmt.Errorf("quiz assignments cannot be graded anonymously")
+		}
+		questionIDs, err = s.validateQuizQuestions(quiz.QuestionIDs, asg.SubjectClassID, asg.SchoolID)
+		if err != nil {
+			return err
+		}
+		applyQuizSettings(asg, *quiz)
+	}
+	asg.AnonymousKey = ""
+	asg.IdentityReleasedAt = nil
+	if asg.AnonymousGrading {
+		if asg.AnonymousKey, err = newAnonymousKey(); err != nil {
+			return err
+		}
+	}
 	attachmentMediaIDs, err := prepareAttachableMediaIDs(s.mediaRepo, mediaIDs, asg.SchoolID, actorUserID, isAdmin)
 	if err != nil {
 		return err
@@ -81,32 +207,24 @@ func (s *assignmentService) CreateAssignment(asg *domain.Assignment, mediaIDs []
 	if err := s.repo.CreateAssignment(asg); err != nil {
 		return err
 	}
+	if asg.Type == domain.AssignmentTypeQuiz {
+		if err := s.repo.ReplaceQuiz(asg, questionIDs); err != nil {
+			return err
+		}
+	}
 
 	if err := replaceSourceAttachments(s.attService, asg.SchoolID, domain.SourceAssignment, asg.ID, attachmentMediaIDs); err != nil {
 		return err
 	}
 
-	// Best-effort: notify students in the class
-	if classID, err := s.repo.GetClassIDBySubjectClass(asg.SubjectClassID); err == nil && classID != "" {
-		if userIDs, err := s.enrRepo.GetStudentUserIDsByClass(classID); err == nil {
-			for _, uid := range userIDs {
-				_ = s.notifService.Create(&dto.CreateNotificationDTO{
-					UserID:    uid,
-					Type:      domain.NotifAssignmentCreated,
-					Title:     "Tugas baru",
-					Message:   asg.Title,
-					Link:      fmt.Sprintf("/student/subjects/%s/assignments/%s", asg.SubjectClassID, asg.ID),
-					RelatedID: asg.ID,
-				})
-			}
-		}
+	if asg.PublishStatus == domain.PublishStatusPublished {
+		s.notifyAssignmentPublished(asg)
 	}
-
 	return nil
 }
 
-func (s *assignmentService) GetAssignmentsBySubjectClass(subjectClassID string, search string, page int, limit int) ([]*domain.Assignment, int64, error) {
-	results, total, err := s.repo.GetAssignmentsBySubjectClass(subjectClassID, search, page, limit)
+func (s *assignmentService) GetAssignmentsBySubjectClass(subjectClassID string, search string, publishedOnly bool, page int, limit int) ([]*domain.Assignment, int64, error) {
+	results, total, err := s.repo.GetAssignmentsBySubjectClass(subjectClassID, search, publishedOnly, page, limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -169,6 +287,9 @@ func (s *assignmentService) GetAssignmentWithSubmissions(id string) (*domain.Ass
 		}
 	}
 
+	if err := s.markLateSubmissions(asg); err != nil {
+		return nil, err
+	}
 	return asg, nil
 }
 
@@ -199,6 +320,9 @@ func (s *assignmentService) GetSubjectClassSubmissions(subjectClassID string, sc
 		}
 	}
 
+	if err := s.markLateSubmissions(assignments...); err != nil {
+		return nil, err
+	}
 	return assignments, nil
 }
 
@@ -216,6 +340,7 @@ func (s *assignmentService) GetTeacherSubmissionInbox(userID string, schoolID st
 	}
 	for _, item := range items {
 		response.Summary.TotalSubmissions += item.SubmissionCount
+		response.Summary.StudentCount += item.StudentCount
 		response.Summary.PendingCount += item.PendingCount
 		response.Summary.GradedCount += item.GradedCount
 		response.Summary.LateCount += item.LateCount
@@ -287,6 +412,9 @@ func (s *assignmentService) GetAssignmentStatus(assignmentID string) (map[string
 	if err != nil {
 		return nil, err
 	}
+	if err := s.markLateSubmissions(asg); err != nil {
+		return nil, err
+	}
 
 	// Get total enrolled students in the class
 	totalStudents, err := s.repo.CountStudentsInClass(asg.SubjectClass.ClassID)
@@ -304,7 +432,7 @@ func (s *assignmentService) GetAssignmentStatus(assignmentID string) (map[string
 		if sub.Assessment != nil {
 			graded++
 		}
-		if asg.Deadline != nil && sub.SubmittedAt.After(*asg.Deadline) {
+		if sub.IsLate {
 			lateSubmissions++
 		}
 	}
@@ -333,6 +461,92 @@ func (s *assignmentService) UpdateAssignment(id string, asg *domain.Assignment,
 			return err
 		}
 	}
+	current, err := s.repo.GetAssignmentByID(id)
+	if err != nil {
+		return err
+	}
+	rubricChanged := !sameOptionalID(current.RubricID, asg.RubricID)
+	if rubricChanged {
+		if current.Type == domain.AssignmentTypeQuiz {
+			return fmt.Errorf("quiz assignments cannot use a rubric")
+		}
+		if err := s.validateAssignmentRubric(asg.RubricID, asg.SchoolID, actorUserID, isAdmin); err != nil {
+			return err
+		}
+		graded, err := s.repo.AssignmentHasRubricScores(id)
+		if err != nil {
+			return err
+		}
+		if graded {
+			return fmt.Errorf("assignment rubric is locked by graded assessments")
+		}
+	}
+	attemptsChanged := !sameOptionalInt(current.MaxAttempts, asg.MaxAttempts)
+	policyChanged := asg.GradingPolicy != "" && asg.GradingPolicy != current.GradingPolicy
+	if current.Type == domain.AssignmentTypeQuiz && (attemptsChanged || policyChanged) {
+		return fmt.Errorf("quiz assignments have a single attempt")
+	}
+	if policyChanged {
+		graded, err := s.repo.AssignmentHasGradedVersions(id)
+		if err != nil {
+			return err
+		}
+		if graded {
+			return fmt.Errorf("grading policy is locked by graded submissions")
+		}
+	}
+	groupModeChanged := asg.GroupMode != current.GroupMode
+	if groupModeChanged {
+		if current.Type == domain.AssignmentTypeQuiz {
+			return fmt.Errorf("quiz assignments cannot be group assignments")
+		}
+		submitted, err := s.repo.AssignmentHasSubmissions(id)
+		if err != nil {
+			return err
+		}
+		if submitted {
+			return fmt.Errorf("group mode cannot change after students have submitted")
+		}
+		if asg.GroupMode {
+			peerReview, err := s.GetPeerReview(current)
+			if err != nil {
+				return err
+			}
+			if peerReview != nil {
+				return fmt.Errorf("peer review is not available for group assignments")
+			}
+		}
+	}
+	anonymousChanged := asg.AnonymousGrading != current.AnonymousGrading
+	if anonymousChanged {
+		if current.Type == domain.AssignmentTypeQuiz {
+			return fmt.Errorf("quiz assignments cannot be graded anonymously")
+		}
+		graded, err := s.repo.AssignmentHasAssessments(id)
+		if err != nil {
+			return err
+		}
+		if graded {
+			return fmt.Errorf("anonymous grading is locked by graded submissions")
+		}
+		asg.AnonymousKey = ""
+		if asg.AnonymousGrading {
+			if asg.AnonymousKey, err = newAnonymousKey(); err != nil {
+				return err
+			}
+		}
+	}
+	now := time.Now()
+	publishStatus, publishAt, publishChanged, err := resolvePublishUpdate(current.PublishStatus, current.PublishAt, asg.PublishStatus, asg.PublishAt, now)
+	if err != nil {
+		return err
+	}
+	asg.PublishStatus = publishStatus
+	asg.PublishAt = publishAt
+	asg.PublishedAt = current.PublishedAt
+	if publishChanged && publishStatus == domain.PublishStatusPublished {
+		asg.PublishedAt = &now
+	}
 	var attachmentMediaIDs []string
 	if mediaIDs != nil {
 		var err error
@@ -342,16 +556,44 @@ func (s *assignmentService) UpdateAssignment(id string, asg *domain.Assignment,
 		}
 	}
 
-	err := s.repo.UpdateAssignment(asg)
+	err = s.repo.UpdateAssignment(asg)
 	if err != nil {
 		return err
 	}
+	if rubricChanged {
+		if err := s.repo.SetAssignmentRubric(id, asg.RubricID); err != nil {
+			return err
+		}
+	}
+	if attemptsChanged {
+		if err := s.repo.SetAssignmentMaxAttempts(id, asg.MaxAttempts); err != nil {
+			return err
+		}
+	}
+	if groupModeChanged {
+		if err := s.repo.SetAssignmentGroupMode(id, asg.GroupMode); err != nil {
+			return err
+		}
+	}
+	if anonymousChanged {
+		if err := s.repo.SetAssignmentAnonymousGrading(id, asg.AnonymousGrading, asg.AnonymousKey); err != nil {
+			return err
+		}
+	}
+	if publishChanged {
+		if err := s.repo.SetAssignmentPublishState(id, asg.PublishStatus, asg.PublishAt, asg.PublishedAt); err != nil {
+			return err
+		}
+	}
 
 	if mediaIDs != nil {
 		if err := replaceSourceAttachments(s.attService, asg.SchoolID, domain.SourceAssignment, id, attachmentMediaIDs); err != nil {
 			return err
 		}
 	}
+	if publishChanged && asg.PublishStatus == domain.PublishStatusPublished {
+		s.notifyAssignmentPublished(asg)
+	}
 	return nil
 }
 
@@ -377,8 +619,19 @@ func (s *assignmentService) Submit(sbm *domain.Submission, mediaIDs []string, ac
 		return err
 	}
 
-	if !assignment.AllowLateSubmission && assignment.Deadline != nil && assignment.Deadline.Before(sbm.SubmittedAt) {
-		return fmt.Errorf("submission past due")
+	if assignment.Type == domain.AssignmentTypeQuiz {
+		return fmt.Errorf("quiz assignments are submitted through quiz attempts")
+	}
+	if assignment.GroupMode {
+		if err := s.prepareGroupSubmission(sbm, assignment); err != nil {
+			return err
+		}
+	}
+	if err := s.ensureSubmissionOpen(assignment, sbm); err != nil {
+		return err
+	}
+	if err := s.ensureAttemptAvailable(assignment, sbm); err != nil {
+		return err
 	}
 
 	attachmentMediaIDs, err := prepareAttachableMediaIDs(s.mediaRepo, mediaIDs, sbm.SchoolID, actorUserID, isAdmin)
@@ -389,11 +642,16 @@ func (s *assignmentService) Submit(sbm *domain.Submission, mediaIDs []string, ac
 	if err = s.repo.UpsertSubmission(sbm); err != nil {
 		return err
 	}
+	if sbm.GroupID != nil {
+		if err := s.repo.SyncGroupSubmissionMembers(sbm.ID, sbm.AssignmentID, *sbm.GroupID); err != nil {
+			return err
+		}
+	}
 
 	if err := replaceSourceAttachments(s.attService, sbm.SchoolID, domain.SourceSubmission, sbm.ID, attachmentMediaIDs); err != nil {
 		return err
 	}
-	return nil
+	return s.recordSubmissionVersion(sbm, attachmentMediaIDs)
 }
 
 func (s *assignmentService) GetSubmissions(asgID string) ([]*domain.Submission, error) {
@@ -421,6 +679,10 @@ func (s *assignmentService) GetSubmissionByID(id string) (*domain.Submission, er
 	for _, a := range atts {
 		sbm.Attachments = append(sbm.Attachments, *a)
 	}
+	if err := s.countSubmissionAttempts(sbm); err != nil {
+		return nil, err
+	}
+	scoreGroupMembers(sbm)
 	return sbm, nil
 }
 
@@ -437,18 +699,62 @@ func (s *assignmentService) GetMySubmissionByAssignment(assignmentID string, use
 	for _, a := range atts {
 		sbm.Attachments = append(sbm.Attachments, *a)
 	}
+	if err := s.countSubmissionAttempts(sbm); err != nil {
+		return nil, err
+	}
+	scoreGroupMembers(sbm)
 	return sbm, nil
 }
 
-func (s *assignmentService) Assess(asm *domain.Assessment) error {
+func (s *assignmentService) countSubmissionAttempts(sbm *domain.Submission) error {
+	count, err := s.repo.CountSubmissionVersions(sbm)
+	if err != nil {
+		return err
+	}
+	sbm.AttemptCount = int(count)
+	return nil
+}
+
+// Assess records a grade for the latest turn-in, or for versionID. When the
+// assignment has a rubric, criteria must pick one level per criterion and the
+// score is computed from the rubric.
+func (s *assignmentService) Assess(asm *domain.Assessment, criteria []dto.AssessmentCriterionInputDTO, versionID string) error {
+	rubric, err := s.getSubmissionRubric(asm.SubmissionID)
+	if err != nil {
+		return err
+	}
+	if rubric == nil && len(criteria) > 0 {
+		return fmt.Errorf("invalid rubric assessment: assignment has no rubric")
+	}
+	if rubric != nil {
+		asm.RubricScores, asm.Score, err = scoreRubric(rubric, criteria)
+		if err != nil {
+			return err
+		}
+	}
+	return s.gradeSubmissionVersion(asm, versionID)
+}
+
+// saveAssessment upserts the grade of a submission and tells the students it
+// counts for.
+func (s *assignmentService) saveAssessment(asm *domain.Assessment) error {
 	if err := s.repo.UpsertAssessment(asm); err != nil {
 		return err
 	}
+	s.notifySubmissionGraded(asm)
+	return nil
+}
 
+// notifySubmissionGraded tells the students a graded submission counts for.
+// Best-effort: failures are ignored.
+func (s *assignmentService) notifySubmissionGraded(asm *domain.Assessment) {
 	sbm, err := s.repo.GetSubmissionByID(asm.SubmissionID)
-	if err == nil {
+	if err != nil {
+		return
+	}
+	for _, userID := range submissionStudentIDs(sbm) {
 		_ = s.notifService.Create(&dto.CreateNotificationDTO{
-			UserID:    sbm.UserID,
+			UserID:    userID,
 			Type:      domain.NotifAssignmentGraded,
 			Title:     "Tugas sudah dinilai",
 			Message:   fmt.Sprintf("Nilai Anda sudah tersedia: %.2f", asm.Score),
@@ -457,14 +763,44 @@ func (s *assignmentService) Assess(asm *domain.Assessment) error {
 		})
 	}
 
-	return nil
+	s.publishSubmissionGraded(sbm)
+}
+
+// publishSubmissionGraded pushes the stored assessment to the open realtime
+// connections of the students the submission counts for.
+func (s *assignmentService) publishSubmissionGraded(sbm *domain.Submission) {
+	if s.realtime == nil || sbm.Assessment == nil {
+		return
+	}
+	for _, userID := range submissionStudentIDs(sbm) {
+		s.realtime.SubmissionGraded(sbm.SchoolID, userID, dto.SubmissionGradedEventDTO{
+			SubmissionID: sbm.ID,
+			AssignmentID: sbm.AssignmentID,
+			Score:        sbm.Assessment.Score,
+			Feedback:     sbm.Assessment.Feedback,
+			AssessedAt:   formatAPITime(sbm.Assessment.AssessedAt),
+		})
+	}
 }
 
+// UpdateSubmission turns the submission in again as a new version. Without
+// mediaIDs the new version keeps the current attachments.
 func (s *assignmentService) UpdateSubmission(id string, mediaIDs []string, actorUserID string, isAdmin bool) error {
 	sbm, err := s.repo.GetSubmissionByID(id)
 	if err != nil {
 		return err
 	}
+	assignment, err := s.getFileSubmissionAssignment(sbm)
+	if err != nil {
+		return err
+	}
+	sbm.SubmittedAt = time.Now()
+	if err := s.ensureSubmissionOpen(assignment, sbm); err != nil {
+		return err
+	}
+	if err := s.ensureAttemptAvailable(assignment, sbm); err != nil {
+		return err
+	}
 	var attachmentMediaIDs []string
 	if mediaIDs != nil {
 		var err error
@@ -472,36 +808,173 @@ func (s *assignmentService) UpdateSubmission(id string, mediaIDs []string, actor
 		if err != nil {
 			return err
 		}
+	} else {
+		atts, err := s.attService.GetBySource(string(domain.SourceSubmission), id)
+		if err != nil {
+			return err
+		}
+		for _, a := range atts {
+			attachmentMediaIDs = append(attachmentMediaIDs, a.MediaID)
+		}
 	}
 
-	sbm.SubmittedAt = time.Now()
 	err = s.repo.UpdateSubmission(sbm)
 	if err != nil {
 		return err
 	}
+	if sbm.GroupID != nil {
+		if err := s.repo.SyncGroupSubmissionMembers(sbm.ID, sbm.AssignmentID, *sbm.GroupID); err != nil {
+			return err
+		}
+	}
 
 	if mediaIDs != nil {
 		if err := replaceSourceAttachments(s.attService, sbm.SchoolID, domain.SourceSubmission, id, attachmentMediaIDs); err != nil {
 			return err
 		}
 	}
-	return nil
+	return s.recordSubmissionVersion(sbm, attachmentMediaIDs)
 }
 
 func (s *assignmentService) DeleteSubmission(id string) error {
+	sbm, err := s.repo.GetSubmissionByID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.getFileSubmissionAssignment(sbm); err != nil {
+		return err
+	}
 	s.attService.UnlinkBySource(string(domain.SourceSubmission), id)
 	return s.repo.DeleteSubmission(id)
 }
 
-func (s *assignmentService) UpdateAssessment(submissionID string, asm *domain.Assessment) error {
+func (s *assignmentService) UpdateAssessment(submissionID string, asm *domain.Assessment, criteria []dto.AssessmentCriterionInputDTO) error {
 	asm.SubmissionID = submissionID
-	return s.repo.UpdateAssessment(asm)
+	rubric, err := s.getSubmissionRubric(submissionID)
+	if err != nil {
+		return err
+	}
+	if rubric == nil && len(criteria) > 0 {
+		return fmt.Errorf("invalid rubric assessment: assignment has no rubric")
+	}
+	if rubric != nil {
+		if asm.Score != 0 && len(criteria) == 0 {
+			return fmt.Errorf("invalid rubric assessment: score is computed from the rubric")
+		}
+		if len(criteria) > 0 {
+			asm.RubricScores, asm.Score, err = scoreRubric(rubric, criteria)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.repo.UpdateAssessment(asm); err != nil {
+		return err
+	}
+	if err := s.syncCountedVersionGrade(submissionID); err != nil {
+		return err
+	}
+
+	if sbm, err := s.repo.GetSubmissionByID(submissionID); err == nil {
+		s.publishSubmissionGraded(sbm)
+	}
+	return nil
 }
 
 func (s *assignmentService) DeleteAssessment(submissionID string) error {
 	return s.repo.DeleteAssessment(submissionID)
 }
 
+// GetAssignmentRubric returns the rubric attached to assignment, or nil.
+func (s *assignmentService) GetAssignmentRubric(assignment *domain.Assignment) (*domain.Rubric, error) {
+	if assignment.RubricID == nil {
+		return nil, nil
+	}
+	return s.rubricRepo.GetByID(*assignment.RubricID)
+}
+
+// getSubmissionRubric returns the rubric used to grade a submission, or nil.
+// Quiz submissions are graded from their answers and reject manual grades.
+func (s *assignmentService) getSubmissionRubric(submissionID string) (*domain.Rubric, error) {
+	sbm, err := s.repo.GetSubmissionByID(submissionID)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := s.repo.GetAssignmentByID(sbm.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment.Type == domain.AssignmentTypeQuiz {
+		return nil, fmt.Errorf("quiz scores are computed from quiz answers")
+	}
+	return s.GetAssignmentRubric(assignment)
+}
+
+func (s *assignmentService) validateAssignmentRubric(rubricID *string, schoolID string, actorUserID string, isAdmin bool) error {
+	if rubricID == nil {
+		return nil
+	}
+	rubric, err := s.rubricRepo.GetByID(*rubricID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("invalid assignment rubric")
+		}
+		return err
+	}
+	if !canUseRubric(rubric, actorUserID, schoolID, isAdmin) {
+		return fmt.Errorf("invalid assignment rubric")
+	}
+	return nil
+}
+
+// getFileSubmissionAssignment returns the assignment of a submission the
+// student may change; quiz submissions are fixed by their attempt.
+// ensureSubmissionOpen rejects a turn-in at sbm.SubmittedAt unless the
+// assignment is published and, when late work is not allowed, the student's
+// effective deadline has not passed.
+func (s *assignmentService) ensureSubmissionOpen(assignment *domain.Assignment, sbm *domain.Submission) error {
+	if assignment.PublishStatus != domain.PublishStatusPublished {
+		return fmt.Errorf("assignment is not published")
+	}
+	if assignment.AllowLateSubmission {
+		return nil
+	}
+	deadline, err := s.GetEffectiveDeadline(assignment, sbm.UserID)
+	if err != nil {
+		return err
+	}
+	if deadline != nil && deadline.Before(sbm.SubmittedAt) {
+		return fmt.Errorf("submission past due")
+	}
+	return nil
+}
+
+func (s *assignmentService) getFileSubmissionAssignment(sbm *domain.Submission) (*domain.Assignment, error) {
+	assignment, err := s.repo.GetAssignmentByID(sbm.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment.Type == domain.AssignmentTypeQuiz {
+		return nil, fmt.Errorf("quiz submissions cannot be changed")
+	}
+	return assignment, nil
+}
+
+func sameOptionalInt(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func sameOptionalID(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return strings.EqualFold(*a, *b)
+}
+
 func (s *assignmentService) validateAssignmentCategory(categoryID string, schoolID string) error {
 	allowed, err := s.repo.AssignmentCategoryBelongsToSchool(categoryID, schoolID)
 	if err != nil {
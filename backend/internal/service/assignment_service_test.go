@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEffectiveDeadline(t *testing.T) {
+	deadline := time.Date(2026, 7, 1, 17, 0, 0, 0, time.UTC)
+	extended := deadline.Add(48 * time.Hour)
+	earlier := deadline.Add(-time.Hour)
+
+	if got := effectiveDeadline(&deadline, nil); got != &deadline {
+		t.Fatalf("expected assignment deadline without extension, got %v", got)
+	}
+	if got := effectiveDeadline(&deadline, &extended); got != &extended {
+		t.Fatalf("expected extended deadline, got %v", got)
+	}
+	if got := effectiveDeadline(&deadline, &earlier); got != &deadline {
+		t.Fatalf("expected a later assignment deadline to win over an older extension, got %v", got)
+	}
+	if got := effectiveDeadline(nil, &extended); got != nil {
+		t.Fatalf("expected no deadline for assignments without one, got %v", got)
+	}
+}
+
+func TestValidateExtendedDeadline(t *testing.T) {
+	now := time.Date(2026, 7, 2, 8, 0, 0, 0, time.UTC)
+	deadline := now.Add(-24 * time.Hour)
+
+	extended := now.Add(24 * time.Hour)
+	if err := validateExtendedDeadline(&extended, &deadline, now); err != nil {
+		t.Fatalf("expected future extended deadline to be accepted, got %v", err)
+	}
+	if err := validateExtendedDeadline(nil, &deadline, now); err == nil {
+		t.Fatalf("expected approval without extended deadline to be rejected")
+	}
+	past := now.Add(-time.Hour)
+	if err := validateExtendedDeadline(&past, &deadline, now); err == nil {
+		t.Fatalf("expected past extended deadline to be rejected")
+	}
+	future := now.Add(72 * time.Hour)
+	beforeDeadline := now.Add(48 * time.Hour)
+	if err := validateExtendedDeadline(&beforeDeadline, &future, now); err == nil {
+		t.Fatalf("expected extended deadline before the assignment deadline to be rejected")
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ListSubmissionVersions returns every turn-in of a submission, oldest first,
+// with its attachments and its lateness against the student's effective
+// deadline.
+func (s *assignmentService) ListSubmissionVersions(sbm *domain.Submission, assignment *domain.Assignment) ([]*domain.SubmissionVersion, error) {
+	versions, err := s.repo.ListSubmissionVersions(sbm.ID)
+	if err != nil {
+		return nil, err
+	}
+	deadline, err := s.GetEffectiveDeadline(assignment, sbm.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceIDs := make([]string, 0, len(versions))
+	for _, version := range versions {
+		sourceIDs = append(sourceIDs, version.ID)
+	}
+	attachmentsBySource, err := s.attService.GetBySources(string(domain.SourceSubmissionVersion), sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		version.IsLate = deadline != nil && version.SubmittedAt.After(*deadline)
+		atts := attachmentsBySource[version.ID]
+		version.Attachments = make([]domain.Attachment, 0, len(atts))
+		for _, a := range atts {
+			version.Attachments = append(version.Attachments, *a)
+		}
+	}
+	return versions, nil
+}
+
+// ensureAttemptAvailable rejects a turn-in once the student, or the group of a
+// group submission, has used every attempt of the assignment.
+func (s *assignmentService) ensureAttemptAvailable(assignment *domain.Assignment, sbm *domain.Submission) error {
+	if assignment.MaxAttempts == nil {
+		return nil
+	}
+	count, err := s.repo.CountSubmissionVersions(sbm)
+	if err != nil {
+		return err
+	}
+	if count >= int64(*assignment.MaxAttempts) {
+		return fmt.Errorf("submission attempt limit reached")
+	}
+	return nil
+}
+
+// recordSubmissionVersion snapshots the current turn-in of sbm with its
+// attachment set.
+func (s *assignmentService) recordSubmissionVersion(sbm *domain.Submission, mediaIDs []string) error {
+	version := &domain.SubmissionVersion{
+		SubmissionID: sbm.ID,
+		SubmittedAt:  sbm.SubmittedAt,
+	}
+	if err := s.repo.CreateSubmissionVersion(version); err != nil {
+		return err
+	}
+	return replaceSourceAttachments(s.attService, sbm.SchoolID, domain.SourceSubmissionVersion, version.ID, mediaIDs)
+}
+
+// gradeSubmissionVersion stores asm as the grade of one version of the
+// submission, the latest unless versionID is set, and then writes the grade
+// of the version that counts under the grading policy as the assessment.
+// Submissions turned in before versions were kept are graded as a whole.
+func (s *assignmentService) gradeSubmissionVersion(asm *domain.Assessment, versionID string) error {
+	sbm, err := s.repo.GetSubmissionByID(asm.SubmissionID)
+	if err != nil {
+		return err
+	}
+	assignment, err := s.repo.GetAssignmentByID(sbm.AssignmentID)
+	if err != nil {
+		return err
+	}
+	versions, err := s.repo.ListSubmissionVersions(sbm.ID)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		if versionID != "" {
+			return fmt.Errorf("invalid submission version")
+		}
+		return s.saveAssessment(asm)
+	}
+
+	target := versions[len(versions)-1]
+	if versionID != "" {
+		target = nil
+		for _, version := range versions {
+			if strings.EqualFold(version.ID, versionID) {
+				target = version
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("invalid submission version")
+		}
+	}
+
+	changed := applyVersionGrade(asm, sbm.Assessment, target, versions, assignment.GradingPolicy, time.Now())
+	if err := s.repo.GradeSubmissionVersion(target); err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	return s.saveAssessment(asm)
+}
+
+// applyVersionGrade grades target, one of versions, with asm and then points
+// asm at the version that counts under policy. It reports false when current,
+// the stored assessment, already holds the counted grade.
+func applyVersionGrade(asm *domain.Assessment, current *domain.Assessment, target *domain.SubmissionVersion, versions []*domain.SubmissionVersion, policy string, now time.Time) bool {
+	score := asm.Score
+	gradedBy := asm.AssessedBy
+	target.Score = &score
+	target.Feedback = asm.Feedback
+	target.GradedBy = &gradedBy
+	target.GradedAt = &now
+
+	counted := countedSubmissionVersion(versions, policy)
+	if counted.ID != target.ID {
+		if current != nil && sameOptionalID(current.VersionID, &counted.ID) {
+			return false
+		}
+		// Another version counts now. Its rubric levels were not kept, so
+		// the assessment carries its score and feedback only.
+		asm.Score = *counted.Score
+		asm.Feedback = counted.Feedback
+		asm.AssessedBy = *counted.GradedBy
+		asm.RubricScores = []domain.AssessmentRubricScore{}
+	}
+	asm.VersionID = &counted.ID
+	return true
+}
+
+// syncCountedVersionGrade copies an edited assessment back to the version it
+// was taken from.
+func (s *assignmentService) syncCountedVersionGrade(submissionID string) error {
+	asm, err := s.repo.GetAssessmentBySubmission(submissionID)
+	if err != nil || asm.VersionID == nil {
+		return err
+	}
+	version, err := s.repo.GetSubmissionVersion(*asm.VersionID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	score := asm.Score
+	version.Score = &score
+	version.Feedback = asm.Feedback
+	if version.GradedBy == nil {
+		version.GradedBy = &asm.AssessedBy
+	}
+	version.GradedAt = &now
+	return s.repo.GradeSubmissionVersion(version)
+}
+
+// countedSubmissionVersion picks the graded version whose grade counts: the
+// newest under the latest policy, the best under the highest policy with
+// ties going to the newer version. It returns nil when none is graded.
+func countedSubmissionVersion(versions []*domain.SubmissionVersion, policy string) *domain.SubmissionVersion {
+	var counted *domain.SubmissionVersion
+	for _, version := range versions {
+		if version.Score == nil {
+			continue
+		}
+		switch {
+		case counted == nil:
+			counted = version
+		case policy == domain.GradingPolicyHighest:
+			if *version.Score > *counted.Score || (*version.Score == *counted.Score && version.Number > counted.Number) {
+				counted = version
+			}
+		case version.Number > counted.Number:
+			counted = version
+		}
+	}
+	return counted
+}
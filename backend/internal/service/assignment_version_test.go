@@ -0,0 +1,78 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repository"
+	"testing"
+	"time"
+)
+
+type resubmitAssignmentRepositoryStub struct {
+	repository.AssignmentRepository
+	assignment *domain.Assignment
+	updated    bool
+}
+
+func (r *resubmitAssignmentRepositoryStub) GetSubmissionByID(id string) (*domain.Submission, error) {
+	return &domain.Submission{ID: id, AssignmentID: r.assignment.ID, UserID: "student-1"}, nil
+}
+
+func (r *resubmitAssignmentRepositoryStub) GetAssignmentByID(string) (*domain.Assignment, error) {
+	return r.assignment, nil
+}
+
+func (r *resubmitAssignmentRepositoryStub) ListApprovedExtensions([]string, []string) ([]*domain.AssignmentExtension, error) {
+	return nil, nil
+}
+
+func (r *resubmitAssignmentRepositoryStub) UpdateSubmission(*domain.Submission) error {
+	r.updated = true
+	return nil
+}
+
+func TestCountedSubmissionVersion(t *testing.T) {
+	versions := []*domain.SubmissionVersion{
+		{ID: "v1", Number: 1, Score: floatPtr(90)},
+		{ID: "v2", Number: 2, Score: floatPtr(70)},
+		{ID: "v3", Number: 3},
+	}
+
+	if counted := countedSubmissionVersion(versions, domain.GradingPolicyLatest); counted == nil || counted.ID != "v2" {
+		t.Fatalf("expected the latest graded version to count, got %+v", counted)
+	}
+	if counted := countedSubmissionVersion(versions, domain.GradingPolicyHighest); counted == nil || counted.ID != "v1" {
+		t.Fatalf("expected the highest scored version to count, got %+v", counted)
+	}
+
+	versions[2].Score = floatPtr(90)
+	if counted := countedSubmissionVersion(versions, domain.GradingPolicyHighest); counted == nil || counted.ID != "v3" {
+		t.Fatalf("expected ties to go to the newer version, got %+v", counted)
+	}
+	if counted := countedSubmissionVersion(versions[:0], domain.GradingPolicyLatest); counted != nil {
+		t.Fatalf("expected no counted version without grades, got %+v", counted)
+	}
+}
+
+func TestUpdateSubmissionChecksPublishAndDeadline(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name       string
+		assignment *domain.Assignment
+		want       string
+	}{
+		{"draft", &domain.Assignment{ID: "assignment-1", PublishStatus: domain.PublishStatusDraft}, "assignment is not published"},
+		{"past due", &domain.Assignment{ID: "assignment-1", PublishStatus: domain.PublishStatusPublished, Deadline: &past}, "submission past due"},
+	}
+	for _, tc := range tests {
+		repo := &resubmitAssignmentRepositoryStub{assignment: tc.assignment}
+		s := &assignmentService{repo: repo}
+
+		err := s.UpdateSubmission("submission-1", []string{}, "student-1", false)
+		if err == nil || err.Error() != tc.want {
+			t.Fatalf("%s: expected %q, got %v", tc.name, tc.want, err)
+		}
+		if repo.updated {
+			t.Fatalf("%s: expected no new version to be turned in", tc.name)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"fmt"
+	"strings"
+)
+
+const maxChatContentCardTitle = 120
+
+// parseChatContentRef validates the refType/refId pair of a new message. Both
+// must be set together.
+func parseChatContentRef(refType *string, refID *string) (*repository.ChatContentRef, error) {
+	if refType == nil && refID == nil {
+		return nil, nil
+	}
+	if refType == nil || refID == nil || strings.TrimSpace(*refID) == "" {
+		return nil, fmt.Errorf("invalid chat content reference")
+	}
+	ref := repository.ChatContentRef{
+		Type: strings.TrimSpace(*refType),
+		ID:   strings.ToLower(strings.TrimSpace(*refID)),
+	}
+	if !isSupportedChatContentRef(ref.Type) {
+		return nil, fmt.Errorf("invalid chat content reference")
+	}
+	return &ref, nil
+}
+
+// ensureCanShareContent rejects content the sender cannot open themselves, so
+// a message never leaks a title or deadline from another class.
+func (s *chatService) ensureCanShareContent(userID string, schoolID string, ref repository.ChatContentRef) error {
+	refs := []repository.ChatContentRef{ref}
+	cards, err := s.repo.ListContentCards(schoolID, refs)
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		return fmt.Errorf("chat content reference not found")
+	}
+	viewers, err := s.repo.ListContentViewers(schoolID, refs, []string{userID})
+	if err != nil {
+		return err
+	}
+	if len(viewers) == 0 {
+		return fmt.Errorf("forbidden: chat content access denied")
+	}
+	return nil
+}
+
+// ResolveContentCards maps the card of one shared content item for each of
+// userIDs, so realtime payloads respect every recipient's access.
+func (s *chatService) ResolveContentCards(schoolID string, refType string, refID string, userIDs []string) (map[string]*dto.ChatContentCardDTO, error) {
+	ref := repository.ChatContentRef{Type: refType, ID: refID}
+	cardsByUser, err := s.contentCardsForUsers(schoolID, []repository.ChatContentRef{ref}, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*dto.ChatContentCardDTO, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = cardsByUser[userID][ref]
+	}
+	return result, nil
+}
+
+// loadContentCards maps the content cards of rows for one viewer, keyed by
+// message ID.
+func (s *chatService) loadContentCards(rows []repository.ChatMessageRow, userID string, schoolID string) (map[string]*dto.ChatContentCardDTO, error) {
+	refs := make([]repository.ChatContentRef, 0)
+	for _, row := range rows {
+		if row.RefType != nil && row.RefID != nil {
+			refs = append(refs, repository.ChatContentRef{Type: *row.RefType, ID: *row.RefID})
+		}
+	}
+	result := make(map[string]*dto.ChatContentCardDTO)
+	if len(refs) == 0 {
+		return result, nil
+	}
+
+	cardsByUser, err := s.contentCardsForUsers(schoolID, refs, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if row.RefType != nil && row.RefID != nil {
+			result[row.MessageID] = cardsByUser[userID][repository.ChatContentRef{Type: *row.RefType, ID: *row.RefID}]
+		}
+	}
+	return result, nil
+}
+
+func (s *chatService) contentCardsForUsers(schoolID string, refs []repository.ChatContentRef, userIDs []string) (map[string]map[repository.ChatContentRef]*dto.ChatContentCardDTO, error) {
+	cards, err := s.repo.ListContentCards(schoolID, refs)
+	if err != nil {
+		return nil, err
+	}
+	viewers, err := s.repo.ListContentViewers(schoolID, refs, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cardsByRef := make(map[repository.ChatContentRef]repository.ChatContentCardRow, len(cards))
+	for _, card := range cards {
+		cardsByRef[repository.ChatContentRef{Type: card.RefType, ID: card.RefID}] = card
+	}
+	audiences := make(map[string]map[repository.ChatContentRef]string, len(userIDs))
+	for _, viewer := range viewers {
+		if audiences[viewer.UserID] == nil {
+			audiences[viewer.UserID] = make(map[repository.ChatContentRef]string)
+		}
+		audiences[viewer.UserID][repository.ChatContentRef{Type: viewer.RefType, ID: viewer.RefID}] = viewer.Audience
+	}
+
+	result := make(map[string]map[repository.ChatContentRef]*dto.ChatContentCardDTO, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = make(map[repository.ChatContentRef]*dto.ChatContentCardDTO, len(refs))
+		for _, ref := range refs {
+			card, found := cardsByRef[ref]
+			audience := audiences[userID][ref]
+			if !found || audience == "" {
+				result[userID][ref] = &dto.ChatContentCardDTO{RefType: ref.Type, RefID: ref.ID}
+				continue
+			}
+			result[userID][ref] = mapChatContentCard(card, audience)
+		}
+	}
+	return result, nil
+}
+
+func mapChatContentCard(card repository.ChatContentCardRow, audience string) *dto.ChatContentCardDTO {
+	title := chatContentCardTitle(card.Title)
+	classID := card.ClassID
+	className := card.ClassName
+	return &dto.ChatContentCardDTO{
+		RefType:        card.RefType,
+		RefID:          card.RefID,
+		Available:      true,
+		Title:          &title,
+		ClassID:        &classID,
+		ClassName:      &className,
+		SubjectClassID: card.SubjectClassID,
+		SubjectName:    card.SubjectName,
+		Deadline:       formatAPITimePtr(card.Deadline),
+		Link:           chatContentCardLink(card, audience),
+	}
+}
+
+// chatContentCardTitle flattens feed post content into a single-line title.
+func chatContentCardTitle(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	titleRunes := []rune(title)
+	if len(titleRunes) > maxChatContentCardTitle {
+		title = string(titleRunes[:maxChatContentCardTitle-3]) + "..."
+	}
+	return title
+}
+
+// chatContentCardLink follows the comment notification links: students get
+// the student pages and everyone else the teacher pages. Feed posts only have
+// a student feed page.
+func chatContentCardLink(card repository.ChatContentCardRow, audience string) *string {
+	var link string
+	switch domain.SourceType(card.RefType) {
+	case domain.SourceMaterial, domain.SourceAssignment:
+		if card.SubjectClassID == nil {
+			return nil
+		}
+		section := "materials"
+		if domain.SourceType(card.RefType) == domain.SourceAssignment {
+			section = "assignments"
+		}
+		prefix := "teacher"
+		if audience == "student" {
+			prefix = "student"
+		}
+		link = fmt.Sprintf("/%s/subjects/%s/%s/%s", prefix, *card.SubjectClassID, section, card.RefID)
+	case domain.SourceFeed:
+		if audience != "student" {
+			return nil
+		}
+		link = "/student/feed"
+	default:
+		return nil
+	}
+	return &link
+}
+
+func isSupportedChatContentRef(refType string) bool {
+	return isSupportedCommentSource(domain.SourceType(refType))
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+const (
+	chatReportStatusOpen      = "open"
+	chatReportStatusDismissed = "dismissed"
+	chatReportStatusActioned  = "actioned"
+	maxChatReportReasonLen    = 500
+	maxChatMuteMinutes        = 30 * 24 * 60
+	maxChatBannedWords        = 500
+	maxChatBannedWordLen      = 50
+)
+
+// Moderation actions recorded in edv.logs.
+const (
+	chatLogReportMessage     = "CHAT_REPORT_MESSAGE"
+	chatLogResolveReport     = "CHAT_RESOLVE_REPORT"
+	chatLogRemoveMessage     = "CHAT_REMOVE_MESSAGE"
+	chatLogMuteMember        = "CHAT_MUTE_MEMBER"
+	chatLogUnmuteMember      = "CHAT_UNMUTE_MEMBER"
+	chatLogBlockMessage      = "CHAT_BLOCK_MESSAGE"
+	chatLogUpdateBannedWords = "CHAT_UPDATE_BANNED_WORDS"
+)
+
+func (s *chatService) ReportMessage(userID string, schoolID string, roomID string, messageID string, reason string) (*dto.ChatReportDTO, error) {
+	allowed, _, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+
+	message, err := s.repo.GetMessageByID(messageID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if message.SenderID == userID {
+		return nil, fmt.Errorf("chat cannot report own message")
+	}
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > maxChatReportReasonLen {
+		return nil, fmt.Errorf("chat report reason exceeds %d characters", maxChatReportReasonLen)
+	}
+
+	report := domain.ChatMessageReport{
+		SchoolID:   schoolID,
+		RoomID:     roomID,
+		MessageID:  messageID,
+		ReporterID: userID,
+		Reason:     reason,
+		Status:     chatReportStatusOpen,
+		CreatedAt:  time.Now(),
+	}
+	created, err := s.moderationRepo.CreateReport(&report)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, fmt.Errorf("chat message already reported")
+	}
+	if err := s.recordModerationLog(schoolID, userID, chatLogReportMessage, map[string]any{
+		"reportId":  report.ID,
+		"roomId":    roomID,
+		"messageId": messageID,
+		"senderId":  message.SenderID,
+	}); err != nil {
+		return nil, err
+	}
+
+	row, err := s.moderationRepo.GetReportRow(report.ID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	mapped := mapChatReport(*row)
+	return &mapped, nil
+}
+
+// ListReports returns the school's moderation queue. The route is limited to
+// school admins, who see reports from every room including DMs.
+func (s *chatService) ListReports(userID string, schoolID string, status string, page int, limit int) (*dto.ChatReportListDTO, error) {
+	status = strings.TrimSpace(status)
+	switch status {
+	case "":
+		status = chatReportStatusOpen
+	case "all":
+		status = ""
+	case chatReportStatusOpen, chatReportStatusDismissed, chatReportStatusActioned:
+	default:
+		return nil, fmt.Errorf("invalid chat report status")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > maxChatMessageLimit {
+		limit = defaultSearchLimit
+	}
+
+	rows, total, err := s.moderationRepo.ListReports(schoolID, status, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]dto.ChatReportDTO, 0, len(rows))
+	for _, row := range rows {
+		reports = append(reports, mapChatReport(row))
+	}
+	return &dto.ChatReportListDTO{
+		Data:       reports,
+		TotalItems: total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// ResolveReport closes an open report. With removeMessage the reported message
+// is deleted for everyone and the report is marked actioned; the deleted
+// message is returned so callers can broadcast it.
+func (s *chatService) ResolveReport(userID string, schoolID string, reportID string, status string, note string, removeMessage bool) (*dto.ChatReportDTO, *dto.ChatMessageDeletedDTO, error) {
+	report, err := s.moderationRepo.GetReport(reportID, schoolID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if report.Status != chatReportStatusOpen {
+		return nil, nil, fmt.Errorf("chat report already resolved")
+	}
+	if status != chatReportStatusDismissed && status != chatReportStatusActioned {
+		return nil, nil, fmt.Errorf("invalid chat report status")
+	}
+	if removeMessage {
+		status = chatReportStatusActioned
+	}
+
+	var deleted *dto.ChatMessageDeletedDTO
+	if removeMessage {
+		message, err := s.repo.GetMessageByID(report.MessageID, report.RoomID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, err
+		}
+		if err == nil {
+			if err := s.repo.SoftDeleteMessage(report.MessageID, report.RoomID, userID); err != nil {
+				return nil, nil, err
+			}
+			deleted = &dto.ChatMessageDeletedDTO{
+				MessageID: report.MessageID,
+				RoomID:    report.RoomID,
+				ReplyTo:   message.ReplyTo,
+				DeletedBy: userID,
+				DeletedAt: formatChatTime(time.Now()),
+			}
+			if err := s.recordModerationLog(schoolID, userID, chatLogRemoveMessage, map[string]any{
+				"reportId":  reportID,
+				"roomId":    report.RoomID,
+				"messageId": report.MessageID,
+				"senderId":  message.SenderID,
+			}); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var resolutionNote *string
+	if note = strings.TrimSpace(note); note != "" {
+		resolutionNote = &note
+	}
+	if err := s.moderationRepo.ResolveReport(reportID, schoolID, status, userID, resolutionNote); err != nil {
+		return nil, nil, err
+	}
+	if err := s.recordModerationLog(schoolID, userID, chatLogResolveReport, map[string]any{
+		"reportId":       reportID,
+		"roomId":         report.RoomID,
+		"messageId":      report.MessageID,
+		"status":         status,
+		"messageRemoved": deleted != nil,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	row, err := s.moderationRepo.GetReportRow(reportID, schoolID)
+	if err != nil {
+		return nil, nil, err
+	}
+	mapped := mapChatReport(*row)
+	return &mapped, deleted, nil
+}
+
+// MuteMember stops a member from sending messages in the room for the given
+// duration. Muting again replaces the previous mute.
+func (s *chatService) MuteMember(userID string, schoolID string, roomID string, targetUserID string, durationMinutes int, reason string) (*dto.ChatMuteDTO, error) {
+	room, err := s.requireRoomModerator(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	targetUserID = strings.TrimSpace(targetUserID)
+	if targetUserID == userID {
+		return nil, fmt.Errorf("chat cannot mute self")
+	}
+	if durationMinutes < 1 || durationMinutes > maxChatMuteMinutes {
+		return nil, fmt.Errorf("invalid chat mute duration")
+	}
+	targetAllowed, _, err := s.CanAccessRoom(targetUserID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !targetAllowed {
+		return nil, fmt.Errorf("invalid chat mute target")
+	}
+	targetIsModerator, err := s.isRoomModerator(targetUserID, schoolID, room)
+	if err != nil {
+		return nil, err
+	}
+	if targetIsModerator {
+		return nil, fmt.Errorf("chat cannot mute moderator")
+	}
+
+	var muteReason *string
+	if reason = strings.TrimSpace(reason); reason != "" {
+		if len([]rune(reason)) > maxChatReportReasonLen {
+			return nil, fmt.Errorf("chat mute reason exceeds %d characters", maxChatReportReasonLen)
+		}
+		muteReason = &reason
+	}
+	now := time.Now()
+	mute := domain.ChatRoomMute{
+		RoomID:     roomID,
+		UserID:     targetUserID,
+		MutedBy:    userID,
+		Reason:     muteReason,
+		MutedUntil: now.Add(time.Duration(durationMinutes) * time.Minute),
+		CreatedAt:  now,
+	}
+	if err := s.moderationRepo.UpsertMute(&mute); err != nil {
+		return nil, err
+	}
+	if err := s.recordModerationLog(schoolID, userID, chatLogMuteMember, map[string]any{
+		"roomId":          roomID,
+		"userId":          targetUserID,
+		"durationMinutes": durationMinutes,
+		"mutedUntil":      formatChatTime(mute.MutedUntil),
+	}); err != nil {
+		return nil, err
+	}
+
+	mutedUntil := formatChatTime(mute.MutedUntil)
+	return &dto.ChatMuteDTO{
+		RoomID:     roomID,
+		UserID:     targetUserID,
+		MutedBy:    userID,
+		Reason:     muteReason,
+		MutedUntil: &mutedUntil,
+		IsMuted:    true,
+	}, nil
+}
+
+func (s *chatService) UnmuteMember(userID string, schoolID string, roomID string, targetUserID string) (*dto.ChatMuteDTO, error) {
+	if _, err := s.requireRoomModerator(userID, schoolID, roomID); err != nil {
+		return nil, err
+	}
+	if err := s.moderationRepo.DeleteMute(roomID, targetUserID); err != nil {
+		return nil, err
+	}
+	if err := s.recordModerationLog(schoolID, userID, chatLogUnmuteMember, map[string]any{
+		"roomId": roomID,
+		"userId": targetUserID,
+	}); err != nil {
+		return nil, err
+	}
+	return &dto.ChatMuteDTO{
+		RoomID:  roomID,
+		UserID:  targetUserID,
+		MutedBy: userID,
+		IsMuted: false,
+	}, nil
+}
+
+func (s *chatService) GetBannedWords(userID string, schoolID string) (*dto.ChatBannedWordsDTO, error) {
+	words, err := s.moderationRepo.ListBannedWords(schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if words == nil {
+		words = make([]string, 0)
+	}
+	return &dto.ChatBannedWordsDTO{Words: words}, nil
+}
+
+// UpdateBannedWords replaces the school's banned word list.
+func (s *chatService) UpdateBannedWords(userID string, schoolID string, words []string) (*dto.ChatBannedWordsDTO, error) {
+	normalized, err := normalizeChatBannedWords(words)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.moderationRepo.ReplaceBannedWords(schoolID, userID, normalized); err != nil {
+		return nil, err
+	}
+	if err := s.recordModerationLog(schoolID, userID, chatLogUpdateBannedWords, map[string]any{
+		"wordCount": len(normalized),
+	}); err != nil {
+		return nil, err
+	}
+	return s.GetBannedWords(userID, schoolID)
+}
+
+// ListModerationRecipients returns the realtime recipients of a room for
+// events caused by a school admin who is not necessarily a room member.
+func (s *chatService) ListModerationRecipients(userID string, schoolID string, roomID string) ([]string, error) {
+	room, err := s.repo.GetRoomContext(roomID, schoolID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isSchoolChatRoom(room, schoolID) {
+		return s.repo.ListSchoolRecipientUserIDs(schoolID)
+	}
+	return s.repo.ListRoomRecipientUserIDs(roomID, schoolID)
+}
+
+func (s *chatService) requireRoomModerator(userID string, schoolID string, roomID string) (*repository.ChatRoomRow, error) {
+	allowed, room, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+	isModerator, err := s.isRoomModerator(userID, schoolID, room)
+	if err != nil {
+		return nil, err
+	}
+	if !isModerator {
+		return nil, fmt.Errorf("forbidden: chat room moderator required")
+	}
+	return room, nil
+}
+
+func (s *chatService) ensureNotMuted(userID string, roomID string) error {
+	_, err := s.moderationRepo.GetActiveMute(roomID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("forbidden: chat member is muted")
+}
+
+// ensureNoBannedWords rejects content containing one of the school's banned
+// words and logs the blocked attempt without storing the message.
+func (s *chatService) ensureNoBannedWords(userID string, schoolID string, roomID string, content string) error {
+	if content == "" {
+		return nil
+	}
+	words, err := s.moderationRepo.ListBannedWords(schoolID)
+	if err != nil {
+		return err
+	}
+	word, found := findChatBannedWord(content, words)
+	if !found {
+		return nil
+	}
+	if err := s.recordModerationLog(schoolID, userID, chatLogBlockMessage, map[string]any{
+		"roomId": roomID,
+		"word":   word,
+	}); err != nil {
+		return err
+	}
+	return fmt.Errorf("chat message contains banned words")
+}
+
+func (s *chatService) recordModerationLog(schoolID string, userID string, action string, metadata map[string]any) error {
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return s.logRepo.Create(&domain.Log{
+		SchoolID: schoolID,
+		UserID:   userID,
+		Action:   action,
+		Metadata: string(encoded),
+	})
+}
+
+// chatWordTokens lowercases text and splits it into letter/digit runs, so
+// punctuation and spacing cannot hide a banned word.
+func chatWordTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// findChatBannedWord reports the first banned word or phrase that appears in
+// content as whole words, ignoring case and punctuation.
+func findChatBannedWord(content string, words []string) (string, bool) {
+	tokens := chatWordTokens(content)
+	for _, word := range words {
+		phrase := chatWordTokens(word)
+		if len(phrase) == 0 || len(phrase) > len(tokens) {
+			continue
+		}
+		for start := 0; start+len(phrase) <= len(tokens); start++ {
+			matched := true
+			for offset, part := range phrase {
+				if tokens[start+offset] != part {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return word, true
+			}
+		}
+	}
+	return "", false
+}
+
+func normalizeChatBannedWords(words []string) ([]string, error) {
+	if len(words) > maxChatBannedWords {
+		return nil, fmt.Errorf("chat banned words exceed %d entries", maxChatBannedWords)
+	}
+	result := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		normalized := strings.Join(chatWordTokens(word), " ")
+		if normalized == "" {
+			continue
+		}
+		if len([]rune(normalized)) > maxChatBannedWordLen {
+			return nil, fmt.Errorf("chat banned word exceeds %d characters", maxChatBannedWordLen)
+		}
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+	return result, nil
+}
+
+func mapChatReport(row repository.ChatReportRow) dto.ChatReportDTO {
+	var resolvedAt *string
+	if row.ResolvedAt != nil {
+		value := formatChatTime(*row.ResolvedAt)
+		resolvedAt = &value
+	}
+	return dto.ChatReportDTO{
+		ReportID:       row.ReportID,
+		RoomID:         row.RoomID,
+		RoomName:       row.RoomName,
+		RoomType:       row.RoomType,
+		MessageID:      row.MessageID,
+		MessageContent: row.MessageContent,
+		MessageDeleted: row.MessageDeleted,
+		SenderID:       row.SenderID,
+		SenderName:     row.SenderName,
+		ReporterID:     row.ReporterID,
+		ReporterName:   row.ReporterName,
+		Reason:         row.Reason,
+		Status:         row.Status,
+		ReportCount:    row.ReportCount,
+		ResolvedBy:     row.ResolvedBy,
+		ResolvedAt:     resolvedAt,
+		ResolutionNote: row.ResolutionNote,
+		CreatedAt:      formatChatTime(row.CreatedAt),
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxChatMentions        = 20
+	maxChatMentionPreview  = 120
+	chatMentionNotifyTitle = "Kamu disebut di chat"
+)
+
+// chatMentionPattern matches the mention markup inserted by the composer,
+// @[Display Name](user-uuid). Users have no handle, so the ID is carried in
+// the markup and the name is only for display.
+var chatMentionPattern = regexp.MustCompile(`@\[([^\[\]\n]{1,100})\]\(([0-9a-fA-F-]{36})\)`)
+
+func (s *chatService) GetRoomSettings(userID string, schoolID string, roomID string) (*dto.ChatRoomSettingsDTO, error) {
+	allowed, room, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+	setting, err := s.getRoomSetting(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapChatRoomSettings(room, schoolID, setting), nil
+}
+
+func (s *chatService) UpdateRoomSettings(userID string, schoolID string, roomID string, input dto.UpdateChatRoomSettingsDTO) (*dto.ChatRoomSettingsDTO, error) {
+	allowed, room, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+	if input.MutedUntil != nil && !input.MutedUntil.After(time.Now()) {
+		return nil, fmt.Errorf("chat notification mute must end in the future")
+	}
+
+	setting := domain.ChatRoomSetting{
+		RoomID:      roomID,
+		UserID:      userID,
+		NotifyLevel: input.NotifyLevel,
+		MutedUntil:  input.MutedUntil,
+		UpdatedAt:   time.Now(),
+	}
+	if err := s.repo.UpsertRoomSetting(&setting); err != nil {
+		return nil, err
+	}
+	return mapChatRoomSettings(room, schoolID, &setting), nil
+}
+
+func (s *chatService) getRoomSetting(roomID string, userID string) (*domain.ChatRoomSetting, error) {
+	setting, err := s.repo.GetRoomSetting(roomID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+func (s *chatService) mapRoomForCurrentUser(row repository.ChatRoomRow, userID string, schoolID string) (dto.ChatRoomDTO, error) {
+	setting, err := s.getRoomSetting(row.RoomID, userID)
+	if err != nil {
+		return dto.ChatRoomDTO{}, err
+	}
+	return s.mapRoomForUser(row, userID, schoolID, setting)
+}
+
+// mapRoomForUser maps the room with the user's unread and mention counters.
+// A muted room or the "none" level hides the unread badge, and the "mentions"
+// level only counts messages that mention the user. The mention counter is
+// always reported so clients can still surface direct mentions. CanSend also
+// reflects a moderators-only posting mode.
+func (s *chatService) mapRoomForUser(row repository.ChatRoomRow, userID string, schoolID string, setting *domain.ChatRoomSetting) (dto.ChatRoomDTO, error) {
+	mentions, err := s.repo.UnreadMentionCount(row.RoomID, userID)
+	if err != nil {
+		return dto.ChatRoomDTO{}, err
+	}
+
+	level := resolveChatNotifyLevel(&row, schoolID, setting)
+	muted := setting != nil && setting.MutedUntil != nil && setting.MutedUntil.After(time.Now())
+	var unread int64
+	switch {
+	case muted || level == domain.ChatNotifyNone:
+		unread = 0
+	case level == domain.ChatNotifyMentions:
+		unread = mentions
+	default:
+		unread, err = s.repo.UnreadCount(row.RoomID, userID)
+		if err != nil {
+			return dto.ChatRoomDTO{}, err
+		}
+	}
+
+	room := mapChatRoomRow(row, unread)
+	room.MentionCount = mentions
+	room.NotifyLevel = level
+	if muted {
+		mutedUntil := formatChatTime(*setting.MutedUntil)
+		room.NotificationsMutedUntil = &mutedUntil
+	}
+	if row.PostingMode == domain.ChatPostingModerators {
+		isModerator, err := s.isRoomModerator(userID, schoolID, &row)
+		if err != nil {
+			return dto.ChatRoomDTO{}, err
+		}
+		room.CanSend = isModerator
+	}
+	return room, nil
+}
+
+// resolveMentionedUsers returns the users mentioned in content who can read
+// the room. Mentions of the sender or of users outside the room are ignored.
+func (s *chatService) resolveMentionedUsers(userID string, schoolID string, room *repository.ChatRoomRow, content string) ([]string, error) {
+	mentioned := parseChatMentions(content)
+	candidates := make([]string, 0, len(mentioned))
+	for _, mentionedID := range mentioned {
+		if mentionedID != userID {
+			candidates = append(candidates, mentionedID)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	allowed := make(map[string]bool, len(candidates))
+	if isSchoolChatRoom(room, schoolID) {
+		activeMembers, err := s.repo.UsersAreActiveSchoolMembers(candidates, schoolID)
+		if err != nil {
+			return nil, err
+		}
+		allowed = activeMembers
+	} else {
+		recipients, err := s.repo.ListRoomRecipientUserIDs(room.RoomID, schoolID)
+		if err != nil {
+			return nil, err
+		}
+		for _, recipientID := range recipients {
+			allowed[recipientID] = true
+		}
+	}
+
+	result := make([]string, 0, len(candidates))
+	for _, candidateID := range candidates {
+		if allowed[candidateID] {
+			result = append(result, candidateID)
+		}
+	}
+	return result, nil
+}
+
+// notifyChatMentions creates a notification for each mentioned user unless
+// they muted the room or set its level to "none". Failures are ignored so a
+// notification problem never rejects a message that was already stored.
+func (s *chatService) notifyChatMentions(schoolID string, room *repository.ChatRoomRow, message dto.ChatMessageDTO, userIDs []string) {
+	if s.notifService == nil || len(userIDs) == 0 {
+		return
+	}
+	targets, err := s.repo.ListMentionTargets(room.RoomID, schoolID, userIDs)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	preview := chatMentionPreview(message.SenderName, message.Content)
+	for _, target := range targets {
+		if chatNotifyLevel(room, schoolID, target.NotifyLevel) == domain.ChatNotifyNone {
+			continue
+		}
+		if target.MutedUntil != nil && target.MutedUntil.After(now) {
+			continue
+		}
+		_ = s.notifService.Create(&dto.CreateNotificationDTO{
+			UserID:    target.UserID,
+			Type:      domain.NotifChatMention,
+			Title:     chatMentionNotifyTitle,
+			Message:   preview,
+			Link:      chatMentionLink(target.Roles, room.RoomID, message.MessageID),
+			RelatedID: message.MessageID,
+		})
+	}
+}
+
+// parseChatMentions returns the distinct user IDs referenced by mention
+// markup, in order of appearance, capped at maxChatMentions.
+func parseChatMentions(content string) []string {
+	matches := chatMentionPattern.FindAllStringSubmatch(content, -1)
+	seen := make(map[string]bool, len(matches))
+	result := make([]string, 0, len(matches))
+	for _, match := range matches {
+		userID := strings.ToLower(match[2])
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		result = append(result, userID)
+		if len(result) == maxChatMentions {
+			break
+		}
+	}
+	return result
+}
+
+// chatMentionPreview renders mention markup as plain @Name text for the
+// notification body.
+func chatMentionPreview(senderName string, content string) string {
+	preview := chatMentionPattern.ReplaceAllString(content, "@$1")
+	preview = strings.Join(strings.Fields(preview), " ")
+	previewRunes := []rune(preview)
+	if len(previewRunes) > maxChatMentionPreview {
+		preview = string(previewRunes[:maxChatMentionPreview-3]) + "..."
+	}
+	if senderName == "" {
+		return preview
+	}
+	return fmt.Sprintf("%s: %s", senderName, preview)
+}
+
+func chatMentionLink(roles string, roomID string, messageID string) string {
+	audience := "student"
+	roleList := strings.Split(roles, ",")
+	if hasCommentRole(roleList, "admin") {
+		audience = "admin"
+	} else if hasCommentRole(roleList, "teacher") {
+		audience = "teacher"
+	}
+	return fmt.Sprintf("/%s/chat?roomId=%s&messageId=%s", audience, roomID, messageID)
+}
+
+// resolveChatNotifyLevel falls back to "mentions" for the school room, where
+// every member would otherwise get a badge for every message, and to "all"
+// for every other room.
+func resolveChatNotifyLevel(room *repository.ChatRoomRow, schoolID string, setting *domain.ChatRoomSetting) string {
+	if setting == nil {
+		return chatNotifyLevel(room, schoolID, nil)
+	}
+	return chatNotifyLevel(room, schoolID, &setting.NotifyLevel)
+}
+
+func chatNotifyLevel(room *repository.ChatRoomRow, schoolID string, saved *string) string {
+	if saved != nil && *saved != "" {
+		return *saved
+	}
+	if isSchoolChatRoom(room, schoolID) {
+		return domain.ChatNotifyMentions
+	}
+	return domain.ChatNotifyAll
+}
+
+func mapChatRoomSettings(room *repository.ChatRoomRow, schoolID string, setting *domain.ChatRoomSetting) *dto.ChatRoomSettingsDTO {
+	result := &dto.ChatRoomSettingsDTO{
+		RoomID:      room.RoomID,
+		NotifyLevel: resolveChatNotifyLevel(room, schoolID, setting),
+		IsDefault:   setting == nil,
+	}
+	if setting != nil && setting.MutedUntil != nil && setting.MutedUntil.After(time.Now()) {
+		mutedUntil := formatChatTime(*setting.MutedUntil)
+		result.MutedUntil = &mutedUntil
+	}
+	return result
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const maxChatPinnedMessages = 20
+
+func (s *chatService) ListPinnedMessages(userID string, schoolID string, roomID string) (*dto.ChatPinnedMessagesDTO, error) {
+	allowed, _, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+
+	rows, err := s.repo.ListPinnedMessages(roomID)
+	if err != nil {
+		return nil, err
+	}
+	messageRows := make([]repository.ChatMessageRow, 0, len(rows))
+	for _, row := range rows {
+		messageRows = append(messageRows, row.ChatMessageRow)
+	}
+	messages, err := s.mapMessageRows(messageRows, userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	pins := make([]dto.ChatPinnedMessageDTO, 0, len(rows))
+	for i, row := range rows {
+		pins = append(pins, dto.ChatPinnedMessageDTO{
+			Message:      messages[i],
+			PinnedBy:     row.PinnedBy,
+			PinnedByName: row.PinnedByName,
+			PinnedAt:     formatChatTime(row.PinnedAt),
+		})
+	}
+	return &dto.ChatPinnedMessagesDTO{RoomID: roomID, Pins: pins}, nil
+}
+
+// PinMessage pins a message for every member of the room. Pinning an already
+// pinned message returns the existing pin.
+func (s *chatService) PinMessage(userID string, schoolID string, roomID string, messageID string) (*dto.ChatPinUpdatedDTO, error) {
+	if err := s.requirePinPermission(userID, schoolID, roomID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetMessageByID(messageID, roomID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetPin(roomID, messageID)
+	if err == nil {
+		return mapChatPinUpdated(roomID, messageID, existing), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	pin := domain.ChatPinnedMessage{
+		RoomID:    roomID,
+		MessageID: messageID,
+		PinnedBy:  userID,
+		CreatedAt: time.Now(),
+	}
+	created, err := s.repo.PinMessage(&pin, maxChatPinnedMessages)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// Another moderator pinned it concurrently; report their pin.
+		existing, err := s.repo.GetPin(roomID, messageID)
+		if err != nil {
+			return nil, err
+		}
+		return mapChatPinUpdated(roomID, messageID, existing), nil
+	}
+	return mapChatPinUpdated(roomID, messageID, &pin), nil
+}
+
+func (s *chatService) UnpinMessage(userID string, schoolID string, roomID string, messageID string) (*dto.ChatPinUpdatedDTO, error) {
+	if err := s.requirePinPermission(userID, schoolID, roomID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UnpinMessage(roomID, messageID); err != nil {
+		return nil, err
+	}
+	return mapChatPinUpdated(roomID, messageID, nil), nil
+}
+
+// UpdatePostingMode switches a group, class or school room between open
+// posting and moderators-only announcements. Direct messages have no
+// moderators and cannot be restricted.
+func (s *chatService) UpdatePostingMode(userID string, schoolID string, roomID string, postingMode string) (*dto.ChatPostingModeDTO, error) {
+	if postingMode != domain.ChatPostingEveryone && postingMode != domain.ChatPostingModerators {
+		return nil, fmt.Errorf("invalid chat posting mode")
+	}
+	if _, err := s.requireRoomModerator(userID, schoolID, roomID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRoomPostingMode(roomID, schoolID, postingMode); err != nil {
+		return nil, err
+	}
+	return &dto.ChatPostingModeDTO{RoomID: roomID, PostingMode: postingMode}, nil
+}
+
+// requirePinPermission lets room moderators pin, and both participants of a
+// direct message since those rooms have no moderators.
+func (s *chatService) requirePinPermission(userID string, schoolID string, roomID string) error {
+	allowed, room, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("forbidden: chat room access denied")
+	}
+	if isDirectMessageRoom(room) {
+		return nil
+	}
+	isModerator, err := s.isRoomModerator(userID, schoolID, room)
+	if err != nil {
+		return err
+	}
+	if !isModerator {
+		return fmt.Errorf("forbidden: chat room moderator required")
+	}
+	return nil
+}
+
+// ensureCanPost rejects messages from non-moderators in moderators-only rooms,
+// including thread replies.
+func (s *chatService) ensureCanPost(userID string, schoolID string, room *repository.ChatRoomRow) error {
+	if room.PostingMode != domain.ChatPostingModerators {
+		return nil
+	}
+	isModerator, err := s.isRoomModerator(userID, schoolID, room)
+	if err != nil {
+		return err
+	}
+	if !isModerator {
+		return fmt.Errorf("forbidden: chat room posting is limited to moderators")
+	}
+	return nil
+}
+
+func mapChatPinUpdated(roomID string, messageID string, pin *domain.ChatPinnedMessage) *dto.ChatPinUpdatedDTO {
+	result := &dto.ChatPinUpdatedDTO{
+		RoomID:    roomID,
+		MessageID: messageID,
+		IsPinned:  pin != nil,
+	}
+	if pin != nil {
+		pinnedBy := pin.PinnedBy
+		pinnedAt := formatChatTime(pin.CreatedAt)
+		result.PinnedBy = &pinnedBy
+		result.PinnedAt = &pinnedAt
+	}
+	return result
+}
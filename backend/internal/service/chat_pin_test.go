@@ -0,0 +1,102 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repository"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (r *chatRepositoryStub) GetPin(string, string) (*domain.ChatPinnedMessage, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *chatRepositoryStub) PinMessage(pin *domain.ChatPinnedMessage, limit int64) (bool, error) {
+	if r.pinned >= limit {
+		return false, fmt.Errorf("chat pinned messages exceed %d", limit)
+	}
+	r.pinned++
+	return true, nil
+}
+
+func TestPinMessageStopsAtLimit(t *testing.T) {
+	repo := &chatRepositoryStub{
+		room:       newChatTestRoom(chatRoomTypeGroup, ""),
+		message:    newChatTestMessage("sender-1", time.Minute),
+		roomAdmins: map[string]bool{"moderator-1": true},
+		pinned:     maxChatPinnedMessages - 1,
+	}
+	service := NewChatService(repo, nil, nil, nil, nil, 15*time.Minute)
+
+	pin, err := service.PinMessage("moderator-1", "school-1", "room-1", "message-1")
+	if err != nil || !pin.IsPinned {
+		t.Fatalf("expected the last free pin to succeed, got %v", err)
+	}
+	_, err = service.PinMessage("moderator-1", "school-1", "room-1", "message-2")
+	if err == nil || !strings.Contains(err.Error(), "chat pinned messages exceed") {
+		t.Fatalf("expected the pin limit error, got %v", err)
+	}
+	if repo.pinned != maxChatPinnedMessages {
+		t.Fatalf("expected %d pins, got %d", maxChatPinnedMessages, repo.pinned)
+	}
+}
+
+func TestPinMessageRequiresModerator(t *testing.T) {
+	repo := &chatRepositoryStub{
+		room:    newChatTestRoom(chatRoomTypeGroup, chatRefTypeClass),
+		message: newChatTestMessage("sender-1", time.Minute),
+	}
+	service := NewChatService(repo, nil, nil, nil, nil, 15*time.Minute)
+
+	if _, err := service.PinMessage("student-1", "school-1", "room-1", "message-1"); err == nil {
+		t.Fatal("expected students to be unable to pin in class rooms")
+	}
+	if repo.pinned != 0 {
+		t.Fatalf("expected no pin, got %d", repo.pinned)
+	}
+}
+
+func TestEnsureCanPostInModeratorsOnlyRoom(t *testing.T) {
+	schoolID := "school-1"
+	schoolRef := chatRefTypeSchool
+	schoolRoom := &repository.ChatRoomRow{RoomID: "room-school", RoomType: chatRoomTypeGroup, SchoolID: schoolID, RoomRefType: &schoolRef, RoomRefID: &schoolID}
+
+	tests := []struct {
+		name    string
+		room    *repository.ChatRoomRow
+		mode    string
+		userID  string
+		allowed bool
+	}{
+		{"open room member", newChatTestRoom(chatRoomTypeGroup, chatRefTypeClass), domain.ChatPostingEveryone, "student-1", true},
+		{"class room student", newChatTestRoom(chatRoomTypeGroup, chatRefTypeClass), domain.ChatPostingModerators, "student-1", false},
+		{"class room teacher", newChatTestRoom(chatRoomTypeGroup, chatRefTypeClass), domain.ChatPostingModerators, "teacher-1", true},
+		{"group member", newChatTestRoom(chatRoomTypeGroup, ""), domain.ChatPostingModerators, "student-1", false},
+		{"group admin", newChatTestRoom(chatRoomTypeGroup, ""), domain.ChatPostingModerators, "teacher-1", true},
+		{"school room member", schoolRoom, domain.ChatPostingModerators, "teacher-1", false},
+		{"school room admin", schoolRoom, domain.ChatPostingModerators, "admin-1", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			room := *tc.room
+			room.PostingMode = tc.mode
+			repo := &chatRepositoryStub{
+				roomAdmins:   map[string]bool{"teacher-1": true},
+				schoolAdmins: map[string]bool{"admin-1": true},
+			}
+			service := &chatService{repo: repo}
+
+			err := service.ensureCanPost(tc.userID, schoolID, &room)
+			if tc.allowed && err != nil {
+				t.Fatalf("expected %s to post, got %v", tc.userID, err)
+			}
+			if !tc.allowed && (err == nil || !strings.Contains(err.Error(), "limited to moderators")) {
+				t.Fatalf("expected %s to be limited to moderators, got %v", tc.userID, err)
+			}
+		})
+	}
+}
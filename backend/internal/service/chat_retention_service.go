@@ -0,0 +1,278 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	minChatRetentionDays      = 30
+	maxChatRetentionDays      = 3650
+	chatPurgeBatchSize        = 500
+	maxChatTranscriptMessages = 5000
+	chatTranscriptFormatJSON  = "json"
+	chatTranscriptFormatHTML  = "html"
+)
+
+const (
+	chatLogUpdateRetention  = "CHAT_UPDATE_RETENTION"
+	chatLogExportTranscript = "CHAT_EXPORT_TRANSCRIPT"
+)
+
+type ChatRetentionService interface {
+	GetPolicy(schoolID string) (*dto.ChatRetentionPolicyDTO, error)
+	UpdatePolicy(userID string, schoolID string, retentionDays *int) (*dto.ChatRetentionPolicyDTO, error)
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+	ExportTranscript(userID string, schoolID string, roomID string, format string, from *time.Time, to *time.Time) (*dto.ChatTranscriptDTO, error)
+}
+
+type chatRetentionService struct {
+	repo         repository.ChatRetentionRepository
+	chatRepo     repository.ChatRepository
+	mediaService MediaService
+	logRepo      repository.LogRepository
+}
+
+func NewChatRetentionService(repo repository.ChatRetentionRepository, chatRepo repository.ChatRepository, mediaService MediaService, logRepo repository.LogRepository) ChatRetentionService {
+	return &chatRetentionService{
+		repo:         repo,
+		chatRepo:     chatRepo,
+		mediaService: mediaService,
+		logRepo:      logRepo,
+	}
+}
+
+func (s *chatRetentionService) GetPolicy(schoolID string) (*dto.ChatRetentionPolicyDTO, error) {
+	policy, err := s.repo.GetPolicy(schoolID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &dto.ChatRetentionPolicyDTO{SchoolID: schoolID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapChatRetentionPolicy(*policy), nil
+}
+
+// UpdatePolicy sets the school's retention period. A nil or zero value keeps
+// chat history indefinitely.
+func (s *chatRetentionService) UpdatePolicy(userID string, schoolID string, retentionDays *int) (*dto.ChatRetentionPolicyDTO, error) {
+	if retentionDays != nil && *retentionDays == 0 {
+		retentionDays = nil
+	}
+	if retentionDays != nil && (*retentionDays < minChatRetentionDays || *retentionDays > maxChatRetentionDays) {
+		return nil, fmt.Errorf("invalid chat retention days")
+	}
+
+	policy := domain.ChatRetentionPolicy{
+		SchoolID:      schoolID,
+		RetentionDays: retentionDays,
+		UpdatedBy:     userID,
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.repo.UpsertPolicy(&policy); err != nil {
+		return nil, err
+	}
+	if err := s.recordLog(schoolID, userID, chatLogUpdateRetention, map[string]any{
+		"retentionDays": retentionDays,
+	}); err != nil {
+		return nil, err
+	}
+	return s.GetPolicy(schoolID)
+}
+
+// PurgeExpired enforces every school's retention policy once. A failing school
+// does not stop the others; their errors are joined in the result.
+func (s *chatRetentionService) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	policies, err := s.repo.ListEnforcedPolicies()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	var errs []error
+	for _, policy := range policies {
+		purged, err := s.purgeSchool(ctx, policy, now)
+		total += purged
+		if err != nil {
+			errs = append(errs, fmt.Errorf("school %s: %w", policy.SchoolID, err))
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+func (s *chatRetentionService) purgeSchool(ctx context.Context, policy domain.ChatRetentionPolicy, now time.Time) (int64, error) {
+	cutoff := now.AddDate(0, 0, -*policy.RetentionDays)
+	var purged int64
+	var mediaIDs []string
+	for {
+		count, batchMediaIDs, err := s.repo.PurgeMessages(policy.SchoolID, cutoff, chatPurgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		purged += count
+		mediaIDs = append(mediaIDs, batchMediaIDs...)
+		if count < chatPurgeBatchSize {
+			break
+		}
+	}
+
+	if err := s.purgeOrphanMedia(ctx, mediaIDs); err != nil {
+		return purged, err
+	}
+	return purged, s.repo.RecordPurge(policy.SchoolID, now, purged)
+}
+
+// purgeOrphanMedia deletes the stored files of purged attachments that are
+// not reused anywhere else.
+func (s *chatRetentionService) purgeOrphanMedia(ctx context.Context, mediaIDs []string) error {
+	orphanIDs, err := s.repo.ListOrphanMediaIDs(mediaIDs)
+	if err != nil {
+		return err
+	}
+	for _, mediaID := range orphanIDs {
+		if err := s.mediaService.Delete(ctx, mediaID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportTranscript returns the full history of a room in the school, including
+// deleted messages, and records the export in the school log before returning.
+func (s *chatRetentionService) ExportTranscript(userID string, schoolID string, roomID string, format string, from *time.Time, to *time.Time) (*dto.ChatTranscriptDTO, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = chatTranscriptFormatJSON
+	}
+	if format != chatTranscriptFormatJSON && format != chatTranscriptFormatHTML {
+		return nil, fmt.Errorf("invalid chat transcript format")
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, fmt.Errorf("invalid chat transcript date range")
+	}
+
+	room, err := s.chatRepo.GetRoomContext(roomID, schoolID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.ListTranscriptMessages(roomID, from, to, maxChatTranscriptMessages+1)
+	if err != nil {
+		return nil, err
+	}
+	truncated := len(rows) > maxChatTranscriptMessages
+	if truncated {
+		rows = rows[:maxChatTranscriptMessages]
+	}
+
+	messageIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		messageIDs = append(messageIDs, row.MessageID)
+	}
+	attachments, err := s.chatRepo.ListMessageAttachments(messageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	transcript := dto.ChatTranscriptDTO{
+		RoomID:       room.RoomID,
+		RoomName:     transcriptRoomName(*room, rows),
+		RoomType:     room.RoomType,
+		RoomRefType:  room.RoomRefType,
+		SchoolID:     room.SchoolID,
+		SchoolName:   room.SchoolName,
+		From:         formatAPITimePtr(from),
+		To:           formatAPITimePtr(to),
+		ExportedBy:   userID,
+		ExportedAt:   formatChatTime(time.Now()),
+		MessageCount: len(rows),
+		Truncated:    truncated,
+		Messages:     make([]dto.ChatTranscriptMessageDTO, 0, len(rows)),
+	}
+	for _, row := range rows {
+		transcript.Messages = append(transcript.Messages, dto.ChatTranscriptMessageDTO{
+			MessageID:   row.MessageID,
+			SenderID:    row.SenderID,
+			SenderName:  row.SenderName,
+			SenderEmail: row.SenderEmail,
+			Content:     row.Content,
+			MessageType: row.Type,
+			ReplyTo:     row.ReplyTo,
+			CreatedAt:   formatChatTime(row.CreatedAt),
+			EditedAt:    formatAPITimePtr(row.EditedAt),
+			DeletedAt:   formatAPITimePtr(row.DeletedAt),
+			DeletedBy:   row.DeletedBy,
+			Attachments: mapChatAttachments(attachments[row.MessageID]),
+		})
+	}
+
+	if err := s.recordLog(schoolID, userID, chatLogExportTranscript, map[string]any{
+		"roomId":       room.RoomID,
+		"roomName":     transcript.RoomName,
+		"format":       format,
+		"from":         transcript.From,
+		"to":           transcript.To,
+		"messageCount": transcript.MessageCount,
+		"truncated":    truncated,
+	}); err != nil {
+		return nil, err
+	}
+	return &transcript, nil
+}
+
+func (s *chatRetentionService) recordLog(schoolID string, userID string, action string, metadata map[string]any) error {
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return s.logRepo.Create(&domain.Log{
+		SchoolID: schoolID,
+		UserID:   userID,
+		Action:   action,
+		Metadata: string(encoded),
+	})
+}
+
+// transcriptRoomName names direct messages after their participants, since
+// the usual DM name is relative to the viewer and the exporting admin is
+// usually not one of them.
+func transcriptRoomName(room repository.ChatRoomRow, rows []repository.ChatTranscriptRow) string {
+	if room.RoomType != chatRoomTypeDM {
+		return resolveRoomName(room)
+	}
+	seen := make(map[string]bool)
+	names := make([]string, 0, 2)
+	for _, row := range rows {
+		if seen[row.SenderID] {
+			continue
+		}
+		seen[row.SenderID] = true
+		names = append(names, row.SenderName)
+	}
+	if len(names) == 0 {
+		return "Direct message"
+	}
+	return "Direct message: " + strings.Join(names, ", ")
+}
+
+func mapChatRetentionPolicy(policy domain.ChatRetentionPolicy) *dto.ChatRetentionPolicyDTO {
+	updatedAt := formatChatTime(policy.UpdatedAt)
+	updatedBy := policy.UpdatedBy
+	return &dto.ChatRetentionPolicyDTO{
+		SchoolID:           policy.SchoolID,
+		RetentionDays:      policy.RetentionDays,
+		UpdatedBy:          &updatedBy,
+		UpdatedAt:          &updatedAt,
+		LastPurgedAt:       formatAPITimePtr(policy.LastPurgedAt),
+		LastPurgedMessages: policy.LastPurgedMessages,
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	maxChatScheduleAhead       = 90 * 24 * time.Hour
+	maxPendingChatSchedules    = 50
+	chatScheduleBatchSize      = 100
+	chatScheduleSendingLease   = 10 * time.Minute
+	chatScheduleFailedTitle    = "Pesan terjadwal gagal dikirim"
+	chatScheduleStatusAll      = "all"
+	maxChatScheduleErrorLength = 500
+)
+
+// ChatMessagePublisher announces a message posted outside a request, such as
+// by the scheduled message dispatcher, over the realtime hub.
+type ChatMessagePublisher interface {
+	PublishNewMessage(userID string, schoolID string, roomID string, message dto.ChatMessageDTO)
+}
+
+type ChatScheduleService interface {
+	Schedule(userID string, schoolID string, roomID string, input dto.CreateChatScheduledMessageDTO) (*dto.ChatScheduledMessageDTO, error)
+	List(userID string, schoolID string, roomID *string, status string) (*dto.ChatScheduledMessagesDTO, error)
+	Update(userID string, schoolID string, scheduledID string, input dto.UpdateChatScheduledMessageDTO) (*dto.ChatScheduledMessageDTO, error)
+	Cancel(userID string, schoolID string, scheduledID string) (*dto.ChatScheduledMessageDTO, error)
+	DispatchDue(now time.Time) (int, error)
+}
+
+type chatScheduleService struct {
+	repo         repository.ChatScheduleRepository
+	chatService  ChatService
+	publisher    ChatMessagePublisher
+	notifService NotificationService
+}
+
+func NewChatScheduleService(repo repository.ChatScheduleRepository, chatService ChatService, publisher ChatMessagePublisher, notifService NotificationService) ChatScheduleService {
+	return &chatScheduleService{
+		repo:         repo,
+		chatService:  chatService,
+		publisher:    publisher,
+		notifService: notifService,
+	}
+}
+
+// Schedule queues a message for sendAt. Only the room access and the shape of
+// the message are checked now; mutes, posting mode, banned words, attachments
+// and shared content are checked by CreateMessage when the message is sent.
+func (s *chatScheduleService) Schedule(userID string, schoolID string, roomID string, input dto.CreateChatScheduledMessageDTO) (*dto.ChatScheduledMessageDTO, error) {
+	allowed, _, err := s.chatService.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+
+	content, mediaIDs, contentRef, err := normalizeChatMessageInput(input.CreateChatMessageDTO)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateChatSendAt(input.SendAt, time.Now()); err != nil {
+		return nil, err
+	}
+	pending, err := s.repo.CountPending(userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if pending >= maxPendingChatSchedules {
+		return nil, fmt.Errorf("chat scheduled messages exceed %d pending", maxPendingChatSchedules)
+	}
+
+	encodedMediaIDs, err := json.Marshal(mediaIDs)
+	if err != nil {
+		return nil, err
+	}
+	scheduled := domain.ChatScheduledMessage{
+		RoomID:   roomID,
+		SchoolID: schoolID,
+		UserID:   userID,
+		Content:  content,
+		MediaIDs: string(encodedMediaIDs),
+		ReplyTo:  input.ReplyTo,
+		SendAt:   input.SendAt,
+		Status:   domain.ChatScheduledPending,
+	}
+	if contentRef != nil {
+		scheduled.RefType = &contentRef.Type
+		scheduled.RefID = &contentRef.ID
+	}
+	if err := s.repo.Create(&scheduled); err != nil {
+		return nil, err
+	}
+	return s.get(scheduled.ID, userID, schoolID)
+}
+
+func (s *chatScheduleService) List(userID string, schoolID string, roomID *string, status string) (*dto.ChatScheduledMessagesDTO, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	switch status {
+	case "":
+		status = domain.ChatScheduledPending
+	case chatScheduleStatusAll:
+		status = ""
+	case domain.ChatScheduledPending, domain.ChatScheduledSending, domain.ChatScheduledSent, domain.ChatScheduledCancelled, domain.ChatScheduledFailed:
+	default:
+		return nil, fmt.Errorf("invalid chat scheduled status")
+	}
+
+	rows, err := s.repo.List(userID, schoolID, roomID, status)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.ChatScheduledMessagesDTO{ScheduledMessages: make([]dto.ChatScheduledMessageDTO, 0, len(rows))}
+	for _, row := range rows {
+		result.ScheduledMessages = append(result.ScheduledMessages, mapChatScheduledMessage(row))
+	}
+	return result, nil
+}
+
+// Update changes the content or send time of a pending message. The
+// attachments and shared content stay as scheduled.
+func (s *chatScheduleService) Update(userID string, schoolID string, scheduledID string, input dto.UpdateChatScheduledMessageDTO) (*dto.ChatScheduledMessageDTO, error) {
+	scheduled, err := s.repo.GetByID(scheduledID, userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if scheduled.Status != domain.ChatScheduledPending {
+		return nil, fmt.Errorf("chat scheduled message is no longer pending")
+	}
+
+	content := scheduled.Content
+	if input.Content != nil {
+		mediaIDs, err := decodeChatScheduledMediaIDs(scheduled.MediaIDs)
+		if err != nil {
+			return nil, err
+		}
+		content, _, _, err = normalizeChatMessageInput(dto.CreateChatMessageDTO{
+			Content:  *input.Content,
+			MediaIDs: mediaIDs,
+			RefType:  scheduled.RefType,
+			RefID:    scheduled.RefID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sendAt := scheduled.SendAt
+	if input.SendAt != nil {
+		if err := validateChatSendAt(*input.SendAt, time.Now()); err != nil {
+			return nil, err
+		}
+		sendAt = *input.SendAt
+	}
+
+	if err := s.repo.UpdatePending(scheduled.ID, content, sendAt); err != nil {
+		return nil, err
+	}
+	return s.get(scheduled.ID, userID, schoolID)
+}
+
+func (s *chatScheduleService) Cancel(userID string, schoolID string, scheduledID string) (*dto.ChatScheduledMessageDTO, error) {
+	if err := s.repo.CancelPending(scheduledID, userID, schoolID); err != nil {
+		return nil, err
+	}
+	return s.get(scheduledID, userID, schoolID)
+}
+
+// DispatchDue sends every scheduled message due at now through CreateMessage,
+// so it passes the same rules as a message sent by hand. A message rejected by
+// those rules is marked failed and its sender is notified. A message still
+// sending after chatScheduleSendingLease is retried, so a dispatcher that
+// stopped mid-send cannot leave it stuck; the message is posted and marked
+// sent in one transaction, so a retry never posts it twice. It returns the
+// number of messages sent.
+func (s *chatScheduleService) DispatchDue(now time.Time) (int, error) {
+	sent := 0
+	var errs []error
+	for {
+		batch, err := s.repo.ClaimDue(now, now.Add(-chatScheduleSendingLease), chatScheduleBatchSize)
+		if err != nil {
+			return sent, errors.Join(append(errs, err)...)
+		}
+		for _, scheduled := range batch {
+			if err := s.dispatch(scheduled); err != nil {
+				errs = append(errs, fmt.Errorf("scheduled message %s: %w", scheduled.ID, err))
+				continue
+			}
+			sent++
+		}
+		if len(batch) < chatScheduleBatchSize {
+			break
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// dispatch returns an error only when the outcome could not be recorded.
+func (s *chatScheduleService) dispatch(scheduled domain.ChatScheduledMessage) error {
+	mediaIDs, err := decodeChatScheduledMediaIDs(scheduled.MediaIDs)
+	if err != nil {
+		return s.fail(scheduled, err)
+	}
+	message, err := s.chatService.CreateScheduledMessage(scheduled, dto.CreateChatMessageDTO{
+		Content:  scheduled.Content,
+		MediaIDs: mediaIDs,
+		ReplyTo:  scheduled.ReplyTo,
+		RefType:  scheduled.RefType,
+		RefID:    scheduled.RefID,
+	})
+	if errors.Is(err, repository.ErrChatScheduledMessageReclaimed) {
+		// Another dispatcher claimed it again after the lease and owns it now.
+		return nil
+	}
+	if err != nil {
+		return s.fail(scheduled, err)
+	}
+	if s.publisher != nil {
+		s.publisher.PublishNewMessage(scheduled.UserID, scheduled.SchoolID, scheduled.RoomID, *message)
+	}
+	return nil
+}
+
+func (s *chatScheduleService) fail(scheduled domain.ChatScheduledMessage, cause error) error {
+	reason := cause.Error()
+	if len([]rune(reason)) > maxChatScheduleErrorLength {
+		reason = string([]rune(reason)[:maxChatScheduleErrorLength])
+	}
+	if err := s.repo.MarkFailed(scheduled.ID, reason); err != nil {
+		return err
+	}
+	if s.notifService != nil {
+		_ = s.notifService.Create(&dto.CreateNotificationDTO{
+			UserID:    scheduled.UserID,
+			Type:      domain.NotifChatScheduleFailed,
+			Title:     chatScheduleFailedTitle,
+			Message:   chatMentionPreview("", scheduled.Content),
+			RelatedID: scheduled.ID,
+		})
+	}
+	return nil
+}
+
+func (s *chatScheduleService) get(scheduledID string, userID string, schoolID string) (*dto.ChatScheduledMessageDTO, error) {
+	scheduled, err := s.repo.GetByID(scheduledID, userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	result := mapChatScheduledMessage(*scheduled)
+	return &result, nil
+}
+
+func validateChatSendAt(sendAt time.Time, now time.Time) error {
+	if !sendAt.After(now) {
+		return fmt.Errorf("chat scheduled send time must be in the future")
+	}
+	if sendAt.After(now.Add(maxChatScheduleAhead)) {
+		return fmt.Errorf("chat scheduled send time exceeds 90 days")
+	}
+	return nil
+}
+
+func decodeChatScheduledMediaIDs(encoded string) ([]string, error) {
+	mediaIDs := make([]string, 0)
+	if strings.TrimSpace(encoded) == "" {
+		return mediaIDs, nil
+	}
+	if err := json.Unmarshal([]byte(encoded), &mediaIDs); err != nil {
+		return nil, err
+	}
+	return mediaIDs, nil
+}
+
+func mapChatScheduledMessage(scheduled domain.ChatScheduledMessage) dto.ChatScheduledMessageDTO {
+	mediaIDs, err := decodeChatScheduledMediaIDs(scheduled.MediaIDs)
+	if err != nil {
+		mediaIDs = make([]string, 0)
+	}
+	return dto.ChatScheduledMessageDTO{
+		ScheduledID: scheduled.ID,
+		RoomID:      scheduled.RoomID,
+		Content:     scheduled.Content,
+		MediaIDs:    mediaIDs,
+		ReplyTo:     scheduled.ReplyTo,
+		RefType:     scheduled.RefType,
+		RefID:       scheduled.RefID,
+		SendAt:      formatChatTime(scheduled.SendAt),
+		Status:      scheduled.Status,
+		MessageID:   scheduled.MessageID,
+		Error:       scheduled.Error,
+		SentAt:      formatAPITimePtr(scheduled.SentAt),
+		CreatedAt:   formatChatTime(scheduled.CreatedAt),
+		UpdatedAt:   formatChatTime(scheduled.UpdatedAt),
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"testing"
+	"time"
+)
+
+type chatScheduleRepositoryStub struct {
+	repository.ChatScheduleRepository
+	due      []domain.ChatScheduledMessage
+	failedID string
+}
+
+func (r *chatScheduleRepositoryStub) ClaimDue(time.Time, time.Time, int) ([]domain.ChatScheduledMessage, error) {
+	due := r.due
+	r.due = nil
+	return due, nil
+}
+
+func (r *chatScheduleRepositoryStub) MarkFailed(id string, _ string) error {
+	r.failedID = id
+	return nil
+}
+
+type scheduledChatServiceStub struct {
+	ChatService
+	err   error
+	posts int
+}
+
+func (s *scheduledChatServiceStub) CreateScheduledMessage(domain.ChatScheduledMessage, dto.CreateChatMessageDTO) (*dto.ChatMessageDTO, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.posts++
+	return &dto.ChatMessageDTO{MessageID: "message-1"}, nil
+}
+
+func TestDispatchDueLeavesReclaimedMessagesToTheirNewClaim(t *testing.T) {
+	repo := &chatScheduleRepositoryStub{due: []domain.ChatScheduledMessage{{ID: "scheduled-1", MediaIDs: "[]"}}}
+	chat := &scheduledChatServiceStub{err: repository.ErrChatScheduledMessageReclaimed}
+	s := NewChatScheduleService(repo, chat, nil, nil)
+
+	if _, err := s.DispatchDue(time.Now()); err != nil {
+		t.Fatalf("DispatchDue() error = %v", err)
+	}
+	if repo.failedID != "" {
+		t.Fatalf("expected a reclaimed message not to be marked failed, got %q", repo.failedID)
+	}
+}
+
+func TestDispatchDuePostsThroughTheScheduledPath(t *testing.T) {
+	repo := &chatScheduleRepositoryStub{due: []domain.ChatScheduledMessage{{ID: "scheduled-1", MediaIDs: "[]"}}}
+	chat := &scheduledChatServiceStub{}
+	s := NewChatScheduleService(repo, chat, nil, nil)
+
+	sent, err := s.DispatchDue(time.Now())
+	if err != nil || sent != 1 || chat.posts != 1 {
+		t.Fatalf("expected one scheduled post, got sent %d posts %d err %v", sent, chat.posts, err)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"backend/internal/repository"
 	"errors"
 	"fmt"
+	"html"
 	"strings"
 	"time"
+	"unicode"
 
 	"gorm.io/gorm"
 )
@@ -16,12 +18,17 @@ const (
 	chatRoomTypeGroup   = "group"
 	chatRoomTypeDM      = "dm"
 	chatRefTypeSchool   = "school"
+	chatRefTypeClass    = "class"
+	chatRefTypeSubject  = "subject"
 	chatMessageTypeText = "text"
 	chatMessageTypeFile = "file"
 	maxChatMessageLimit = 50
 	maxChatContentLen   = 5000
 	maxChatAttachments  = 5
 	maxChatRoomNameLen  = 150
+	maxChatReactionLen  = 32
+	maxChatSearchLen    = 200
+	defaultSearchLimit  = 20
 	defaultSchoolRoom   = "Ruang sekolah"
 )
 
@@ -36,22 +43,60 @@ type ChatService interface {
 	LeaveGroupRoom(userID string, schoolID string, roomID string) error
 	AddGroupMembers(userID string, schoolID string, roomID string, memberUserIDs []string) error
 	RemoveGroupMember(userID string, schoolID string, roomID string, targetUserID string) error
+	SyncAllClassRooms() (int, error)
 	ListMessages(userID string, schoolID string, roomID string, limit int, before *time.Time) (*dto.ChatMessagesResponseDTO, error)
-	CreateMessage(userID string, schoolID string, roomID string, content string, mediaIDs []string) (*dto.ChatMessageDTO, error)
+	SearchMessages(userID string, schoolID string, query dto.ChatMessageSearchQueryDTO) (*dto.ChatMessageSearchResponseDTO, error)
+	CreateMessage(userID string, schoolID string, roomID string, input dto.CreateChatMessageDTO) (*dto.ChatMessageDTO, error)
+	CreateScheduledMessage(scheduled domain.ChatScheduledMessage, input dto.CreateChatMessageDTO) (*dto.ChatMessageDTO, error)
+	ResolveContentCards(schoolID string, refType string, refID string, userIDs []string) (map[string]*dto.ChatContentCardDTO, error)
+	GetThread(userID string, schoolID string, roomID string, messageID string, limit int, before *time.Time) (*dto.ChatThreadResponseDTO, error)
+	GetThreadSummary(userID string, schoolID string, roomID string, parentID string) (*dto.ChatThreadSummaryDTO, error)
+	AddReaction(userID string, schoolID string, roomID string, messageID string, emoji string) (*dto.ChatReactionUpdatedDTO, error)
+	RemoveReaction(userID string, schoolID string, roomID string, messageID string, emoji string) (*dto.ChatReactionUpdatedDTO, error)
+	ListPinnedMessages(userID string, schoolID string, roomID string) (*dto.ChatPinnedMessagesDTO, error)
+	PinMessage(userID string, schoolID string, roomID string, messageID string) (*dto.ChatPinUpdatedDTO, error)
+	UnpinMessage(userID string, schoolID string, roomID string, messageID string) (*dto.ChatPinUpdatedDTO, error)
+	UpdatePostingMode(userID string, schoolID string, roomID string, postingMode string) (*dto.ChatPostingModeDTO, error)
+	EditMessage(userID string, schoolID string, roomID string, messageID string, content string) (*dto.ChatMessageDTO, error)
+	DeleteMessage(userID string, schoolID string, roomID string, messageID string) (*dto.ChatMessageDeletedDTO, error)
 	MarkRead(userID string, schoolID string, roomID string, lastReadMessageID *string) (*dto.ChatReadReceiptDTO, error)
 	GetReadSummary(userID string, schoolID string, roomID string) (*dto.ChatReadSummaryDTO, error)
+	GetRoomSettings(userID string, schoolID string, roomID string) (*dto.ChatRoomSettingsDTO, error)
+	UpdateRoomSettings(userID string, schoolID string, roomID string, input dto.UpdateChatRoomSettingsDTO) (*dto.ChatRoomSettingsDTO, error)
+	ReportMessage(userID string, schoolID string, roomID string, messageID string, reason string) (*dto.ChatReportDTO, error)
+	ListReports(userID string, schoolID string, status string, page int, limit int) (*dto.ChatReportListDTO, error)
+	ResolveReport(userID string, schoolID string, reportID string, status string, note string, removeMessage bool) (*dto.ChatReportDTO, *dto.ChatMessageDeletedDTO, error)
+	MuteMember(userID string, schoolID string, roomID string, targetUserID string, durationMinutes int, reason string) (*dto.ChatMuteDTO, error)
+	UnmuteMember(userID string, schoolID string, roomID string, targetUserID string) (*dto.ChatMuteDTO, error)
+	GetBannedWords(userID string, schoolID string) (*dto.ChatBannedWordsDTO, error)
+	UpdateBannedWords(userID string, schoolID string, words []string) (*dto.ChatBannedWordsDTO, error)
 	ListRealtimeRecipients(userID string, schoolID string, roomID string) ([]string, error)
+	ListPresenceRecipients(userID string, schoolID string) ([]string, error)
+	ListModerationRecipients(userID string, schoolID string, roomID string) ([]string, error)
 	CanAccessSchoolChat(userID string, schoolID string) (bool, error)
 	CanAccessRoom(userID string, schoolID string, roomID string) (bool, *repository.ChatRoomRow, error)
 }
 
 type chatService struct {
-	repo      repository.ChatRepository
-	mediaRepo repository.MediaRepository
+	repo           repository.ChatRepository
+	mediaRepo      repository.MediaRepository
+	moderationRepo repository.ChatModerationRepository
+	logRepo        repository.LogRepository
+	notifService   NotificationService
+	// messageEditWindow limits how long after sending a sender may edit or
+	// delete their own message. Zero means no limit.
+	messageEditWindow time.Duration
 }
 
-func NewChatService(repo repository.ChatRepository, mediaRepo repository.MediaRepository) ChatService {
-	return &chatService{repo: repo, mediaRepo: mediaRepo}
+func NewChatService(repo repository.ChatRepository, mediaRepo repository.MediaRepository, moderationRepo repository.ChatModerationRepository, logRepo repository.LogRepository, notifService NotificationService, messageEditWindow time.Duration) ChatService {
+	return &chatService{
+		repo:              repo,
+		mediaRepo:         mediaRepo,
+		moderationRepo:    moderationRepo,
+		logRepo:           logRepo,
+		notifService:      notifService,
+		messageEditWindow: messageEditWindow,
+	}
 }
 
 func (s *chatService) ListMyRooms(userID string, schoolID string, search string) ([]dto.ChatRoomDTO, error) {
@@ -68,13 +113,31 @@ func (s *chatService) ListMyRooms(userID string, schoolID string, search string)
 		return nil, err
 	}
 
+	mutes, err := s.moderationRepo.ListActiveMutes(userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.repo.ListRoomSettings(userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+
 	rooms := make([]dto.ChatRoomDTO, 0, len(rows))
 	for _, row := range rows {
-		unread, err := s.repo.UnreadCount(row.RoomID, userID)
+		var setting *domain.ChatRoomSetting
+		if saved, ok := settings[row.RoomID]; ok {
+			setting = &saved
+		}
+		room, err := s.mapRoomForUser(row, userID, schoolID, setting)
 		if err != nil {
 			return nil, err
 		}
-		rooms = append(rooms, mapChatRoomRow(row, unread))
+		if mutedUntil, ok := mutes[row.RoomID]; ok {
+			value := formatChatTime(mutedUntil)
+			room.CanSend = false
+			room.MutedUntil = &value
+		}
+		rooms = append(rooms, room)
 	}
 	return rooms, nil
 }
@@ -167,11 +230,19 @@ func (s *chatService) OpenSchoolRoom(userID string, schoolID string) (*dto.ChatR
 	if err != nil {
 		return nil, err
 	}
-	unread, err := s.repo.UnreadCount(room.ID, userID)
+	roomDTO, err := s.mapRoomForCurrentUser(*context, userID, schoolID)
 	if err != nil {
 		return nil, err
 	}
-	roomDTO := mapChatRoomRow(*context, unread)
+	mute, err := s.moderationRepo.GetActiveMute(room.ID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		mutedUntil := formatChatTime(mute.MutedUntil)
+		roomDTO.CanSend = false
+		roomDTO.MutedUntil = &mutedUntil
+	}
 	return &roomDTO, nil
 }
 
@@ -211,11 +282,10 @@ func (s *chatService) OpenDirectMessage(userID string, schoolID string, targetUs
 		}
 	}
 
-	unread, err := s.repo.UnreadCount(room.RoomID, userID)
+	mapped, err := s.mapRoomForCurrentUser(*room, userID, schoolID)
 	if err != nil {
 		return nil, err
 	}
-	mapped := mapChatRoomRow(*room, unread)
 	return &mapped, nil
 }
 
@@ -281,6 +351,7 @@ func (s *chatService) CreateGroupRoom(userID string, schoolID string, roomName s
 		return nil, err
 	}
 	mapped := mapChatRoomRow(*context, 0)
+	mapped.NotifyLevel = resolveChatNotifyLevel(context, schoolID, nil)
 	return &mapped, nil
 }
 
@@ -319,11 +390,10 @@ func (s *chatService) RenameGroupRoom(userID string, schoolID string, roomID str
 	if err != nil {
 		return nil, err
 	}
-	unread, err := s.repo.UnreadCount(roomID, userID)
+	mapped, err := s.mapRoomForCurrentUser(*context, userID, schoolID)
 	if err != nil {
 		return nil, err
 	}
-	mapped := mapChatRoomRow(*context, unread)
 	return &mapped, nil
 }
 
@@ -369,6 +439,27 @@ func (s *chatService) RemoveGroupMember(userID string, schoolID string, roomID s
 	return s.repo.RemoveGroupRoomMember(roomID, schoolID, targetUserID)
 }
 
+// SyncAllClassRooms runs SyncClassRooms for every active class, repairing
+// rooms left behind by a failed sync or created before class rooms existed.
+// A failing class does not stop the others. It returns the number of classes
+// synced.
+func (s *chatService) SyncAllClassRooms() (int, error) {
+	classIDs, err := s.repo.ListActiveClassIDs()
+	if err != nil {
+		return 0, err
+	}
+	synced := 0
+	var errs []error
+	for _, classID := range classIDs {
+		if err := s.repo.SyncClassRooms(classID); err != nil {
+			errs = append(errs, fmt.Errorf("class %s: %w", classID, err))
+			continue
+		}
+		synced++
+	}
+	return synced, errors.Join(errs...)
+}
+
 func (s *chatService) ListMessages(userID string, schoolID string, roomID string, limit int, before *time.Time) (*dto.ChatMessagesResponseDTO, error) {
 	allowed, _, err := s.CanAccessRoom(userID, schoolID, roomID)
 	if err != nil {
@@ -391,18 +482,10 @@ func (s *chatService) ListMessages(userID string, schoolID string, roomID string
 		rows = rows[1:]
 	}
 
-	messages := make([]dto.ChatMessageDTO, 0, len(rows))
-	messageIDs := make([]string, 0, len(rows))
-	for _, row := range rows {
-		messageIDs = append(messageIDs, row.MessageID)
-	}
-	attachmentsByMessage, err := s.repo.ListMessageAttachments(messageIDs)
+	messages, err := s.mapMessageRows(rows, userID, schoolID)
 	if err != nil {
 		return nil, err
 	}
-	for _, row := range rows {
-		messages = append(messages, mapChatMessageRow(row, userID, attachmentsByMessage[row.MessageID]))
-	}
 
 	var nextBefore *string
 	if hasMore && len(rows) > 0 {
@@ -417,8 +500,117 @@ func (s *chatService) ListMessages(userID string, schoolID string, roomID string
 	}, nil
 }
 
-func (s *chatService) CreateMessage(userID string, schoolID string, roomID string, content string, mediaIDs []string) (*dto.ChatMessageDTO, error) {
-	allowed, _, err := s.CanAccessRoom(userID, schoolID, roomID)
+func (s *chatService) SearchMessages(userID string, schoolID string, query dto.ChatMessageSearchQueryDTO) (*dto.ChatMessageSearchResponseDTO, error) {
+	allowed, err := s.CanAccessSchoolChat(userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat school access denied")
+	}
+
+	text := strings.TrimSpace(query.Query)
+	if text == "" {
+		return nil, fmt.Errorf("chat search query is required")
+	}
+	if len([]rune(text)) > maxChatSearchLen {
+		return nil, fmt.Errorf("chat search query exceeds %d characters", maxChatSearchLen)
+	}
+	if query.From != nil && query.To != nil && query.From.After(*query.To) {
+		return nil, fmt.Errorf("invalid chat search date range")
+	}
+	if query.RoomID != nil {
+		allowed, _, err := s.CanAccessRoom(userID, schoolID, *query.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("forbidden: chat room access denied")
+		}
+	}
+
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxChatMessageLimit {
+		limit = maxChatMessageLimit
+	}
+
+	rows, total, err := s.repo.SearchMessages(repository.ChatMessageSearchFilter{
+		UserID:        userID,
+		SchoolID:      schoolID,
+		Query:         text,
+		RoomID:        query.RoomID,
+		SenderID:      query.SenderID,
+		From:          query.From,
+		To:            query.To,
+		HasAttachment: query.HasAttachment,
+		Limit:         limit,
+		Offset:        (page - 1) * limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.ChatMessageSearchResultDTO, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, dto.ChatMessageSearchResultDTO{
+			MessageID: row.MessageID,
+			RoomID:    row.RoomID,
+			RoomName: resolveRoomName(repository.ChatRoomRow{
+				RoomName:      row.RoomName,
+				RoomType:      row.RoomType,
+				DMTargetName:  row.DMTargetName,
+				DMTargetEmail: row.DMTargetEmail,
+			}),
+			RoomType:        row.RoomType,
+			SenderID:        row.SenderID,
+			SenderName:      row.SenderName,
+			MessageType:     row.Type,
+			Snippet:         highlightChatSnippet(row.Snippet),
+			AttachmentCount: row.AttachmentCount,
+			ReplyTo:         row.ReplyTo,
+			CreatedAt:       formatChatTime(row.CreatedAt),
+			// Cursors have second precision, so step past the message's second.
+			JumpBefore: formatChatTime(row.CreatedAt.Add(time.Second)),
+		})
+	}
+
+	return &dto.ChatMessageSearchResponseDTO{
+		Data:       results,
+		TotalItems: total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// highlightChatSnippet HTML-escapes a search snippet and turns the
+// repository's match markers into <mark> tags, so clients can render it as
+// HTML without trusting message content.
+func highlightChatSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, repository.ChatSearchMatchStart, "<mark>")
+	return strings.ReplaceAll(escaped, repository.ChatSearchMatchStop, "</mark>")
+}
+
+func (s *chatService) CreateMessage(userID string, schoolID string, roomID string, input dto.CreateChatMessageDTO) (*dto.ChatMessageDTO, error) {
+	return s.createMessage(userID, schoolID, roomID, input, nil)
+}
+
+// CreateScheduledMessage posts input as the sender of scheduled under the same
+// rules as CreateMessage, and marks scheduled sent with the message.
+func (s *chatService) CreateScheduledMessage(scheduled domain.ChatScheduledMessage, input dto.CreateChatMessageDTO) (*dto.ChatMessageDTO, error) {
+	return s.createMessage(scheduled.UserID, scheduled.SchoolID, scheduled.RoomID, input, &scheduled)
+}
+
+func (s *chatService) createMessage(userID string, schoolID string, roomID string, input dto.CreateChatMessageDTO, scheduled *domain.ChatScheduledMessage) (*dto.ChatMessageDTO, error) {
+	allowed, room, err := s.CanAccessRoom(userID, schoolID, roomID)
 	if err != nil {
 		return nil, err
 	}
@@ -426,19 +618,18 @@ func (s *chatService) CreateMessage(userID string, schoolID string, roomID strin
 		return nil, fmt.Errorf("forbidden: chat room access denied")
 	}
 
-	content = strings.TrimSpace(content)
-	attachmentMediaIDs, err := validateChatMediaIDs(mediaIDs)
+	content, attachmentMediaIDs, contentRef, err := normalizeChatMessageInput(input)
 	if err != nil {
 		return nil, err
 	}
-	if content == "" && len(attachmentMediaIDs) == 0 {
-		return nil, fmt.Errorf("chat message content is required")
+	if err := s.ensureNotMuted(userID, roomID); err != nil {
+		return nil, err
 	}
-	if len([]rune(content)) > maxChatContentLen {
-		return nil, fmt.Errorf("chat message content exceeds %d characters", maxChatContentLen)
+	if err := s.ensureCanPost(userID, schoolID, room); err != nil {
+		return nil, err
 	}
-	if len(attachmentMediaIDs) > maxChatAttachments {
-		return nil, fmt.Errorf("chat message attachments exceed %d files", maxChatAttachments)
+	if err := s.ensureNoBannedWords(userID, schoolID, roomID, content); err != nil {
+		return nil, err
 	}
 	if len(attachmentMediaIDs) > 0 {
 		attachmentMediaIDs, err = prepareAttachableMediaIDs(s.mediaRepo, attachmentMediaIDs, schoolID, userID, false)
@@ -447,6 +638,21 @@ func (s *chatService) CreateMessage(userID string, schoolID string, roomID strin
 		}
 	}
 
+	if contentRef != nil {
+		if err := s.ensureCanShareContent(userID, schoolID, *contentRef); err != nil {
+			return nil, err
+		}
+	}
+
+	threadParentID, err := s.resolveThreadParent(roomID, input.ReplyTo)
+	if err != nil {
+		return nil, err
+	}
+	mentionUserIDs, err := s.resolveMentionedUsers(userID, schoolID, room, content)
+	if err != nil {
+		return nil, err
+	}
+
 	messageType := chatMessageTypeText
 	if len(attachmentMediaIDs) > 0 {
 		messageType = chatMessageTypeFile
@@ -456,22 +662,363 @@ func (s *chatService) CreateMessage(userID string, schoolID string, roomID strin
 		UserID:    userID,
 		Content:   content,
 		Type:      messageType,
+		ReplyTo:   threadParentID,
 		CreatedAt: time.Now(),
 	}
-	if err := s.repo.CreateMessageWithAttachments(&message, attachmentMediaIDs); err != nil {
+	if contentRef != nil {
+		message.RefType = &contentRef.Type
+		message.RefID = &contentRef.ID
+	}
+	if scheduled != nil {
+		err = s.repo.CreateScheduledMessage(scheduled, &message, attachmentMediaIDs, mentionUserIDs)
+	} else {
+		err = s.repo.CreateMessageWithAttachments(&message, attachmentMediaIDs, mentionUserIDs)
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	row, err := s.repo.GetMessageByID(message.ID, roomID)
+	mapped, err := s.getMappedMessage(message.ID, roomID, userID, schoolID)
 	if err != nil {
 		return nil, err
 	}
-	attachmentsByMessage, err := s.repo.ListMessageAttachments([]string{message.ID})
+	s.notifyChatMentions(schoolID, room, *mapped, mentionUserIDs)
+	return mapped, nil
+}
+
+// normalizeChatMessageInput trims the content and checks the shape of a new
+// message before any room-specific rule is applied.
+func normalizeChatMessageInput(input dto.CreateChatMessageDTO) (string, []string, *repository.ChatContentRef, error) {
+	content := strings.TrimSpace(input.Content)
+	mediaIDs, err := validateChatMediaIDs(input.MediaIDs)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	contentRef, err := parseChatContentRef(input.RefType, input.RefID)
 	if err != nil {
+		return "", nil, nil, err
+	}
+	if content == "" && len(mediaIDs) == 0 && contentRef == nil {
+		return "", nil, nil, fmt.Errorf("chat message content is required")
+	}
+	if len([]rune(content)) > maxChatContentLen {
+		return "", nil, nil, fmt.Errorf("chat message content exceeds %d characters", maxChatContentLen)
+	}
+	if len(mediaIDs) > maxChatAttachments {
+		return "", nil, nil, fmt.Errorf("chat message attachments exceed %d files", maxChatAttachments)
+	}
+	return content, mediaIDs, contentRef, nil
+}
+
+// resolveThreadParent validates replyTo and returns the top-level message of
+// its thread, so replies to replies stay in a single flat thread.
+func (s *chatService) resolveThreadParent(roomID string, replyTo *string) (*string, error) {
+	if replyTo == nil || strings.TrimSpace(*replyTo) == "" {
+		return nil, nil
+	}
+	parent, err := s.repo.GetMessageByID(strings.TrimSpace(*replyTo), roomID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("invalid chat reply target")
+		}
 		return nil, err
 	}
-	mapped := mapChatMessageRow(*row, userID, attachmentsByMessage[row.MessageID])
-	return &mapped, nil
+	if parent.ReplyTo != nil {
+		return parent.ReplyTo, nil
+	}
+	return &parent.MessageID, nil
+}
+
+func (s *chatService) GetThread(userID string, schoolID string, roomID string, messageID string, limit int, before *time.Time) (*dto.ChatThreadResponseDTO, error) {
+	allowed, _, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+
+	parent, err := s.repo.GetMessageByID(messageID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.ReplyTo != nil {
+		parent, err = s.repo.GetMessageByID(*parent.ReplyTo, roomID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if limit <= 0 || limit > maxChatMessageLimit {
+		limit = maxChatMessageLimit
+	}
+	rows, err := s.repo.ListThreadReplies(parent.MessageID, roomID, limit+1, before)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[1:]
+	}
+
+	messages, err := s.mapMessageRows(append([]repository.ChatMessageRow{*parent}, rows...), userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextBefore *string
+	if hasMore && len(rows) > 0 {
+		value := formatChatTime(rows[0].CreatedAt)
+		nextBefore = &value
+	}
+
+	return &dto.ChatThreadResponseDTO{
+		Parent:     messages[0],
+		Replies:    messages[1:],
+		ReplyCount: parent.ReplyCount,
+		NextBefore: nextBefore,
+		HasMore:    hasMore,
+	}, nil
+}
+
+func (s *chatService) GetThreadSummary(userID string, schoolID string, roomID string, parentID string) (*dto.ChatThreadSummaryDTO, error) {
+	allowed, _, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+
+	parent, err := s.repo.GetMessageByID(parentID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ChatThreadSummaryDTO{
+		RoomID:          roomID,
+		ParentMessageID: parent.MessageID,
+		ReplyCount:      parent.ReplyCount,
+	}, nil
+}
+
+func (s *chatService) AddReaction(userID string, schoolID string, roomID string, messageID string, emoji string) (*dto.ChatReactionUpdatedDTO, error) {
+	return s.setReaction(userID, schoolID, roomID, messageID, emoji, true)
+}
+
+func (s *chatService) RemoveReaction(userID string, schoolID string, roomID string, messageID string, emoji string) (*dto.ChatReactionUpdatedDTO, error) {
+	return s.setReaction(userID, schoolID, roomID, messageID, emoji, false)
+}
+
+func (s *chatService) setReaction(userID string, schoolID string, roomID string, messageID string, emoji string, reacted bool) (*dto.ChatReactionUpdatedDTO, error) {
+	allowed, _, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+
+	emoji, err = normalizeChatReactionEmoji(emoji)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetMessageByID(messageID, roomID); err != nil {
+		return nil, err
+	}
+
+	if reacted {
+		err = s.repo.AddReaction(messageID, userID, emoji)
+	} else {
+		err = s.repo.RemoveReaction(messageID, userID, emoji)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountReaction(messageID, emoji)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ChatReactionUpdatedDTO{
+		MessageID: messageID,
+		RoomID:    roomID,
+		UserID:    userID,
+		Emoji:     emoji,
+		Reacted:   reacted,
+		Count:     int(count),
+	}, nil
+}
+
+// normalizeChatReactionEmoji accepts a single emoji, including multi-codepoint
+// sequences such as skin tones and ZWJ families, and rejects plain text.
+func normalizeChatReactionEmoji(emoji string) (string, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" {
+		return "", fmt.Errorf("chat reaction emoji is required")
+	}
+	if len(emoji) > maxChatReactionLen {
+		return "", fmt.Errorf("invalid chat reaction emoji")
+	}
+	for _, r := range emoji {
+		if r < 0x80 || unicode.IsSpace(r) || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return "", fmt.Errorf("invalid chat reaction emoji")
+		}
+	}
+	return emoji, nil
+}
+
+func (s *chatService) getMappedMessage(messageID string, roomID string, userID string, schoolID string) (*dto.ChatMessageDTO, error) {
+	row, err := s.repo.GetMessageByID(messageID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.mapMessageRows([]repository.ChatMessageRow{*row}, userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	return &messages[0], nil
+}
+
+// mapMessageRows loads attachments, reactions and content cards for rows and
+// maps them from the point of view of userID.
+func (s *chatService) mapMessageRows(rows []repository.ChatMessageRow, userID string, schoolID string) ([]dto.ChatMessageDTO, error) {
+	messageIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		messageIDs = append(messageIDs, row.MessageID)
+	}
+	attachmentsByMessage, err := s.repo.ListMessageAttachments(messageIDs)
+	if err != nil {
+		return nil, err
+	}
+	reactionsByMessage, err := s.repo.ListMessageReactions(messageIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	pinnedMessages, err := s.repo.ListPinnedMessageIDs(messageIDs)
+	if err != nil {
+		return nil, err
+	}
+	contentCards, err := s.loadContentCards(rows, userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]dto.ChatMessageDTO, 0, len(rows))
+	for _, row := range rows {
+		mapped := mapChatMessageRow(row, userID, attachmentsByMessage[row.MessageID])
+		mapped.Reactions = mapChatReactions(reactionsByMessage[row.MessageID])
+		mapped.IsPinned = pinnedMessages[row.MessageID]
+		mapped.ContentCard = contentCards[row.MessageID]
+		messages = append(messages, mapped)
+	}
+	return messages, nil
+}
+
+func (s *chatService) EditMessage(userID string, schoolID string, roomID string, messageID string, content string) (*dto.ChatMessageDTO, error) {
+	allowed, _, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+
+	row, err := s.repo.GetMessageByID(messageID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if row.SenderID != userID {
+		return nil, fmt.Errorf("forbidden: only the sender can edit a chat message")
+	}
+	if !s.withinMessageEditWindow(row.CreatedAt) {
+		return nil, fmt.Errorf("forbidden: chat message edit window has expired")
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" && row.Type != chatMessageTypeFile && row.RefID == nil {
+		return nil, fmt.Errorf("chat message content is required")
+	}
+	if len([]rune(content)) > maxChatContentLen {
+		return nil, fmt.Errorf("chat message content exceeds %d characters", maxChatContentLen)
+	}
+
+	if content != row.Content {
+		if err := s.ensureNoBannedWords(userID, schoolID, roomID, content); err != nil {
+			return nil, err
+		}
+		if err := s.repo.UpdateMessageContent(messageID, roomID, userID, content); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.getMappedMessage(messageID, roomID, userID, schoolID)
+}
+
+// DeleteMessage removes a message for everyone. Senders may delete their own
+// messages within the edit window; room moderators may delete any message.
+func (s *chatService) DeleteMessage(userID string, schoolID string, roomID string, messageID string) (*dto.ChatMessageDeletedDTO, error) {
+	allowed, room, err := s.CanAccessRoom(userID, schoolID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: chat room access denied")
+	}
+
+	row, err := s.repo.GetMessageByID(messageID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if row.SenderID != userID || !s.withinMessageEditWindow(row.CreatedAt) {
+		isModerator, err := s.isRoomModerator(userID, schoolID, room)
+		if err != nil {
+			return nil, err
+		}
+		if !isModerator {
+			if row.SenderID == userID {
+				return nil, fmt.Errorf("forbidden: chat message edit window has expired")
+			}
+			return nil, fmt.Errorf("forbidden: chat message delete not allowed")
+		}
+	}
+
+	if err := s.repo.SoftDeleteMessage(messageID, roomID, userID); err != nil {
+		return nil, err
+	}
+	if row.SenderID != userID {
+		if err := s.recordModerationLog(schoolID, userID, chatLogRemoveMessage, map[string]any{
+			"roomId":    roomID,
+			"messageId": messageID,
+			"senderId":  row.SenderID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return &dto.ChatMessageDeletedDTO{
+		MessageID: messageID,
+		RoomID:    roomID,
+		ReplyTo:   row.ReplyTo,
+		DeletedBy: userID,
+		DeletedAt: formatChatTime(time.Now()),
+	}, nil
+}
+
+func (s *chatService) withinMessageEditWindow(createdAt time.Time) bool {
+	return s.messageEditWindow <= 0 || time.Since(createdAt) <= s.messageEditWindow
+}
+
+// isRoomModerator reports whether the user may moderate other members'
+// messages: group admins in custom groups, teachers in class rooms, and school
+// admins in the school room. Direct messages have no moderator.
+func (s *chatService) isRoomModerator(userID string, schoolID string, room *repository.ChatRoomRow) (bool, error) {
+	if isSchoolChatRoom(room, schoolID) {
+		return s.repo.UserIsSchoolAdmin(userID, schoolID)
+	}
+	if isCustomGroupRoom(room) || isClassChatRoom(room) {
+		return s.repo.UserIsRoomAdmin(userID, room.RoomID)
+	}
+	return false, nil
 }
 
 func validateChatMediaIDs(mediaIDs []string) ([]string, error) {
@@ -533,7 +1080,7 @@ func (s *chatService) GetReadSummary(userID string, schoolID string, roomID stri
 	var memberRows []repository.ChatReadMemberRow
 	if isSchoolChatRoom(room, schoolID) {
 		memberRows, err = s.repo.ListSchoolReadMembers(roomID, schoolID)
-	} else if isCustomGroupRoom(room) || isDirectMessageRoom(room) {
+	} else if isCustomGroupRoom(room) || isClassChatRoom(room) || isDirectMessageRoom(room) {
 		memberRows, err = s.repo.ListRoomReadMembers(roomID, schoolID)
 	} else {
 		return nil, fmt.Errorf("forbidden: chat room access denied")
@@ -567,12 +1114,18 @@ func (s *chatService) ListRealtimeRecipients(userID string, schoolID string, roo
 	if isSchoolChatRoom(room, schoolID) {
 		return s.repo.ListSchoolRecipientUserIDs(schoolID)
 	}
-	if isCustomGroupRoom(room) || isDirectMessageRoom(room) {
+	if isCustomGroupRoom(room) || isClassChatRoom(room) || isDirectMessageRoom(room) {
 		return s.repo.ListRoomRecipientUserIDs(roomID, schoolID)
 	}
 	return nil, fmt.Errorf("forbidden: chat room access denied")
 }
 
+// ListPresenceRecipients returns the users who may see userID come online or
+// go offline: those sharing a room with them other than the school room.
+func (s *chatService) ListPresenceRecipients(userID string, schoolID string) ([]string, error) {
+	return s.repo.ListRoomPeerUserIDs(userID, schoolID)
+}
+
 func (s *chatService) CanAccessSchoolChat(userID string, schoolID string) (bool, error) {
 	if userID == "" || schoolID == "" {
 		return false, nil
@@ -601,7 +1154,7 @@ func (s *chatService) CanAccessRoom(userID string, schoolID string, roomID strin
 		return true, room, nil
 	}
 
-	if isCustomGroupRoom(room) || isDirectMessageRoom(room) {
+	if isCustomGroupRoom(room) || isClassChatRoom(room) || isDirectMessageRoom(room) {
 		activeRoomMember, err := s.repo.UserIsActiveRoomMember(userID, roomID)
 		if err != nil {
 			return false, nil, err
@@ -681,6 +1234,9 @@ func mapChatRoomRow(row repository.ChatRoomRow, unread int64) dto.ChatRoomDTO {
 		if row.LastType != nil {
 			lastMessage.MessageType = *row.LastType
 		}
+		if row.LastRefType != nil {
+			lastMessage.RefType = *row.LastRefType
+		}
 		if row.LastSenderID != nil {
 			lastMessage.SenderID = *row.LastSenderID
 		}
@@ -704,6 +1260,7 @@ func mapChatRoomRow(row repository.ChatRoomRow, unread int64) dto.ChatRoomDTO {
 		LastMessageAt:  lastMessageAt,
 		UnreadCount:    unread,
 		CanSend:        true,
+		PostingMode:    row.PostingMode,
 	}
 }
 
@@ -748,6 +1305,7 @@ func mapChatGroupInfo(row repository.ChatGroupInfoRow, memberRows []repository.C
 		Members:     members,
 		CreatedAt:   formatChatTime(row.CreatedAt),
 		MemberCount: row.ActiveMemberCount,
+		PostingMode: row.PostingMode,
 	}
 }
 
@@ -778,10 +1336,26 @@ func mapChatMessageRow(row repository.ChatMessageRow, currentUserID string, atta
 		MessageType: row.Type,
 		Attachments: mapChatAttachments(attachments),
 		CreatedAt:   formatChatTime(row.CreatedAt),
+		EditedAt:    formatAPITimePtr(row.EditedAt),
+		ReplyTo:     row.ReplyTo,
+		ReplyCount:  row.ReplyCount,
+		Reactions:   make([]dto.ChatReactionDTO, 0),
 		IsMine:      row.SenderID == currentUserID,
 	}
 }
 
+func mapChatReactions(rows []repository.ChatReactionRow) []dto.ChatReactionDTO {
+	reactions := make([]dto.ChatReactionDTO, 0, len(rows))
+	for _, row := range rows {
+		reactions = append(reactions, dto.ChatReactionDTO{
+			Emoji:       row.Emoji,
+			Count:       row.Count,
+			ReactedByMe: row.ReactedByMe,
+		})
+	}
+	return reactions
+}
+
 func mapChatAttachments(rows []repository.ChatAttachmentRow) []dto.ChatAttachmentDTO {
 	attachments := make([]dto.ChatAttachmentDTO, 0, len(rows))
 	for _, row := range rows {
@@ -844,6 +1418,16 @@ func isCustomGroupRoom(room *repository.ChatRoomRow) bool {
 		room.RoomRefID == nil
 }
 
+// isClassChatRoom reports whether the room is synced from a class or subject
+// class; its membership follows enrollments instead of manual invites.
+func isClassChatRoom(room *repository.ChatRoomRow) bool {
+	return room != nil &&
+		room.RoomType == chatRoomTypeGroup &&
+		room.RoomRefType != nil &&
+		(*room.RoomRefType == chatRefTypeClass || *room.RoomRefType == chatRefTypeSubject) &&
+		room.RoomRefID != nil
+}
+
 func isDirectMessageRoom(room *repository.ChatRoomRow) bool {
 	return room != nil &&
 		room.RoomType == chatRoomTypeDM &&
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chatRepositoryStub serves one room and one message to active members of
+// the room. Admins are listed per room and per school.
+type chatRepositoryStub struct {
+	repository.ChatRepository
+	room         *repository.ChatRoomRow
+	message      *repository.ChatMessageRow
+	roomAdmins   map[string]bool
+	schoolAdmins map[string]bool
+	deletedBy    string
+	editedBy     string
+	pinned       int64
+}
+
+func (r *chatRepositoryStub) GetRoomContext(string, string, string) (*repository.ChatRoomRow, error) {
+	return r.room, nil
+}
+
+func (r *chatRepositoryStub) UserIsActiveSchoolMember(string, string) (bool, error) {
+	return true, nil
+}
+
+func (r *chatRepositoryStub) UserIsActiveRoomMember(string, string) (bool, error) {
+	return true, nil
+}
+
+func (r *chatRepositoryStub) UserIsRoomAdmin(userID string, roomID string) (bool, error) {
+	return r.roomAdmins[userID], nil
+}
+
+func (r *chatRepositoryStub) UserIsSchoolAdmin(userID string, schoolID string) (bool, error) {
+	return r.schoolAdmins[userID], nil
+}
+
+func (r *chatRepositoryStub) GetMessageByID(string, string) (*repository.ChatMessageRow, error) {
+	return r.message, nil
+}
+
+func (r *chatRepositoryStub) UpdateMessageContent(messageID string, roomID string, editorID string, content string) error {
+	r.editedBy = editorID
+	return nil
+}
+
+func (r *chatRepositoryStub) SoftDeleteMessage(messageID string, roomID string, deletedBy string) error {
+	r.deletedBy = deletedBy
+	return nil
+}
+
+type chatLogRepositoryStub struct {
+	repository.LogRepository
+	logs []domain.Log
+}
+
+func (r *chatLogRepositoryStub) Create(log *domain.Log) error {
+	r.logs = append(r.logs, *log)
+	return nil
+}
+
+func newChatTestRoom(roomType string, refType string) *repository.ChatRoomRow {
+	room := &repository.ChatRoomRow{RoomID: "room-1", RoomType: roomType, SchoolID: "school-1"}
+	if refType != "" {
+		refID := "ref-1"
+		room.RoomRefType = &refType
+		room.RoomRefID = &refID
+	}
+	return room
+}
+
+func newChatTestMessage(senderID string, age time.Duration) *repository.ChatMessageRow {
+	return &repository.ChatMessageRow{
+		MessageID: "message-1",
+		RoomID:    "room-1",
+		SenderID:  senderID,
+		Content:   "halo",
+		Type:      "text",
+		CreatedAt: time.Now().Add(-age),
+	}
+}
+
+func TestNormalizeChatReactionEmoji(t *testing.T) {
+	valid := []string{"👍", " 🎉 ", "👍🏽", "👨‍👩‍👧", "❤️"}
+	for _, emoji := range valid {
+		if _, err := normalizeChatReactionEmoji(emoji); err != nil {
+			t.Fatalf("expected %q to be accepted, got %v", emoji, err)
+		}
+	}
+
+	invalid := []string{"", "   ", "ok", ":+1:", "👍 👍", "a👍"}
+	for _, emoji := range invalid {
+		if _, err := normalizeChatReactionEmoji(emoji); err == nil {
+			t.Fatalf("expected %q to be rejected", emoji)
+		}
+	}
+}
+
+func TestHighlightChatSnippet(t *testing.T) {
+	raw := "tugas " + repository.ChatSearchMatchStart + "<b>matematika</b>" + repository.ChatSearchMatchStop + " & fisika"
+	want := "tugas <mark>&lt;b&gt;matematika&lt;/b&gt;</mark> &amp; fisika"
+	if got := highlightChatSnippet(raw); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestFindChatBannedWord(t *testing.T) {
+	words := []string{"bodoh", "kata kasar"}
+	blocked := []string{"Dasar BODOH!", "ini kata, kasar sekali", "bodoh."}
+	for _, content := range blocked {
+		if _, found := findChatBannedWord(content, words); !found {
+			t.Fatalf("expected %q to be blocked", content)
+		}
+	}
+
+	allowed := []string{"kebodohan bukan kata", "katakasar", "kata yang kasar"}
+	for _, content := range allowed {
+		if word, found := findChatBannedWord(content, words); found {
+			t.Fatalf("expected %q to be allowed, matched %q", content, word)
+		}
+	}
+}
+
+func TestParseChatMentions(t *testing.T) {
+	first := "0f8fad5b-d9cb-469f-a165-70867728950e"
+	second := "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	content := "Halo @[Budi Santoso](" + first + ") dan @[Siti](" + strings.ToUpper(second) + "), " +
+		"cek lagi @[Budi](" + first + ") ya. Email budi@sekolah.id bukan mention."
+
+	got := parseChatMentions(content)
+	if len(got) != 2 || got[0] != first || got[1] != second {
+		t.Fatalf("parseChatMentions() = %v, want [%s %s]", got, first, second)
+	}
+	if mentions := parseChatMentions("@Budi @[](" + first + ")"); len(mentions) != 0 {
+		t.Fatalf("parseChatMentions() = %v, want none", mentions)
+	}
+}
+
+func TestChatMentionPreview(t *testing.T) {
+	content := "Tolong @[Budi Santoso](0f8fad5b-d9cb-469f-a165-70867728950e)   cek tugas"
+	if got := chatMentionPreview("Ani", content); got != "Ani: Tolong @Budi Santoso cek tugas" {
+		t.Fatalf("chatMentionPreview() = %q", got)
+	}
+}
+
+func TestRenderChatTranscriptHTML(t *testing.T) {
+	editedAt := "2026-06-26T03:05:00Z"
+	body, err := RenderChatTranscriptHTML(&dto.ChatTranscriptDTO{
+		RoomName:     "Kelas 10A",
+		MessageCount: 1,
+		Messages: []dto.ChatTranscriptMessageDTO{{
+			SenderName: "Budi",
+			Content:    "<script>alert(1)</script>",
+			CreatedAt:  "2026-06-26T03:00:00Z",
+			EditedAt:   &editedAt,
+			Attachments: []dto.ChatAttachmentDTO{
+				{FileName: "tugas.pdf", URL: "https://storage.example/tugas.pdf"},
+				{FileName: "bahaya", URL: "javascript:alert(1)"},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("RenderChatTranscriptHTML() error = %v", err)
+	}
+	html := string(body)
+	if strings.Contains(html, "<script>") {
+		t.Fatalf("expected message content to be escaped")
+	}
+	if !strings.Contains(html, `href="https://storage.example/tugas.pdf"`) {
+		t.Fatalf("expected attachment link in transcript")
+	}
+	if strings.Contains(html, "javascript:") {
+		t.Fatalf("expected unsafe attachment URL to be filtered")
+	}
+	if !strings.Contains(html, "Diubah "+editedAt) {
+		t.Fatalf("expected edited timestamp in transcript")
+	}
+}
+
+func TestParseChatContentRef(t *testing.T) {
+	refType := "assignment"
+	refID := " 6F1C2B7E-2D4A-4E8B-9C3D-0A1B2C3D4E5F "
+	ref, err := parseChatContentRef(&refType, &refID)
+	if err != nil {
+		t.Fatalf("parseChatContentRef() error = %v", err)
+	}
+	if ref.Type != "assignment" || ref.ID != "6f1c2b7e-2d4a-4e8b-9c3d-0a1b2c3d4e5f" {
+		t.Fatalf("unexpected ref %+v", ref)
+	}
+
+	if ref, err := parseChatContentRef(nil, nil); ref != nil || err != nil {
+		t.Fatalf("expected no ref without refType and refId, got %+v, %v", ref, err)
+	}
+	if _, err := parseChatContentRef(&refType, nil); err == nil {
+		t.Fatalf("expected refType without refId to be rejected")
+	}
+	submission := "submission"
+	if _, err := parseChatContentRef(&submission, &refID); err == nil {
+		t.Fatalf("expected submission refs to be rejected")
+	}
+}
+
+func TestChatContentCardLink(t *testing.T) {
+	subjectClassID := "scl-1"
+	material := repository.ChatContentCardRow{RefType: "material", RefID: "mat-1", SubjectClassID: &subjectClassID}
+	if link := chatContentCardLink(material, "student"); link == nil || *link != "/student/subjects/scl-1/materials/mat-1" {
+		t.Fatalf("unexpected student material link %v", link)
+	}
+	assignment := repository.ChatContentCardRow{RefType: "assignment", RefID: "asg-1", SubjectClassID: &subjectClassID}
+	if link := chatContentCardLink(assignment, "admin"); link == nil || *link != "/teacher/subjects/scl-1/assignments/asg-1" {
+		t.Fatalf("unexpected admin assignment link %v", link)
+	}
+	feed := repository.ChatContentCardRow{RefType: "feed", RefID: "fds-1"}
+	if link := chatContentCardLink(feed, "teacher"); link != nil {
+		t.Fatalf("expected no feed link for teachers, got %q", *link)
+	}
+}
+
+func TestValidateChatSendAt(t *testing.T) {
+	now := time.Date(2026, 6, 26, 3, 0, 0, 0, time.UTC)
+	if err := validateChatSendAt(now.Add(time.Minute), now); err != nil {
+		t.Fatalf("expected future send time to be accepted, got %v", err)
+	}
+	if err := validateChatSendAt(now, now); err == nil {
+		t.Fatalf("expected current time to be rejected")
+	}
+	if err := validateChatSendAt(now.Add(91*24*time.Hour), now); err == nil {
+		t.Fatalf("expected send time beyond 90 days to be rejected")
+	}
+}
+
+func TestEditMessageAfterWindowIsRejected(t *testing.T) {
+	repo := &chatRepositoryStub{
+		room:    newChatTestRoom(chatRoomTypeGroup, ""),
+		message: newChatTestMessage("sender-1", 20*time.Minute),
+	}
+	service := NewChatService(repo, nil, nil, nil, nil, 15*time.Minute)
+
+	_, err := service.EditMessage("sender-1", "school-1", "room-1", "message-1", "halo semua")
+	if err == nil || !strings.Contains(err.Error(), "edit window has expired") {
+		t.Fatalf("expected the edit window error, got %v", err)
+	}
+	if repo.editedBy != "" {
+		t.Fatalf("expected the message to stay unchanged, edited by %q", repo.editedBy)
+	}
+}
+
+func TestDeleteMessageByNonSenderNonModeratorIsRejected(t *testing.T) {
+	repo := &chatRepositoryStub{
+		room:    newChatTestRoom(chatRoomTypeGroup, ""),
+		message: newChatTestMessage("sender-1", time.Minute),
+	}
+	service := NewChatService(repo, nil, nil, nil, nil, 15*time.Minute)
+
+	_, err := service.DeleteMessage("member-2", "school-1", "room-1", "message-1")
+	if err == nil || !strings.Contains(err.Error(), "delete not allowed") {
+		t.Fatalf("expected delete to be forbidden, got %v", err)
+	}
+	if repo.deletedBy != "" {
+		t.Fatalf("expected the message to stay, deleted by %q", repo.deletedBy)
+	}
+}
+
+func TestDeleteMessageByModerator(t *testing.T) {
+	rooms := []struct {
+		name    string
+		room    *repository.ChatRoomRow
+		allowed bool
+	}{
+		{"custom group", newChatTestRoom(chatRoomTypeGroup, ""), true},
+		{"class", newChatTestRoom(chatRoomTypeGroup, chatRefTypeClass), true},
+		{"subject class", newChatTestRoom(chatRoomTypeGroup, chatRefTypeSubject), true},
+		{"direct message", newChatTestRoom(chatRoomTypeDM, ""), false},
+	}
+	for _, tc := range rooms {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &chatRepositoryStub{
+				room:       tc.room,
+				message:    newChatTestMessage("sender-1", time.Hour),
+				roomAdmins: map[string]bool{"moderator-1": true},
+			}
+			logRepo := &chatLogRepositoryStub{}
+			service := NewChatService(repo, nil, nil, logRepo, nil, 15*time.Minute)
+
+			deleted, err := service.DeleteMessage("moderator-1", "school-1", "room-1", "message-1")
+			if !tc.allowed {
+				if err == nil || repo.deletedBy != "" {
+					t.Fatalf("expected moderators to be unable to delete in direct messages, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected the moderator to delete the message, got %v", err)
+			}
+			if repo.deletedBy != "moderator-1" || deleted.DeletedBy != "moderator-1" {
+				t.Fatalf("expected the message deleted by the moderator, got %q", repo.deletedBy)
+			}
+			if len(logRepo.logs) != 1 || logRepo.logs[0].Action != chatLogRemoveMessage {
+				t.Fatalf("expected a moderation log entry, got %v", logRepo.logs)
+			}
+		})
+	}
+}
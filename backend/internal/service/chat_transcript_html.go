@@ -0,0 +1,67 @@
+package service
+
+import (
+	"backend/internal/dto"
+	"bytes"
+	"html/template"
+)
+
+// chatTranscriptTemplate renders a printable transcript. html/template escapes
+// message content and only allows http(s) attachment links.
+var chatTranscriptTemplate = template.Must(template.New("chat-transcript").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Transkrip chat - {{.RoomName}}</title>
+<style>
+body { font-family: Arial, sans-serif; font-size: 12px; color: #111; margin: 24px; }
+h1 { font-size: 18px; margin: 0 0 4px; }
+.meta { color: #555; margin-bottom: 16px; }
+table { width: 100%; border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 6px; text-align: left; vertical-align: top; }
+th { background: #f3f3f3; }
+tr { page-break-inside: avoid; }
+.content { white-space: pre-wrap; word-break: break-word; }
+.deleted { color: #a00; }
+.note { color: #555; font-size: 11px; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Transkrip chat: {{.RoomName}}</h1>
+<div class="meta">
+<div>Sekolah: {{.SchoolName}}</div>
+<div>Room ID: {{.RoomID}} ({{.RoomType}})</div>
+<div>Periode: {{if .From}}{{.From}}{{else}}awal{{end}} s.d. {{if .To}}{{.To}}{{else}}sekarang{{end}}</div>
+<div>Diekspor: {{.ExportedAt}} oleh {{.ExportedBy}}</div>
+<div>Jumlah pesan: {{.MessageCount}}{{if .Truncated}} (terpotong, persempit periode untuk melihat sisanya){{end}}</div>
+</div>
+<table>
+<thead>
+<tr><th>Waktu</th><th>Pengirim</th><th>Pesan</th><th>Lampiran</th></tr>
+</thead>
+<tbody>
+{{range .Messages}}<tr>
+<td>{{.CreatedAt}}</td>
+<td>{{.SenderName}}<br><span class="note">{{.SenderEmail}}</span></td>
+<td>
+{{if .ReplyTo}}<div class="note">Balasan untuk {{.ReplyTo}}</div>{{end}}
+<div class="content">{{.Content}}</div>
+{{if .EditedAt}}<div class="note">Diubah {{.EditedAt}}</div>{{end}}
+{{if .DeletedAt}}<div class="note deleted">Dihapus {{.DeletedAt}}{{if .DeletedBy}} oleh {{.DeletedBy}}{{end}}</div>{{end}}
+</td>
+<td>{{range .Attachments}}<div><a href="{{.URL}}">{{.FileName}}</a> <span class="note">({{.MimeType}})</span></div>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+func RenderChatTranscriptHTML(transcript *dto.ChatTranscriptDTO) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := chatTranscriptTemplate.Execute(&buf, transcript); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
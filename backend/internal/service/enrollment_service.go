@@ -25,10 +25,11 @@ type enrollmentService struct {
 	repo           repository.EnrollmentRepository
 	classRepo      repository.ClassRepository
 	schoolUserRepo repository.SchoolUserRepository
+	chatRepo       repository.ChatRepository
 }
 
-func NewEnrollmentService(repo repository.EnrollmentRepository, classRepo repository.ClassRepository, schoolUserRepo repository.SchoolUserRepository) EnrollmentService {
-	return &enrollmentService{repo: repo, classRepo: classRepo, schoolUserRepo: schoolUserRepo}
+func NewEnrollmentService(repo repository.EnrollmentRepository, classRepo repository.ClassRepository, schoolUserRepo repository.SchoolUserRepository, chatRepo repository.ChatRepository) EnrollmentService {
+	return &enrollmentService{repo: repo, classRepo: classRepo, schoolUserRepo: schoolUserRepo, chatRepo: chatRepo}
 }
 
 func (s *enrollmentService) Enroll(schoolID string, classID string, schoolUserIDs []string, role string) error {
@@ -66,6 +67,7 @@ func (s *enrollmentService) Enroll(schoolID string, classID string, schoolUserID
 			return err
 		}
 	}
+	syncClassRooms(s.chatRepo, classID)
 	return nil
 }
 
@@ -106,7 +108,17 @@ func (s *enrollmentService) Update(id string, schoolID string, role string) erro
 	if err := s.ensureActiveEnrollmentInSchool(id, schoolID); err != nil {
 		return err
 	}
-	return s.repo.Update(id, role)
+	enrollment, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Update(id, role); err != nil {
+		return err
+	}
+
+	// Teachers moderate the class room, so a role change moves chat rights.
+	syncClassRooms(s.chatRepo, enrollment.ClassID)
+	return nil
 }
 
 func (s *enrollmentService) Unenroll(id string, schoolID string) error {
@@ -131,7 +143,11 @@ func (s *enrollmentService) Unenroll(id string, schoolID string) error {
 		}
 	}
 
-	return s.repo.SoftDelete(id)
+	if err := s.repo.SoftDelete(id); err != nil {
+		return err
+	}
+	syncClassRooms(s.chatRepo, enrollment.ClassID)
+	return nil
 }
 
 func (s *enrollmentService) ensureClassInSchool(classID string, schoolID string) error {
@@ -27,9 +27,10 @@ type feedService struct {
 	enrRepo          repository.EnrollmentRepository
 	classRepo        repository.ClassRepository
 	subjectClassRepo repository.SubjectClassRepository
+	realtime         RealtimePublisher
 }
 
-func NewFeedService(repo repository.FeedRepository, attService AttachmentService, notifService NotificationService, enrRepo repository.EnrollmentRepository, classRepo repository.ClassRepository, subjectClassRepo repository.SubjectClassRepository) FeedService {
+func NewFeedService(repo repository.FeedRepository, attService AttachmentService, notifService NotificationService, enrRepo repository.EnrollmentRepository, classRepo repository.ClassRepository, subjectClassRepo repository.SubjectClassRepository, realtime RealtimePublisher) FeedService {
 	return &feedService{
 		repo:             repo,
 		attService:       attService,
@@ -37,6 +38,7 @@ func NewFeedService(repo repository.FeedRepository, attService AttachmentService
 		enrRepo:          enrRepo,
 		classRepo:        classRepo,
 		subjectClassRepo: subjectClassRepo,
+		realtime:         realtime,
 	}
 }
 
@@ -82,10 +84,12 @@ func (s *feedService) Create(feed *domain.Feed, userID string, roles []string) e
 		}
 
 		message := feedNotificationMessage(className, feed.Content)
+		recipients := make([]string, 0, len(userIDs))
 		for _, uid := range userIDs {
 			if uid == feed.CreatedBy {
 				continue
 			}
+			recipients = append(recipients, uid)
 			_ = s.notifService.Create(&dto.CreateNotificationDTO{
 				UserID:    uid,
 				Type:      domain.NotifFeedPosted,
@@ -95,6 +99,17 @@ func (s *feedService) Create(feed *domain.Feed, userID string, roles []string) e
 				RelatedID: feed.ID,
 			})
 		}
+
+		if s.realtime != nil {
+			s.realtime.FeedPosted(feed.SchoolID, recipients, dto.FeedPostedEventDTO{
+				FeedID:    feed.ID,
+				ClassID:   feed.ClassID,
+				ClassName: className,
+				Content:   feed.Content,
+				CreatedBy: feed.CreatedBy,
+				CreatedAt: formatAPITime(feed.CreatedAt),
+			})
+		}
 	}
 
 	return nil
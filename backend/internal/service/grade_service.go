@@ -175,10 +175,38 @@ func (s *gradeService) CalculateFinalGrade(studentID string, subjectID string) (
 		return nil, err
 	}
 
+	submissionIDs := make([]string, 0, len(assessments))
+	for _, assessment := range assessments {
+		submissionIDs = append(submissionIDs, assessment.SubmissionID)
+	}
+	penalties, err := s.latePenalties(submissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	adjustments, err := s.gradeRepo.GetGroupScoreAdjustments(studentID, submissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	adjustmentBySubmission := make(map[string]float64, len(adjustments))
+	for _, adjustment := range adjustments {
+		adjustmentBySubmission[adjustment.SubmissionID] = adjustment.ScoreAdjustment
+	}
+	assignmentIDs := make([]string, 0, len(assessments))
+	for _, assessment := range assessments {
+		assignmentIDs = append(assignmentIDs, assessment.Submission.AssignmentID)
+	}
+	completions, err := s.peerReviewCompletion(studentID, assignmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	categoryScores := make(map[string][]float64)
 	for _, assessment := range assessments {
 		categoryID := assessment.Submission.Assignment.CategoryID
-		categoryScores[categoryID] = append(categoryScores[categoryID], assessment.Score)
+		score := groupMemberScore(assessment.Score, adjustmentBySubmission[assessment.SubmissionID])
+		score = applyLatePenalty(score, penalties[assessment.SubmissionID])
+		score = applyPeerReviewCompletion(score, completions[assessment.Submission.AssignmentID])
+		categoryScores[categoryID] = append(categoryScores[categoryID], score)
 	}
 
 	breakdown := []dto.CategoryBreakdownDTO{}
@@ -288,6 +316,23 @@ func (s *gradeService) GetMyGradebookByClass(userID string, schoolID string, cla
 		Summary:  dto.MyGradebookSummaryDTO{},
 	}
 
+	submissionIDs := make([]string, 0, len(rows))
+	assignmentIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.SubmissionID != nil && row.Score != nil {
+			submissionIDs = append(submissionIDs, *row.SubmissionID)
+			assignmentIDs = append(assignmentIDs, *row.AssignmentID)
+		}
+	}
+	penalties, err := s.latePenalties(submissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	completions, err := s.peerReviewCompletion(userID, assignmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	subjectIndexes := make(map[string]int)
 	categoryScoresBySubject := make(map[string]map[string][]float64)
 
@@ -318,14 +363,22 @@ func (s *gradeService) GetMyGradebookByClass(userID string, schoolID string, cla
 			response.Subjects[subjectIndex].PendingCount++
 			response.Summary.PendingAssessmentCount++
 		}
+		var finalScore *float64
+		var penalty *dto.LatePenaltyDTO
+		var peerReview *dto.PeerReviewCompletionDTO
 		if row.Score != nil {
 			status = "graded"
 			response.Subjects[subjectIndex].GradedCount++
 			response.Summary.GradedAssignmentCount++
 			response.Subjects[subjectIndex].PendingCount--
 			response.Summary.PendingAssessmentCount--
+			penalty = penalties[*row.SubmissionID]
+			peerReview = completions[*row.AssignmentID]
+			score := applyLatePenalty(*row.Score, penalty)
+			score = applyPeerReviewCompletion(score, peerReview)
+			finalScore = &score
 			if row.CategoryID != nil {
-				categoryScoresBySubject[row.SubjectClassID][*row.CategoryID] = append(categoryScoresBySubject[row.SubjectClassID][*row.CategoryID], *row.Score)
+				categoryScoresBySubject[row.SubjectClassID][*row.CategoryID] = append(categoryScoresBySubject[row.SubjectClassID][*row.CategoryID], score)
 			}
 		}
 
@@ -337,6 +390,9 @@ func (s *gradeService) GetMyGradebookByClass(userID string, schoolID string, cla
 			Status:          status,
 			SubmittedAt:     formatTimePointer(row.SubmittedAt),
 			Score:           row.Score,
+			FinalScore:      finalScore,
+			LatePenalty:     penalty,
+			PeerReview:      peerReview,
 			Feedback:        row.Feedback,
 			AssessedAt:      formatTimePointer(row.AssessedAt),
 			AssessorName:    row.AssessorName,
@@ -354,6 +410,33 @@ func (s *gradeService) GetMyGradebookByClass(userID string, schoolID string, cla
 	return response, nil
 }
 
+// latePenalties maps the given submissions to their late penalty, if any.
+func (s *gradeService) latePenalties(submissionIDs []string) (map[string]*dto.LatePenaltyDTO, error) {
+	rows, err := s.gradeRepo.GetLatePenaltyInputs(submissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	return latePenaltiesBySubmission(rows), nil
+}
+
+// peerReviewCompletion maps the given assignments to the student's peer
+// review completion, when it counts toward the grade.
+func (s *gradeService) peerReviewCompletion(userID string, assignmentIDs []string) (map[string]*dto.PeerReviewCompletionDTO, error) {
+	rows, err := s.gradeRepo.GetPeerReviewCompletion(userID, assignmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	completions := make(map[string]*dto.PeerReviewCompletionDTO, len(rows))
+	for _, row := range rows {
+		completions[row.AssignmentID] = &dto.PeerReviewCompletionDTO{
+			Assigned:  row.Assigned,
+			Completed: row.Completed,
+			Weight:    row.Weight,
+		}
+	}
+	return completions, nil
+}
+
 func calculateAverage(scores []float64) float64 {
 	if len(scores) == 0 {
 		return 0.0
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"math"
+	"time"
+)
+
+// latePenalty returns the penalty of a submission, or nil when it is on time,
+// within the grace period or no late policy applies.
+func latePenalty(row dto.LatePenaltyInputRow) *dto.LatePenaltyDTO {
+	if row.PolicyID == nil || row.PercentPerDay == nil {
+		return nil
+	}
+	deadline := effectiveDeadline(row.Deadline, row.ExtendedDeadline)
+	if deadline == nil {
+		return nil
+	}
+
+	policy := domain.LatePolicy{
+		PercentPerDay: *row.PercentPerDay,
+		MaxPercent:    row.MaxPercent,
+		ZeroAfterDays: row.ZeroAfterDays,
+	}
+	if row.GraceMinutes != nil {
+		policy.GraceMinutes = *row.GraceMinutes
+	}
+	daysLate, percent := latePenaltyPercent(policy, row.SubmittedAt, *deadline)
+	if daysLate == 0 {
+		return nil
+	}
+	return &dto.LatePenaltyDTO{DaysLate: daysLate, Percent: percent, Waived: row.Waived}
+}
+
+// latePenaltyPercent counts the started days late after the grace period and
+// the percentage of the score they cost.
+func latePenaltyPercent(policy domain.LatePolicy, submittedAt time.Time, deadline time.Time) (int, float64) {
+	late := submittedAt.Sub(deadline) - time.Duration(policy.GraceMinutes)*time.Minute
+	if late <= 0 {
+		return 0, 0
+	}
+	daysLate := int(math.Ceil(late.Hours() / 24))
+	if policy.ZeroAfterDays != nil && daysLate > *policy.ZeroAfterDays {
+		return daysLate, 100
+	}
+
+	percent := policy.PercentPerDay * float64(daysLate)
+	if policy.MaxPercent != nil && percent > *policy.MaxPercent {
+		percent = *policy.MaxPercent
+	}
+	return daysLate, math.Min(percent, 100)
+}
+
+// applyLatePenalty returns the score that counts after the penalty, rounded
+// to two decimals like stored scores.
+func applyLatePenalty(score float64, penalty *dto.LatePenaltyDTO) float64 {
+	if penalty == nil || penalty.Waived || penalty.Percent == 0 {
+		return score
+	}
+	return math.Round(score*(100-penalty.Percent)) / 100
+}
+
+// latePenaltiesBySubmission maps submission IDs to their late penalty.
+// Submissions without a penalty are left out.
+func latePenaltiesBySubmission(rows []dto.LatePenaltyInputRow) map[string]*dto.LatePenaltyDTO {
+	result := make(map[string]*dto.LatePenaltyDTO)
+	for _, row := range rows {
+		if penalty := latePenalty(row); penalty != nil {
+			result[row.SubmissionID] = penalty
+		}
+	}
+	return result
+}
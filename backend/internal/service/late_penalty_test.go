@@ -0,0 +1,49 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"testing"
+	"time"
+)
+
+func TestLatePenaltyPercent(t *testing.T) {
+	deadline := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	maxPercent := 30.0
+	zeroAfter := 5
+	policy := domain.LatePolicy{PercentPerDay: 10, MaxPercent: &maxPercent, GraceMinutes: 15, ZeroAfterDays: &zeroAfter}
+
+	cases := []struct {
+		name     string
+		late     time.Duration
+		daysLate int
+		percent  float64
+	}{
+		{"on time", -time.Hour, 0, 0},
+		{"within grace", 10 * time.Minute, 0, 0},
+		{"first day", 2 * time.Hour, 1, 10},
+		{"second day", 25 * time.Hour, 2, 20},
+		{"capped", 4 * 24 * time.Hour, 4, 30},
+		{"zero after days", 6 * 24 * time.Hour, 6, 100},
+	}
+	for _, tc := range cases {
+		daysLate, percent := latePenaltyPercent(policy, deadline.Add(tc.late), deadline)
+		if daysLate != tc.daysLate || percent != tc.percent {
+			t.Fatalf("%s: expected %d days and %v%%, got %d days and %v%%", tc.name, tc.daysLate, tc.percent, daysLate, percent)
+		}
+	}
+}
+
+func TestApplyLatePenalty(t *testing.T) {
+	penalty := &dto.LatePenaltyDTO{DaysLate: 2, Percent: 20}
+	if got := applyLatePenalty(87.5, penalty); got != 70 {
+		t.Fatalf("expected 70, got %v", got)
+	}
+	penalty.Waived = true
+	if got := applyLatePenalty(87.5, penalty); got != 87.5 {
+		t.Fatalf("expected a waived penalty to keep the raw score, got %v", got)
+	}
+	if got := applyLatePenalty(87.5, nil); got != 87.5 {
+		t.Fatalf("expected no penalty to keep the raw score, got %v", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"backend/internal/repository"
 	"backend/internal/storage"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -25,10 +26,11 @@ type UploadFile struct {
 
 type MaterialService interface {
 	Create(ctx context.Context, mat *domain.Material, mediaIDs []string, medias []dto.CreateMediaInline, uploads []UploadFile, actorUserID string, isAdmin bool) error
-	FindAll(search string, subjectClassID string, page int, limit int) ([]*domain.Material, int64, error)
+	FindAll(search string, subjectClassID string, publishedOnly bool, page int, limit int) ([]*domain.Material, int64, error)
 	GetByID(id string) (*domain.Material, error)
 	Update(mat *domain.Material, mediaIDs []string, actorUserID string, isAdmin bool) error
 	Delete(id string) error
+	PublishDue(now time.Time) (int, error)
 
 	// Progress
 	UpdateProgress(userID, matID string, status string) error
@@ -62,6 +64,17 @@ func NewMaterialService(repo repository.MaterialRepository, attService Attachmen
 
 func (s *materialService) Create(ctx context.Context, mat *domain.Material, mediaIDs []string, medias []dto.CreateMediaInline, uploads []UploadFile, actorUserID string, isAdmin bool) error {
 	mat.Title = strings.TrimSpace(mat.Title)
+	now := time.Now()
+	status, publishAt, err := resolvePublishState(mat.PublishStatus, mat.PublishAt, now)
+	if err != nil {
+		return err
+	}
+	mat.PublishStatus = status
+	mat.PublishAt = publishAt
+	mat.PublishedAt = nil
+	if status == domain.PublishStatusPublished {
+		mat.PublishedAt = &now
+	}
 
 	if err := validateAttachableMedia(s.mediaRepo, mediaIDs, mat.SchoolID, actorUserID, isAdmin); err != nil {
 		return err
@@ -126,27 +139,14 @@ func (s *materialService) Create(ctx context.Context, mat *domain.Material, medi
 		return err
 	}
 
-	// Best-effort: notify students in the class
-	if classID, err := s.sclRepo.GetClassIDBySubjectClass(mat.SubjectClassID); err == nil && classID != "" {
-		if userIDs, err := s.enrRepo.GetStudentUserIDsByClass(classID); err == nil {
-			for _, uid := range userIDs {
-				_ = s.notifService.Create(&dto.CreateNotificationDTO{
-					UserID:    uid,
-					Type:      domain.NotifMaterialAdded,
-					Title:     "Materi baru",
-					Message:   mat.Title,
-					Link:      fmt.Sprintf("/student/subjects/%s/materials/%s", mat.SubjectClassID, mat.ID),
-					RelatedID: mat.ID,
-				})
-			}
-		}
+	if mat.PublishStatus == domain.PublishStatusPublished {
+		s.notifyPublished(mat)
 	}
-
 	return nil
 }
 
-func (s *materialService) FindAll(search string, subjectClassID string, page int, limit int) ([]*domain.Material, int64, error) {
-	materials, total, err := s.repo.FindAll(search, subjectClassID, page, limit)
+func (s *materialService) FindAll(search string, subjectClassID string, publishedOnly bool, page int, limit int) ([]*domain.Material, int64, error) {
+	materials, total, err := s.repo.FindAll(search, subjectClassID, publishedOnly, page, limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -193,6 +193,21 @@ func (s *materialService) GetByID(id string) (*domain.Material, error) {
 
 func (s *materialService) Update(mat *domain.Material, mediaIDs []string, actorUserID string, isAdmin bool) error {
 	mat.Title = strings.TrimSpace(mat.Title)
+	current, err := s.repo.GetByID(mat.ID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	publishStatus, publishAt, publishChanged, err := resolvePublishUpdate(current.PublishStatus, current.PublishAt, mat.PublishStatus, mat.PublishAt, now)
+	if err != nil {
+		return err
+	}
+	mat.PublishStatus = publishStatus
+	mat.PublishAt = publishAt
+	mat.PublishedAt = current.PublishedAt
+	if publishChanged && publishStatus == domain.PublishStatusPublished {
+		mat.PublishedAt = &now
+	}
 	var attachmentMediaIDs []string
 	if mediaIDs != nil {
 		var err error
@@ -202,7 +217,7 @@ func (s *materialService) Update(mat *domain.Material, mediaIDs []string, actorU
 		}
 	}
 
-	err := s.repo.Update(mat)
+	err = s.repo.Update(mat)
 	if err != nil {
 		return err
 	}
@@ -213,9 +228,62 @@ func (s *materialService) Update(mat *domain.Material, mediaIDs []string, actorU
 		}
 	}
 
+	if publishChanged && mat.PublishStatus == domain.PublishStatusPublished {
+		s.notifyPublished(mat)
+	}
 	return nil
 }
 
+// PublishDue publishes scheduled materials whose publish time has passed and
+// notifies their students. It returns how many were published.
+func (s *materialService) PublishDue(now time.Time) (int, error) {
+	materials, err := s.repo.ListDueScheduled(now)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var errs []error
+	for _, mat := range materials {
+		ok, err := s.repo.Publish(mat.ID, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		mat.PublishStatus = domain.PublishStatusPublished
+		mat.PublishedAt = &now
+		s.notifyPublished(mat)
+		published++
+	}
+	return published, errors.Join(errs...)
+}
+
+// notifyPublished tells the students of the class about a newly published
+// material. Best-effort: failures are ignored.
+func (s *materialService) notifyPublished(mat *domain.Material) {
+	classID, err := s.sclRepo.GetClassIDBySubjectClass(mat.SubjectClassID)
+	if err != nil || classID == "" {
+		return
+	}
+	userIDs, err := s.enrRepo.GetStudentUserIDsByClass(classID)
+	if err != nil {
+		return
+	}
+	for _, uid := range userIDs {
+		_ = s.notifService.Create(&dto.CreateNotificationDTO{
+			UserID:    uid,
+			Type:      domain.NotifMaterialAdded,
+			Title:     "Materi baru",
+			Message:   mat.Title,
+			Link:      fmt.Sprintf("/student/subjects/%s/materials/%s", mat.SubjectClassID, mat.ID),
+			RelatedID: mat.ID,
+		})
+	}
+}
+
 func (s *materialService) Delete(id string) error {
 	// 1. Unlink all attachments associated with this material
 	s.attService.UnlinkBySource(string(domain.SourceMaterial), id)
@@ -19,11 +19,12 @@ type NotificationService interface {
 }
 
 type notificationService struct {
-	repo repository.NotificationRepository
+	repo     repository.NotificationRepository
+	realtime RealtimePublisher
 }
 
-func NewNotificationService(repo repository.NotificationRepository) NotificationService {
-	return &notificationService{repo: repo}
+func NewNotificationService(repo repository.NotificationRepository, realtime RealtimePublisher) NotificationService {
+	return &notificationService{repo: repo, realtime: realtime}
 }
 
 func (s *notificationService) Create(req *dto.CreateNotificationDTO) error {
@@ -36,7 +37,23 @@ func (s *notificationService) Create(req *dto.CreateNotificationDTO) error {
 		RelatedID: req.RelatedID,
 	}
 
-	return s.repo.Create(notification)
+	if err := s.repo.Create(notification); err != nil {
+		return err
+	}
+
+	if s.realtime != nil {
+		s.realtime.NotificationCreated(notification.UserID, dto.NotificationResponseDTO{
+			NotificationID: notification.ID,
+			Type:           notification.Type,
+			Title:          notification.Title,
+			Message:        notification.Message,
+			Link:           notification.Link,
+			IsRead:         notification.IsRead,
+			CreatedAt:      formatAPITime(notification.CreatedAt),
+		})
+	}
+
+	return nil
 }
 
 func (s *notificationService) GetByUserID(userID string, page, limit int, unreadOnly bool) (*dto.NotificationListDTO, error) {
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"fmt"
+	"time"
+)
+
+// resolvePublishState validates the requested publish state of an assignment
+// or material. An empty status publishes immediately unless publishAt lies in
+// the future, in which case the item is scheduled.
+func resolvePublishState(status string, publishAt *time.Time, now time.Time) (string, *time.Time, error) {
+	switch status {
+	case "":
+		if publishAt != nil && publishAt.After(now) {
+			return domain.PublishStatusScheduled, publishAt, nil
+		}
+		return domain.PublishStatusPublished, nil, nil
+	case domain.PublishStatusDraft, domain.PublishStatusPublished:
+		return status, nil, nil
+	case domain.PublishStatusScheduled:
+		if publishAt == nil {
+			return "", nil, fmt.Errorf("publish time is required for scheduled items")
+		}
+		if !publishAt.After(now) {
+			return "", nil, fmt.Errorf("publish time must be in the future")
+		}
+		return status, publishAt, nil
+	default:
+		return "", nil, fmt.Errorf("invalid publish status")
+	}
+}
+
+// resolvePublishUpdate applies a publish state change to an existing item.
+// Published items stay published; changed reports whether anything moved.
+func resolvePublishUpdate(currentStatus string, currentPublishAt *time.Time, status string, publishAt *time.Time, now time.Time) (string, *time.Time, bool, error) {
+	resolvedStatus, resolvedAt, err := resolvePublishState(status, publishAt, now)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if currentStatus == domain.PublishStatusPublished && resolvedStatus != domain.PublishStatusPublished {
+		return "", nil, false, fmt.Errorf("published items cannot be unpublished")
+	}
+	changed := resolvedStatus != currentStatus || !sameOptionalTime(currentPublishAt, resolvedAt)
+	return resolvedStatus, resolvedAt, changed, nil
+}
+
+func sameOptionalTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
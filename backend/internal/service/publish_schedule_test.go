@@ -0,0 +1,56 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestResolvePublishState(t *testing.T) {
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	cases := []struct {
+		name      string
+		status    string
+		publishAt *time.Time
+		want      string
+		wantAt    bool
+		wantErr   bool
+	}{
+		{"default publishes now", "", nil, domain.PublishStatusPublished, false, false},
+		{"default with future time schedules", "", &future, domain.PublishStatusScheduled, true, false},
+		{"default with past time publishes", "", &past, domain.PublishStatusPublished, false, false},
+		{"draft drops publish time", domain.PublishStatusDraft, &future, domain.PublishStatusDraft, false, false},
+		{"scheduled", domain.PublishStatusScheduled, &future, domain.PublishStatusScheduled, true, false},
+		{"scheduled without time", domain.PublishStatusScheduled, nil, "", false, true},
+		{"scheduled in the past", domain.PublishStatusScheduled, &past, "", false, true},
+		{"unknown status", "archived", nil, "", false, true},
+	}
+	for _, tc := range cases {
+		status, publishAt, err := resolvePublishState(tc.status, tc.publishAt, now)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+		if status != tc.want || (publishAt != nil) != tc.wantAt {
+			t.Fatalf("%s: got status %q with publish time %v", tc.name, status, publishAt)
+		}
+	}
+}
+
+func TestResolvePublishUpdate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+
+	if _, _, _, err := resolvePublishUpdate(domain.PublishStatusPublished, nil, domain.PublishStatusDraft, nil, now); err == nil {
+		t.Fatal("expected a published item to stay published")
+	}
+	if _, _, changed, err := resolvePublishUpdate(domain.PublishStatusPublished, nil, domain.PublishStatusPublished, nil, now); err != nil || changed {
+		t.Fatalf("expected republishing to be a no-op, got changed=%v err=%v", changed, err)
+	}
+	status, _, changed, err := resolvePublishUpdate(domain.PublishStatusDraft, nil, "", &future, now)
+	if err != nil || !changed || status != domain.PublishStatusScheduled {
+		t.Fatalf("expected a draft with a publish time to be scheduled, got %q changed=%v err=%v", status, changed, err)
+	}
+}
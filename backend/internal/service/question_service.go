@@ -0,0 +1,282 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const maxQuestionOptions = 10
+
+type QuestionService interface {
+	Create(userID string, schoolID string, input dto.CreateQuestionDTO) (*dto.QuestionResponseDTO, error)
+	List(schoolID string, subjectID string, questionType string) ([]dto.QuestionResponseDTO, error)
+	Get(id string, schoolID string) (*dto.QuestionResponseDTO, error)
+	Replace(id string, userID string, schoolID string, isAdmin bool, input dto.CreateQuestionDTO) (*dto.QuestionResponseDTO, error)
+	Delete(id string, userID string, schoolID string, isAdmin bool) error
+}
+
+type questionSubjectRepository interface {
+	GetByID(id string) (*domain.Subject, error)
+}
+
+type questionService struct {
+	repo        repository.QuestionRepository
+	subjectRepo questionSubjectRepository
+}
+
+func NewQuestionService(repo repository.QuestionRepository, subjectRepo questionSubjectRepository) QuestionService {
+	return &questionService{repo: repo, subjectRepo: subjectRepo}
+}
+
+func (s *questionService) Create(userID string, schoolID string, input dto.CreateQuestionDTO) (*dto.QuestionResponseDTO, error) {
+	if err := s.ensureSubjectInSchool(input.SubjectID, schoolID); err != nil {
+		return nil, err
+	}
+	question, err := buildQuestion(input)
+	if err != nil {
+		return nil, err
+	}
+
+	question.SchoolID = schoolID
+	question.CreatedBy = userID
+	if err := s.repo.Create(question); err != nil {
+		return nil, err
+	}
+	return s.Get(question.ID, schoolID)
+}
+
+func (s *questionService) List(schoolID string, subjectID string, questionType string) ([]dto.QuestionResponseDTO, error) {
+	if err := s.ensureSubjectInSchool(subjectID, schoolID); err != nil {
+		return nil, err
+	}
+	questionType = strings.TrimSpace(questionType)
+	if questionType != "" && !isQuestionType(questionType) {
+		return nil, fmt.Errorf("invalid question type")
+	}
+
+	questions, err := s.repo.List(schoolID, subjectID, questionType)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.QuestionResponseDTO, 0, len(questions))
+	for _, question := range questions {
+		result = append(result, mapQuestionResponse(question))
+	}
+	return result, nil
+}
+
+func (s *questionService) Get(id string, schoolID string) (*dto.QuestionResponseDTO, error) {
+	question, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if question.SchoolID != schoolID {
+		return nil, fmt.Errorf("forbidden: question access denied")
+	}
+	result := mapQuestionResponse(question)
+	return &result, nil
+}
+
+// Replace rewrites a question in place. Questions already asked in a quiz
+// attempt are locked so graded answers keep matching their question.
+func (s *questionService) Replace(id string, userID string, schoolID string, isAdmin bool, input dto.CreateQuestionDTO) (*dto.QuestionResponseDTO, error) {
+	current, err := s.getEditable(id, userID, schoolID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	answered, err := s.repo.IsAnswered(id)
+	if err != nil {
+		return nil, err
+	}
+	if answered {
+		return nil, fmt.Errorf("question is locked by quiz attempts")
+	}
+	if !strings.EqualFold(input.SubjectID, current.SubjectID) {
+		count, err := s.repo.CountQuizzes(id)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("question subject cannot change while it is used in quizzes")
+		}
+		if err := s.ensureSubjectInSchool(input.SubjectID, schoolID); err != nil {
+			return nil, err
+		}
+	}
+
+	question, err := buildQuestion(input)
+	if err != nil {
+		return nil, err
+	}
+	question.ID = id
+	if err := s.repo.Replace(question); err != nil {
+		return nil, err
+	}
+	return s.Get(id, schoolID)
+}
+
+func (s *questionService) Delete(id string, userID string, schoolID string, isAdmin bool) error {
+	if _, err := s.getEditable(id, userID, schoolID, isAdmin); err != nil {
+		return err
+	}
+	count, err := s.repo.CountQuizzes(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("question cannot be deleted because it is used in quizzes")
+	}
+	return s.repo.Delete(id)
+}
+
+// getEditable lets the author of a question and school admins change it.
+func (s *questionService) getEditable(id string, userID string, schoolID string, isAdmin bool) (*domain.Question, error) {
+	question, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if question.SchoolID != schoolID || (question.CreatedBy != userID && !isAdmin) {
+		return nil, fmt.Errorf("forbidden: question access denied")
+	}
+	return question, nil
+}
+
+func (s *questionService) ensureSubjectInSchool(subjectID string, schoolID string) error {
+	subject, err := s.subjectRepo.GetByID(subjectID)
+	if err != nil || subject.SchoolID != schoolID {
+		return fmt.Errorf("invalid question subject")
+	}
+	return nil
+}
+
+// buildQuestion validates the answer fields of the question type and drops
+// the fields of other types.
+func buildQuestion(input dto.CreateQuestionDTO) (*domain.Question, error) {
+	question := &domain.Question{
+		SubjectID: input.SubjectID,
+		Type:      input.Type,
+		Prompt:    strings.TrimSpace(input.Prompt),
+		Points:    input.Points,
+	}
+	if question.Prompt == "" {
+		return nil, fmt.Errorf("invalid question: prompt is required")
+	}
+	if question.Points <= 0 || math.IsNaN(question.Points) || math.IsInf(question.Points, 0) {
+		return nil, fmt.Errorf("invalid question: points must be greater than zero")
+	}
+
+	switch input.Type {
+	case domain.QuestionMultipleChoice, domain.QuestionMultiSelect:
+		if len(input.Options) < 2 || len(input.Options) > maxQuestionOptions {
+			return nil, fmt.Errorf("invalid question: choice questions need 2 to %d options", maxQuestionOptions)
+		}
+		correct := 0
+		for i, option := range input.Options {
+			text := strings.TrimSpace(option.Text)
+			if text == "" {
+				return nil, fmt.Errorf("invalid question: option text is required")
+			}
+			if option.IsCorrect {
+				correct++
+			}
+			question.Options = append(question.Options, domain.QuestionOption{
+				Text:      text,
+				IsCorrect: option.IsCorrect,
+				Position:  i + 1,
+			})
+		}
+		if input.Type == domain.QuestionMultipleChoice && correct != 1 {
+			return nil, fmt.Errorf("invalid question: multiple choice needs exactly one correct option")
+		}
+		if correct == 0 {
+			return nil, fmt.Errorf("invalid question: multi-select needs at least one correct option")
+		}
+	case domain.QuestionTrueFalse:
+		if input.BooleanAnswer == nil {
+			return nil, fmt.Errorf("invalid question: true/false needs booleanAnswer")
+		}
+		question.BooleanAnswer = input.BooleanAnswer
+	case domain.QuestionShortAnswer:
+		accepted := make([]string, 0, len(input.AcceptedAnswers))
+		for _, answer := range input.AcceptedAnswers {
+			if answer = strings.Join(strings.Fields(answer), " "); answer != "" {
+				accepted = append(accepted, answer)
+			}
+		}
+		if len(accepted) == 0 {
+			return nil, fmt.Errorf("invalid question: short answer needs accepted answers")
+		}
+		encoded, err := json.Marshal(accepted)
+		if err != nil {
+			return nil, err
+		}
+		question.AcceptedAnswers = string(encoded)
+		question.CaseSensitive = input.CaseSensitive
+	case domain.QuestionNumeric:
+		if input.NumericAnswer == nil || math.IsNaN(*input.NumericAnswer) || math.IsInf(*input.NumericAnswer, 0) {
+			return nil, fmt.Errorf("invalid question: numeric needs numericAnswer")
+		}
+		if input.NumericTolerance < 0 || math.IsNaN(input.NumericTolerance) || math.IsInf(input.NumericTolerance, 0) {
+			return nil, fmt.Errorf("invalid question: numeric tolerance must not be negative")
+		}
+		question.NumericAnswer = input.NumericAnswer
+		question.NumericTolerance = input.NumericTolerance
+	case domain.QuestionEssay:
+	default:
+		return nil, fmt.Errorf("invalid question type")
+	}
+	return question, nil
+}
+
+func isQuestionType(questionType string) bool {
+	switch questionType {
+	case domain.QuestionMultipleChoice, domain.QuestionMultiSelect, domain.QuestionTrueFalse,
+		domain.QuestionShortAnswer, domain.QuestionNumeric, domain.QuestionEssay:
+		return true
+	}
+	return false
+}
+
+func mapQuestionResponse(question *domain.Question) dto.QuestionResponseDTO {
+	response := dto.QuestionResponseDTO{
+		QuestionID:       question.ID,
+		SubjectID:        question.SubjectID,
+		Type:             question.Type,
+		Prompt:           question.Prompt,
+		Points:           question.Points,
+		Options:          make([]dto.QuestionOptionDTO, 0, len(question.Options)),
+		BooleanAnswer:    question.BooleanAnswer,
+		AcceptedAnswers:  decodeStringList(question.AcceptedAnswers),
+		CaseSensitive:    question.CaseSensitive,
+		NumericAnswer:    question.NumericAnswer,
+		NumericTolerance: question.NumericTolerance,
+		CreatedBy:        question.CreatedBy,
+		CreatedAt:        formatAPITime(question.CreatedAt),
+		UpdatedAt:        formatAPITime(question.UpdatedAt),
+	}
+	for _, option := range question.Options {
+		response.Options = append(response.Options, dto.QuestionOptionDTO{
+			OptionID:  option.ID,
+			Text:      option.Text,
+			IsCorrect: option.IsCorrect,
+		})
+	}
+	return response
+}
+
+// decodeStringList reads a JSON array column. Malformed values read as empty.
+func decodeStringList(encoded string) []string {
+	values := make([]string, 0)
+	if strings.TrimSpace(encoded) == "" {
+		return values
+	}
+	if err := json.Unmarshal([]byte(encoded), &values); err != nil {
+		return make([]string, 0)
+	}
+	return values
+}
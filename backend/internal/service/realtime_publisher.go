@@ -0,0 +1,12 @@
+package service
+
+import "backend/internal/dto"
+
+// RealtimePublisher pushes events to the affected users' open realtime
+// connections. Delivery is best-effort; the REST endpoints stay the source of
+// truth, so services ignore a nil publisher.
+type RealtimePublisher interface {
+	NotificationCreated(userID string, notification dto.NotificationResponseDTO)
+	FeedPosted(schoolID string, userIDs []string, feed dto.FeedPostedEventDTO)
+	SubmissionGraded(schoolID string, userID string, grade dto.SubmissionGradedEventDTO)
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	RubricScopePersonal = "personal"
+	RubricScopeSchool   = "school"
+)
+
+type RubricService interface {
+	Create(userID string, schoolID string, isAdmin bool, input dto.CreateRubricDTO) (*domain.Rubric, error)
+	List(userID string, schoolID string) ([]*domain.Rubric, error)
+	Get(id string, userID string, schoolID string, isAdmin bool) (*domain.Rubric, error)
+	Replace(id string, userID string, schoolID string, isAdmin bool, input dto.CreateRubricDTO) (*domain.Rubric, error)
+	Delete(id string, userID string, schoolID string, isAdmin bool) error
+}
+
+type rubricService struct {
+	repo repository.RubricRepository
+}
+
+func NewRubricService(repo repository.RubricRepository) RubricService {
+	return &rubricService{repo: repo}
+}
+
+func (s *rubricService) Create(userID string, schoolID string, isAdmin bool, input dto.CreateRubricDTO) (*domain.Rubric, error) {
+	ownerID, err := rubricOwner(input.Scope, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	criteria, err := buildRubricCriteria(input.Criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	rubric := domain.Rubric{
+		SchoolID:    schoolID,
+		OwnerID:     ownerID,
+		Title:       strings.TrimSpace(input.Title),
+		Description: strings.TrimSpace(input.Description),
+		CreatedBy:   userID,
+		Criteria:    criteria,
+	}
+	if err := s.repo.Create(&rubric); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(rubric.ID)
+}
+
+func (s *rubricService) List(userID string, schoolID string) ([]*domain.Rubric, error) {
+	return s.repo.List(schoolID, userID)
+}
+
+func (s *rubricService) Get(id string, userID string, schoolID string, isAdmin bool) (*domain.Rubric, error) {
+	rubric, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canUseRubric(rubric, userID, schoolID, isAdmin) {
+		return nil, fmt.Errorf("forbidden: rubric access denied")
+	}
+	return rubric, nil
+}
+
+// Replace rewrites a rubric in place. Rubrics that already graded a
+// submission are locked so filled rubrics keep matching their criteria.
+func (s *rubricService) Replace(id string, userID string, schoolID string, isAdmin bool, input dto.CreateRubricDTO) (*domain.Rubric, error) {
+	rubric, err := s.getEditable(id, userID, schoolID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.repo.IsUsedInAssessments(id)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, fmt.Errorf("rubric is locked by graded assessments")
+	}
+
+	ownerID, err := rubricOwner(input.Scope, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	criteria, err := buildRubricCriteria(input.Criteria)
+	if err != nil {
+		return nil, err
+	}
+	rubric.OwnerID = ownerID
+	rubric.Title = strings.TrimSpace(input.Title)
+	rubric.Description = strings.TrimSpace(input.Description)
+	rubric.Criteria = criteria
+	if err := s.repo.Replace(rubric); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *rubricService) Delete(id string, userID string, schoolID string, isAdmin bool) error {
+	if _, err := s.getEditable(id, userID, schoolID, isAdmin); err != nil {
+		return err
+	}
+	count, err := s.repo.CountAssignments(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("rubric cannot be deleted because it is attached to assignments")
+	}
+	return s.repo.Delete(id)
+}
+
+// getEditable lets owners edit their personal rubrics and admins edit the
+// school-wide ones.
+func (s *rubricService) getEditable(id string, userID string, schoolID string, isAdmin bool) (*domain.Rubric, error) {
+	rubric, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if rubric.SchoolID != schoolID {
+		return nil, fmt.Errorf("forbidden: rubric access denied")
+	}
+	if rubric.OwnerID == nil && !isAdmin {
+		return nil, fmt.Errorf("forbidden: school rubrics require the admin role")
+	}
+	if rubric.OwnerID != nil && *rubric.OwnerID != userID {
+		return nil, fmt.Errorf("forbidden: rubric access denied")
+	}
+	return rubric, nil
+}
+
+func rubricOwner(scope string, userID string, isAdmin bool) (*string, error) {
+	if scope == RubricScopeSchool {
+		if !isAdmin {
+			return nil, fmt.Errorf("forbidden: school rubrics require the admin role")
+		}
+		return nil, nil
+	}
+	return &userID, nil
+}
+
+func buildRubricCriteria(inputs []dto.RubricCriterionInputDTO) ([]domain.RubricCriterion, error) {
+	criteria := make([]domain.RubricCriterion, 0, len(inputs))
+	var maxPoints float64
+	for i, input := range inputs {
+		criterion := domain.RubricCriterion{
+			Title:       strings.TrimSpace(input.Title),
+			Description: strings.TrimSpace(input.Description),
+			Position:    i + 1,
+			Levels:      make([]domain.RubricLevel, 0, len(input.Levels)),
+		}
+		if criterion.Title == "" {
+			return nil, fmt.Errorf("invalid rubric: criterion title is required")
+		}
+		var criterionMax float64
+		for j, level := range input.Levels {
+			title := strings.TrimSpace(level.Title)
+			if title == "" {
+				return nil, fmt.Errorf("invalid rubric: level title is required")
+			}
+			if level.Points < 0 || math.IsNaN(level.Points) || math.IsInf(level.Points, 0) {
+				return nil, fmt.Errorf("invalid rubric: level points must not be negative")
+			}
+			criterion.Levels = append(criterion.Levels, domain.RubricLevel{
+				Title:       title,
+				Description: strings.TrimSpace(level.Description),
+				Points:      level.Points,
+				Position:    j + 1,
+			})
+			criterionMax = math.Max(criterionMax, level.Points)
+		}
+		maxPoints += criterionMax
+		criteria = append(criteria, criterion)
+	}
+	if maxPoints <= 0 {
+		return nil, fmt.Errorf("invalid rubric: total points must be greater than zero")
+	}
+	return criteria, nil
+}
+
+// canUseRubric reports whether a user may view or attach a rubric: every
+// school-wide rubric, their own personal rubrics, and any rubric of the school
+// for admins.
+func canUseRubric(rubric *domain.Rubric, userID string, schoolID string, isAdmin bool) bool {
+	if rubric.SchoolID != schoolID {
+		return false
+	}
+	return rubric.OwnerID == nil || *rubric.OwnerID == userID || isAdmin
+}
+
+// rubricMaxPoints is the sum of the best level of every criterion.
+func rubricMaxPoints(rubric *domain.Rubric) float64 {
+	var total float64
+	for _, criterion := range rubric.Criteria {
+		total += rubricCriterionMaxPoints(criterion)
+	}
+	return total
+}
+
+func rubricCriterionMaxPoints(criterion domain.RubricCriterion) float64 {
+	var best float64
+	for _, level := range criterion.Levels {
+		best = math.Max(best, level.Points)
+	}
+	return best
+}
+
+// scoreRubric turns one level per criterion into rubric scores and an
+// assessment score on the 0-100 scale used by the gradebook.
+func scoreRubric(rubric *domain.Rubric, inputs []dto.AssessmentCriterionInputDTO) ([]domain.AssessmentRubricScore, float64, error) {
+	byCriterion := make(map[string]dto.AssessmentCriterionInputDTO, len(inputs))
+	for _, input := range inputs {
+		key := strings.ToLower(input.CriterionID)
+		if _, duplicate := byCriterion[key]; duplicate {
+			return nil, 0, fmt.Errorf("invalid rubric assessment: criterion scored twice")
+		}
+		byCriterion[key] = input
+	}
+	if len(byCriterion) != len(rubric.Criteria) {
+		return nil, 0, fmt.Errorf("invalid rubric assessment: every criterion needs one level")
+	}
+
+	scores := make([]domain.AssessmentRubricScore, 0, len(rubric.Criteria))
+	var earned float64
+	for _, criterion := range rubric.Criteria {
+		input, ok := byCriterion[strings.ToLower(criterion.ID)]
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid rubric assessment: every criterion needs one level")
+		}
+		var picked *domain.RubricLevel
+		for i := range criterion.Levels {
+			if strings.EqualFold(criterion.Levels[i].ID, input.LevelID) {
+				picked = &criterion.Levels[i]
+				break
+			}
+		}
+		if picked == nil {
+			return nil, 0, fmt.Errorf("invalid rubric assessment: level does not belong to criterion")
+		}
+		scores = append(scores, domain.AssessmentRubricScore{
+			CriterionID: criterion.ID,
+			LevelID:     picked.ID,
+			Points:      picked.Points,
+			Comment:     strings.TrimSpace(input.Comment),
+		})
+		earned += picked.Points
+	}
+
+	maxPoints := rubricMaxPoints(rubric)
+	if maxPoints <= 0 {
+		return nil, 0, fmt.Errorf("invalid rubric: total points must be greater than zero")
+	}
+	return scores, percentScore(earned, maxPoints), nil
+}
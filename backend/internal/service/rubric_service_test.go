@@ -0,0 +1,55 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"testing"
+)
+
+func TestScoreRubric(t *testing.T) {
+	rubric := &domain.Rubric{Criteria: []domain.RubricCriterion{
+		{ID: "c1", Levels: []domain.RubricLevel{{ID: "c1-low", Points: 1}, {ID: "c1-high", Points: 4}}},
+		{ID: "c2", Levels: []domain.RubricLevel{{ID: "c2-low", Points: 0}, {ID: "c2-high", Points: 4}}},
+	}}
+
+	scores, score, err := scoreRubric(rubric, []dto.AssessmentCriterionInputDTO{
+		{CriterionID: "c1", LevelID: "c1-high", Comment: " Rapi "},
+		{CriterionID: "c2", LevelID: "c2-low"},
+	})
+	if err != nil {
+		t.Fatalf("expected rubric to be scored, got %v", err)
+	}
+	if score != 50 {
+		t.Fatalf("expected 4 of 8 points to score 50, got %v", score)
+	}
+	if len(scores) != 2 || scores[0].Points != 4 || scores[0].Comment != "Rapi" {
+		t.Fatalf("unexpected rubric scores %+v", scores)
+	}
+
+	if _, _, err := scoreRubric(rubric, []dto.AssessmentCriterionInputDTO{{CriterionID: "c1", LevelID: "c1-high"}}); err == nil {
+		t.Fatalf("expected a missing criterion to be rejected")
+	}
+	if _, _, err := scoreRubric(rubric, []dto.AssessmentCriterionInputDTO{
+		{CriterionID: "c1", LevelID: "c2-high"},
+		{CriterionID: "c2", LevelID: "c2-high"},
+	}); err == nil {
+		t.Fatalf("expected a level of another criterion to be rejected")
+	}
+}
+
+func TestBuildRubricCriteria(t *testing.T) {
+	criteria, err := buildRubricCriteria([]dto.RubricCriterionInputDTO{
+		{Title: "Isi", Levels: []dto.RubricLevelInputDTO{{Title: "Kurang", Points: 1}, {Title: "Baik", Points: 3}}},
+	})
+	if err != nil {
+		t.Fatalf("expected criteria to be built, got %v", err)
+	}
+	if criteria[0].Position != 1 || criteria[0].Levels[1].Position != 2 {
+		t.Fatalf("expected positions to follow input order, got %+v", criteria)
+	}
+	if _, err := buildRubricCriteria([]dto.RubricCriterionInputDTO{
+		{Title: "Isi", Levels: []dto.RubricLevelInputDTO{{Title: "Nol", Points: 0}}},
+	}); err == nil {
+		t.Fatalf("expected a rubric without points to be rejected")
+	}
+}
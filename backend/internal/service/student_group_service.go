@@ -0,0 +1,267 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type StudentGroupService interface {
+	Create(userID string, schoolID string, roles []string, input dto.CreateStudentGroupDTO) (*domain.StudentGroup, error)
+	ListBySubjectClass(subjectClassID string, userID string, schoolID string, roles []string) ([]*domain.StudentGroup, error)
+	Update(id string, userID string, schoolID string, roles []string, input dto.UpdateStudentGroupDTO) (*domain.StudentGroup, error)
+	Delete(id string, userID string, schoolID string, roles []string) error
+	SetMembers(id string, userID string, schoolID string, roles []string, memberIDs []string) (*domain.StudentGroup, error)
+	Join(id string, userID string, schoolID string) (*domain.StudentGroup, error)
+	Leave(id string, userID string, schoolID string) error
+}
+
+type studentGroupService struct {
+	repo                repository.StudentGroupRepository
+	subjectClassService SubjectClassService
+	enrRepo             repository.EnrollmentRepository
+}
+
+func NewStudentGroupService(
+	repo repository.StudentGroupRepository,
+	subjectClassService SubjectClassService,
+	enrRepo repository.EnrollmentRepository,
+) StudentGroupService {
+	return &studentGroupService{
+		repo:                repo,
+		subjectClassService: subjectClassService,
+		enrRepo:             enrRepo,
+	}
+}
+
+func (s *studentGroupService) Create(userID string, schoolID string, roles []string, input dto.CreateStudentGroupDTO) (*domain.StudentGroup, error) {
+	subjectClass, err := s.getManagedSubjectClass(input.SubjectClassID, userID, schoolID, roles)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("student group name is required")
+	}
+	memberIDs, err := uniqueNonEmptyIDs(input.MemberIDs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid student group member")
+	}
+	if input.MaxSize != nil && len(memberIDs) > *input.MaxSize {
+		return nil, fmt.Errorf("student group has more members than its size limit")
+	}
+	if err := s.ensureClassStudents(memberIDs, schoolID, subjectClass.ClassID); err != nil {
+		return nil, err
+	}
+
+	group := domain.StudentGroup{
+		SchoolID:       schoolID,
+		SubjectClassID: subjectClass.ID,
+		Name:           name,
+		MaxSize:        input.MaxSize,
+		SelfJoin:       input.SelfJoin,
+		CreatedBy:      userID,
+	}
+	if err := s.repo.Create(&group); err != nil {
+		return nil, err
+	}
+	if len(memberIDs) > 0 {
+		if err := s.repo.ReplaceMembers(&group, memberIDs); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.GetByID(group.ID)
+}
+
+func (s *studentGroupService) ListBySubjectClass(subjectClassID string, userID string, schoolID string, roles []string) ([]*domain.StudentGroup, error) {
+	allowed, err := s.subjectClassService.UserCanAccessSubjectClass(userID, schoolID, subjectClassID, roles)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: subject class access denied")
+	}
+	return s.repo.ListBySubjectClass(subjectClassID)
+}
+
+func (s *studentGroupService) Update(id string, userID string, schoolID string, roles []string, input dto.UpdateStudentGroupDTO) (*domain.StudentGroup, error) {
+	group, _, err := s.getManaged(id, userID, schoolID, roles)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, fmt.Errorf("student group name is required")
+		}
+		group.Name = name
+	}
+	if input.MaxSize != nil {
+		if *input.MaxSize == 0 {
+			group.MaxSize = nil
+		} else {
+			group.MaxSize = input.MaxSize
+		}
+	}
+	if input.SelfJoin != nil {
+		group.SelfJoin = *input.SelfJoin
+	}
+	if group.MaxSize != nil && len(group.Members) > *group.MaxSize {
+		return nil, fmt.Errorf("student group has more members than its size limit")
+	}
+	if err := s.repo.Update(group); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Delete removes a group that has not turned in work yet. Group submissions
+// keep pointing at their group, so groups with submissions stay.
+func (s *studentGroupService) Delete(id string, userID string, schoolID string, roles []string) error {
+	if _, _, err := s.getManaged(id, userID, schoolID, roles); err != nil {
+		return err
+	}
+	count, err := s.repo.CountSubmissions(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("student group cannot be deleted because it has submissions")
+	}
+	return s.repo.Delete(id)
+}
+
+// SetMembers replaces the members of a group. Students in another group of
+// the subject class move to this one.
+func (s *studentGroupService) SetMembers(id string, userID string, schoolID string, roles []string, memberIDs []string) (*domain.StudentGroup, error) {
+	group, subjectClass, err := s.getManaged(id, userID, schoolID, roles)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs, err = uniqueNonEmptyIDs(memberIDs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid student group member")
+	}
+	if group.MaxSize != nil && len(memberIDs) > *group.MaxSize {
+		return nil, fmt.Errorf("student group has more members than its size limit")
+	}
+	if err := s.ensureClassStudents(memberIDs, schoolID, subjectClass.ClassID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceMembers(group, memberIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Join adds a student to a self-join group. Students leave their current
+// group before joining another one.
+func (s *studentGroupService) Join(id string, userID string, schoolID string) (*domain.StudentGroup, error) {
+	group, err := s.getSelfJoinGroup(id, userID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetMembership(group.SubjectClassID, userID); err == nil {
+		return nil, fmt.Errorf("student is already in a group")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	added, err := s.repo.AddMember(group, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, fmt.Errorf("student group is full")
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *studentGroupService) Leave(id string, userID string, schoolID string) error {
+	if _, err := s.getSelfJoinGroup(id, userID, schoolID); err != nil {
+		return err
+	}
+	return s.repo.RemoveMember(id, userID)
+}
+
+func (s *studentGroupService) getManaged(id string, userID string, schoolID string, roles []string) (*domain.StudentGroup, *domain.SubjectClass, error) {
+	group, err := s.getInSchool(id, schoolID)
+	if err != nil {
+		return nil, nil, err
+	}
+	subjectClass, err := s.getManagedSubjectClass(group.SubjectClassID, userID, schoolID, roles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return group, subjectClass, nil
+}
+
+// getManagedSubjectClass lets admins and the teacher of a subject class
+// manage its groups.
+func (s *studentGroupService) getManagedSubjectClass(subjectClassID string, userID string, schoolID string, roles []string) (*domain.SubjectClass, error) {
+	subjectClass, err := s.subjectClassService.GetByIDInSchool(subjectClassID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	managerRoles := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role == "admin" || role == "teacher" {
+			managerRoles = append(managerRoles, role)
+		}
+	}
+	allowed, err := s.subjectClassService.UserCanAccessSubjectClass(userID, schoolID, subjectClassID, managerRoles)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: student groups can only be managed by the class teacher")
+	}
+	return subjectClass, nil
+}
+
+func (s *studentGroupService) getSelfJoinGroup(id string, userID string, schoolID string) (*domain.StudentGroup, error) {
+	group, err := s.getInSchool(id, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.subjectClassService.UserCanAccessSubjectClass(userID, schoolID, group.SubjectClassID, []string{"student"})
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("forbidden: student is not enrolled in this class")
+	}
+	if !group.SelfJoin {
+		return nil, fmt.Errorf("forbidden: student group is not open for self-join")
+	}
+	return group, nil
+}
+
+func (s *studentGroupService) getInSchool(id string, schoolID string) (*domain.StudentGroup, error) {
+	group, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if group.SchoolID != schoolID {
+		return nil, fmt.Errorf("forbidden: student group does not belong to active school")
+	}
+	return group, nil
+}
+
+func (s *studentGroupService) ensureClassStudents(userIDs []string, schoolID string, classID string) error {
+	for _, userID := range userIDs {
+		ok, err := s.enrRepo.UserEnrolledInClassAsRole(userID, schoolID, classID, "student")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("invalid student group member")
+		}
+	}
+	return nil
+}
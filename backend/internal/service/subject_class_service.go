@@ -23,11 +23,12 @@ type SubjectClassService interface {
 }
 
 type subjectClassService struct {
-	repo repository.SubjectClassRepository
+	repo     repository.SubjectClassRepository
+	chatRepo repository.ChatRepository
 }
 
-func NewSubjectClassService(repo repository.SubjectClassRepository) SubjectClassService {
-	return &subjectClassService{repo: repo}
+func NewSubjectClassService(repo repository.SubjectClassRepository, chatRepo repository.ChatRepository) SubjectClassService {
+	return &subjectClassService{repo: repo, chatRepo: chatRepo}
 }
 
 func (s *subjectClassService) Assign(scl *domain.SubjectClass) error {
@@ -40,7 +41,11 @@ func (s *subjectClassService) Assign(scl *domain.SubjectClass) error {
 		return fmt.Errorf("this subject is already assigned to the class with the same teacher")
 	}
 
-	return s.repo.Create(scl)
+	if err := s.repo.Create(scl); err != nil {
+		return err
+	}
+	syncClassRooms(s.chatRepo, scl.ClassID)
+	return nil
 }
 
 func (s *subjectClassService) AssignInSchool(scl *domain.SubjectClass, schoolID string) error {
@@ -52,14 +57,22 @@ func (s *subjectClassService) AssignInSchool(scl *domain.SubjectClass, schoolID
 		return err
 	}
 
-	return s.repo.Create(scl)
+	if err := s.repo.Create(scl); err != nil {
+		return err
+	}
+	syncClassRooms(s.chatRepo, scl.ClassID)
+	return nil
 }
 
 func (s *subjectClassService) Update(scl *domain.SubjectClass) error {
 	// Validasi duplikasi (jika data yang diupdate ternyata sama dengan assignment lain)
 	// butuh method CheckExists yang lebih detail jika ingin validasi update,
 	// tapi untuk sekarang kita asumsikan update guru saja yang paling sering.
-	return s.repo.Update(scl)
+	if err := s.repo.Update(scl); err != nil {
+		return err
+	}
+	syncClassRooms(s.chatRepo, scl.ClassID)
+	return nil
 }
 
 func (s *subjectClassService) UpdateInSchool(scl *domain.SubjectClass, schoolID string) error {
@@ -72,7 +85,13 @@ func (s *subjectClassService) UpdateInSchool(scl *domain.SubjectClass, schoolID
 	if err := s.ensureNoClassSubjectDuplicate(scl.ClassID, scl.SubjectID, scl.ID); err != nil {
 		return err
 	}
-	return s.repo.Update(scl)
+	if err := s.repo.Update(scl); err != nil {
+		return err
+	}
+	// The room follows the subject class, so a teacher change hands over
+	// moderation and a class change moves the members.
+	syncClassRooms(s.chatRepo, scl.ClassID)
+	return nil
 }
 
 func (s *subjectClassService) GetByClass(classID string) ([]*domain.SubjectClass, error) {
@@ -126,7 +145,11 @@ func (s *subjectClassService) UserCanAccessSubjectClass(userID string, schoolID
 }
 
 func (s *subjectClassService) Unassign(id string) error {
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	closeSubjectClassRoom(s.chatRepo, id)
+	return nil
 }
 
 func (s *subjectClassService) UnassignInSchool(id string, schoolID string) error {
@@ -140,7 +163,11 @@ func (s *subjectClassService) UnassignInSchool(id string, schoolID string) error
 	if hasContent {
 		return fmt.Errorf("subject class has content")
 	}
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	closeSubjectClassRoom(s.chatRepo, id)
+	return nil
 }
 
 func (s *subjectClassService) validateAssignmentScope(classID string, subjectID string, teacherSchoolUserID string, schoolID string) error {
@@ -227,3 +254,20 @@ func (s *subjectClassService) ensureNoClassSubjectDuplicate(classID string, subj
 	}
 	return nil
 }
+
+// syncClassRooms aligns the chat rooms of the class after a committed
+// enrollment or subject class change. The change itself already succeeded, so
+// a failed sync is logged and left for the startup backfill to repair.
+func syncClassRooms(chatRepo repository.ChatRepository, classID string) {
+	if err := chatRepo.SyncClassRooms(classID); err != nil {
+		fmt.Printf("[Chat Warning] failed to sync class rooms class_id=%s error=%s\n", classID, err.Error())
+	}
+}
+
+// closeSubjectClassRoom retires the room of a deleted subject class, logging
+// failures the same way as syncClassRooms.
+func closeSubjectClassRoom(chatRepo repository.ChatRepository, subjectClassID string) {
+	if err := chatRepo.CloseSubjectClassRoom(subjectClassID); err != nil {
+		fmt.Printf("[Chat Warning] failed to close subject class room subject_class_id=%s error=%s\n", subjectClassID, err.Error())
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"backend/internal/repository"
+	"errors"
+	"testing"
+)
+
+type subjectClassRepositoryStub struct {
+	repository.SubjectClassRepository
+	hasContent bool
+	deletedID  string
+}
+
+func (r *subjectClassRepositoryStub) SubjectClassBelongsToSchool(string, string) (bool, error) {
+	return true, nil
+}
+
+func (r *subjectClassRepositoryStub) HasSubjectClassContent(string, string) (bool, error) {
+	return r.hasContent, nil
+}
+
+func (r *subjectClassRepositoryStub) Delete(id string) error {
+	r.deletedID = id
+	return nil
+}
+
+type classRoomChatRepositoryStub struct {
+	repository.ChatRepository
+	closedID string
+	closeErr error
+}
+
+func (r *classRoomChatRepositoryStub) CloseSubjectClassRoom(subjectClassID string) error {
+	r.closedID = subjectClassID
+	return r.closeErr
+}
+
+func TestUnassignSubjectClassClosesRoom(t *testing.T) {
+	repo := &subjectClassRepositoryStub{}
+	chatRepo := &classRoomChatRepositoryStub{}
+	service := NewSubjectClassService(repo, chatRepo)
+
+	if err := service.UnassignInSchool("subject-class-1", "school-1"); err != nil {
+		t.Fatalf("expected unassign to succeed, got %v", err)
+	}
+	if repo.deletedID != "subject-class-1" || chatRepo.closedID != "subject-class-1" {
+		t.Fatalf("expected subject class deleted and room closed, got deleted %q closed %q", repo.deletedID, chatRepo.closedID)
+	}
+}
+
+func TestUnassignSubjectClassWithContentKeepsRoom(t *testing.T) {
+	repo := &subjectClassRepositoryStub{hasContent: true}
+	chatRepo := &classRoomChatRepositoryStub{}
+	service := NewSubjectClassService(repo, chatRepo)
+
+	if err := service.UnassignInSchool("subject-class-1", "school-1"); err == nil {
+		t.Fatal("expected unassign with content to fail")
+	}
+	if repo.deletedID != "" || chatRepo.closedID != "" {
+		t.Fatalf("expected nothing deleted or closed, got deleted %q closed %q", repo.deletedID, chatRepo.closedID)
+	}
+}
+
+func TestUnassignSubjectClassIgnoresRoomCloseFailure(t *testing.T) {
+	repo := &subjectClassRepositoryStub{}
+	chatRepo := &classRoomChatRepositoryStub{closeErr: errors.New("connection reset")}
+	service := NewSubjectClassService(repo, chatRepo)
+
+	if err := service.UnassignInSchool("subject-class-1", "school-1"); err != nil {
+		t.Fatalf("expected the committed delete to succeed despite the room, got %v", err)
+	}
+	if repo.deletedID != "subject-class-1" {
+		t.Fatalf("expected subject class deleted, got %q", repo.deletedID)
+	}
+}